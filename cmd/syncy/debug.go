@@ -64,12 +64,16 @@ func debugCommands(outFlag, scratchLocalPath cli.StringFlag) cli.Command {
 			{
 				Name:  "create-project",
 				Usage: "create an project on a remote backend",
-				Flags: []cli.Flag{serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag, blockSizeFlag},
+				Flags: []cli.Flag{serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag, blockSizeFlag, namePolicyFlag},
 				Action: func(cctx *cli.Context) error {
 					project := cctx.Args().Get(0)
 					if project == "" {
 						return fmt.Errorf("<project> is required")
 					}
+					namePolicy, ok := syncv1.NamePolicy_value[cctx.String(namePolicyFlag.Name)]
+					if !ok {
+						return fmt.Errorf("unsupported name policy: %q", cctx.String(namePolicyFlag.Name))
+					}
 					ctx, ll, printer, err := makeDeps(cctx)
 					if err != nil {
 						return fmt.Errorf("preparing dependencies: %w", err)
@@ -86,6 +90,7 @@ func debugCommands(outFlag, scratchLocalPath cli.StringFlag) cli.Command {
 					res, err := client.CreateProject(ctx, connect.NewRequest(&syncv1.CreateProjectRequest{
 						AccountId:   meta.AccountId,
 						ProjectName: project,
+						NamePolicy:  syncv1.NamePolicy(namePolicy),
 					}))
 					if err != nil {
 						return fmt.Errorf("creating project: %w", err)
@@ -264,6 +269,7 @@ func debugCommands(outFlag, scratchLocalPath cli.StringFlag) cli.Command {
 					patchSize, err := dirsync.Rsync(ctx, srcf, sinkSum,
 						enc.WriteBlock,
 						enc.WriteBlockID,
+						enc.WriteHole,
 					)
 					duration := time.Since(start)
 					if err != nil {
@@ -370,6 +376,7 @@ func debugCommands(outFlag, scratchLocalPath cli.StringFlag) cli.Command {
 					patchedSize, err := patchcodec.NewDecoder(patchf).Decode(
 						patcher.WriteBlock,
 						patcher.Copy,
+						patcher.WriteHole,
 					)
 					if err != nil {
 						return fmt.Errorf("patching file %q: %w", patch, err)
@@ -453,7 +460,7 @@ func debugCommands(outFlag, scratchLocalPath cli.StringFlag) cli.Command {
 
 					ll.Info("creating patch")
 					enc := patchcodec.NewEncoder(patchf)
-					_, err = dirsync.Rsync(ctx, srcf, sum, enc.WriteBlock, enc.WriteBlockID)
+					_, err = dirsync.Rsync(ctx, srcf, sum, enc.WriteBlock, enc.WriteBlockID, enc.WriteHole)
 					if err != nil {
 						return fmt.Errorf("computing patch file from <src> to <dst>: %w", err)
 					}
@@ -467,7 +474,7 @@ func debugCommands(outFlag, scratchLocalPath cli.StringFlag) cli.Command {
 					// server side
 					ll.Info("applying patch")
 					patcher := dirsync.NewFilePatcher(origf, dstf, sum)
-					_, err = patchcodec.NewDecoder(patchf).Decode(patcher.WriteBlock, patcher.Copy)
+					_, err = patchcodec.NewDecoder(patchf).Decode(patcher.WriteBlock, patcher.Copy, patcher.WriteHole)
 					if err != nil {
 						return fmt.Errorf("patching destination <dst>: %w", err)
 					}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
@@ -10,7 +13,10 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"syscall"
 
 	"connectrpc.com/connect"
 	syncv1 "github.com/aybabtme/syncy/pkg/gen/svc/sync/v1"
@@ -19,6 +25,8 @@ import (
 	"github.com/aybabtme/syncy/pkg/logic/dirsync"
 	"github.com/aybabtme/syncy/pkg/logic/syncclient"
 	"github.com/urfave/cli"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -61,6 +69,23 @@ var (
 		Value: 2 << 16,
 		Usage: "block size for rsync algorithm",
 	}
+	symlinksFlag = cli.StringFlag{
+		Name:  "symlinks",
+		Value: "preserve",
+		Usage: "what to do with symlinks, must be one of: preserve, follow, skip",
+	}
+	excludeFlag = cli.StringSliceFlag{
+		Name:  "exclude",
+		Usage: "gitignore-style pattern of paths not to sync, on top of the ones in " + dirsync.IgnoreFile + " files",
+	}
+	includeFlag = cli.StringSliceFlag{
+		Name:  "include",
+		Usage: "gitignore-style pattern of paths to sync even if they are excluded",
+	}
+	deleteExcludedFlag = cli.BoolFlag{
+		Name:  "delete-excluded",
+		Usage: "delete excluded paths from the remote, instead of leaving them alone",
+	}
 	scratchLocalPath = cli.StringFlag{
 		Name:  "scratch.local_path",
 		Value: "/tmp/syncy_scratch",
@@ -71,9 +96,115 @@ var (
 	}
 	printerFlag = cli.StringFlag{
 		Name:  "printer",
-		Usage: "must be one of: text, json",
+		Usage: "must be one of: text, json, proto",
 		Value: "json",
 	}
+	dryRunFlag = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "print the plan of what would be synced, without syncing it",
+	}
+	planFileFlag = cli.StringFlag{
+		Name:  "plan",
+		Usage: "if specified, a plan file (json or proto) to execute instead of computing a new plan",
+	}
+	bidirectionalFlag = cli.BoolFlag{
+		Name:  "bidirectional",
+		Usage: "also apply the changes made on the backend to the path, keeping both versions of what changed on both sides",
+	}
+	stateFileFlag = cli.StringFlag{
+		Name:  "state",
+		Usage: "if specified, the file where to keep the state of the last bidirectional sync, instead of the user config dir",
+	}
+	indexFileFlag = cli.StringFlag{
+		Name:  "index",
+		Usage: "if specified, the file where to keep the index of the files synced, instead of the user cache dir",
+	}
+	journalFileFlag = cli.StringFlag{
+		Name:  "journal",
+		Usage: "if specified, the file where to journal the sync for it to be resumed if it's interrupted, instead of the user cache dir",
+	}
+	streamFlag = cli.BoolFlag{
+		Name:  "stream",
+		Usage: "diff and sync dir by dir while walking the path, for trees too large to hold in memory; renames aren't detected",
+	}
+	noIndexFlag = cli.BoolFlag{
+		Name:  "no-index",
+		Usage: "read every file whose info matches the backend, instead of trusting the index of the last sync",
+	}
+	noDeleteFlag = cli.BoolFlag{
+		Name:  "no-delete",
+		Usage: "only add to the destination, leaving alone what's gone from the source",
+	}
+	maxDeleteFlag = cli.IntFlag{
+		Name:  "max-delete",
+		Usage: "if more than this many files would be deleted, fail before deleting any, 0 for no limit",
+	}
+	maxDeletePercentFlag = cli.Float64Flag{
+		Name:  "max-delete-percent",
+		Usage: "if more than this percentage of the files of the destination would be deleted, fail before deleting any, 0 for no limit",
+	}
+	ownersFlag = cli.BoolFlag{
+		Name:  "owners",
+		Usage: "sync the owners of files, which are only applied to local files when running as root",
+	}
+	xattrsFlag = cli.BoolFlag{
+		Name:  "xattrs",
+		Usage: "sync the extended attributes of files, POSIX ACLs included",
+	}
+	unstableRetriesFlag = cli.IntFlag{
+		Name:  "unstable-retries",
+		Usage: "how many more times to send a file that changes while it's synced, before skipping it",
+		Value: 3,
+	}
+	checksumFlag = cli.BoolFlag{
+		Name:  "checksum",
+		Usage: "ignore mod times and compare the content of files, for trees whose mod times were lost",
+	}
+	sizeOnlyFlag = cli.BoolFlag{
+		Name:  "size-only",
+		Usage: "ignore mod times and only sync files whose size or mode differs, without reading their content",
+	}
+	trustMtimeFlag = cli.BoolFlag{
+		Name:  "trust-mtime",
+		Usage: "only sync files whose size, mode or mod time differs, without reading their content",
+	}
+	specialFilesFlag = cli.StringFlag{
+		Name:  "special-files",
+		Value: "skip",
+		Usage: "what to do with named pipes, sockets and devices, must be one of: skip (and report them), fail, ignore",
+	}
+	unreadableFlag = cli.StringFlag{
+		Name:  "unreadable",
+		Value: "skip",
+		Usage: "what to do with files and dirs that can't be read for lack of permissions, must be one of: skip (and report them), fail, ignore",
+	}
+	escapingSymlinksFlag = cli.StringFlag{
+		Name:  "escaping-symlinks",
+		Value: "skip",
+		Usage: "what to do with symlinks whose target is outside of the synced dir, which are never synced, must be one of: skip (and report them), fail, ignore",
+	}
+	namePolicyFlag = cli.StringFlag{
+		Name:  "names",
+		Value: "allow",
+		Usage: "what the backend does with names that collide with the one of a sibling once case and unicode normalization are ignored, must be one of: allow, warn, reject, normalize",
+	}
+	remotePathFlag = cli.StringFlag{
+		Name:  "remote-path",
+		Usage: "if specified, the dir of the project to sync the path into, made if it's missing; only what's under it is compared and deleted",
+	}
+	manifestFlag = cli.StringFlag{
+		Name:  "manifest",
+		Usage: "if specified, a YAML manifest of the paths to sync, each with its project, remote path and options, instead of <path>",
+	}
+	maxParallelMappingsFlag = cli.IntFlag{
+		Name:  "max-parallel-mappings",
+		Usage: "how many mappings of a manifest to sync at once, the ones that overlap are synced one after the other",
+		Value: 4,
+	}
+	forceFlag = cli.BoolFlag{
+		Name:  "force",
+		Usage: "delete what's gone from the source, however much of the destination it is",
+	}
 )
 
 func main() {
@@ -92,6 +223,10 @@ func main() {
 	}
 	app.Commands = []cli.Command{
 		syncCommand(serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag, maxParallelFileStreamFlag),
+		planCommand(serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag),
+		pullCommand(serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag, maxParallelFileStreamFlag),
+		watchCommand(serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag, maxParallelFileStreamFlag),
+		statusCommand(),
 		statsCommands(serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag),
 		debugCommands(outFlag, scratchLocalPath),
 	}
@@ -107,8 +242,11 @@ func syncCommand(serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFla
 	return cli.Command{
 		Name:  "sync",
 		Usage: "sync a path against a backend",
-		Flags: []cli.Flag{serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag, maxParallelFileStreamFlag, blockSizeFlag},
+		Flags: []cli.Flag{serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag, maxParallelFileStreamFlag, blockSizeFlag, symlinksFlag, excludeFlag, includeFlag, deleteExcludedFlag, noDeleteFlag, maxDeleteFlag, maxDeletePercentFlag, forceFlag, ownersFlag, xattrsFlag, checksumFlag, sizeOnlyFlag, trustMtimeFlag, specialFilesFlag, unreadableFlag, escapingSymlinksFlag, unstableRetriesFlag, remotePathFlag, manifestFlag, maxParallelMappingsFlag, dryRunFlag, planFileFlag, bidirectionalFlag, stateFileFlag, indexFileFlag, noIndexFlag, journalFileFlag, streamFlag},
 		Action: func(cctx *cli.Context) error {
+			if cctx.String(manifestFlag.Name) != "" {
+				return syncManifestAction(cctx, serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag)
+			}
 			path := cctx.Args().First()
 			if !filepath.IsAbs(path) {
 				return fmt.Errorf("<path> is not absolute")
@@ -117,49 +255,577 @@ func syncCommand(serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFla
 			if err != nil {
 				return fmt.Errorf("preparing dependencies: %w", err)
 			}
-			httpClient, err := makeHttpClient(cctx)
+			sink, err := makeSink(cctx, ll, serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag)
 			if err != nil {
-				return fmt.Errorf("creating http client: %w", err)
+				return err
 			}
-			client, meta, err := makeClient(cctx, httpClient, serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag)
+
+			syncParams, err := makeSyncParams(cctx)
 			if err != nil {
-				return fmt.Errorf("creating sync service client: %w", err)
+				return err
+			}
+
+			src := dirsync.NewLocalSource(path)
+
+			if cctx.Bool(bidirectionalFlag.Name) {
+				if cctx.Bool(dryRunFlag.Name) || cctx.String(planFileFlag.Name) != "" || cctx.String(remotePathFlag.Name) != "" {
+					return fmt.Errorf("--%s can't be used with --%s, --%s or --%s", bidirectionalFlag.Name, dryRunFlag.Name, planFileFlag.Name, remotePathFlag.Name)
+				}
+				remote, err := makeSource(ctx, cctx, ll, serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag)
+				if err != nil {
+					return err
+				}
+				stateFile := cctx.String(stateFileFlag.Name)
+				if stateFile == "" {
+					stateFile, err = defaultStateFile(cctx, path)
+					if err != nil {
+						return err
+					}
+				}
+				base, err := readSyncState(stateFile)
+				if err != nil {
+					return fmt.Errorf("reading state of last sync: %w", err)
+				}
+				syncParams.Machine, err = os.Hostname()
+				if err != nil {
+					return fmt.Errorf("getting hostname: %w", err)
+				}
+
+				ll.InfoContext(ctx, "preparing to sync both ways",
+					slog.String("path", path),
+					slog.String("state", stateFile),
+					slog.Bool("first_sync", base == nil),
+				)
+				local := dirsync.Side{Source: src, Sink: dirsync.NewLocalSink(path)}
+				newBase, conflicts, err := dirsync.SyncBidirectional(ctx, ".", local, dirsync.Side{Source: remote, Sink: sink}, base, syncParams)
+				if err != nil {
+					return fmt.Errorf("failed to sync: %w", err)
+				}
+				if err := writeSyncState(stateFile, newBase); err != nil {
+					return fmt.Errorf("saving state of sync: %w", err)
+				}
+				for _, conflict := range conflicts {
+					printer.Emit(conflictReport{
+						Path:       typesv1.StringFromPath(conflict.Path),
+						Resolution: conflict.Resolution.String(),
+						CopyName:   conflict.CopyName,
+					})
+				}
+				printer.Emit("sync completed")
+				return nil
 			}
-			blockSize := cctx.Uint(blockSizeFlag.Name)
-			if blockSize < 128 {
-				return fmt.Errorf("minimum block size is 128")
+
+			if cctx.Bool(dryRunFlag.Name) {
+				ll.InfoContext(ctx, "planning sync", slog.String("path", path))
+				plan, err := dirsync.Plan(ctx, ".", src, sink, syncParams)
+				if err != nil {
+					return fmt.Errorf("failed to plan sync: %w", forceHint(err))
+				}
+				plan.Root = sink.Root()
+				printer.Emit(plan)
+				return nil
 			}
-			if blockSize >= math.MaxUint32 {
-				return fmt.Errorf("block size must fit in a uint32")
+
+			if planFile := cctx.String(planFileFlag.Name); planFile != "" {
+				plan, err := readPlan(planFile)
+				if err != nil {
+					return fmt.Errorf("reading plan: %w", err)
+				}
+				sink, err = planSink(sink, plan, cctx.String(remotePathFlag.Name))
+				if err != nil {
+					return err
+				}
+				ll.InfoContext(ctx, "executing plan",
+					slog.String("path", path),
+					slog.String("plan", planFile),
+					slog.Int("ops", len(plan.Ops)),
+				)
+				observer, stopProgress := makeProgress(printer)
+				syncParams.Observer = observer
+				err = dirsync.ExecutePlan(ctx, src, sink, plan, syncParams)
+				stopProgress()
+				if err != nil {
+					return fmt.Errorf("failed to execute plan: %w", forceHint(err))
+				}
+				printer.Emit("sync completed")
+				return nil
 			}
 
-			sink, err := syncclient.ClientAdapter(ll, client, meta, blockSize)
+			var indexFile string
+			if !cctx.Bool(noIndexFlag.Name) {
+				indexFile, err = makeIndexFile(cctx, path)
+				if err != nil {
+					return err
+				}
+				syncParams.Index, err = dirsync.LoadIndex(indexFile)
+				if err != nil {
+					return fmt.Errorf("loading index: %w", err)
+				}
+			}
+
+			syncParams.Streaming = cctx.Bool(streamFlag.Name)
+
+			var journalFile string
+			if !syncParams.Streaming {
+				journalFile, err = makeJournalFile(cctx, path)
+				if err != nil {
+					return err
+				}
+				syncParams.Journal, err = dirsync.OpenJournal(journalFile)
+				if err != nil {
+					return fmt.Errorf("opening journal: %w", err)
+				}
+				defer syncParams.Journal.Close()
+			}
+
+			ll.InfoContext(ctx, "preparing to sync",
+				slog.String("path", path),
+				slog.String("index", indexFile),
+				slog.String("journal", journalFile),
+				slog.Bool("streaming", syncParams.Streaming),
+			)
+
+			observer, stopProgress := makeProgress(printer)
+			syncParams.Observer = observer
+			stats, err := syncOrResume(ctx, ll, src, sink, syncParams)
+			stopProgress()
+			printer.Emit(makeStatsReport(stats))
+			if err != nil {
+				return fmt.Errorf("failed to sync: %w", interruptHint(ctx, forceHint(err)))
+			}
+			if syncParams.Index != nil {
+				if err := syncParams.Index.Save(indexFile); err != nil {
+					return fmt.Errorf("saving index: %w", err)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// syncOrResume resumes the sync journaled in `params.Journal` if it was
+// interrupted, or syncs from scratch. A sync whose plan no longer fits the
+// sink is synced from scratch too.
+func syncOrResume(ctx context.Context, ll *slog.Logger, src dirsync.Source, sink dirsync.Sink, params dirsync.Params) (*dirsync.SyncStats, error) {
+	if pending := params.Journal.Pending(); pending != nil {
+		ll.InfoContext(ctx, "resuming interrupted sync", slog.Int("ops", len(pending.Ops)))
+		stats, err := dirsync.Resume(ctx, src, sink, params)
+		if !errors.Is(err, dirsync.ErrStalePlan) {
+			return stats, err
+		}
+		ll.WarnContext(ctx, "interrupted sync can't be resumed, syncing from scratch", slog.Any("err", err))
+	}
+	return dirsync.Sync(ctx, ".", src, sink, params)
+}
+
+// syncManifestAction syncs all the mappings of the manifest of the flags.
+func syncManifestAction(cctx *cli.Context, serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag cli.StringFlag) error {
+	if cctx.NArg() != 0 {
+		return fmt.Errorf("<path> can't be used with --%s, the paths are in the manifest", manifestFlag.Name)
+	}
+	for _, flag := range []string{remotePathFlag.Name, dryRunFlag.Name, planFileFlag.Name, bidirectionalFlag.Name, stateFileFlag.Name, indexFileFlag.Name, journalFileFlag.Name} {
+		if cctx.IsSet(flag) {
+			return fmt.Errorf("--%s can't be used with --%s", flag, manifestFlag.Name)
+		}
+	}
+	ctx, ll, printer, err := makeDeps(cctx)
+	if err != nil {
+		return fmt.Errorf("preparing dependencies: %w", err)
+	}
+	manifestFile := cctx.String(manifestFlag.Name)
+	manifest, err := readManifest(manifestFile)
+	if err != nil {
+		return fmt.Errorf("reading manifest: %w", err)
+	}
+	httpClient, err := makeHttpClient(cctx)
+	if err != nil {
+		return fmt.Errorf("creating http client: %w", err)
+	}
+	client, defaults, err := makeClient(cctx, httpClient, serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag)
+	if err != nil {
+		return fmt.Errorf("creating sync service client: %w", err)
+	}
+	syncParams, err := makeSyncParams(cctx)
+	if err != nil {
+		return err
+	}
+	syncParams.Streaming = cctx.Bool(streamFlag.Name)
+
+	ll.InfoContext(ctx, "preparing to sync manifest",
+		slog.String("manifest", manifestFile),
+		slog.Int("mappings", len(manifest.Mappings)),
+	)
+	report := syncAll(ctx, cctx, ll, client, defaults, manifest, syncParams, cctx.Int(maxParallelMappingsFlag.Name))
+	printer.Emit(report)
+	if report.Failed != 0 {
+		return fmt.Errorf("failed to sync %d of %d mappings", report.Failed, len(report.Mappings))
+	}
+	return nil
+}
+
+// Plan sync command: plan <absolute folder path>
+
+func planCommand(serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag cli.StringFlag) cli.Command {
+	return cli.Command{
+		Name:  "plan",
+		Usage: "print what syncing a path against a backend would do, use `--printer proto` to save it for `sync --plan`",
+		Flags: []cli.Flag{serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag, blockSizeFlag, symlinksFlag, excludeFlag, includeFlag, deleteExcludedFlag, noDeleteFlag, maxDeleteFlag, maxDeletePercentFlag, forceFlag, ownersFlag, xattrsFlag, checksumFlag, sizeOnlyFlag, trustMtimeFlag, specialFilesFlag, unreadableFlag, escapingSymlinksFlag, remotePathFlag},
+		Action: func(cctx *cli.Context) error {
+			path := cctx.Args().First()
+			if !filepath.IsAbs(path) {
+				return fmt.Errorf("<path> is not absolute")
+			}
+			ctx, ll, printer, err := makeDeps(cctx)
+			if err != nil {
+				return fmt.Errorf("preparing dependencies: %w", err)
+			}
+			sink, err := makeSink(cctx, ll, serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag)
+			if err != nil {
+				return err
+			}
+			syncParams, err := makeSyncParams(cctx)
 			if err != nil {
-				return fmt.Errorf("configuring sync service client: %w", err)
+				return err
 			}
 
-			maxParallelFileStream := cctx.Uint(maxParallelFileStreamFlag.Name)
+			ll.InfoContext(ctx, "planning sync", slog.String("path", path))
+
+			src := dirsync.NewLocalSource(path)
 
-			syncParams := dirsync.Params{
-				MaxParallelFileStreams: int(maxParallelFileStream),
+			plan, err := dirsync.Plan(ctx, ".", src, sink, syncParams)
+			if err != nil {
+				return fmt.Errorf("failed to plan sync: %w", forceHint(err))
 			}
+			plan.Root = sink.Root()
 
-			ll.InfoContext(ctx, "preparing to sync", slog.String("path", path))
+			printer.Emit(plan)
 
-			src := os.DirFS(path).(dirsync.Source)
+			return nil
+		},
+	}
+}
 
-			err = dirsync.Sync(ctx, ".", src, sink, syncParams)
+// Pull project command: pull <absolute folder path>
+
+func pullCommand(serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag cli.StringFlag, maxParallelFileStreamFlag cli.UintFlag) cli.Command {
+	return cli.Command{
+		Name:  "pull",
+		Usage: "sync a path with the content of a backend, the reverse of `sync`",
+		Flags: []cli.Flag{serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag, maxParallelFileStreamFlag, symlinksFlag, excludeFlag, includeFlag, deleteExcludedFlag, noDeleteFlag, maxDeleteFlag, maxDeletePercentFlag, forceFlag, ownersFlag, xattrsFlag, checksumFlag, sizeOnlyFlag, trustMtimeFlag, specialFilesFlag, unreadableFlag, escapingSymlinksFlag, unstableRetriesFlag, dryRunFlag},
+		Action: func(cctx *cli.Context) error {
+			path := cctx.Args().First()
+			if !filepath.IsAbs(path) {
+				return fmt.Errorf("<path> is not absolute")
+			}
+			ctx, ll, printer, err := makeDeps(cctx)
+			if err != nil {
+				return fmt.Errorf("preparing dependencies: %w", err)
+			}
+			src, err := makeSource(ctx, cctx, ll, serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag)
+			if err != nil {
+				return err
+			}
+			syncParams, err := makeSyncParams(cctx)
 			if err != nil {
-				return fmt.Errorf("failed to sync: %w", err)
+				return err
 			}
 
-			printer.Emit("sync completed")
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return fmt.Errorf("creating %q: %w", path, err)
+			}
+			sink := dirsync.NewLocalSink(path)
+
+			if cctx.Bool(dryRunFlag.Name) {
+				ll.InfoContext(ctx, "planning pull", slog.String("path", path))
+				plan, err := dirsync.Plan(ctx, ".", src, sink, syncParams)
+				if err != nil {
+					return fmt.Errorf("failed to plan pull: %w", forceHint(err))
+				}
+				printer.Emit(plan)
+				return nil
+			}
+
+			ll.InfoContext(ctx, "preparing to pull", slog.String("path", path))
+
+			observer, stopProgress := makeProgress(printer)
+			syncParams.Observer = observer
+			stats, err := dirsync.Sync(ctx, ".", src, sink, syncParams)
+			stopProgress()
+			printer.Emit(makeStatsReport(stats))
+			if err != nil {
+				return fmt.Errorf("failed to pull: %w", forceHint(err))
+			}
 
 			return nil
 		},
 	}
 }
 
+// Status command: status <absolute folder path>
+
+func statusCommand() cli.Command {
+	return cli.Command{
+		Name:  "status",
+		Usage: "list the files of a path that changed since it was last synced, without contacting the backend",
+		Flags: []cli.Flag{symlinksFlag, excludeFlag, includeFlag, specialFilesFlag, unreadableFlag, escapingSymlinksFlag, indexFileFlag},
+		Action: func(cctx *cli.Context) error {
+			path := cctx.Args().First()
+			if !filepath.IsAbs(path) {
+				return fmt.Errorf("<path> is not absolute")
+			}
+			ctx, _, printer, err := makeDeps(cctx)
+			if err != nil {
+				return fmt.Errorf("preparing dependencies: %w", err)
+			}
+			syncParams, err := makeSyncParams(cctx)
+			if err != nil {
+				return err
+			}
+			indexFile, err := makeIndexFile(cctx, path)
+			if err != nil {
+				return err
+			}
+			index, err := dirsync.LoadIndex(indexFile)
+			if err != nil {
+				return fmt.Errorf("loading index: %w", err)
+			}
+			if index.Len() == 0 {
+				return fmt.Errorf("no index of a previous sync of %q in %q", path, indexFile)
+			}
+
+			changes, err := index.Changes(ctx, ".", dirsync.NewLocalSource(path), syncParams)
+			if err != nil {
+				return fmt.Errorf("listing changes: %w", err)
+			}
+			for _, change := range changes {
+				printer.Emit(changeReport{Path: change.Path, Change: change.Kind.String()})
+			}
+			return nil
+		},
+	}
+}
+
+type changeReport struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+}
+
+// statsReport is what a sync did, in a shape meant to be graphed.
+type statsReport struct {
+	FilesCreated    int     `json:"files_created"`
+	DirsCreated     int     `json:"dirs_created"`
+	FilesPatched    int     `json:"files_patched"`
+	DirsPatched     int     `json:"dirs_patched"`
+	FilesDeleted    int     `json:"files_deleted"`
+	DirsDeleted     int     `json:"dirs_deleted"`
+	Moved           int     `json:"moved"`
+	BytesRead       uint64  `json:"bytes_read"`
+	LiteralBytes    uint64  `json:"literal_bytes"`
+	ReusedBytes     uint64  `json:"reused_bytes"`
+	TraceSeconds    float64 `json:"trace_seconds"`
+	TransferSeconds float64 `json:"transfer_seconds"`
+
+	Unstable       []string        `json:"unstable,omitempty"`
+	Skipped        []skippedReport `json:"skipped,omitempty"`
+	NameCollisions [][]string      `json:"name_collisions,omitempty"`
+	Errors         []opErrorReport `json:"errors,omitempty"`
+}
+
+type skippedReport struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+	Error  string `json:"error"`
+}
+
+type opErrorReport struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+func makeStatsReport(stats *dirsync.SyncStats) statsReport {
+	report := statsReport{
+		FilesCreated:    stats.FilesCreated,
+		DirsCreated:     stats.DirsCreated,
+		FilesPatched:    stats.FilesPatched,
+		DirsPatched:     stats.DirsPatched,
+		FilesDeleted:    stats.FilesDeleted,
+		DirsDeleted:     stats.DirsDeleted,
+		Moved:           stats.Moved,
+		BytesRead:       stats.BytesRead,
+		LiteralBytes:    stats.LiteralBytes,
+		ReusedBytes:     stats.ReusedBytes,
+		TraceSeconds:    stats.TraceTime.Seconds(),
+		TransferSeconds: stats.TransferTime.Seconds(),
+	}
+	for _, path := range stats.Unstable {
+		report.Unstable = append(report.Unstable, typesv1.StringFromPath(path))
+	}
+	for _, skipped := range stats.Skipped {
+		report.Skipped = append(report.Skipped, skippedReport{
+			Path:   typesv1.StringFromPath(skipped.Path),
+			Reason: skipped.Reason.String(),
+			Error:  skipped.Err.Error(),
+		})
+	}
+	for _, collision := range stats.NameCollisions {
+		var paths []string
+		for _, name := range collision.Names {
+			paths = append(paths, typesv1.StringFromPath(typesv1.PathJoin(collision.Dir, name)))
+		}
+		report.NameCollisions = append(report.NameCollisions, paths)
+	}
+	for _, opErr := range stats.Errors {
+		report.Errors = append(report.Errors, opErrorReport{
+			Op:    opErr.Op,
+			Path:  typesv1.StringFromPath(opErr.Path),
+			Error: opErr.Err.Error(),
+		})
+	}
+	return report
+}
+
+func readPlan(filename string) (*typesv1.SyncPlan, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading plan file %q: %w", filename, err)
+	}
+	plan := new(typesv1.SyncPlan)
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = protojson.Unmarshal(data, plan)
+	} else {
+		err = proto.Unmarshal(data, plan)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding plan file %q: %w", filename, err)
+	}
+	return plan, nil
+}
+
+// planSink is `sink` under the dir of the project that `plan` was made for.
+// The plan of another dir than `remotePath`, if it's set, isn't executed.
+func planSink(sink *syncclient.Sink, plan *typesv1.SyncPlan, remotePath string) (*syncclient.Sink, error) {
+	root := plan.GetRoot()
+	if remotePath == "" {
+		if len(root.GetElements()) == 0 {
+			return sink, nil
+		}
+		return sink.Under(root), nil
+	}
+	if !slices.Equal(root.GetElements(), sink.Root().GetElements()) {
+		planned := "the root of the project"
+		if len(root.GetElements()) > 0 {
+			planned = fmt.Sprintf("the remote path %q", typesv1.StringFromPath(root))
+		}
+		return nil, fmt.Errorf("plan was made for %s, not for the remote path %q", planned, remotePath)
+	}
+	return sink, nil
+}
+
+type conflictReport struct {
+	Path       string `json:"path"`
+	Resolution string `json:"resolution"`
+	CopyName   string `json:"copy_name,omitempty"`
+}
+
+// defaultStateFile is where the state of bidirectional syncs of `path` is
+// kept.
+func defaultStateFile(cctx *cli.Context, path string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("finding user config dir: %w", err)
+	}
+	return projectFile(configDir, "state", flagsMeta(cctx), path), nil
+}
+
+// makeIndexFile is where the index of the files of `path` is kept.
+func makeIndexFile(cctx *cli.Context, path string) (string, error) {
+	if indexFile := cctx.String(indexFileFlag.Name); indexFile != "" {
+		return indexFile, nil
+	}
+	return defaultIndexFile(flagsMeta(cctx), path)
+}
+
+// defaultIndexFile is where the index of the files of `path` is kept when
+// it's synced with the project of `meta`.
+func defaultIndexFile(meta *typesv1.ReqMeta, path string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("finding user cache dir: %w", err)
+	}
+	return projectFile(cacheDir, "index", meta, path), nil
+}
+
+// makeJournalFile is where the journal of the syncs of `path` is kept.
+func makeJournalFile(cctx *cli.Context, path string) (string, error) {
+	if journalFile := cctx.String(journalFileFlag.Name); journalFile != "" {
+		return journalFile, nil
+	}
+	return defaultJournalFile(flagsMeta(cctx), path, cctx.String(remotePathFlag.Name))
+}
+
+// defaultJournalFile is where the journal of the syncs of `path` into the
+// dir `remotePath` of the project of `meta` is kept.
+func defaultJournalFile(meta *typesv1.ReqMeta, path, remotePath string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("finding user cache dir: %w", err)
+	}
+	if remotePath != "" {
+		path = path + "\x00" + remotePath
+	}
+	return projectFile(cacheDir, "journal", meta, path), nil
+}
+
+// projectFile is a file under `dir` about syncing `path`, one per project and
+// path.
+func projectFile(dir, kind string, meta *typesv1.ReqMeta, path string) string {
+	pathSum := sha256.Sum256([]byte(path))
+	filename := fmt.Sprintf("%s-%s-%x.pb",
+		meta.AccountId,
+		meta.ProjectId,
+		pathSum[:8],
+	)
+	return filepath.Join(dir, name, kind, filename)
+}
+
+// readSyncState reads the signatures as of the last sync, nil if there was
+// none.
+func readSyncState(filename string) (*typesv1.DirSum, error) {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading state file %q: %w", filename, err)
+	}
+	state := new(typesv1.DirSum)
+	if err := proto.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("decoding state file %q: %w", filename, err)
+	}
+	return state, nil
+}
+
+func writeSyncState(filename string, state *typesv1.DirSum) error {
+	data, err := proto.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encoding state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return fmt.Errorf("creating state dir: %w", err)
+	}
+	// swapped in, so that an interrupted write doesn't lose the last state
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("writing state file %q: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		return fmt.Errorf("swapping in state file %q: %w", filename, err)
+	}
+	return nil
+}
+
 // File stats command: stats file <absolute file path>
 // Folder stats command: stats folder <absolute folder path>
 
@@ -269,6 +935,85 @@ func statsCommands(serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathF
 	}
 }
 
+func makeSink(
+	cctx *cli.Context,
+	ll *slog.Logger,
+	serverSchemeFlag cli.StringFlag,
+	serverAddrFlag cli.StringFlag,
+	serverPortFlag cli.StringFlag,
+	serverPathFlag cli.StringFlag,
+) (*syncclient.Sink, error) {
+	httpClient, err := makeHttpClient(cctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating http client: %w", err)
+	}
+	client, meta, err := makeClient(cctx, httpClient, serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag)
+	if err != nil {
+		return nil, fmt.Errorf("creating sync service client: %w", err)
+	}
+	return makeProjectSink(cctx, ll, client, meta, cctx.String(remotePathFlag.Name))
+}
+
+// makeProjectSink is a sink for the project of `meta`, or for its dir at
+// `remotePath` if there's one.
+func makeProjectSink(
+	cctx *cli.Context,
+	ll *slog.Logger,
+	client syncv1connect.SyncServiceClient,
+	meta *typesv1.ReqMeta,
+	remotePath string,
+) (*syncclient.Sink, error) {
+	blockSize := cctx.Uint(blockSizeFlag.Name)
+	if blockSize < 128 {
+		return nil, fmt.Errorf("minimum block size is 128")
+	}
+	if blockSize >= math.MaxUint32 {
+		return nil, fmt.Errorf("block size must fit in a uint32")
+	}
+
+	sink, err := syncclient.ClientAdapter(ll, client, meta, blockSize)
+	if err != nil {
+		return nil, fmt.Errorf("configuring sync service client: %w", err)
+	}
+	if remotePath != "" {
+		root, err := parseRemotePath(remotePath)
+		if err != nil {
+			return nil, err
+		}
+		sink = sink.Under(root)
+	}
+	return sink, nil
+}
+
+// parseRemotePath is the path of a dir of a project.
+func parseRemotePath(remotePath string) (*typesv1.Path, error) {
+	root := typesv1.PathFromString(remotePath)
+	if slices.Contains(root.Elements, ".") || slices.Contains(root.Elements, "..") {
+		return nil, fmt.Errorf("remote path %q must be a path in the project, without . or ..", remotePath)
+	}
+	return root, nil
+}
+
+func makeSource(
+	ctx context.Context,
+	cctx *cli.Context,
+	ll *slog.Logger,
+	serverSchemeFlag cli.StringFlag,
+	serverAddrFlag cli.StringFlag,
+	serverPortFlag cli.StringFlag,
+	serverPathFlag cli.StringFlag,
+) (*syncclient.Source, error) {
+	httpClient, err := makeHttpClient(cctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating http client: %w", err)
+	}
+	client, meta, err := makeClient(cctx, httpClient, serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag)
+	if err != nil {
+		return nil, fmt.Errorf("creating sync service client: %w", err)
+	}
+	return syncclient.SourceAdapter(ctx, ll, client, meta), nil
+}
+
 func makeDeps(cctx *cli.Context) (context.Context, *slog.Logger, printer, error) {
 	ctx, err := makeContext(cctx)
 	if err != nil {
@@ -285,8 +1030,12 @@ func makeDeps(cctx *cli.Context) (context.Context, *slog.Logger, printer, error)
 	return ctx, logger, printer, nil
 }
 
+// makeContext is canceled on SIGINT or SIGTERM, for commands to stop
+// cleanly. A second signal kills the process.
 func makeContext(_ *cli.Context) (context.Context, error) {
-	return context.Background(), nil
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(ctx, stop)
+	return ctx, nil
 }
 
 func makeLogger(_ *cli.Context) (*slog.Logger, error) {
@@ -307,6 +1056,123 @@ func makePrinter(cctx *cli.Context, printerFlag cli.StringFlag) (printer, error)
 	}
 }
 
+func makeSyncParams(cctx *cli.Context) (dirsync.Params, error) {
+	symlinks, err := makeSymlinkPolicy(cctx, symlinksFlag)
+	if err != nil {
+		return dirsync.Params{}, err
+	}
+	compare, err := makeCompare(cctx)
+	if err != nil {
+		return dirsync.Params{}, err
+	}
+	specialFiles, err := makeErrorPolicy(cctx, specialFilesFlag)
+	if err != nil {
+		return dirsync.Params{}, err
+	}
+	unreadable, err := makeErrorPolicy(cctx, unreadableFlag)
+	if err != nil {
+		return dirsync.Params{}, err
+	}
+	escapingSymlinks, err := makeErrorPolicy(cctx, escapingSymlinksFlag)
+	if err != nil {
+		return dirsync.Params{}, err
+	}
+	maxDeletes, maxDeletePercent := cctx.Int(maxDeleteFlag.Name), cctx.Float64(maxDeletePercentFlag.Name)
+	if cctx.Bool(forceFlag.Name) {
+		maxDeletes, maxDeletePercent = 0, 0
+	}
+	var attrs dirsync.Attrs
+	if cctx.Bool(ownersFlag.Name) {
+		attrs |= dirsync.AttrOwner
+	}
+	if cctx.Bool(xattrsFlag.Name) {
+		attrs |= dirsync.AttrXattrs
+	}
+	return dirsync.Params{
+		MaxParallelFileStreams: int(cctx.Uint(maxParallelFileStreamFlag.Name)),
+		Symlinks:               symlinks,
+		Exclude:                cctx.StringSlice(excludeFlag.Name),
+		Include:                cctx.StringSlice(includeFlag.Name),
+		DeleteExcluded:         cctx.Bool(deleteExcludedFlag.Name),
+		NoDelete:               cctx.Bool(noDeleteFlag.Name),
+		MaxDeletes:             maxDeletes,
+		MaxDeletePercent:       maxDeletePercent,
+		Attrs:                  attrs,
+		UnstableRetries:        cctx.Int(unstableRetriesFlag.Name),
+		Compare:                compare,
+		SpecialFiles:           specialFiles,
+		Unreadable:             unreadable,
+		EscapingSymlinks:       escapingSymlinks,
+	}, nil
+}
+
+// forceHint tells how to go through with a sync that the deletion guard
+// stopped.
+func forceHint(err error) error {
+	if errors.Is(err, dirsync.ErrTooManyDeletes) {
+		return fmt.Errorf("%w, use --%s to delete them anyway", err, forceFlag.Name)
+	}
+	return err
+}
+
+// interruptHint tells that a sync that was interrupted can be resumed.
+func interruptHint(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("%w, run it again to resume it", err)
+	}
+	return err
+}
+
+// makeCompare is the comparison mode picked with at most one of its flags.
+func makeCompare(cctx *cli.Context) (dirsync.Compare, error) {
+	compare := dirsync.CompareDefault
+	for _, mode := range []struct {
+		flag    cli.BoolFlag
+		compare dirsync.Compare
+	}{
+		{checksumFlag, dirsync.CompareChecksum},
+		{sizeOnlyFlag, dirsync.CompareSizeOnly},
+		{trustMtimeFlag, dirsync.CompareTrustMtime},
+	} {
+		if !cctx.Bool(mode.flag.Name) {
+			continue
+		}
+		if compare != dirsync.CompareDefault {
+			return 0, fmt.Errorf("--%s and --%s can't be used together", compare, mode.flag.Name)
+		}
+		compare = mode.compare
+	}
+	return compare, nil
+}
+
+func makeErrorPolicy(cctx *cli.Context, policyFlag cli.StringFlag) (dirsync.ErrorPolicy, error) {
+	policy := cctx.String(policyFlag.Name)
+	switch policy {
+	case "skip":
+		return dirsync.ErrorSkip, nil
+	case "fail":
+		return dirsync.ErrorFail, nil
+	case "ignore":
+		return dirsync.ErrorIgnore, nil
+	default:
+		return 0, fmt.Errorf("unsupported --%s policy: %q", policyFlag.Name, policy)
+	}
+}
+
+func makeSymlinkPolicy(cctx *cli.Context, symlinksFlag cli.StringFlag) (dirsync.SymlinkPolicy, error) {
+	policy := cctx.String(symlinksFlag.Name)
+	switch policy {
+	case "preserve":
+		return dirsync.SymlinkPreserve, nil
+	case "follow":
+		return dirsync.SymlinkFollow, nil
+	case "skip":
+		return dirsync.SymlinkSkip, nil
+	default:
+		return 0, fmt.Errorf("unsupported symlink policy: %q", policy)
+	}
+}
+
 func makeHttpClient(
 	_ *cli.Context,
 ) (connect.HTTPClient, error) {
@@ -331,12 +1197,16 @@ func makeClient(
 		),
 		Path: cctx.String(serverPathFlag.Name),
 	}
-	req := &typesv1.ReqMeta{
+	return syncv1connect.NewSyncServiceClient(httpClient, baseURL.String()), flagsMeta(cctx), nil
+}
+
+// flagsMeta is the meta of requests about the account and project given by
+// flags or env vars.
+func flagsMeta(cctx *cli.Context) *typesv1.ReqMeta {
+	return &typesv1.ReqMeta{
 		AccountId: stringFlagOrEnvVar(cctx, accountIDFlag),
 		ProjectId: stringFlagOrEnvVar(cctx, projectIDFlag),
 	}
-
-	return syncv1connect.NewSyncServiceClient(httpClient, baseURL.String()), req, nil
 }
 
 func stringFlagOrEnvVar(cctx *cli.Context, flag cli.StringFlag) string {
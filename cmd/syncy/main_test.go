@@ -0,0 +1,53 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/aybabtme/syncy/pkg/logic/syncclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSink(t *testing.T) {
+	tests := []struct {
+		name string
+		// the root the plan was made for, and the one of the flags
+		planRoot   string
+		remotePath string
+		want       string
+		wantErr    string
+	}{
+		{name: "project", want: ""},
+		{name: "plan root", planRoot: "a/b", want: "a/b"},
+		{name: "same root", planRoot: "a/b", remotePath: "a/b", want: "a/b"},
+		{name: "other root", planRoot: "a/b", remotePath: "a/c", wantErr: `plan was made for the remote path "a/b", not for the remote path "a/c"`},
+		{name: "planned for project", remotePath: "a/c", wantErr: `plan was made for the root of the project, not for the remote path "a/c"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ll := slog.New(slog.NewTextHandler(io.Discard, nil))
+			sink, err := syncclient.ClientAdapter(ll, nil, &typesv1.ReqMeta{}, 10<<10)
+			require.NoError(t, err)
+			if tt.remotePath != "" {
+				root, err := parseRemotePath(tt.remotePath)
+				require.NoError(t, err)
+				sink = sink.Under(root)
+			}
+			plan := &typesv1.SyncPlan{}
+			if tt.planRoot != "" {
+				plan.Root = typesv1.PathFromString(tt.planRoot)
+			}
+
+			got, err := planSink(sink, plan, tt.remotePath)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, strings.Join(got.Root().GetElements(), "/"))
+		})
+	}
+}
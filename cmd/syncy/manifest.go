@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/aybabtme/syncy/pkg/gen/svc/sync/v1/syncv1connect"
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/aybabtme/syncy/pkg/logic/dirsync"
+	"github.com/urfave/cli"
+	"gopkg.in/yaml.v3"
+)
+
+// syncManifest lists local paths to sync, each with the project and the dir
+// of the project to sync it with, like:
+//
+//	mappings:
+//	  - name: docs
+//	    local: ./site/docs
+//	    project: prj_docs
+//	    remote_path: public/docs
+//	    exclude: ["*.tmp"]
+//	    no_delete: true
+//
+// Options that are missing from a mapping are the ones of the flags.
+type syncManifest struct {
+	Mappings []syncMapping `yaml:"mappings"`
+}
+
+type syncMapping struct {
+	// Name is what the mapping is reported as, its local path if empty.
+	Name string `yaml:"name"`
+	// Local is the path to sync, relative to the manifest if it's not
+	// absolute.
+	Local      string `yaml:"local"`
+	Account    string `yaml:"account"`
+	Project    string `yaml:"project"`
+	RemotePath string `yaml:"remote_path"`
+
+	// added to the ones of the flags
+	Exclude []string `yaml:"exclude"`
+	Include []string `yaml:"include"`
+
+	DeleteExcluded   *bool    `yaml:"delete_excluded"`
+	NoDelete         *bool    `yaml:"no_delete"`
+	MaxDelete        *int     `yaml:"max_delete"`
+	MaxDeletePercent *float64 `yaml:"max_delete_percent"`
+	Force            bool     `yaml:"force"`
+}
+
+// readManifest reads and checks the manifest in `filename`.
+func readManifest(filename string) (*syncManifest, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	manifest := new(syncManifest)
+	if err := dec.Decode(manifest); err != nil {
+		return nil, fmt.Errorf("decoding manifest: %w", err)
+	}
+	if len(manifest.Mappings) == 0 {
+		return nil, fmt.Errorf("manifest has no mappings")
+	}
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return nil, fmt.Errorf("finding dir of manifest: %w", err)
+	}
+	for i := range manifest.Mappings {
+		m := &manifest.Mappings[i]
+		if m.Local == "" {
+			return nil, fmt.Errorf("mapping %d has no local path", i)
+		}
+		if !filepath.IsAbs(m.Local) {
+			m.Local = filepath.Join(dir, m.Local)
+		}
+		if m.Name == "" {
+			m.Name = m.Local
+		}
+		if _, err := parseRemotePath(m.RemotePath); err != nil {
+			return nil, fmt.Errorf("mapping %q: %w", m.Name, err)
+		}
+	}
+	return manifest, nil
+}
+
+// meta is the meta of the requests of the mapping, whose account and
+// project default to the ones of `defaults`.
+func (m *syncMapping) meta(defaults *typesv1.ReqMeta) *typesv1.ReqMeta {
+	meta := &typesv1.ReqMeta{AccountId: m.Account, ProjectId: m.Project}
+	if meta.AccountId == "" {
+		meta.AccountId = defaults.AccountId
+	}
+	if meta.ProjectId == "" {
+		meta.ProjectId = defaults.ProjectId
+	}
+	return meta
+}
+
+// params are `params` with the options of the mapping.
+func (m *syncMapping) params(params dirsync.Params) dirsync.Params {
+	params.Exclude = slices.Concat(params.Exclude, m.Exclude)
+	params.Include = slices.Concat(params.Include, m.Include)
+	if m.DeleteExcluded != nil {
+		params.DeleteExcluded = *m.DeleteExcluded
+	}
+	if m.NoDelete != nil {
+		params.NoDelete = *m.NoDelete
+	}
+	if m.MaxDelete != nil {
+		params.MaxDeletes = *m.MaxDelete
+	}
+	if m.MaxDeletePercent != nil {
+		params.MaxDeletePercent = *m.MaxDeletePercent
+	}
+	if m.Force {
+		params.MaxDeletes, params.MaxDeletePercent = 0, 0
+	}
+	return params
+}
+
+// overlaps tells if two mappings can't be synced at once: they're synced
+// into the same dir of a project, or one into a dir of the other, or they
+// share an index.
+func overlaps(a, b *typesv1.ReqMeta, aMapping, bMapping *syncMapping) bool {
+	if a.AccountId != b.AccountId || a.ProjectId != b.ProjectId {
+		return false
+	}
+	if aMapping.Local == bMapping.Local {
+		return true
+	}
+	aRoot, bRoot := typesv1.PathFromString(aMapping.RemotePath).Elements, typesv1.PathFromString(bMapping.RemotePath).Elements
+	n := min(len(aRoot), len(bRoot))
+	return slices.Equal(aRoot[:n], bRoot[:n])
+}
+
+type manifestReport struct {
+	Mappings []mappingReport `json:"mappings"`
+	Failed   int             `json:"failed"`
+}
+
+type mappingReport struct {
+	Name       string       `json:"name"`
+	Local      string       `json:"local"`
+	Project    string       `json:"project"`
+	RemotePath string       `json:"remote_path,omitempty"`
+	Stats      *statsReport `json:"stats,omitempty"`
+	Error      string       `json:"error,omitempty"`
+}
+
+// syncAll syncs the mappings of `manifest`, at most `parallel` at once. The
+// mappings that overlap one that comes before them wait for it to be synced.
+// A mapping that fails doesn't stop the others.
+func syncAll(
+	ctx context.Context,
+	cctx *cli.Context,
+	ll *slog.Logger,
+	client syncv1connect.SyncServiceClient,
+	defaults *typesv1.ReqMeta,
+	manifest *syncManifest,
+	params dirsync.Params,
+	parallel int,
+) manifestReport {
+	n := len(manifest.Mappings)
+	metas := make([]*typesv1.ReqMeta, n)
+	for i := range manifest.Mappings {
+		metas[i] = manifest.Mappings[i].meta(defaults)
+	}
+	report := manifestReport{Mappings: make([]mappingReport, n)}
+	eachMapping(manifest, metas, parallel, func(i int) {
+		m := &manifest.Mappings[i]
+		ll := ll.With(slog.String("mapping", m.Name))
+		ll.InfoContext(ctx, "syncing mapping",
+			slog.String("path", m.Local),
+			slog.String("project", metas[i].ProjectId),
+			slog.String("remote_path", m.RemotePath),
+		)
+		stats, err := syncOne(ctx, cctx, ll, client, metas[i], m, m.params(params))
+		mr := mappingReport{
+			Name:       m.Name,
+			Local:      m.Local,
+			Project:    metas[i].ProjectId,
+			RemotePath: m.RemotePath,
+		}
+		if stats != nil {
+			sr := makeStatsReport(stats)
+			mr.Stats = &sr
+		}
+		if err != nil {
+			ll.ErrorContext(ctx, "failed to sync mapping", slog.Any("err", err))
+			mr.Error = err.Error()
+		}
+		report.Mappings[i] = mr
+	})
+	for _, mr := range report.Mappings {
+		if mr.Error != "" {
+			report.Failed++
+		}
+	}
+	return report
+}
+
+// eachMapping calls `fn` with the index of each mapping of `manifest`, at
+// most `parallel` at once. The mappings that overlap one that comes before
+// them wait for it to be done.
+func eachMapping(manifest *syncManifest, metas []*typesv1.ReqMeta, parallel int, fn func(i int)) {
+	done := make([]chan struct{}, len(manifest.Mappings))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	sem := make(chan struct{}, max(parallel, 1))
+	var wg sync.WaitGroup
+	for i := range manifest.Mappings {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[i])
+			for j := 0; j < i; j++ {
+				if overlaps(metas[i], metas[j], &manifest.Mappings[i], &manifest.Mappings[j]) {
+					<-done[j]
+				}
+			}
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// syncOne syncs a mapping of a manifest, with its own index and journal.
+func syncOne(
+	ctx context.Context,
+	cctx *cli.Context,
+	ll *slog.Logger,
+	client syncv1connect.SyncServiceClient,
+	meta *typesv1.ReqMeta,
+	m *syncMapping,
+	params dirsync.Params,
+) (*dirsync.SyncStats, error) {
+	sink, err := makeProjectSink(cctx, ll, client, meta, m.RemotePath)
+	if err != nil {
+		return nil, err
+	}
+	var indexFile string
+	if !cctx.Bool(noIndexFlag.Name) {
+		indexFile, err = defaultIndexFile(meta, m.Local)
+		if err != nil {
+			return nil, err
+		}
+		params.Index, err = dirsync.LoadIndex(indexFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading index: %w", err)
+		}
+	}
+	if !params.Streaming {
+		journalFile, err := defaultJournalFile(meta, m.Local, m.RemotePath)
+		if err != nil {
+			return nil, err
+		}
+		params.Journal, err = dirsync.OpenJournal(journalFile)
+		if err != nil {
+			return nil, fmt.Errorf("opening journal: %w", err)
+		}
+		defer params.Journal.Close()
+	}
+	stats, err := syncOrResume(ctx, ll, dirsync.NewLocalSource(m.Local), sink, params)
+	if errors.Is(err, dirsync.ErrTooManyDeletes) {
+		return stats, fmt.Errorf("%w, set `force: true` on the mapping to delete them anyway", err)
+	}
+	if err != nil {
+		return stats, interruptHint(ctx, err)
+	}
+	if params.Index != nil {
+		if err := params.Index.Save(indexFile); err != nil {
+			return stats, fmt.Errorf("saving index: %w", err)
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/aybabtme/syncy/pkg/logic/dirsync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		// locals are relative to the dir of the manifest
+		want    []syncMapping
+		wantErr string
+	}{
+		{
+			name: "relative",
+			manifest: `
+mappings:
+  - name: docs
+    local: site/docs
+    project: prj_docs
+    remote_path: public/docs
+  - local: ../build
+`,
+			want: []syncMapping{
+				{Name: "docs", Local: "site/docs", Project: "prj_docs", RemotePath: "public/docs"},
+				{Name: "../build", Local: "../build"},
+			},
+		},
+		{
+			name: "absolute",
+			manifest: `
+mappings:
+  - local: /srv/data
+    exclude: ["*.tmp"]
+    no_delete: true
+    max_delete: 10
+    force: true
+`,
+			want: []syncMapping{
+				{Name: "/srv/data", Local: "/srv/data", Exclude: []string{"*.tmp"}, NoDelete: ptr(true), MaxDelete: ptr(10), Force: true},
+			},
+		},
+		{name: "no mappings", manifest: "mappings: []\n", wantErr: "manifest has no mappings"},
+		{name: "no local", manifest: "mappings:\n  - project: prj\n", wantErr: "mapping 0 has no local path"},
+		{name: "unknown option", manifest: "mappings:\n  - local: a\n    no_delet: true\n", wantErr: "field no_delet not found"},
+		{name: "remote path out of project", manifest: "mappings:\n  - name: up\n    local: a\n    remote_path: ../a\n", wantErr: `mapping "up": remote path "../a" must be a path in the project`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filename := filepath.Join(dir, "syncy.yaml")
+			require.NoError(t, os.WriteFile(filename, []byte(tt.manifest), 0644))
+
+			got, err := readManifest(filename)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			for i := range tt.want {
+				want := &tt.want[i]
+				if !filepath.IsAbs(want.Local) {
+					if want.Name == want.Local {
+						want.Name = filepath.Join(dir, want.Local)
+					}
+					want.Local = filepath.Join(dir, want.Local)
+				}
+			}
+			require.Equal(t, tt.want, got.Mappings)
+		})
+	}
+}
+
+func TestMappingParams(t *testing.T) {
+	flags := dirsync.Params{
+		Exclude:          []string{"*.log"},
+		Include:          []string{"keep.log"},
+		MaxDeletes:       100,
+		MaxDeletePercent: 50,
+	}
+	tests := []struct {
+		name    string
+		mapping syncMapping
+		want    dirsync.Params
+	}{
+		{name: "flags", want: flags},
+		{
+			name:    "patterns are added",
+			mapping: syncMapping{Exclude: []string{"*.tmp"}, Include: []string{"keep.tmp"}},
+			want: dirsync.Params{
+				Exclude:          []string{"*.log", "*.tmp"},
+				Include:          []string{"keep.log", "keep.tmp"},
+				MaxDeletes:       100,
+				MaxDeletePercent: 50,
+			},
+		},
+		{
+			name:    "options override",
+			mapping: syncMapping{DeleteExcluded: ptr(true), NoDelete: ptr(true), MaxDelete: ptr(10), MaxDeletePercent: ptr(5.0)},
+			want: dirsync.Params{
+				Exclude:          []string{"*.log"},
+				Include:          []string{"keep.log"},
+				DeleteExcluded:   true,
+				NoDelete:         true,
+				MaxDeletes:       10,
+				MaxDeletePercent: 5,
+			},
+		},
+		{
+			name:    "force",
+			mapping: syncMapping{Force: true},
+			want:    dirsync.Params{Exclude: []string{"*.log"}, Include: []string{"keep.log"}},
+		},
+		{
+			name:    "force wins over max_delete",
+			mapping: syncMapping{MaxDelete: ptr(10), MaxDeletePercent: ptr(5.0), Force: true},
+			want:    dirsync.Params{Exclude: []string{"*.log"}, Include: []string{"keep.log"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.mapping.params(flags)
+			require.Equal(t, tt.want, got)
+		})
+	}
+	require.Equal(t, []string{"*.log"}, flags.Exclude, "params of the flags are left as they are")
+}
+
+func TestOverlaps(t *testing.T) {
+	tests := []struct {
+		name string
+		// project, local path and remote path of each mapping
+		a, b [3]string
+		want bool
+	}{
+		{name: "other project", a: [3]string{"p1", "/a", "x"}, b: [3]string{"p2", "/b", "x"}, want: false},
+		{name: "same dir", a: [3]string{"p", "/a", "x"}, b: [3]string{"p", "/b", "x"}, want: true},
+		{name: "sub dir", a: [3]string{"p", "/a", "x"}, b: [3]string{"p", "/b", "x/y"}, want: true},
+		{name: "project root", a: [3]string{"p", "/a", ""}, b: [3]string{"p", "/b", "x"}, want: true},
+		{name: "sibling dirs", a: [3]string{"p", "/a", "x/y"}, b: [3]string{"p", "/b", "x/z"}, want: false},
+		{name: "prefix of name", a: [3]string{"p", "/a", "x"}, b: [3]string{"p", "/b", "xx"}, want: false},
+		{name: "same local path", a: [3]string{"p", "/a", "x"}, b: [3]string{"p", "/a", "y"}, want: true},
+		{name: "same local path, other project", a: [3]string{"p1", "/a", "x"}, b: [3]string{"p2", "/a", "x"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aMeta, bMeta := &typesv1.ReqMeta{ProjectId: tt.a[0]}, &typesv1.ReqMeta{ProjectId: tt.b[0]}
+			aMapping, bMapping := &syncMapping{Local: tt.a[1], RemotePath: tt.a[2]}, &syncMapping{Local: tt.b[1], RemotePath: tt.b[2]}
+			require.Equal(t, tt.want, overlaps(aMeta, bMeta, aMapping, bMapping))
+			require.Equal(t, tt.want, overlaps(bMeta, aMeta, bMapping, aMapping))
+		})
+	}
+}
+
+func TestEachMapping(t *testing.T) {
+	// docs and assets are synced at once, site waits for docs
+	manifest := &syncManifest{Mappings: []syncMapping{
+		{Name: "docs", Local: "/docs", RemotePath: "site/docs"},
+		{Name: "assets", Local: "/assets", RemotePath: "assets"},
+		{Name: "site", Local: "/site", RemotePath: "site"},
+	}}
+	metas := make([]*typesv1.ReqMeta, len(manifest.Mappings))
+	for i := range metas {
+		metas[i] = &typesv1.ReqMeta{ProjectId: "p"}
+	}
+
+	var (
+		mu   sync.Mutex
+		done = make(map[string]bool)
+	)
+	assetsStarted := make(chan struct{})
+	eachMapping(manifest, metas, 2, func(i int) {
+		name := manifest.Mappings[i].Name
+		switch name {
+		case "docs":
+			select {
+			case <-assetsStarted:
+			case <-time.After(10 * time.Second):
+				t.Error("assets weren't synced along docs")
+			}
+		case "assets":
+			close(assetsStarted)
+		case "site":
+			mu.Lock()
+			if !done["docs"] {
+				t.Error("site was synced before docs")
+			}
+			mu.Unlock()
+		}
+		mu.Lock()
+		done[name] = true
+		mu.Unlock()
+	})
+	require.Len(t, done, 3)
+}
+
+func TestEachMappingParallel(t *testing.T) {
+	manifest := &syncManifest{}
+	var metas []*typesv1.ReqMeta
+	for _, name := range []string{"a", "b", "c", "d"} {
+		manifest.Mappings = append(manifest.Mappings, syncMapping{Local: "/" + name, RemotePath: name})
+		metas = append(metas, &typesv1.ReqMeta{ProjectId: "p"})
+	}
+
+	var (
+		mu                  sync.Mutex
+		running, maxRunning int
+	)
+	eachMapping(manifest, metas, 2, func(i int) {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+	})
+	require.LessOrEqual(t, maxRunning, 2)
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -50,7 +50,8 @@ func (pp *jsonPrinter) encode(v any) int {
 	if err != nil {
 		panic(err)
 	}
-	if n, err := pp.out.Write(out); err != nil {
+	// one value per line, for the output to be streamed
+	if n, err := pp.out.Write(append(out, '\n')); err != nil {
 		panic(err)
 	} else {
 		return n
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/aybabtme/syncy/pkg/logic/dirsync"
+	"github.com/dustin/go-humanize"
+	"github.com/mattn/go-isatty"
+)
+
+// makeProgress returns what shows the progress of a sync with `printer`: a
+// stream of events with the json printer, a live display on a terminal with
+// the text printer. `stop` is to be called once the sync is done.
+func makeProgress(printer printer) (obs dirsync.Observer, stop func()) {
+	switch pp := printer.(type) {
+	case *jsonPrinter:
+		return &jsonProgress{printer: pp}, func() {}
+	case *textPrinter:
+		if !isatty.IsTerminal(os.Stderr.Fd()) {
+			return nil, func() {}
+		}
+		tp := newTTYProgress(os.Stderr)
+		return tp, tp.stop
+	default:
+		return nil, func() {}
+	}
+}
+
+// eventReport is an event of a sync, as printed.
+type eventReport struct {
+	Event string `json:"event"`
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	IsDir bool   `json:"is_dir,omitempty"`
+	Size  uint64 `json:"size,omitempty"`
+	Bytes uint64 `json:"bytes,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// jsonProgress prints the events of a sync as they come.
+type jsonProgress struct {
+	mu      sync.Mutex
+	printer printer
+}
+
+func (jp *jsonProgress) Observe(ev dirsync.Event) {
+	report := eventReport{
+		Event: ev.Kind.String(),
+		Op:    ev.Op,
+		Path:  typesv1.StringFromPath(ev.Path),
+		IsDir: ev.IsDir,
+		Size:  ev.Size,
+		Bytes: ev.Bytes,
+	}
+	if ev.Err != nil {
+		report.Error = ev.Err.Error()
+	}
+	jp.mu.Lock()
+	defer jp.mu.Unlock()
+	jp.printer.Emit(report)
+}
+
+// ttyProgress keeps a line on a terminal up to date with the progress of a
+// sync.
+type ttyProgress struct {
+	out   io.Writer
+	start time.Time
+
+	mu           sync.Mutex
+	plannedFiles int
+	doneFiles    int
+	plannedBytes uint64
+	sentBytes    uint64
+
+	stopc chan struct{}
+	donec chan struct{}
+}
+
+const ttyRefreshInterval = 200 * time.Millisecond
+
+func newTTYProgress(out io.Writer) *ttyProgress {
+	tp := &ttyProgress{
+		out:   out,
+		start: time.Now(),
+		stopc: make(chan struct{}),
+		donec: make(chan struct{}),
+	}
+	go tp.loop()
+	return tp
+}
+
+func (tp *ttyProgress) Observe(ev dirsync.Event) {
+	if ev.IsDir {
+		return
+	}
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	switch ev.Kind {
+	case dirsync.EventPlanned:
+		tp.plannedFiles++
+		tp.plannedBytes += ev.Size
+	case dirsync.EventProgressed:
+		tp.sentBytes += ev.Bytes
+	case dirsync.EventDone, dirsync.EventFailed, dirsync.EventSkipped:
+		tp.doneFiles++
+	}
+}
+
+func (tp *ttyProgress) loop() {
+	defer close(tp.donec)
+	ticker := time.NewTicker(ttyRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-tp.stopc:
+			tp.render()
+			fmt.Fprintln(tp.out)
+			return
+		case <-ticker.C:
+			tp.render()
+		}
+	}
+}
+
+func (tp *ttyProgress) render() {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	elapsed := time.Since(tp.start)
+	throughput := float64(tp.sentBytes) / elapsed.Seconds()
+	eta := "?"
+	if throughput > 0 && tp.plannedBytes >= tp.sentBytes {
+		left := time.Duration(float64(tp.plannedBytes-tp.sentBytes) / throughput * float64(time.Second))
+		eta = left.Round(time.Second).String()
+	}
+	// go back to the start of the line and clear it
+	fmt.Fprintf(tp.out, "\r\x1b[K%d/%d files, %s/%s, %s/s, ETA %s",
+		tp.doneFiles, tp.plannedFiles,
+		humanize.IBytes(tp.sentBytes), humanize.IBytes(tp.plannedBytes),
+		humanize.IBytes(uint64(throughput)), eta,
+	)
+}
+
+func (tp *ttyProgress) stop() {
+	close(tp.stopc)
+	<-tp.donec
+}
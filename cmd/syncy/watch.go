@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/aybabtme/syncy/pkg/logic/dirsync"
+	"github.com/urfave/cli"
+)
+
+var (
+	debounceFlag = cli.DurationFlag{
+		Name:  "debounce",
+		Value: time.Second,
+		Usage: "how long changes have to settle before they're synced",
+	}
+	scanIntervalFlag = cli.DurationFlag{
+		Name:  "scan-interval",
+		Value: time.Minute,
+		Usage: "how often the whole path is synced when it can't be watched",
+	}
+	maxBackoffFlag = cli.DurationFlag{
+		Name:  "max-backoff",
+		Value: 5 * time.Minute,
+		Usage: "the longest to wait before a sync that failed is tried again",
+	}
+)
+
+// Watch command: watch <absolute folder path>
+
+func watchCommand(serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag cli.StringFlag, maxParallelFileStreamFlag cli.UintFlag) cli.Command {
+	return cli.Command{
+		Name:  "watch",
+		Usage: "keep syncing a path against a backend as it changes, until interrupted",
+		Flags: []cli.Flag{serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag, maxParallelFileStreamFlag, blockSizeFlag, symlinksFlag, excludeFlag, includeFlag, deleteExcludedFlag, noDeleteFlag, maxDeleteFlag, maxDeletePercentFlag, forceFlag, ownersFlag, xattrsFlag, checksumFlag, sizeOnlyFlag, trustMtimeFlag, specialFilesFlag, unreadableFlag, escapingSymlinksFlag, unstableRetriesFlag, remotePathFlag, indexFileFlag, noIndexFlag, debounceFlag, scanIntervalFlag, maxBackoffFlag},
+		Action: func(cctx *cli.Context) error {
+			path := cctx.Args().First()
+			if !filepath.IsAbs(path) {
+				return fmt.Errorf("<path> is not absolute")
+			}
+			ctx, ll, printer, err := makeDeps(cctx)
+			if err != nil {
+				return fmt.Errorf("preparing dependencies: %w", err)
+			}
+			sink, err := makeSink(cctx, ll, serverSchemeFlag, serverAddrFlag, serverPortFlag, serverPathFlag)
+			if err != nil {
+				return err
+			}
+			syncParams, err := makeSyncParams(cctx)
+			if err != nil {
+				return err
+			}
+			var indexFile string
+			if !cctx.Bool(noIndexFlag.Name) {
+				indexFile, err = makeIndexFile(cctx, path)
+				if err != nil {
+					return err
+				}
+				syncParams.Index, err = dirsync.LoadIndex(indexFile)
+				if err != nil {
+					return fmt.Errorf("loading index: %w", err)
+				}
+			}
+
+			watchParams := dirsync.WatchParams{
+				Debounce:     cctx.Duration(debounceFlag.Name),
+				ScanInterval: cctx.Duration(scanIntervalFlag.Name),
+				MaxBackoff:   cctx.Duration(maxBackoffFlag.Name),
+				OnPass: func(pass dirsync.WatchPass) {
+					printer.Emit(makeWatchPassReport(pass))
+					if pass.Err != nil && !pass.Full && errors.Is(pass.Err, dirsync.ErrTooManyDeletes) {
+						ll.WarnContext(ctx, "changes would delete too much of the dirs they're in, syncing the whole path instead", slog.Any("err", pass.Err))
+						return
+					}
+					if pass.Err != nil {
+						ll.ErrorContext(ctx, "failed to sync, trying again",
+							slog.Any("err", forceHint(pass.Err)),
+							slog.Duration("retry_in", pass.Retry),
+						)
+						return
+					}
+					if syncParams.Index != nil {
+						if err := syncParams.Index.Save(indexFile); err != nil {
+							ll.ErrorContext(ctx, "saving index", slog.Any("err", err))
+						}
+					}
+				},
+				OnScan: func(err error) {
+					ll.WarnContext(ctx, "can't watch path, syncing it periodically instead",
+						slog.Any("err", err),
+						slog.Duration("scan_interval", cctx.Duration(scanIntervalFlag.Name)),
+					)
+				},
+			}
+
+			ll.InfoContext(ctx, "watching path",
+				slog.String("path", path),
+				slog.String("index", indexFile),
+			)
+			if err := dirsync.Watch(ctx, path, sink, syncParams, watchParams); err != nil {
+				return fmt.Errorf("failed to watch: %w", err)
+			}
+			ll.InfoContext(ctx, "stopped watching path", slog.String("path", path))
+			return nil
+		},
+	}
+}
+
+type watchPassReport struct {
+	Full         bool         `json:"full"`
+	Dirs         []string     `json:"dirs,omitempty"`
+	Stats        *statsReport `json:"stats,omitempty"`
+	Error        string       `json:"error,omitempty"`
+	RetrySeconds float64      `json:"retry_seconds,omitempty"`
+}
+
+func makeWatchPassReport(pass dirsync.WatchPass) watchPassReport {
+	report := watchPassReport{Full: pass.Full, RetrySeconds: pass.Retry.Seconds()}
+	for _, dir := range pass.Dirs {
+		report.Dirs = append(report.Dirs, typesv1.StringFromPath(dir))
+	}
+	if pass.Stats != nil {
+		sr := makeStatsReport(pass.Stats)
+		report.Stats = &sr
+	}
+	if pass.Err != nil {
+		report.Error = pass.Err.Error()
+	}
+	return report
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"flag"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"time"
 
 	"github.com/aybabtme/syncy/pkg/gen/svc/sync/v1/syncv1connect"
 	"github.com/aybabtme/syncy/pkg/storage"
@@ -24,6 +26,7 @@ func main() {
 		metadbMySQLAddr  = flag.String("metadb.mysql.addr", "root@tcp(127.0.0.1:3306)/syncy", "")
 		blobLocalPath    = flag.String("blob.local.path", "tmp/blobs", "")
 		scratchLocalPath = flag.String("scratch.local.path", "/tmp/blobs", "")
+		uploadsTTL       = flag.Duration("uploads.ttl", 24*time.Hour, "how long what's kept of an interrupted upload waits for it to be resumed")
 	)
 	flag.Parse()
 
@@ -35,6 +38,7 @@ func main() {
 		*metadbMySQLAddr,
 		*blobLocalPath,
 		*scratchLocalPath,
+		*uploadsTTL,
 	); err != nil {
 		ll.Error("program failed", slog.Any("error", err))
 		os.Exit(1)
@@ -47,6 +51,7 @@ func realMain(
 	metadbMySQLAddr string,
 	blobLocalPath string,
 	scratchLocalPath string,
+	uploadsTTL time.Duration,
 ) error {
 	var (
 		meta metadb.Metadata
@@ -97,7 +102,8 @@ func realMain(
 		slog.Int("listen.port", addr.Port),
 	)
 
-	state := storage.NewState(meta, blob)
+	state := storage.NewState(ll.WithGroup("storage"), meta, blob)
+	go expireUploads(ll, state, uploadsTTL)
 
 	syncsvcPath, synchdl := syncv1connect.NewSyncServiceHandler(
 		syncsvc.NewHandler(ll.WithGroup("syncsvc"), state),
@@ -122,3 +128,18 @@ func realMain(
 	}
 	return nil
 }
+
+// expireUploads removes what was kept of the uploads that weren't resumed
+// for `ttl`, every so often.
+func expireUploads(ll *slog.Logger, state *storage.State, ttl time.Duration) {
+	ctx := context.Background()
+	for {
+		removed, err := state.ExpireUploads(ctx, ttl)
+		if err != nil {
+			ll.Error("expiring uploads", slog.Any("err", err))
+		} else if removed > 0 {
+			ll.Info("expired uploads", slog.Int("removed", removed))
+		}
+		time.Sleep(min(ttl/4, time.Hour))
+	}
+}
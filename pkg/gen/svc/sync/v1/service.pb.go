@@ -21,6 +21,65 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// what's done with the names of entries that collide with the name of a
+// sibling once case and unicode normalization are ignored, like `README.md`
+// and `Readme.md`: they can't both be on case-insensitive or normalizing
+// filesystems
+type NamePolicy int32
+
+const (
+	NamePolicy_allow NamePolicy = 0
+	// the collisions are logged
+	NamePolicy_warn NamePolicy = 1
+	// entries whose name collides are rejected
+	NamePolicy_reject NamePolicy = 2
+	// names are stored in NFC form, the collisions left are logged
+	NamePolicy_normalize NamePolicy = 3
+)
+
+// Enum value maps for NamePolicy.
+var (
+	NamePolicy_name = map[int32]string{
+		0: "allow",
+		1: "warn",
+		2: "reject",
+		3: "normalize",
+	}
+	NamePolicy_value = map[string]int32{
+		"allow":     0,
+		"warn":      1,
+		"reject":    2,
+		"normalize": 3,
+	}
+)
+
+func (x NamePolicy) Enum() *NamePolicy {
+	p := new(NamePolicy)
+	*p = x
+	return p
+}
+
+func (x NamePolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NamePolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_svc_sync_v1_service_proto_enumTypes[0].Descriptor()
+}
+
+func (NamePolicy) Type() protoreflect.EnumType {
+	return &file_svc_sync_v1_service_proto_enumTypes[0]
+}
+
+func (x NamePolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NamePolicy.Descriptor instead.
+func (NamePolicy) EnumDescriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{0}
+}
+
 type Hasher int32
 
 const (
@@ -51,11 +110,11 @@ func (x Hasher) String() string {
 }
 
 func (Hasher) Descriptor() protoreflect.EnumDescriptor {
-	return file_svc_sync_v1_service_proto_enumTypes[0].Descriptor()
+	return file_svc_sync_v1_service_proto_enumTypes[1].Descriptor()
 }
 
 func (Hasher) Type() protoreflect.EnumType {
-	return &file_svc_sync_v1_service_proto_enumTypes[0]
+	return &file_svc_sync_v1_service_proto_enumTypes[1]
 }
 
 func (x Hasher) Number() protoreflect.EnumNumber {
@@ -64,7 +123,7 @@ func (x Hasher) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Hasher.Descriptor instead.
 func (Hasher) EnumDescriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{0}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{1}
 }
 
 type CreateAccountRequest struct {
@@ -166,8 +225,9 @@ type CreateProjectRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AccountId   string `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
-	ProjectName string `protobuf:"bytes,2,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	AccountId   string     `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	ProjectName string     `protobuf:"bytes,2,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	NamePolicy  NamePolicy `protobuf:"varint,3,opt,name=name_policy,json=namePolicy,proto3,enum=svc.sync.v1.NamePolicy" json:"name_policy,omitempty"`
 }
 
 func (x *CreateProjectRequest) Reset() {
@@ -216,6 +276,13 @@ func (x *CreateProjectRequest) GetProjectName() string {
 	return ""
 }
 
+func (x *CreateProjectRequest) GetNamePolicy() NamePolicy {
+	if x != nil {
+		return x.NamePolicy
+	}
+	return NamePolicy_allow
+}
+
 type CreateProjectResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -263,6 +330,110 @@ func (x *CreateProjectResponse) GetProjectId() string {
 	return ""
 }
 
+type GetProjectRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *v1.ReqMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+}
+
+func (x *GetProjectRequest) Reset() {
+	*x = GetProjectRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProjectRequest) ProtoMessage() {}
+
+func (x *GetProjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProjectRequest.ProtoReflect.Descriptor instead.
+func (*GetProjectRequest) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetProjectRequest) GetMeta() *v1.ReqMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type GetProjectResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *v1.ResMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+	// the policy the project was created with, names are stored in NFC form
+	// under `normalize`
+	NamePolicy NamePolicy `protobuf:"varint,1,opt,name=name_policy,json=namePolicy,proto3,enum=svc.sync.v1.NamePolicy" json:"name_policy,omitempty"`
+}
+
+func (x *GetProjectResponse) Reset() {
+	*x = GetProjectResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProjectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProjectResponse) ProtoMessage() {}
+
+func (x *GetProjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProjectResponse.ProtoReflect.Descriptor instead.
+func (*GetProjectResponse) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetProjectResponse) GetMeta() *v1.ResMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *GetProjectResponse) GetNamePolicy() NamePolicy {
+	if x != nil {
+		return x.NamePolicy
+	}
+	return NamePolicy_allow
+}
+
 type GetRootRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -274,7 +445,7 @@ type GetRootRequest struct {
 func (x *GetRootRequest) Reset() {
 	*x = GetRootRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[4]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -287,7 +458,7 @@ func (x *GetRootRequest) String() string {
 func (*GetRootRequest) ProtoMessage() {}
 
 func (x *GetRootRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[4]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -300,7 +471,7 @@ func (x *GetRootRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRootRequest.ProtoReflect.Descriptor instead.
 func (*GetRootRequest) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{4}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetRootRequest) GetMeta() *v1.ReqMeta {
@@ -322,7 +493,7 @@ type GetRootResponse struct {
 func (x *GetRootResponse) Reset() {
 	*x = GetRootResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[5]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -335,7 +506,7 @@ func (x *GetRootResponse) String() string {
 func (*GetRootResponse) ProtoMessage() {}
 
 func (x *GetRootResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[5]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -348,7 +519,7 @@ func (x *GetRootResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRootResponse.ProtoReflect.Descriptor instead.
 func (*GetRootResponse) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{5}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetRootResponse) GetMeta() *v1.ResMeta {
@@ -377,7 +548,7 @@ type StatRequest struct {
 func (x *StatRequest) Reset() {
 	*x = StatRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[6]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -390,7 +561,7 @@ func (x *StatRequest) String() string {
 func (*StatRequest) ProtoMessage() {}
 
 func (x *StatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[6]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -403,7 +574,7 @@ func (x *StatRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatRequest.ProtoReflect.Descriptor instead.
 func (*StatRequest) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{6}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *StatRequest) GetMeta() *v1.ReqMeta {
@@ -432,7 +603,7 @@ type StatResponse struct {
 func (x *StatResponse) Reset() {
 	*x = StatResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[7]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -445,7 +616,7 @@ func (x *StatResponse) String() string {
 func (*StatResponse) ProtoMessage() {}
 
 func (x *StatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[7]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -458,7 +629,7 @@ func (x *StatResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatResponse.ProtoReflect.Descriptor instead.
 func (*StatResponse) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{7}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *StatResponse) GetMeta() *v1.ResMeta {
@@ -487,7 +658,7 @@ type ListDirRequest struct {
 func (x *ListDirRequest) Reset() {
 	*x = ListDirRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[8]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -500,7 +671,7 @@ func (x *ListDirRequest) String() string {
 func (*ListDirRequest) ProtoMessage() {}
 
 func (x *ListDirRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[8]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -513,7 +684,7 @@ func (x *ListDirRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDirRequest.ProtoReflect.Descriptor instead.
 func (*ListDirRequest) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{8}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ListDirRequest) GetMeta() *v1.ReqMeta {
@@ -542,7 +713,7 @@ type ListDirResponse struct {
 func (x *ListDirResponse) Reset() {
 	*x = ListDirResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[9]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -555,7 +726,7 @@ func (x *ListDirResponse) String() string {
 func (*ListDirResponse) ProtoMessage() {}
 
 func (x *ListDirResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[9]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -568,7 +739,7 @@ func (x *ListDirResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDirResponse.ProtoReflect.Descriptor instead.
 func (*ListDirResponse) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{9}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ListDirResponse) GetMeta() *v1.ResMeta {
@@ -596,7 +767,7 @@ type GetSignatureRequest struct {
 func (x *GetSignatureRequest) Reset() {
 	*x = GetSignatureRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[10]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -609,7 +780,7 @@ func (x *GetSignatureRequest) String() string {
 func (*GetSignatureRequest) ProtoMessage() {}
 
 func (x *GetSignatureRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[10]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -622,7 +793,7 @@ func (x *GetSignatureRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSignatureRequest.ProtoReflect.Descriptor instead.
 func (*GetSignatureRequest) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{10}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *GetSignatureRequest) GetMeta() *v1.ReqMeta {
@@ -644,7 +815,7 @@ type GetSignatureResponse struct {
 func (x *GetSignatureResponse) Reset() {
 	*x = GetSignatureResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[11]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -657,7 +828,7 @@ func (x *GetSignatureResponse) String() string {
 func (*GetSignatureResponse) ProtoMessage() {}
 
 func (x *GetSignatureResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[11]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -670,7 +841,7 @@ func (x *GetSignatureResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSignatureResponse.ProtoReflect.Descriptor instead.
 func (*GetSignatureResponse) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{11}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *GetSignatureResponse) GetMeta() *v1.ResMeta {
@@ -699,7 +870,7 @@ type GetFileSumRequest struct {
 func (x *GetFileSumRequest) Reset() {
 	*x = GetFileSumRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[12]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -712,7 +883,7 @@ func (x *GetFileSumRequest) String() string {
 func (*GetFileSumRequest) ProtoMessage() {}
 
 func (x *GetFileSumRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[12]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -725,7 +896,7 @@ func (x *GetFileSumRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetFileSumRequest.ProtoReflect.Descriptor instead.
 func (*GetFileSumRequest) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{12}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *GetFileSumRequest) GetMeta() *v1.ReqMeta {
@@ -754,7 +925,7 @@ type GetFileSumResponse struct {
 func (x *GetFileSumResponse) Reset() {
 	*x = GetFileSumResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[13]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -767,7 +938,7 @@ func (x *GetFileSumResponse) String() string {
 func (*GetFileSumResponse) ProtoMessage() {}
 
 func (x *GetFileSumResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[13]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -780,7 +951,7 @@ func (x *GetFileSumResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetFileSumResponse.ProtoReflect.Descriptor instead.
 func (*GetFileSumResponse) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{13}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetFileSumResponse) GetMeta() *v1.ResMeta {
@@ -797,37 +968,31 @@ func (x *GetFileSumResponse) GetSum() *v1.FileSum {
 	return nil
 }
 
-type CreateRequest struct {
+type GetTreeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Meta *v1.ReqMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
-	// Types that are assignable to Step:
-	//
-	//	*CreateRequest_Creating_
-	//	*CreateRequest_Writing_
-	//	*CreateRequest_Closing_
-	Step isCreateRequest_Step `protobuf_oneof:"step"`
 }
 
-func (x *CreateRequest) Reset() {
-	*x = CreateRequest{}
+func (x *GetTreeRequest) Reset() {
+	*x = GetTreeRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[14]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CreateRequest) String() string {
+func (x *GetTreeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateRequest) ProtoMessage() {}
+func (*GetTreeRequest) ProtoMessage() {}
 
-func (x *CreateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[14]
+func (x *GetTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -838,93 +1003,45 @@ func (x *CreateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
-func (*CreateRequest) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use GetTreeRequest.ProtoReflect.Descriptor instead.
+func (*GetTreeRequest) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *CreateRequest) GetMeta() *v1.ReqMeta {
+func (x *GetTreeRequest) GetMeta() *v1.ReqMeta {
 	if x != nil {
 		return x.Meta
 	}
 	return nil
 }
 
-func (m *CreateRequest) GetStep() isCreateRequest_Step {
-	if m != nil {
-		return m.Step
-	}
-	return nil
-}
-
-func (x *CreateRequest) GetCreating() *CreateRequest_Creating {
-	if x, ok := x.GetStep().(*CreateRequest_Creating_); ok {
-		return x.Creating
-	}
-	return nil
-}
-
-func (x *CreateRequest) GetWriting() *CreateRequest_Writing {
-	if x, ok := x.GetStep().(*CreateRequest_Writing_); ok {
-		return x.Writing
-	}
-	return nil
-}
-
-func (x *CreateRequest) GetClosing() *CreateRequest_Closing {
-	if x, ok := x.GetStep().(*CreateRequest_Closing_); ok {
-		return x.Closing
-	}
-	return nil
-}
-
-type isCreateRequest_Step interface {
-	isCreateRequest_Step()
-}
-
-type CreateRequest_Creating_ struct {
-	Creating *CreateRequest_Creating `protobuf:"bytes,1,opt,name=creating,proto3,oneof"`
-}
-
-type CreateRequest_Writing_ struct {
-	Writing *CreateRequest_Writing `protobuf:"bytes,2,opt,name=writing,proto3,oneof"`
-}
-
-type CreateRequest_Closing_ struct {
-	Closing *CreateRequest_Closing `protobuf:"bytes,3,opt,name=closing,proto3,oneof"`
-}
-
-func (*CreateRequest_Creating_) isCreateRequest_Step() {}
-
-func (*CreateRequest_Writing_) isCreateRequest_Step() {}
-
-func (*CreateRequest_Closing_) isCreateRequest_Step() {}
-
-type CreateResponse struct {
+type GetTreeResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Meta *v1.ResMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+	// the files only have their info
+	Root *v1.DirSum `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
 }
 
-func (x *CreateResponse) Reset() {
-	*x = CreateResponse{}
+func (x *GetTreeResponse) Reset() {
+	*x = GetTreeResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[15]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CreateResponse) String() string {
+func (x *GetTreeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateResponse) ProtoMessage() {}
+func (*GetTreeResponse) ProtoMessage() {}
 
-func (x *CreateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[15]
+func (x *GetTreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -935,19 +1052,137 @@ func (x *CreateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateResponse.ProtoReflect.Descriptor instead.
-func (*CreateResponse) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use GetTreeResponse.ProtoReflect.Descriptor instead.
+func (*GetTreeResponse) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *CreateResponse) GetMeta() *v1.ResMeta {
+func (x *GetTreeResponse) GetMeta() *v1.ResMeta {
 	if x != nil {
 		return x.Meta
 	}
 	return nil
 }
 
-type PatchRequest struct {
+func (x *GetTreeResponse) GetRoot() *v1.DirSum {
+	if x != nil {
+		return x.Root
+	}
+	return nil
+}
+
+type GetFileSumsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta  *v1.ReqMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+	Paths []*v1.Path  `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+}
+
+func (x *GetFileSumsRequest) Reset() {
+	*x = GetFileSumsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFileSumsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileSumsRequest) ProtoMessage() {}
+
+func (x *GetFileSumsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileSumsRequest.ProtoReflect.Descriptor instead.
+func (*GetFileSumsRequest) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetFileSumsRequest) GetMeta() *v1.ReqMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *GetFileSumsRequest) GetPaths() []*v1.Path {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+type GetFileSumsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *v1.ResMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+	// in the order of the paths
+	Sums []*v1.FileSum `protobuf:"bytes,1,rep,name=sums,proto3" json:"sums,omitempty"`
+}
+
+func (x *GetFileSumsResponse) Reset() {
+	*x = GetFileSumsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFileSumsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileSumsResponse) ProtoMessage() {}
+
+func (x *GetFileSumsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileSumsResponse.ProtoReflect.Descriptor instead.
+func (*GetFileSumsResponse) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetFileSumsResponse) GetMeta() *v1.ResMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *GetFileSumsResponse) GetSums() []*v1.FileSum {
+	if x != nil {
+		return x.Sums
+	}
+	return nil
+}
+
+type CreateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -955,29 +1190,29 @@ type PatchRequest struct {
 	Meta *v1.ReqMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
 	// Types that are assignable to Step:
 	//
-	//	*PatchRequest_Opening_
-	//	*PatchRequest_Patching_
-	//	*PatchRequest_Closing_
-	Step isPatchRequest_Step `protobuf_oneof:"step"`
+	//	*CreateRequest_Creating_
+	//	*CreateRequest_Writing_
+	//	*CreateRequest_Closing_
+	Step isCreateRequest_Step `protobuf_oneof:"step"`
 }
 
-func (x *PatchRequest) Reset() {
-	*x = PatchRequest{}
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[16]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PatchRequest) String() string {
+func (x *CreateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PatchRequest) ProtoMessage() {}
+func (*CreateRequest) ProtoMessage() {}
 
-func (x *PatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[16]
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -988,69 +1223,69 @@ func (x *PatchRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PatchRequest.ProtoReflect.Descriptor instead.
-func (*PatchRequest) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *PatchRequest) GetMeta() *v1.ReqMeta {
+func (x *CreateRequest) GetMeta() *v1.ReqMeta {
 	if x != nil {
 		return x.Meta
 	}
 	return nil
 }
 
-func (m *PatchRequest) GetStep() isPatchRequest_Step {
+func (m *CreateRequest) GetStep() isCreateRequest_Step {
 	if m != nil {
 		return m.Step
 	}
 	return nil
 }
 
-func (x *PatchRequest) GetOpening() *PatchRequest_Opening {
-	if x, ok := x.GetStep().(*PatchRequest_Opening_); ok {
-		return x.Opening
+func (x *CreateRequest) GetCreating() *CreateRequest_Creating {
+	if x, ok := x.GetStep().(*CreateRequest_Creating_); ok {
+		return x.Creating
 	}
 	return nil
 }
 
-func (x *PatchRequest) GetPatching() *PatchRequest_Patching {
-	if x, ok := x.GetStep().(*PatchRequest_Patching_); ok {
-		return x.Patching
+func (x *CreateRequest) GetWriting() *CreateRequest_Writing {
+	if x, ok := x.GetStep().(*CreateRequest_Writing_); ok {
+		return x.Writing
 	}
 	return nil
 }
 
-func (x *PatchRequest) GetClosing() *PatchRequest_Closing {
-	if x, ok := x.GetStep().(*PatchRequest_Closing_); ok {
+func (x *CreateRequest) GetClosing() *CreateRequest_Closing {
+	if x, ok := x.GetStep().(*CreateRequest_Closing_); ok {
 		return x.Closing
 	}
 	return nil
 }
 
-type isPatchRequest_Step interface {
-	isPatchRequest_Step()
+type isCreateRequest_Step interface {
+	isCreateRequest_Step()
 }
 
-type PatchRequest_Opening_ struct {
-	Opening *PatchRequest_Opening `protobuf:"bytes,1,opt,name=opening,proto3,oneof"`
+type CreateRequest_Creating_ struct {
+	Creating *CreateRequest_Creating `protobuf:"bytes,1,opt,name=creating,proto3,oneof"`
 }
 
-type PatchRequest_Patching_ struct {
-	Patching *PatchRequest_Patching `protobuf:"bytes,2,opt,name=patching,proto3,oneof"`
+type CreateRequest_Writing_ struct {
+	Writing *CreateRequest_Writing `protobuf:"bytes,2,opt,name=writing,proto3,oneof"`
 }
 
-type PatchRequest_Closing_ struct {
-	Closing *PatchRequest_Closing `protobuf:"bytes,3,opt,name=closing,proto3,oneof"`
+type CreateRequest_Closing_ struct {
+	Closing *CreateRequest_Closing `protobuf:"bytes,3,opt,name=closing,proto3,oneof"`
 }
 
-func (*PatchRequest_Opening_) isPatchRequest_Step() {}
+func (*CreateRequest_Creating_) isCreateRequest_Step() {}
 
-func (*PatchRequest_Patching_) isPatchRequest_Step() {}
+func (*CreateRequest_Writing_) isCreateRequest_Step() {}
 
-func (*PatchRequest_Closing_) isPatchRequest_Step() {}
+func (*CreateRequest_Closing_) isCreateRequest_Step() {}
 
-type PatchResponse struct {
+type CreateResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -1058,23 +1293,71 @@ type PatchResponse struct {
 	Meta *v1.ResMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
 }
 
-func (x *PatchResponse) Reset() {
-	*x = PatchResponse{}
+func (x *CreateResponse) Reset() {
+	*x = CreateResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[17]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PatchResponse) String() string {
+func (x *CreateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PatchResponse) ProtoMessage() {}
+func (*CreateResponse) ProtoMessage() {}
 
-func (x *PatchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[17]
+func (x *CreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResponse.ProtoReflect.Descriptor instead.
+func (*CreateResponse) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CreateResponse) GetMeta() *v1.ResMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type GetUploadRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta     *v1.ReqMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+	UploadId string      `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+}
+
+func (x *GetUploadRequest) Reset() {
+	*x = GetUploadRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUploadRequest) ProtoMessage() {}
+
+func (x *GetUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1082,48 +1365,654 @@ func (x *PatchResponse) ProtoReflect() protoreflect.Message {
 		}
 		return ms
 	}
-	return mi.MessageOf(x)
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUploadRequest.ProtoReflect.Descriptor instead.
+func (*GetUploadRequest) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetUploadRequest) GetMeta() *v1.ReqMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *GetUploadRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+type GetUploadResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *v1.ResMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+	// how much of the upload was kept, 0 if there's no such upload
+	Size uint64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+}
+
+func (x *GetUploadResponse) Reset() {
+	*x = GetUploadResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUploadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUploadResponse) ProtoMessage() {}
+
+func (x *GetUploadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUploadResponse.ProtoReflect.Descriptor instead.
+func (*GetUploadResponse) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetUploadResponse) GetMeta() *v1.ResMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *GetUploadResponse) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type PatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *v1.ReqMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+	// Types that are assignable to Step:
+	//
+	//	*PatchRequest_Opening_
+	//	*PatchRequest_Patching_
+	//	*PatchRequest_Closing_
+	Step isPatchRequest_Step `protobuf_oneof:"step"`
+}
+
+func (x *PatchRequest) Reset() {
+	*x = PatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchRequest) ProtoMessage() {}
+
+func (x *PatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchRequest.ProtoReflect.Descriptor instead.
+func (*PatchRequest) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PatchRequest) GetMeta() *v1.ReqMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (m *PatchRequest) GetStep() isPatchRequest_Step {
+	if m != nil {
+		return m.Step
+	}
+	return nil
+}
+
+func (x *PatchRequest) GetOpening() *PatchRequest_Opening {
+	if x, ok := x.GetStep().(*PatchRequest_Opening_); ok {
+		return x.Opening
+	}
+	return nil
+}
+
+func (x *PatchRequest) GetPatching() *PatchRequest_Patching {
+	if x, ok := x.GetStep().(*PatchRequest_Patching_); ok {
+		return x.Patching
+	}
+	return nil
+}
+
+func (x *PatchRequest) GetClosing() *PatchRequest_Closing {
+	if x, ok := x.GetStep().(*PatchRequest_Closing_); ok {
+		return x.Closing
+	}
+	return nil
+}
+
+type isPatchRequest_Step interface {
+	isPatchRequest_Step()
+}
+
+type PatchRequest_Opening_ struct {
+	Opening *PatchRequest_Opening `protobuf:"bytes,1,opt,name=opening,proto3,oneof"`
+}
+
+type PatchRequest_Patching_ struct {
+	Patching *PatchRequest_Patching `protobuf:"bytes,2,opt,name=patching,proto3,oneof"`
+}
+
+type PatchRequest_Closing_ struct {
+	Closing *PatchRequest_Closing `protobuf:"bytes,3,opt,name=closing,proto3,oneof"`
+}
+
+func (*PatchRequest_Opening_) isPatchRequest_Step() {}
+
+func (*PatchRequest_Patching_) isPatchRequest_Step() {}
+
+func (*PatchRequest_Closing_) isPatchRequest_Step() {}
+
+type PatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *v1.ResMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+}
+
+func (x *PatchResponse) Reset() {
+	*x = PatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchResponse) ProtoMessage() {}
+
+func (x *PatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchResponse.ProtoReflect.Descriptor instead.
+func (*PatchResponse) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PatchResponse) GetMeta() *v1.ResMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *v1.ReqMeta  `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+	Path *v1.Path     `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Info *v1.FileInfo `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DeleteRequest) GetMeta() *v1.ReqMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *DeleteRequest) GetPath() *v1.Path {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *DeleteRequest) GetInfo() *v1.FileInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *v1.ResMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DeleteResponse) GetMeta() *v1.ResMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type MoveRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *v1.ReqMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+	// where the file or dir is, and its info there
+	From     *v1.Path     `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	FromInfo *v1.FileInfo `protobuf:"bytes,2,opt,name=from_info,json=fromInfo,proto3" json:"from_info,omitempty"`
+	// where it goes, and its info once moved
+	ParentDir *v1.Path     `protobuf:"bytes,3,opt,name=parent_dir,json=parentDir,proto3" json:"parent_dir,omitempty"`
+	Info      *v1.FileInfo `protobuf:"bytes,4,opt,name=info,proto3" json:"info,omitempty"`
+}
+
+func (x *MoveRequest) Reset() {
+	*x = MoveRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveRequest) ProtoMessage() {}
+
+func (x *MoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveRequest.ProtoReflect.Descriptor instead.
+func (*MoveRequest) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *MoveRequest) GetMeta() *v1.ReqMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *MoveRequest) GetFrom() *v1.Path {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *MoveRequest) GetFromInfo() *v1.FileInfo {
+	if x != nil {
+		return x.FromInfo
+	}
+	return nil
+}
+
+func (x *MoveRequest) GetParentDir() *v1.Path {
+	if x != nil {
+		return x.ParentDir
+	}
+	return nil
+}
+
+func (x *MoveRequest) GetInfo() *v1.FileInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+type MoveResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *v1.ResMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+}
+
+func (x *MoveResponse) Reset() {
+	*x = MoveResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MoveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveResponse) ProtoMessage() {}
+
+func (x *MoveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveResponse.ProtoReflect.Descriptor instead.
+func (*MoveResponse) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *MoveResponse) GetMeta() *v1.ResMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type DownloadRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta   *v1.ReqMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+	Path   *v1.Path    `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Hasher Hasher      `protobuf:"varint,2,opt,name=hasher,proto3,enum=svc.sync.v1.Hasher" json:"hasher,omitempty"`
+}
+
+func (x *DownloadRequest) Reset() {
+	*x = DownloadRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadRequest) ProtoMessage() {}
+
+func (x *DownloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadRequest.ProtoReflect.Descriptor instead.
+func (*DownloadRequest) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DownloadRequest) GetMeta() *v1.ReqMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *DownloadRequest) GetPath() *v1.Path {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *DownloadRequest) GetHasher() Hasher {
+	if x != nil {
+		return x.Hasher
+	}
+	return Hasher_invalid
+}
+
+type DownloadResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *v1.ResMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+	// Types that are assignable to Step:
+	//
+	//	*DownloadResponse_Writing_
+	//	*DownloadResponse_Closing_
+	Step isDownloadResponse_Step `protobuf_oneof:"step"`
+}
+
+func (x *DownloadResponse) Reset() {
+	*x = DownloadResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownloadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadResponse) ProtoMessage() {}
+
+func (x *DownloadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadResponse.ProtoReflect.Descriptor instead.
+func (*DownloadResponse) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DownloadResponse) GetMeta() *v1.ResMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (m *DownloadResponse) GetStep() isDownloadResponse_Step {
+	if m != nil {
+		return m.Step
+	}
+	return nil
 }
 
-// Deprecated: Use PatchResponse.ProtoReflect.Descriptor instead.
-func (*PatchResponse) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{17}
+func (x *DownloadResponse) GetWriting() *DownloadResponse_Writing {
+	if x, ok := x.GetStep().(*DownloadResponse_Writing_); ok {
+		return x.Writing
+	}
+	return nil
 }
 
-func (x *PatchResponse) GetMeta() *v1.ResMeta {
-	if x != nil {
-		return x.Meta
+func (x *DownloadResponse) GetClosing() *DownloadResponse_Closing {
+	if x, ok := x.GetStep().(*DownloadResponse_Closing_); ok {
+		return x.Closing
 	}
 	return nil
 }
 
-type DeleteRequest struct {
+type isDownloadResponse_Step interface {
+	isDownloadResponse_Step()
+}
+
+type DownloadResponse_Writing_ struct {
+	Writing *DownloadResponse_Writing `protobuf:"bytes,1,opt,name=writing,proto3,oneof"`
+}
+
+type DownloadResponse_Closing_ struct {
+	Closing *DownloadResponse_Closing `protobuf:"bytes,2,opt,name=closing,proto3,oneof"`
+}
+
+func (*DownloadResponse_Writing_) isDownloadResponse_Step() {}
+
+func (*DownloadResponse_Closing_) isDownloadResponse_Step() {}
+
+type DownloadPatchRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Meta *v1.ReqMeta  `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
-	Path *v1.Path     `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Info *v1.FileInfo `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	Meta   *v1.ReqMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+	Path   *v1.Path    `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Hasher Hasher      `protobuf:"varint,2,opt,name=hasher,proto3,enum=svc.sync.v1.Hasher" json:"hasher,omitempty"`
+	// sum of the requester's version of the file, the patch
+	// turns it into the server's version
+	Sum *v1.FileSum `protobuf:"bytes,3,opt,name=sum,proto3" json:"sum,omitempty"`
 }
 
-func (x *DeleteRequest) Reset() {
-	*x = DeleteRequest{}
+func (x *DownloadPatchRequest) Reset() {
+	*x = DownloadPatchRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[18]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DeleteRequest) String() string {
+func (x *DownloadPatchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRequest) ProtoMessage() {}
+func (*DownloadPatchRequest) ProtoMessage() {}
 
-func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[18]
+func (x *DownloadPatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1134,57 +2023,69 @@ func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use DownloadPatchRequest.ProtoReflect.Descriptor instead.
+func (*DownloadPatchRequest) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *DeleteRequest) GetMeta() *v1.ReqMeta {
+func (x *DownloadPatchRequest) GetMeta() *v1.ReqMeta {
 	if x != nil {
 		return x.Meta
 	}
 	return nil
 }
 
-func (x *DeleteRequest) GetPath() *v1.Path {
+func (x *DownloadPatchRequest) GetPath() *v1.Path {
 	if x != nil {
 		return x.Path
 	}
 	return nil
 }
 
-func (x *DeleteRequest) GetInfo() *v1.FileInfo {
+func (x *DownloadPatchRequest) GetHasher() Hasher {
 	if x != nil {
-		return x.Info
+		return x.Hasher
+	}
+	return Hasher_invalid
+}
+
+func (x *DownloadPatchRequest) GetSum() *v1.FileSum {
+	if x != nil {
+		return x.Sum
 	}
 	return nil
 }
 
-type DeleteResponse struct {
+type DownloadPatchResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Meta *v1.ResMeta `protobuf:"bytes,1000,opt,name=meta,proto3" json:"meta,omitempty"`
+	// Types that are assignable to Step:
+	//
+	//	*DownloadPatchResponse_Patching_
+	//	*DownloadPatchResponse_Closing_
+	Step isDownloadPatchResponse_Step `protobuf_oneof:"step"`
 }
 
-func (x *DeleteResponse) Reset() {
-	*x = DeleteResponse{}
+func (x *DownloadPatchResponse) Reset() {
+	*x = DownloadPatchResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[19]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DeleteResponse) String() string {
+func (x *DownloadPatchResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteResponse) ProtoMessage() {}
+func (*DownloadPatchResponse) ProtoMessage() {}
 
-func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[19]
+func (x *DownloadPatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1195,18 +2096,55 @@ func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
-func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use DownloadPatchResponse.ProtoReflect.Descriptor instead.
+func (*DownloadPatchResponse) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *DeleteResponse) GetMeta() *v1.ResMeta {
+func (x *DownloadPatchResponse) GetMeta() *v1.ResMeta {
 	if x != nil {
 		return x.Meta
 	}
 	return nil
 }
 
+func (m *DownloadPatchResponse) GetStep() isDownloadPatchResponse_Step {
+	if m != nil {
+		return m.Step
+	}
+	return nil
+}
+
+func (x *DownloadPatchResponse) GetPatching() *DownloadPatchResponse_Patching {
+	if x, ok := x.GetStep().(*DownloadPatchResponse_Patching_); ok {
+		return x.Patching
+	}
+	return nil
+}
+
+func (x *DownloadPatchResponse) GetClosing() *DownloadPatchResponse_Closing {
+	if x, ok := x.GetStep().(*DownloadPatchResponse_Closing_); ok {
+		return x.Closing
+	}
+	return nil
+}
+
+type isDownloadPatchResponse_Step interface {
+	isDownloadPatchResponse_Step()
+}
+
+type DownloadPatchResponse_Patching_ struct {
+	Patching *DownloadPatchResponse_Patching `protobuf:"bytes,1,opt,name=patching,proto3,oneof"`
+}
+
+type DownloadPatchResponse_Closing_ struct {
+	Closing *DownloadPatchResponse_Closing `protobuf:"bytes,2,opt,name=closing,proto3,oneof"`
+}
+
+func (*DownloadPatchResponse_Patching_) isDownloadPatchResponse_Step() {}
+
+func (*DownloadPatchResponse_Closing_) isDownloadPatchResponse_Step() {}
+
 type CreateRequest_Creating struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1215,12 +2153,19 @@ type CreateRequest_Creating struct {
 	Path   *v1.Path     `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
 	Info   *v1.FileInfo `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
 	Hasher Hasher       `protobuf:"varint,3,opt,name=hasher,proto3,enum=svc.sync.v1.Hasher" json:"hasher,omitempty"`
+	// set for uploads that can be resumed: what's received is kept under
+	// this id until the file is created, for an upload that's interrupted
+	// to be resumed with the same id
+	UploadId string `protobuf:"bytes,4,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	// where the content sent starts, what was kept of the upload before it
+	// is reused
+	Offset uint64 `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
 }
 
 func (x *CreateRequest_Creating) Reset() {
 	*x = CreateRequest_Creating{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[20]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1233,7 +2178,7 @@ func (x *CreateRequest_Creating) String() string {
 func (*CreateRequest_Creating) ProtoMessage() {}
 
 func (x *CreateRequest_Creating) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[20]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1246,7 +2191,7 @@ func (x *CreateRequest_Creating) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRequest_Creating.ProtoReflect.Descriptor instead.
 func (*CreateRequest_Creating) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{14, 0}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{20, 0}
 }
 
 func (x *CreateRequest_Creating) GetPath() *v1.Path {
@@ -1270,18 +2215,35 @@ func (x *CreateRequest_Creating) GetHasher() Hasher {
 	return Hasher_invalid
 }
 
+func (x *CreateRequest_Creating) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *CreateRequest_Creating) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
 type CreateRequest_Writing struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	ContentBlock []byte `protobuf:"bytes,1,opt,name=content_block,json=contentBlock,proto3" json:"content_block,omitempty"`
+	// size of a run of zeros after the content block, left as a hole
+	// where it can be
+	Hole uint64 `protobuf:"varint,2,opt,name=hole,proto3" json:"hole,omitempty"`
 }
 
 func (x *CreateRequest_Writing) Reset() {
 	*x = CreateRequest_Writing{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[21]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1294,7 +2256,7 @@ func (x *CreateRequest_Writing) String() string {
 func (*CreateRequest_Writing) ProtoMessage() {}
 
 func (x *CreateRequest_Writing) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[21]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1307,7 +2269,7 @@ func (x *CreateRequest_Writing) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRequest_Writing.ProtoReflect.Descriptor instead.
 func (*CreateRequest_Writing) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{14, 1}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{20, 1}
 }
 
 func (x *CreateRequest_Writing) GetContentBlock() []byte {
@@ -1317,6 +2279,13 @@ func (x *CreateRequest_Writing) GetContentBlock() []byte {
 	return nil
 }
 
+func (x *CreateRequest_Writing) GetHole() uint64 {
+	if x != nil {
+		return x.Hole
+	}
+	return 0
+}
+
 type CreateRequest_Closing struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1328,7 +2297,7 @@ type CreateRequest_Closing struct {
 func (x *CreateRequest_Closing) Reset() {
 	*x = CreateRequest_Closing{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[22]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[36]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1341,7 +2310,7 @@ func (x *CreateRequest_Closing) String() string {
 func (*CreateRequest_Closing) ProtoMessage() {}
 
 func (x *CreateRequest_Closing) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[22]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[36]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1354,7 +2323,7 @@ func (x *CreateRequest_Closing) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRequest_Closing.ProtoReflect.Descriptor instead.
 func (*CreateRequest_Closing) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{14, 2}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{20, 2}
 }
 
 func (x *CreateRequest_Closing) GetSum() []byte {
@@ -1378,7 +2347,7 @@ type PatchRequest_Opening struct {
 func (x *PatchRequest_Opening) Reset() {
 	*x = PatchRequest_Opening{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[23]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1391,7 +2360,7 @@ func (x *PatchRequest_Opening) String() string {
 func (*PatchRequest_Opening) ProtoMessage() {}
 
 func (x *PatchRequest_Opening) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[23]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1404,7 +2373,7 @@ func (x *PatchRequest_Opening) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PatchRequest_Opening.ProtoReflect.Descriptor instead.
 func (*PatchRequest_Opening) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{16, 0}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{24, 0}
 }
 
 func (x *PatchRequest_Opening) GetPath() *v1.Path {
@@ -1446,7 +2415,7 @@ type PatchRequest_Patching struct {
 func (x *PatchRequest_Patching) Reset() {
 	*x = PatchRequest_Patching{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[24]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[38]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1459,7 +2428,7 @@ func (x *PatchRequest_Patching) String() string {
 func (*PatchRequest_Patching) ProtoMessage() {}
 
 func (x *PatchRequest_Patching) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[24]
+	mi := &file_svc_sync_v1_service_proto_msgTypes[38]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1472,7 +2441,7 @@ func (x *PatchRequest_Patching) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PatchRequest_Patching.ProtoReflect.Descriptor instead.
 func (*PatchRequest_Patching) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{16, 1}
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{24, 1}
 }
 
 func (x *PatchRequest_Patching) GetPatch() *v1.FileBlockPatch {
@@ -1493,7 +2462,7 @@ type PatchRequest_Closing struct {
 func (x *PatchRequest_Closing) Reset() {
 	*x = PatchRequest_Closing{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_svc_sync_v1_service_proto_msgTypes[25]
+		mi := &file_svc_sync_v1_service_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1505,8 +2474,206 @@ func (x *PatchRequest_Closing) String() string {
 
 func (*PatchRequest_Closing) ProtoMessage() {}
 
-func (x *PatchRequest_Closing) ProtoReflect() protoreflect.Message {
-	mi := &file_svc_sync_v1_service_proto_msgTypes[25]
+func (x *PatchRequest_Closing) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchRequest_Closing.ProtoReflect.Descriptor instead.
+func (*PatchRequest_Closing) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{24, 2}
+}
+
+func (x *PatchRequest_Closing) GetSum() []byte {
+	if x != nil {
+		return x.Sum
+	}
+	return nil
+}
+
+type DownloadResponse_Writing struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ContentBlock []byte `protobuf:"bytes,1,opt,name=content_block,json=contentBlock,proto3" json:"content_block,omitempty"`
+	// size of a run of zeros after the content block, that the file has
+	// as a hole
+	Hole uint64 `protobuf:"varint,2,opt,name=hole,proto3" json:"hole,omitempty"`
+}
+
+func (x *DownloadResponse_Writing) Reset() {
+	*x = DownloadResponse_Writing{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownloadResponse_Writing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadResponse_Writing) ProtoMessage() {}
+
+func (x *DownloadResponse_Writing) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadResponse_Writing.ProtoReflect.Descriptor instead.
+func (*DownloadResponse_Writing) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{31, 0}
+}
+
+func (x *DownloadResponse_Writing) GetContentBlock() []byte {
+	if x != nil {
+		return x.ContentBlock
+	}
+	return nil
+}
+
+func (x *DownloadResponse_Writing) GetHole() uint64 {
+	if x != nil {
+		return x.Hole
+	}
+	return 0
+}
+
+type DownloadResponse_Closing struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sum []byte `protobuf:"bytes,1,opt,name=sum,proto3" json:"sum,omitempty"`
+}
+
+func (x *DownloadResponse_Closing) Reset() {
+	*x = DownloadResponse_Closing{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownloadResponse_Closing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadResponse_Closing) ProtoMessage() {}
+
+func (x *DownloadResponse_Closing) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadResponse_Closing.ProtoReflect.Descriptor instead.
+func (*DownloadResponse_Closing) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{31, 1}
+}
+
+func (x *DownloadResponse_Closing) GetSum() []byte {
+	if x != nil {
+		return x.Sum
+	}
+	return nil
+}
+
+type DownloadPatchResponse_Patching struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Patch *v1.FileBlockPatch `protobuf:"bytes,1,opt,name=patch,proto3" json:"patch,omitempty"`
+}
+
+func (x *DownloadPatchResponse_Patching) Reset() {
+	*x = DownloadPatchResponse_Patching{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownloadPatchResponse_Patching) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadPatchResponse_Patching) ProtoMessage() {}
+
+func (x *DownloadPatchResponse_Patching) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadPatchResponse_Patching.ProtoReflect.Descriptor instead.
+func (*DownloadPatchResponse_Patching) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{33, 0}
+}
+
+func (x *DownloadPatchResponse_Patching) GetPatch() *v1.FileBlockPatch {
+	if x != nil {
+		return x.Patch
+	}
+	return nil
+}
+
+type DownloadPatchResponse_Closing struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sum []byte `protobuf:"bytes,1,opt,name=sum,proto3" json:"sum,omitempty"`
+}
+
+func (x *DownloadPatchResponse_Closing) Reset() {
+	*x = DownloadPatchResponse_Closing{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_svc_sync_v1_service_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownloadPatchResponse_Closing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadPatchResponse_Closing) ProtoMessage() {}
+
+func (x *DownloadPatchResponse_Closing) ProtoReflect() protoreflect.Message {
+	mi := &file_svc_sync_v1_service_proto_msgTypes[43]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1517,12 +2684,12 @@ func (x *PatchRequest_Closing) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PatchRequest_Closing.ProtoReflect.Descriptor instead.
-func (*PatchRequest_Closing) Descriptor() ([]byte, []int) {
-	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{16, 2}
+// Deprecated: Use DownloadPatchResponse_Closing.ProtoReflect.Descriptor instead.
+func (*DownloadPatchResponse_Closing) Descriptor() ([]byte, []int) {
+	return file_svc_sync_v1_service_proto_rawDescGZIP(), []int{33, 1}
 }
 
-func (x *PatchRequest_Closing) GetSum() []byte {
+func (x *DownloadPatchResponse_Closing) GetSum() []byte {
 	if x != nil {
 		return x.Sum
 	}
@@ -1546,16 +2713,31 @@ var file_svc_sync_v1_service_proto_rawDesc = []byte{
 	0x6e, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x36, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
 	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
 	0x1d, 0x0a, 0x0a, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x58,
-	0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
-	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x63, 0x63, 0x6f,
-	0x75, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
-	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f,
-	0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x36, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x64,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x92,
+	0x01, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x38, 0x0a, 0x0b, 0x6e, 0x61, 0x6d,
+	0x65, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17,
+	0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d,
+	0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x22, 0x36, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x64, 0x22, 0x3b, 0x0a, 0x11, 0x47,
+	0x65, 0x74, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x4d, 0x65,
+	0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x22, 0x76, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x50,
+	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26,
+	0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x4d, 0x65, 0x74, 0x61,
+	0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x38, 0x0a, 0x0b, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x70,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x73, 0x76,
+	0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x52, 0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
 	0x22, 0x38, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28,
 	0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71,
@@ -1612,146 +2794,297 @@ var file_svc_sync_v1_service_proto_rawDesc = []byte{
 	0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x52,
 	0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x23, 0x0a, 0x03, 0x73, 0x75, 0x6d, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69,
-	0x6c, 0x65, 0x53, 0x75, 0x6d, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x22, 0xd5, 0x03, 0x0a, 0x0d, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x04,
+	0x6c, 0x65, 0x53, 0x75, 0x6d, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x22, 0x38, 0x0a, 0x0e, 0x47, 0x65,
+	0x74, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x04,
 	0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79,
 	0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04,
-	0x6d, 0x65, 0x74, 0x61, 0x12, 0x41, 0x0a, 0x08, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6e, 0x67,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e,
-	0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x08, 0x63,
-	0x72, 0x65, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x3e, 0x0a, 0x07, 0x77, 0x72, 0x69, 0x74, 0x69,
-	0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73,
-	0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x07,
-	0x77, 0x72, 0x69, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x3e, 0x0a, 0x07, 0x63, 0x6c, 0x6f, 0x73, 0x69,
-	0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73,
-	0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x07,
-	0x63, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x1a, 0x83, 0x01, 0x0a, 0x08, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x69, 0x6e, 0x67, 0x12, 0x22, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x6d, 0x65, 0x74, 0x61, 0x22, 0x5f, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x54, 0x72, 0x65, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18,
+	0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x65, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12,
+	0x24, 0x0a, 0x04, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x72, 0x53, 0x75, 0x6d, 0x52,
+	0x04, 0x72, 0x6f, 0x6f, 0x74, 0x22, 0x62, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65,
+	0x53, 0x75, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x6d,
+	0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d,
+	0x65, 0x74, 0x61, 0x12, 0x24, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x01, 0x20, 0x03,
 	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61,
-	0x74, 0x68, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x26, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76,
-	0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f,
-	0x12, 0x2b, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x13, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x48,
-	0x61, 0x73, 0x68, 0x65, 0x72, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x1a, 0x2e, 0x0a,
-	0x07, 0x57, 0x72, 0x69, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x74,
-	0x65, 0x6e, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x1a, 0x1b, 0x0a,
-	0x07, 0x43, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x75, 0x6d, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x42, 0x06, 0x0a, 0x04, 0x73, 0x74,
-	0x65, 0x70, 0x22, 0x38, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52,
-	0x65, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x22, 0x81, 0x04, 0x0a,
-	0x0c, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a,
-	0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74,
-	0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x4d, 0x65, 0x74, 0x61, 0x52,
-	0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x3d, 0x0a, 0x07, 0x6f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e,
-	0x63, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x07, 0x6f, 0x70, 0x65,
-	0x6e, 0x69, 0x6e, 0x67, 0x12, 0x40, 0x0a, 0x08, 0x70, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e,
-	0x63, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x08, 0x70, 0x61,
-	0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x12, 0x3d, 0x0a, 0x07, 0x63, 0x6c, 0x6f, 0x73, 0x69, 0x6e,
-	0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79,
-	0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x07, 0x63, 0x6c,
-	0x6f, 0x73, 0x69, 0x6e, 0x67, 0x1a, 0xa7, 0x01, 0x0a, 0x07, 0x4f, 0x70, 0x65, 0x6e, 0x69, 0x6e,
-	0x67, 0x12, 0x22, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52,
-	0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x26, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46,
-	0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x2b, 0x0a,
-	0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e,
-	0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61, 0x73, 0x68,
-	0x65, 0x72, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x03, 0x73, 0x75,
-	0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
-	0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x1a,
-	0x3a, 0x0a, 0x08, 0x50, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x12, 0x2e, 0x0a, 0x05, 0x70,
-	0x61, 0x74, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x74, 0x79, 0x70,
-	0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50,
-	0x61, 0x74, 0x63, 0x68, 0x52, 0x05, 0x70, 0x61, 0x74, 0x63, 0x68, 0x1a, 0x1b, 0x0a, 0x07, 0x43,
+	0x74, 0x68, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x22, 0x64, 0x0a, 0x13, 0x47, 0x65, 0x74,
+	0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x4d, 0x65,
+	0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x25, 0x0a, 0x04, 0x73, 0x75, 0x6d, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d, 0x52, 0x04, 0x73, 0x75, 0x6d, 0x73, 0x22,
+	0x9e, 0x04, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x4d,
+	0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x41, 0x0a, 0x08, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x73, 0x76,
+	0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6e, 0x67,
+	0x48, 0x00, 0x52, 0x08, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x3e, 0x0a, 0x07,
+	0x77, 0x72, 0x69, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e,
+	0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x69, 0x6e,
+	0x67, 0x48, 0x00, 0x52, 0x07, 0x77, 0x72, 0x69, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x3e, 0x0a, 0x07,
+	0x63, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e,
+	0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x69, 0x6e,
+	0x67, 0x48, 0x00, 0x52, 0x07, 0x63, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x1a, 0xb8, 0x01, 0x0a,
+	0x08, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x22, 0x0a, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x26, 0x0a,
+	0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x2b, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63,
+	0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x65, 0x72, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68,
+	0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x69, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x1a, 0x42, 0x0a, 0x07, 0x57, 0x72, 0x69, 0x74, 0x69,
+	0x6e, 0x67, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x6c, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x68, 0x6f, 0x6c, 0x65, 0x1a, 0x1b, 0x0a, 0x07, 0x43,
 	0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x75, 0x6d, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x0c, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x42, 0x06, 0x0a, 0x04, 0x73, 0x74, 0x65, 0x70,
-	0x22, 0x37, 0x0a, 0x0d, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x4d,
-	0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x22, 0x83, 0x01, 0x0a, 0x0d, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x6d,
-	0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70,
-	0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d,
-	0x65, 0x74, 0x61, 0x12, 0x22, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74,
-	0x68, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x26, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31,
-	0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x22,
-	0x38, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x4d,
-	0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x2a, 0x28, 0x0a, 0x06, 0x48, 0x61, 0x73,
-	0x68, 0x65, 0x72, 0x12, 0x0b, 0x0a, 0x07, 0x69, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x10, 0x00,
-	0x12, 0x11, 0x0a, 0x0d, 0x62, 0x6c, 0x61, 0x6b, 0x65, 0x33, 0x5f, 0x36, 0x34, 0x5f, 0x32, 0x35,
-	0x36, 0x10, 0x01, 0x32, 0xc0, 0x05, 0x0a, 0x0b, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x58, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63,
-	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e,
-	0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79,
-	0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f,
-	0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a,
-	0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x21,
-	0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65,
-	0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x22, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x04, 0x53, 0x74, 0x61, 0x74, 0x12,
-	0x18, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74,
-	0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73, 0x76, 0x63, 0x2e,
-	0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x07, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69,
-	0x72, 0x12, 0x1b, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e,
-	0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
-	0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73,
-	0x74, 0x44, 0x69, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x55,
-	0x0a, 0x0c, 0x47, 0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x20,
-	0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
-	0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x21, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47,
-	0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65,
-	0x53, 0x75, 0x6d, 0x12, 0x1e, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76,
-	0x31, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76,
-	0x31, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x45, 0x0a, 0x06, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x12, 0x1a, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x73,
+	0x22, 0x38, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73,
+	0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x22, 0x57, 0x0a, 0x10, 0x47, 0x65,
+	0x74, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26,
+	0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x4d, 0x65, 0x74, 0x61,
+	0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61,
+	0x64, 0x49, 0x64, 0x22, 0x4f, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61,
+	0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61,
+	0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04,
+	0x73, 0x69, 0x7a, 0x65, 0x22, 0x81, 0x04, 0x0a, 0x0c, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x65, 0x71, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x3d, 0x0a,
+	0x07, 0x6f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21,
+	0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x69, 0x6e,
+	0x67, 0x48, 0x00, 0x52, 0x07, 0x6f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x40, 0x0a, 0x08,
+	0x70, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22,
+	0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x69,
+	0x6e, 0x67, 0x48, 0x00, 0x52, 0x08, 0x70, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x12, 0x3d,
+	0x0a, 0x07, 0x63, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x21, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x69,
+	0x6e, 0x67, 0x48, 0x00, 0x52, 0x07, 0x63, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x1a, 0xa7, 0x01,
+	0x0a, 0x07, 0x4f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x22, 0x0a, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x26, 0x0a,
+	0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x2b, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63,
+	0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x65, 0x72, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68,
+	0x65, 0x72, 0x12, 0x23, 0x0a, 0x03, 0x73, 0x75, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x53,
+	0x75, 0x6d, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x1a, 0x3a, 0x0a, 0x08, 0x50, 0x61, 0x74, 0x63, 0x68,
+	0x69, 0x6e, 0x67, 0x12, 0x2e, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69,
+	0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x05, 0x70, 0x61,
+	0x74, 0x63, 0x68, 0x1a, 0x1b, 0x0a, 0x07, 0x43, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x12, 0x10,
+	0x0a, 0x03, 0x73, 0x75, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x73, 0x75, 0x6d,
+	0x42, 0x06, 0x0a, 0x04, 0x73, 0x74, 0x65, 0x70, 0x22, 0x37, 0x0a, 0x0d, 0x50, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74,
+	0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74,
+	0x61, 0x22, 0x83, 0x01, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65,
+	0x71, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x22, 0x0a, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12,
+	0x26, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x22, 0x38, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74,
+	0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74,
+	0x61, 0x22, 0xe1, 0x01, 0x0a, 0x0b, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x4d,
+	0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x22, 0x0a, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2f, 0x0a,
+	0x09, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x66, 0x72, 0x6f, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x2d,
+	0x0a, 0x0a, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x74, 0x68, 0x52, 0x09, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x72, 0x12, 0x26, 0x0a,
+	0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x04, 0x69, 0x6e, 0x66, 0x6f, 0x22, 0x36, 0x0a, 0x0c, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x65, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x22, 0x8a, 0x01,
+	0x0a, 0x0f, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x4d,
+	0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x22, 0x0a, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x2b, 0x0a,
+	0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e,
+	0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61, 0x73, 0x68,
+	0x65, 0x72, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x22, 0xa9, 0x02, 0x0a, 0x10, 0x44,
+	0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x4d, 0x65, 0x74,
+	0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x41, 0x0a, 0x07, 0x77, 0x72, 0x69, 0x74, 0x69,
+	0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73,
+	0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x69, 0x6e, 0x67, 0x48,
+	0x00, 0x52, 0x07, 0x77, 0x72, 0x69, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x41, 0x0a, 0x07, 0x63, 0x6c,
+	0x6f, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x73, 0x76,
+	0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f,
+	0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x69,
+	0x6e, 0x67, 0x48, 0x00, 0x52, 0x07, 0x63, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x1a, 0x42, 0x0a,
+	0x07, 0x57, 0x72, 0x69, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x12, 0x0a,
+	0x04, 0x68, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x68, 0x6f, 0x6c,
+	0x65, 0x1a, 0x1b, 0x0a, 0x07, 0x43, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x12, 0x10, 0x0a, 0x03,
+	0x73, 0x75, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x42, 0x06,
+	0x0a, 0x04, 0x73, 0x74, 0x65, 0x70, 0x22, 0xb4, 0x01, 0x0a, 0x14, 0x44, 0x6f, 0x77, 0x6e, 0x6c,
+	0x6f, 0x61, 0x64, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x4d, 0x65, 0x74,
+	0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x22, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x2b, 0x0a, 0x06, 0x68,
+	0x61, 0x73, 0x68, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x73, 0x76,
+	0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x65, 0x72,
+	0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x03, 0x73, 0x75, 0x6d, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x22, 0xb3, 0x02,
+	0x0a, 0x15, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18,
+	0xe8, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x65, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12,
+	0x49, 0x0a, 0x08, 0x70, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2b, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e,
+	0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x48, 0x00,
+	0x52, 0x08, 0x70, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x12, 0x46, 0x0a, 0x07, 0x63, 0x6c,
+	0x6f, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x73, 0x76,
+	0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f,
+	0x61, 0x64, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e,
+	0x43, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x07, 0x63, 0x6c, 0x6f, 0x73, 0x69,
+	0x6e, 0x67, 0x1a, 0x3a, 0x0a, 0x08, 0x50, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x12, 0x2e,
+	0x0a, 0x05, 0x70, 0x61, 0x74, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x05, 0x70, 0x61, 0x74, 0x63, 0x68, 0x1a, 0x1b,
+	0x0a, 0x07, 0x43, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x75, 0x6d,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x42, 0x06, 0x0a, 0x04, 0x73,
+	0x74, 0x65, 0x70, 0x2a, 0x3c, 0x0a, 0x0a, 0x4e, 0x61, 0x6d, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x12, 0x09, 0x0a, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04,
+	0x77, 0x61, 0x72, 0x6e, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74,
+	0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x10,
+	0x03, 0x2a, 0x28, 0x0a, 0x06, 0x48, 0x61, 0x73, 0x68, 0x65, 0x72, 0x12, 0x0b, 0x0a, 0x07, 0x69,
+	0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x62, 0x6c, 0x61, 0x6b,
+	0x65, 0x33, 0x5f, 0x36, 0x34, 0x5f, 0x32, 0x35, 0x36, 0x10, 0x01, 0x32, 0xe3, 0x09, 0x0a, 0x0b,
+	0x53, 0x79, 0x6e, 0x63, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x58, 0x0a, 0x0d, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x2e, 0x73,
 	0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x42, 0x0a,
-	0x05, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x19, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e,
-	0x63, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x1a, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e,
-	0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28,
-	0x01, 0x12, 0x43, 0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x1a, 0x2e, 0x73, 0x76,
-	0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79,
-	0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0xa3, 0x01, 0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x2e, 0x73,
-	0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x42, 0x0c, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x34, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x79, 0x62, 0x61, 0x62, 0x74, 0x6d, 0x65, 0x2f,
-	0x73, 0x79, 0x6e, 0x63, 0x79, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x73, 0x76,
-	0x63, 0x2f, 0x73, 0x79, 0x6e, 0x63, 0x2f, 0x76, 0x31, 0x3b, 0x73, 0x79, 0x6e, 0x63, 0x76, 0x31,
-	0xa2, 0x02, 0x03, 0x53, 0x53, 0x58, 0xaa, 0x02, 0x0b, 0x53, 0x76, 0x63, 0x2e, 0x53, 0x79, 0x6e,
-	0x63, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x0b, 0x53, 0x76, 0x63, 0x5c, 0x53, 0x79, 0x6e, 0x63, 0x5c,
-	0x56, 0x31, 0xe2, 0x02, 0x17, 0x53, 0x76, 0x63, 0x5c, 0x53, 0x79, 0x6e, 0x63, 0x5c, 0x56, 0x31,
-	0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x0d, 0x53,
-	0x76, 0x63, 0x3a, 0x3a, 0x53, 0x79, 0x6e, 0x63, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x22, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50,
+	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x21, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e,
+	0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6a, 0x65,
+	0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x73, 0x76, 0x63, 0x2e,
+	0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x4f, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1e, 0x2e,
+	0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50,
+	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e,
+	0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50,
+	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x3d, 0x0a, 0x04, 0x53, 0x74, 0x61, 0x74, 0x12, 0x18, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73,
+	0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x46, 0x0a, 0x07, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x12, 0x1b, 0x2e, 0x73, 0x76, 0x63,
+	0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79,
+	0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x53, 0x69,
+	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x20, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79,
+	0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x73, 0x76, 0x63, 0x2e,
+	0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f,
+	0x0a, 0x0a, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d, 0x12, 0x1e, 0x2e, 0x73,
+	0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x69,
+	0x6c, 0x65, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x73,
+	0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x69,
+	0x6c, 0x65, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x46, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x54, 0x72, 0x65, 0x65, 0x12, 0x1b, 0x2e, 0x73, 0x76, 0x63,
+	0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x65, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79,
+	0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x46, 0x69,
+	0x6c, 0x65, 0x53, 0x75, 0x6d, 0x73, 0x12, 0x1f, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e,
+	0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79,
+	0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x45, 0x0a, 0x06, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x28, 0x01, 0x12, 0x4c, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x12,
+	0x1d, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e,
+	0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x42, 0x0a, 0x05, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x19, 0x2e, 0x73, 0x76, 0x63, 0x2e,
+	0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x28, 0x01, 0x12, 0x43, 0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x1a,
+	0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x73, 0x76, 0x63,
+	0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x04, 0x4d, 0x6f, 0x76,
+	0x65, 0x12, 0x18, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e,
+	0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73, 0x76,
+	0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4b, 0x0a, 0x08, 0x44, 0x6f, 0x77, 0x6e,
+	0x6c, 0x6f, 0x61, 0x64, 0x12, 0x1c, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x5a, 0x0a, 0x0d, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61,
+	0x64, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x21, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79, 0x6e,
+	0x63, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x73, 0x76, 0x63, 0x2e,
+	0x73, 0x79, 0x6e, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64,
+	0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30,
+	0x01, 0x42, 0xa3, 0x01, 0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x2e, 0x73, 0x76, 0x63, 0x2e, 0x73, 0x79,
+	0x6e, 0x63, 0x2e, 0x76, 0x31, 0x42, 0x0c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x34, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x61, 0x79, 0x62, 0x61, 0x62, 0x74, 0x6d, 0x65, 0x2f, 0x73, 0x79, 0x6e, 0x63, 0x79,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x73, 0x76, 0x63, 0x2f, 0x73, 0x79, 0x6e,
+	0x63, 0x2f, 0x76, 0x31, 0x3b, 0x73, 0x79, 0x6e, 0x63, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x53, 0x53,
+	0x58, 0xaa, 0x02, 0x0b, 0x53, 0x76, 0x63, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x2e, 0x56, 0x31, 0xca,
+	0x02, 0x0b, 0x53, 0x76, 0x63, 0x5c, 0x53, 0x79, 0x6e, 0x63, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x17,
+	0x53, 0x76, 0x63, 0x5c, 0x53, 0x79, 0x6e, 0x63, 0x5c, 0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x0d, 0x53, 0x76, 0x63, 0x3a, 0x3a, 0x53,
+	0x79, 0x6e, 0x63, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1766,109 +3099,175 @@ func file_svc_sync_v1_service_proto_rawDescGZIP() []byte {
 	return file_svc_sync_v1_service_proto_rawDescData
 }
 
-var file_svc_sync_v1_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_svc_sync_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
+var file_svc_sync_v1_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_svc_sync_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 44)
 var file_svc_sync_v1_service_proto_goTypes = []interface{}{
-	(Hasher)(0),                    // 0: svc.sync.v1.Hasher
-	(*CreateAccountRequest)(nil),   // 1: svc.sync.v1.CreateAccountRequest
-	(*CreateAccountResponse)(nil),  // 2: svc.sync.v1.CreateAccountResponse
-	(*CreateProjectRequest)(nil),   // 3: svc.sync.v1.CreateProjectRequest
-	(*CreateProjectResponse)(nil),  // 4: svc.sync.v1.CreateProjectResponse
-	(*GetRootRequest)(nil),         // 5: svc.sync.v1.GetRootRequest
-	(*GetRootResponse)(nil),        // 6: svc.sync.v1.GetRootResponse
-	(*StatRequest)(nil),            // 7: svc.sync.v1.StatRequest
-	(*StatResponse)(nil),           // 8: svc.sync.v1.StatResponse
-	(*ListDirRequest)(nil),         // 9: svc.sync.v1.ListDirRequest
-	(*ListDirResponse)(nil),        // 10: svc.sync.v1.ListDirResponse
-	(*GetSignatureRequest)(nil),    // 11: svc.sync.v1.GetSignatureRequest
-	(*GetSignatureResponse)(nil),   // 12: svc.sync.v1.GetSignatureResponse
-	(*GetFileSumRequest)(nil),      // 13: svc.sync.v1.GetFileSumRequest
-	(*GetFileSumResponse)(nil),     // 14: svc.sync.v1.GetFileSumResponse
-	(*CreateRequest)(nil),          // 15: svc.sync.v1.CreateRequest
-	(*CreateResponse)(nil),         // 16: svc.sync.v1.CreateResponse
-	(*PatchRequest)(nil),           // 17: svc.sync.v1.PatchRequest
-	(*PatchResponse)(nil),          // 18: svc.sync.v1.PatchResponse
-	(*DeleteRequest)(nil),          // 19: svc.sync.v1.DeleteRequest
-	(*DeleteResponse)(nil),         // 20: svc.sync.v1.DeleteResponse
-	(*CreateRequest_Creating)(nil), // 21: svc.sync.v1.CreateRequest.Creating
-	(*CreateRequest_Writing)(nil),  // 22: svc.sync.v1.CreateRequest.Writing
-	(*CreateRequest_Closing)(nil),  // 23: svc.sync.v1.CreateRequest.Closing
-	(*PatchRequest_Opening)(nil),   // 24: svc.sync.v1.PatchRequest.Opening
-	(*PatchRequest_Patching)(nil),  // 25: svc.sync.v1.PatchRequest.Patching
-	(*PatchRequest_Closing)(nil),   // 26: svc.sync.v1.PatchRequest.Closing
-	(*v1.ReqMeta)(nil),             // 27: types.v1.ReqMeta
-	(*v1.ResMeta)(nil),             // 28: types.v1.ResMeta
-	(*v1.Dir)(nil),                 // 29: types.v1.Dir
-	(*v1.Path)(nil),                // 30: types.v1.Path
-	(*v1.FileInfo)(nil),            // 31: types.v1.FileInfo
-	(*v1.DirSum)(nil),              // 32: types.v1.DirSum
-	(*v1.FileSum)(nil),             // 33: types.v1.FileSum
-	(*v1.FileBlockPatch)(nil),      // 34: types.v1.FileBlockPatch
+	(NamePolicy)(0),                        // 0: svc.sync.v1.NamePolicy
+	(Hasher)(0),                            // 1: svc.sync.v1.Hasher
+	(*CreateAccountRequest)(nil),           // 2: svc.sync.v1.CreateAccountRequest
+	(*CreateAccountResponse)(nil),          // 3: svc.sync.v1.CreateAccountResponse
+	(*CreateProjectRequest)(nil),           // 4: svc.sync.v1.CreateProjectRequest
+	(*CreateProjectResponse)(nil),          // 5: svc.sync.v1.CreateProjectResponse
+	(*GetProjectRequest)(nil),              // 6: svc.sync.v1.GetProjectRequest
+	(*GetProjectResponse)(nil),             // 7: svc.sync.v1.GetProjectResponse
+	(*GetRootRequest)(nil),                 // 8: svc.sync.v1.GetRootRequest
+	(*GetRootResponse)(nil),                // 9: svc.sync.v1.GetRootResponse
+	(*StatRequest)(nil),                    // 10: svc.sync.v1.StatRequest
+	(*StatResponse)(nil),                   // 11: svc.sync.v1.StatResponse
+	(*ListDirRequest)(nil),                 // 12: svc.sync.v1.ListDirRequest
+	(*ListDirResponse)(nil),                // 13: svc.sync.v1.ListDirResponse
+	(*GetSignatureRequest)(nil),            // 14: svc.sync.v1.GetSignatureRequest
+	(*GetSignatureResponse)(nil),           // 15: svc.sync.v1.GetSignatureResponse
+	(*GetFileSumRequest)(nil),              // 16: svc.sync.v1.GetFileSumRequest
+	(*GetFileSumResponse)(nil),             // 17: svc.sync.v1.GetFileSumResponse
+	(*GetTreeRequest)(nil),                 // 18: svc.sync.v1.GetTreeRequest
+	(*GetTreeResponse)(nil),                // 19: svc.sync.v1.GetTreeResponse
+	(*GetFileSumsRequest)(nil),             // 20: svc.sync.v1.GetFileSumsRequest
+	(*GetFileSumsResponse)(nil),            // 21: svc.sync.v1.GetFileSumsResponse
+	(*CreateRequest)(nil),                  // 22: svc.sync.v1.CreateRequest
+	(*CreateResponse)(nil),                 // 23: svc.sync.v1.CreateResponse
+	(*GetUploadRequest)(nil),               // 24: svc.sync.v1.GetUploadRequest
+	(*GetUploadResponse)(nil),              // 25: svc.sync.v1.GetUploadResponse
+	(*PatchRequest)(nil),                   // 26: svc.sync.v1.PatchRequest
+	(*PatchResponse)(nil),                  // 27: svc.sync.v1.PatchResponse
+	(*DeleteRequest)(nil),                  // 28: svc.sync.v1.DeleteRequest
+	(*DeleteResponse)(nil),                 // 29: svc.sync.v1.DeleteResponse
+	(*MoveRequest)(nil),                    // 30: svc.sync.v1.MoveRequest
+	(*MoveResponse)(nil),                   // 31: svc.sync.v1.MoveResponse
+	(*DownloadRequest)(nil),                // 32: svc.sync.v1.DownloadRequest
+	(*DownloadResponse)(nil),               // 33: svc.sync.v1.DownloadResponse
+	(*DownloadPatchRequest)(nil),           // 34: svc.sync.v1.DownloadPatchRequest
+	(*DownloadPatchResponse)(nil),          // 35: svc.sync.v1.DownloadPatchResponse
+	(*CreateRequest_Creating)(nil),         // 36: svc.sync.v1.CreateRequest.Creating
+	(*CreateRequest_Writing)(nil),          // 37: svc.sync.v1.CreateRequest.Writing
+	(*CreateRequest_Closing)(nil),          // 38: svc.sync.v1.CreateRequest.Closing
+	(*PatchRequest_Opening)(nil),           // 39: svc.sync.v1.PatchRequest.Opening
+	(*PatchRequest_Patching)(nil),          // 40: svc.sync.v1.PatchRequest.Patching
+	(*PatchRequest_Closing)(nil),           // 41: svc.sync.v1.PatchRequest.Closing
+	(*DownloadResponse_Writing)(nil),       // 42: svc.sync.v1.DownloadResponse.Writing
+	(*DownloadResponse_Closing)(nil),       // 43: svc.sync.v1.DownloadResponse.Closing
+	(*DownloadPatchResponse_Patching)(nil), // 44: svc.sync.v1.DownloadPatchResponse.Patching
+	(*DownloadPatchResponse_Closing)(nil),  // 45: svc.sync.v1.DownloadPatchResponse.Closing
+	(*v1.ReqMeta)(nil),                     // 46: types.v1.ReqMeta
+	(*v1.ResMeta)(nil),                     // 47: types.v1.ResMeta
+	(*v1.Dir)(nil),                         // 48: types.v1.Dir
+	(*v1.Path)(nil),                        // 49: types.v1.Path
+	(*v1.FileInfo)(nil),                    // 50: types.v1.FileInfo
+	(*v1.DirSum)(nil),                      // 51: types.v1.DirSum
+	(*v1.FileSum)(nil),                     // 52: types.v1.FileSum
+	(*v1.FileBlockPatch)(nil),              // 53: types.v1.FileBlockPatch
 }
 var file_svc_sync_v1_service_proto_depIdxs = []int32{
-	27, // 0: svc.sync.v1.GetRootRequest.meta:type_name -> types.v1.ReqMeta
-	28, // 1: svc.sync.v1.GetRootResponse.meta:type_name -> types.v1.ResMeta
-	29, // 2: svc.sync.v1.GetRootResponse.root:type_name -> types.v1.Dir
-	27, // 3: svc.sync.v1.StatRequest.meta:type_name -> types.v1.ReqMeta
-	30, // 4: svc.sync.v1.StatRequest.path:type_name -> types.v1.Path
-	28, // 5: svc.sync.v1.StatResponse.meta:type_name -> types.v1.ResMeta
-	31, // 6: svc.sync.v1.StatResponse.info:type_name -> types.v1.FileInfo
-	27, // 7: svc.sync.v1.ListDirRequest.meta:type_name -> types.v1.ReqMeta
-	30, // 8: svc.sync.v1.ListDirRequest.path:type_name -> types.v1.Path
-	28, // 9: svc.sync.v1.ListDirResponse.meta:type_name -> types.v1.ResMeta
-	31, // 10: svc.sync.v1.ListDirResponse.dir_entries:type_name -> types.v1.FileInfo
-	27, // 11: svc.sync.v1.GetSignatureRequest.meta:type_name -> types.v1.ReqMeta
-	28, // 12: svc.sync.v1.GetSignatureResponse.meta:type_name -> types.v1.ResMeta
-	32, // 13: svc.sync.v1.GetSignatureResponse.root:type_name -> types.v1.DirSum
-	27, // 14: svc.sync.v1.GetFileSumRequest.meta:type_name -> types.v1.ReqMeta
-	30, // 15: svc.sync.v1.GetFileSumRequest.path:type_name -> types.v1.Path
-	28, // 16: svc.sync.v1.GetFileSumResponse.meta:type_name -> types.v1.ResMeta
-	33, // 17: svc.sync.v1.GetFileSumResponse.sum:type_name -> types.v1.FileSum
-	27, // 18: svc.sync.v1.CreateRequest.meta:type_name -> types.v1.ReqMeta
-	21, // 19: svc.sync.v1.CreateRequest.creating:type_name -> svc.sync.v1.CreateRequest.Creating
-	22, // 20: svc.sync.v1.CreateRequest.writing:type_name -> svc.sync.v1.CreateRequest.Writing
-	23, // 21: svc.sync.v1.CreateRequest.closing:type_name -> svc.sync.v1.CreateRequest.Closing
-	28, // 22: svc.sync.v1.CreateResponse.meta:type_name -> types.v1.ResMeta
-	27, // 23: svc.sync.v1.PatchRequest.meta:type_name -> types.v1.ReqMeta
-	24, // 24: svc.sync.v1.PatchRequest.opening:type_name -> svc.sync.v1.PatchRequest.Opening
-	25, // 25: svc.sync.v1.PatchRequest.patching:type_name -> svc.sync.v1.PatchRequest.Patching
-	26, // 26: svc.sync.v1.PatchRequest.closing:type_name -> svc.sync.v1.PatchRequest.Closing
-	28, // 27: svc.sync.v1.PatchResponse.meta:type_name -> types.v1.ResMeta
-	27, // 28: svc.sync.v1.DeleteRequest.meta:type_name -> types.v1.ReqMeta
-	30, // 29: svc.sync.v1.DeleteRequest.path:type_name -> types.v1.Path
-	31, // 30: svc.sync.v1.DeleteRequest.info:type_name -> types.v1.FileInfo
-	28, // 31: svc.sync.v1.DeleteResponse.meta:type_name -> types.v1.ResMeta
-	30, // 32: svc.sync.v1.CreateRequest.Creating.path:type_name -> types.v1.Path
-	31, // 33: svc.sync.v1.CreateRequest.Creating.info:type_name -> types.v1.FileInfo
-	0,  // 34: svc.sync.v1.CreateRequest.Creating.hasher:type_name -> svc.sync.v1.Hasher
-	30, // 35: svc.sync.v1.PatchRequest.Opening.path:type_name -> types.v1.Path
-	31, // 36: svc.sync.v1.PatchRequest.Opening.info:type_name -> types.v1.FileInfo
-	0,  // 37: svc.sync.v1.PatchRequest.Opening.hasher:type_name -> svc.sync.v1.Hasher
-	33, // 38: svc.sync.v1.PatchRequest.Opening.sum:type_name -> types.v1.FileSum
-	34, // 39: svc.sync.v1.PatchRequest.Patching.patch:type_name -> types.v1.FileBlockPatch
-	1,  // 40: svc.sync.v1.SyncService.CreateAccount:input_type -> svc.sync.v1.CreateAccountRequest
-	3,  // 41: svc.sync.v1.SyncService.CreateProject:input_type -> svc.sync.v1.CreateProjectRequest
-	7,  // 42: svc.sync.v1.SyncService.Stat:input_type -> svc.sync.v1.StatRequest
-	9,  // 43: svc.sync.v1.SyncService.ListDir:input_type -> svc.sync.v1.ListDirRequest
-	11, // 44: svc.sync.v1.SyncService.GetSignature:input_type -> svc.sync.v1.GetSignatureRequest
-	13, // 45: svc.sync.v1.SyncService.GetFileSum:input_type -> svc.sync.v1.GetFileSumRequest
-	15, // 46: svc.sync.v1.SyncService.Create:input_type -> svc.sync.v1.CreateRequest
-	17, // 47: svc.sync.v1.SyncService.Patch:input_type -> svc.sync.v1.PatchRequest
-	19, // 48: svc.sync.v1.SyncService.Delete:input_type -> svc.sync.v1.DeleteRequest
-	2,  // 49: svc.sync.v1.SyncService.CreateAccount:output_type -> svc.sync.v1.CreateAccountResponse
-	4,  // 50: svc.sync.v1.SyncService.CreateProject:output_type -> svc.sync.v1.CreateProjectResponse
-	8,  // 51: svc.sync.v1.SyncService.Stat:output_type -> svc.sync.v1.StatResponse
-	10, // 52: svc.sync.v1.SyncService.ListDir:output_type -> svc.sync.v1.ListDirResponse
-	12, // 53: svc.sync.v1.SyncService.GetSignature:output_type -> svc.sync.v1.GetSignatureResponse
-	14, // 54: svc.sync.v1.SyncService.GetFileSum:output_type -> svc.sync.v1.GetFileSumResponse
-	16, // 55: svc.sync.v1.SyncService.Create:output_type -> svc.sync.v1.CreateResponse
-	18, // 56: svc.sync.v1.SyncService.Patch:output_type -> svc.sync.v1.PatchResponse
-	20, // 57: svc.sync.v1.SyncService.Delete:output_type -> svc.sync.v1.DeleteResponse
-	49, // [49:58] is the sub-list for method output_type
-	40, // [40:49] is the sub-list for method input_type
-	40, // [40:40] is the sub-list for extension type_name
-	40, // [40:40] is the sub-list for extension extendee
-	0,  // [0:40] is the sub-list for field type_name
+	0,  // 0: svc.sync.v1.CreateProjectRequest.name_policy:type_name -> svc.sync.v1.NamePolicy
+	46, // 1: svc.sync.v1.GetProjectRequest.meta:type_name -> types.v1.ReqMeta
+	47, // 2: svc.sync.v1.GetProjectResponse.meta:type_name -> types.v1.ResMeta
+	0,  // 3: svc.sync.v1.GetProjectResponse.name_policy:type_name -> svc.sync.v1.NamePolicy
+	46, // 4: svc.sync.v1.GetRootRequest.meta:type_name -> types.v1.ReqMeta
+	47, // 5: svc.sync.v1.GetRootResponse.meta:type_name -> types.v1.ResMeta
+	48, // 6: svc.sync.v1.GetRootResponse.root:type_name -> types.v1.Dir
+	46, // 7: svc.sync.v1.StatRequest.meta:type_name -> types.v1.ReqMeta
+	49, // 8: svc.sync.v1.StatRequest.path:type_name -> types.v1.Path
+	47, // 9: svc.sync.v1.StatResponse.meta:type_name -> types.v1.ResMeta
+	50, // 10: svc.sync.v1.StatResponse.info:type_name -> types.v1.FileInfo
+	46, // 11: svc.sync.v1.ListDirRequest.meta:type_name -> types.v1.ReqMeta
+	49, // 12: svc.sync.v1.ListDirRequest.path:type_name -> types.v1.Path
+	47, // 13: svc.sync.v1.ListDirResponse.meta:type_name -> types.v1.ResMeta
+	50, // 14: svc.sync.v1.ListDirResponse.dir_entries:type_name -> types.v1.FileInfo
+	46, // 15: svc.sync.v1.GetSignatureRequest.meta:type_name -> types.v1.ReqMeta
+	47, // 16: svc.sync.v1.GetSignatureResponse.meta:type_name -> types.v1.ResMeta
+	51, // 17: svc.sync.v1.GetSignatureResponse.root:type_name -> types.v1.DirSum
+	46, // 18: svc.sync.v1.GetFileSumRequest.meta:type_name -> types.v1.ReqMeta
+	49, // 19: svc.sync.v1.GetFileSumRequest.path:type_name -> types.v1.Path
+	47, // 20: svc.sync.v1.GetFileSumResponse.meta:type_name -> types.v1.ResMeta
+	52, // 21: svc.sync.v1.GetFileSumResponse.sum:type_name -> types.v1.FileSum
+	46, // 22: svc.sync.v1.GetTreeRequest.meta:type_name -> types.v1.ReqMeta
+	47, // 23: svc.sync.v1.GetTreeResponse.meta:type_name -> types.v1.ResMeta
+	51, // 24: svc.sync.v1.GetTreeResponse.root:type_name -> types.v1.DirSum
+	46, // 25: svc.sync.v1.GetFileSumsRequest.meta:type_name -> types.v1.ReqMeta
+	49, // 26: svc.sync.v1.GetFileSumsRequest.paths:type_name -> types.v1.Path
+	47, // 27: svc.sync.v1.GetFileSumsResponse.meta:type_name -> types.v1.ResMeta
+	52, // 28: svc.sync.v1.GetFileSumsResponse.sums:type_name -> types.v1.FileSum
+	46, // 29: svc.sync.v1.CreateRequest.meta:type_name -> types.v1.ReqMeta
+	36, // 30: svc.sync.v1.CreateRequest.creating:type_name -> svc.sync.v1.CreateRequest.Creating
+	37, // 31: svc.sync.v1.CreateRequest.writing:type_name -> svc.sync.v1.CreateRequest.Writing
+	38, // 32: svc.sync.v1.CreateRequest.closing:type_name -> svc.sync.v1.CreateRequest.Closing
+	47, // 33: svc.sync.v1.CreateResponse.meta:type_name -> types.v1.ResMeta
+	46, // 34: svc.sync.v1.GetUploadRequest.meta:type_name -> types.v1.ReqMeta
+	47, // 35: svc.sync.v1.GetUploadResponse.meta:type_name -> types.v1.ResMeta
+	46, // 36: svc.sync.v1.PatchRequest.meta:type_name -> types.v1.ReqMeta
+	39, // 37: svc.sync.v1.PatchRequest.opening:type_name -> svc.sync.v1.PatchRequest.Opening
+	40, // 38: svc.sync.v1.PatchRequest.patching:type_name -> svc.sync.v1.PatchRequest.Patching
+	41, // 39: svc.sync.v1.PatchRequest.closing:type_name -> svc.sync.v1.PatchRequest.Closing
+	47, // 40: svc.sync.v1.PatchResponse.meta:type_name -> types.v1.ResMeta
+	46, // 41: svc.sync.v1.DeleteRequest.meta:type_name -> types.v1.ReqMeta
+	49, // 42: svc.sync.v1.DeleteRequest.path:type_name -> types.v1.Path
+	50, // 43: svc.sync.v1.DeleteRequest.info:type_name -> types.v1.FileInfo
+	47, // 44: svc.sync.v1.DeleteResponse.meta:type_name -> types.v1.ResMeta
+	46, // 45: svc.sync.v1.MoveRequest.meta:type_name -> types.v1.ReqMeta
+	49, // 46: svc.sync.v1.MoveRequest.from:type_name -> types.v1.Path
+	50, // 47: svc.sync.v1.MoveRequest.from_info:type_name -> types.v1.FileInfo
+	49, // 48: svc.sync.v1.MoveRequest.parent_dir:type_name -> types.v1.Path
+	50, // 49: svc.sync.v1.MoveRequest.info:type_name -> types.v1.FileInfo
+	47, // 50: svc.sync.v1.MoveResponse.meta:type_name -> types.v1.ResMeta
+	46, // 51: svc.sync.v1.DownloadRequest.meta:type_name -> types.v1.ReqMeta
+	49, // 52: svc.sync.v1.DownloadRequest.path:type_name -> types.v1.Path
+	1,  // 53: svc.sync.v1.DownloadRequest.hasher:type_name -> svc.sync.v1.Hasher
+	47, // 54: svc.sync.v1.DownloadResponse.meta:type_name -> types.v1.ResMeta
+	42, // 55: svc.sync.v1.DownloadResponse.writing:type_name -> svc.sync.v1.DownloadResponse.Writing
+	43, // 56: svc.sync.v1.DownloadResponse.closing:type_name -> svc.sync.v1.DownloadResponse.Closing
+	46, // 57: svc.sync.v1.DownloadPatchRequest.meta:type_name -> types.v1.ReqMeta
+	49, // 58: svc.sync.v1.DownloadPatchRequest.path:type_name -> types.v1.Path
+	1,  // 59: svc.sync.v1.DownloadPatchRequest.hasher:type_name -> svc.sync.v1.Hasher
+	52, // 60: svc.sync.v1.DownloadPatchRequest.sum:type_name -> types.v1.FileSum
+	47, // 61: svc.sync.v1.DownloadPatchResponse.meta:type_name -> types.v1.ResMeta
+	44, // 62: svc.sync.v1.DownloadPatchResponse.patching:type_name -> svc.sync.v1.DownloadPatchResponse.Patching
+	45, // 63: svc.sync.v1.DownloadPatchResponse.closing:type_name -> svc.sync.v1.DownloadPatchResponse.Closing
+	49, // 64: svc.sync.v1.CreateRequest.Creating.path:type_name -> types.v1.Path
+	50, // 65: svc.sync.v1.CreateRequest.Creating.info:type_name -> types.v1.FileInfo
+	1,  // 66: svc.sync.v1.CreateRequest.Creating.hasher:type_name -> svc.sync.v1.Hasher
+	49, // 67: svc.sync.v1.PatchRequest.Opening.path:type_name -> types.v1.Path
+	50, // 68: svc.sync.v1.PatchRequest.Opening.info:type_name -> types.v1.FileInfo
+	1,  // 69: svc.sync.v1.PatchRequest.Opening.hasher:type_name -> svc.sync.v1.Hasher
+	52, // 70: svc.sync.v1.PatchRequest.Opening.sum:type_name -> types.v1.FileSum
+	53, // 71: svc.sync.v1.PatchRequest.Patching.patch:type_name -> types.v1.FileBlockPatch
+	53, // 72: svc.sync.v1.DownloadPatchResponse.Patching.patch:type_name -> types.v1.FileBlockPatch
+	2,  // 73: svc.sync.v1.SyncService.CreateAccount:input_type -> svc.sync.v1.CreateAccountRequest
+	4,  // 74: svc.sync.v1.SyncService.CreateProject:input_type -> svc.sync.v1.CreateProjectRequest
+	6,  // 75: svc.sync.v1.SyncService.GetProject:input_type -> svc.sync.v1.GetProjectRequest
+	10, // 76: svc.sync.v1.SyncService.Stat:input_type -> svc.sync.v1.StatRequest
+	12, // 77: svc.sync.v1.SyncService.ListDir:input_type -> svc.sync.v1.ListDirRequest
+	14, // 78: svc.sync.v1.SyncService.GetSignature:input_type -> svc.sync.v1.GetSignatureRequest
+	16, // 79: svc.sync.v1.SyncService.GetFileSum:input_type -> svc.sync.v1.GetFileSumRequest
+	18, // 80: svc.sync.v1.SyncService.GetTree:input_type -> svc.sync.v1.GetTreeRequest
+	20, // 81: svc.sync.v1.SyncService.GetFileSums:input_type -> svc.sync.v1.GetFileSumsRequest
+	22, // 82: svc.sync.v1.SyncService.Create:input_type -> svc.sync.v1.CreateRequest
+	24, // 83: svc.sync.v1.SyncService.GetUpload:input_type -> svc.sync.v1.GetUploadRequest
+	26, // 84: svc.sync.v1.SyncService.Patch:input_type -> svc.sync.v1.PatchRequest
+	28, // 85: svc.sync.v1.SyncService.Delete:input_type -> svc.sync.v1.DeleteRequest
+	30, // 86: svc.sync.v1.SyncService.Move:input_type -> svc.sync.v1.MoveRequest
+	32, // 87: svc.sync.v1.SyncService.Download:input_type -> svc.sync.v1.DownloadRequest
+	34, // 88: svc.sync.v1.SyncService.DownloadPatch:input_type -> svc.sync.v1.DownloadPatchRequest
+	3,  // 89: svc.sync.v1.SyncService.CreateAccount:output_type -> svc.sync.v1.CreateAccountResponse
+	5,  // 90: svc.sync.v1.SyncService.CreateProject:output_type -> svc.sync.v1.CreateProjectResponse
+	7,  // 91: svc.sync.v1.SyncService.GetProject:output_type -> svc.sync.v1.GetProjectResponse
+	11, // 92: svc.sync.v1.SyncService.Stat:output_type -> svc.sync.v1.StatResponse
+	13, // 93: svc.sync.v1.SyncService.ListDir:output_type -> svc.sync.v1.ListDirResponse
+	15, // 94: svc.sync.v1.SyncService.GetSignature:output_type -> svc.sync.v1.GetSignatureResponse
+	17, // 95: svc.sync.v1.SyncService.GetFileSum:output_type -> svc.sync.v1.GetFileSumResponse
+	19, // 96: svc.sync.v1.SyncService.GetTree:output_type -> svc.sync.v1.GetTreeResponse
+	21, // 97: svc.sync.v1.SyncService.GetFileSums:output_type -> svc.sync.v1.GetFileSumsResponse
+	23, // 98: svc.sync.v1.SyncService.Create:output_type -> svc.sync.v1.CreateResponse
+	25, // 99: svc.sync.v1.SyncService.GetUpload:output_type -> svc.sync.v1.GetUploadResponse
+	27, // 100: svc.sync.v1.SyncService.Patch:output_type -> svc.sync.v1.PatchResponse
+	29, // 101: svc.sync.v1.SyncService.Delete:output_type -> svc.sync.v1.DeleteResponse
+	31, // 102: svc.sync.v1.SyncService.Move:output_type -> svc.sync.v1.MoveResponse
+	33, // 103: svc.sync.v1.SyncService.Download:output_type -> svc.sync.v1.DownloadResponse
+	35, // 104: svc.sync.v1.SyncService.DownloadPatch:output_type -> svc.sync.v1.DownloadPatchResponse
+	89, // [89:105] is the sub-list for method output_type
+	73, // [73:89] is the sub-list for method input_type
+	73, // [73:73] is the sub-list for extension type_name
+	73, // [73:73] is the sub-list for extension extendee
+	0,  // [0:73] is the sub-list for field type_name
 }
 
 func init() { file_svc_sync_v1_service_proto_init() }
@@ -1926,7 +3325,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetRootRequest); i {
+			switch v := v.(*GetProjectRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1938,7 +3337,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetRootResponse); i {
+			switch v := v.(*GetProjectResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1950,7 +3349,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StatRequest); i {
+			switch v := v.(*GetRootRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1962,7 +3361,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StatResponse); i {
+			switch v := v.(*GetRootResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1974,7 +3373,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListDirRequest); i {
+			switch v := v.(*StatRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1986,7 +3385,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListDirResponse); i {
+			switch v := v.(*StatResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1998,7 +3397,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetSignatureRequest); i {
+			switch v := v.(*ListDirRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2010,7 +3409,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetSignatureResponse); i {
+			switch v := v.(*ListDirResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2022,7 +3421,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetFileSumRequest); i {
+			switch v := v.(*GetSignatureRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2034,7 +3433,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetFileSumResponse); i {
+			switch v := v.(*GetSignatureResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2046,7 +3445,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateRequest); i {
+			switch v := v.(*GetFileSumRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2058,7 +3457,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateResponse); i {
+			switch v := v.(*GetFileSumResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2070,7 +3469,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PatchRequest); i {
+			switch v := v.(*GetTreeRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2082,7 +3481,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PatchResponse); i {
+			switch v := v.(*GetTreeResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2094,7 +3493,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteRequest); i {
+			switch v := v.(*GetFileSumsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2106,7 +3505,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteResponse); i {
+			switch v := v.(*GetFileSumsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2118,7 +3517,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateRequest_Creating); i {
+			switch v := v.(*CreateRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2130,7 +3529,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateRequest_Writing); i {
+			switch v := v.(*CreateResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2142,7 +3541,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateRequest_Closing); i {
+			switch v := v.(*GetUploadRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2154,7 +3553,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PatchRequest_Opening); i {
+			switch v := v.(*GetUploadResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2166,7 +3565,7 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PatchRequest_Patching); i {
+			switch v := v.(*PatchRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2178,6 +3577,174 @@ func file_svc_sync_v1_service_proto_init() {
 			}
 		}
 		file_svc_sync_v1_service_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MoveRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MoveResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownloadRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownloadResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownloadPatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownloadPatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateRequest_Creating); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateRequest_Writing); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateRequest_Closing); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatchRequest_Opening); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatchRequest_Patching); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PatchRequest_Closing); i {
 			case 0:
 				return &v.state
@@ -2189,24 +3756,80 @@ func file_svc_sync_v1_service_proto_init() {
 				return nil
 			}
 		}
+		file_svc_sync_v1_service_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownloadResponse_Writing); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownloadResponse_Closing); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownloadPatchResponse_Patching); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_svc_sync_v1_service_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownloadPatchResponse_Closing); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_svc_sync_v1_service_proto_msgTypes[14].OneofWrappers = []interface{}{
+	file_svc_sync_v1_service_proto_msgTypes[20].OneofWrappers = []interface{}{
 		(*CreateRequest_Creating_)(nil),
 		(*CreateRequest_Writing_)(nil),
 		(*CreateRequest_Closing_)(nil),
 	}
-	file_svc_sync_v1_service_proto_msgTypes[16].OneofWrappers = []interface{}{
+	file_svc_sync_v1_service_proto_msgTypes[24].OneofWrappers = []interface{}{
 		(*PatchRequest_Opening_)(nil),
 		(*PatchRequest_Patching_)(nil),
 		(*PatchRequest_Closing_)(nil),
 	}
+	file_svc_sync_v1_service_proto_msgTypes[31].OneofWrappers = []interface{}{
+		(*DownloadResponse_Writing_)(nil),
+		(*DownloadResponse_Closing_)(nil),
+	}
+	file_svc_sync_v1_service_proto_msgTypes[33].OneofWrappers = []interface{}{
+		(*DownloadPatchResponse_Patching_)(nil),
+		(*DownloadPatchResponse_Closing_)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_svc_sync_v1_service_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   26,
+			NumEnums:      2,
+			NumMessages:   44,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -39,6 +39,8 @@ const (
 	// SyncServiceCreateProjectProcedure is the fully-qualified name of the SyncService's CreateProject
 	// RPC.
 	SyncServiceCreateProjectProcedure = "/svc.sync.v1.SyncService/CreateProject"
+	// SyncServiceGetProjectProcedure is the fully-qualified name of the SyncService's GetProject RPC.
+	SyncServiceGetProjectProcedure = "/svc.sync.v1.SyncService/GetProject"
 	// SyncServiceStatProcedure is the fully-qualified name of the SyncService's Stat RPC.
 	SyncServiceStatProcedure = "/svc.sync.v1.SyncService/Stat"
 	// SyncServiceListDirProcedure is the fully-qualified name of the SyncService's ListDir RPC.
@@ -48,12 +50,25 @@ const (
 	SyncServiceGetSignatureProcedure = "/svc.sync.v1.SyncService/GetSignature"
 	// SyncServiceGetFileSumProcedure is the fully-qualified name of the SyncService's GetFileSum RPC.
 	SyncServiceGetFileSumProcedure = "/svc.sync.v1.SyncService/GetFileSum"
+	// SyncServiceGetTreeProcedure is the fully-qualified name of the SyncService's GetTree RPC.
+	SyncServiceGetTreeProcedure = "/svc.sync.v1.SyncService/GetTree"
+	// SyncServiceGetFileSumsProcedure is the fully-qualified name of the SyncService's GetFileSums RPC.
+	SyncServiceGetFileSumsProcedure = "/svc.sync.v1.SyncService/GetFileSums"
 	// SyncServiceCreateProcedure is the fully-qualified name of the SyncService's Create RPC.
 	SyncServiceCreateProcedure = "/svc.sync.v1.SyncService/Create"
+	// SyncServiceGetUploadProcedure is the fully-qualified name of the SyncService's GetUpload RPC.
+	SyncServiceGetUploadProcedure = "/svc.sync.v1.SyncService/GetUpload"
 	// SyncServicePatchProcedure is the fully-qualified name of the SyncService's Patch RPC.
 	SyncServicePatchProcedure = "/svc.sync.v1.SyncService/Patch"
 	// SyncServiceDeleteProcedure is the fully-qualified name of the SyncService's Delete RPC.
 	SyncServiceDeleteProcedure = "/svc.sync.v1.SyncService/Delete"
+	// SyncServiceMoveProcedure is the fully-qualified name of the SyncService's Move RPC.
+	SyncServiceMoveProcedure = "/svc.sync.v1.SyncService/Move"
+	// SyncServiceDownloadProcedure is the fully-qualified name of the SyncService's Download RPC.
+	SyncServiceDownloadProcedure = "/svc.sync.v1.SyncService/Download"
+	// SyncServiceDownloadPatchProcedure is the fully-qualified name of the SyncService's DownloadPatch
+	// RPC.
+	SyncServiceDownloadPatchProcedure = "/svc.sync.v1.SyncService/DownloadPatch"
 )
 
 // These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
@@ -61,13 +76,20 @@ var (
 	syncServiceServiceDescriptor             = v1.File_svc_sync_v1_service_proto.Services().ByName("SyncService")
 	syncServiceCreateAccountMethodDescriptor = syncServiceServiceDescriptor.Methods().ByName("CreateAccount")
 	syncServiceCreateProjectMethodDescriptor = syncServiceServiceDescriptor.Methods().ByName("CreateProject")
+	syncServiceGetProjectMethodDescriptor    = syncServiceServiceDescriptor.Methods().ByName("GetProject")
 	syncServiceStatMethodDescriptor          = syncServiceServiceDescriptor.Methods().ByName("Stat")
 	syncServiceListDirMethodDescriptor       = syncServiceServiceDescriptor.Methods().ByName("ListDir")
 	syncServiceGetSignatureMethodDescriptor  = syncServiceServiceDescriptor.Methods().ByName("GetSignature")
 	syncServiceGetFileSumMethodDescriptor    = syncServiceServiceDescriptor.Methods().ByName("GetFileSum")
+	syncServiceGetTreeMethodDescriptor       = syncServiceServiceDescriptor.Methods().ByName("GetTree")
+	syncServiceGetFileSumsMethodDescriptor   = syncServiceServiceDescriptor.Methods().ByName("GetFileSums")
 	syncServiceCreateMethodDescriptor        = syncServiceServiceDescriptor.Methods().ByName("Create")
+	syncServiceGetUploadMethodDescriptor     = syncServiceServiceDescriptor.Methods().ByName("GetUpload")
 	syncServicePatchMethodDescriptor         = syncServiceServiceDescriptor.Methods().ByName("Patch")
 	syncServiceDeleteMethodDescriptor        = syncServiceServiceDescriptor.Methods().ByName("Delete")
+	syncServiceMoveMethodDescriptor          = syncServiceServiceDescriptor.Methods().ByName("Move")
+	syncServiceDownloadMethodDescriptor      = syncServiceServiceDescriptor.Methods().ByName("Download")
+	syncServiceDownloadPatchMethodDescriptor = syncServiceServiceDescriptor.Methods().ByName("DownloadPatch")
 )
 
 // SyncServiceClient is a client for the svc.sync.v1.SyncService service.
@@ -75,6 +97,7 @@ type SyncServiceClient interface {
 	// mgmt
 	CreateAccount(context.Context, *connect.Request[v1.CreateAccountRequest]) (*connect.Response[v1.CreateAccountResponse], error)
 	CreateProject(context.Context, *connect.Request[v1.CreateProjectRequest]) (*connect.Response[v1.CreateProjectResponse], error)
+	GetProject(context.Context, *connect.Request[v1.GetProjectRequest]) (*connect.Response[v1.GetProjectResponse], error)
 	// info
 	Stat(context.Context, *connect.Request[v1.StatRequest]) (*connect.Response[v1.StatResponse], error)
 	ListDir(context.Context, *connect.Request[v1.ListDirRequest]) (*connect.Response[v1.ListDirResponse], error)
@@ -82,9 +105,19 @@ type SyncServiceClient interface {
 	// TODO: split in a separate service definition
 	GetSignature(context.Context, *connect.Request[v1.GetSignatureRequest]) (*connect.Response[v1.GetSignatureResponse], error)
 	GetFileSum(context.Context, *connect.Request[v1.GetFileSumRequest]) (*connect.Response[v1.GetFileSumResponse], error)
+	// GetTree is GetSignature without the sums of the blocks of files, which
+	// are fetched with GetFileSums for the files that need them.
+	GetTree(context.Context, *connect.Request[v1.GetTreeRequest]) (*connect.Response[v1.GetTreeResponse], error)
+	GetFileSums(context.Context, *connect.Request[v1.GetFileSumsRequest]) (*connect.Response[v1.GetFileSumsResponse], error)
 	Create(context.Context) *connect.ClientStreamForClient[v1.CreateRequest, v1.CreateResponse]
+	// GetUpload is how much of a resumable upload was kept, see
+	// `CreateRequest.Creating.upload_id`.
+	GetUpload(context.Context, *connect.Request[v1.GetUploadRequest]) (*connect.Response[v1.GetUploadResponse], error)
 	Patch(context.Context) *connect.ClientStreamForClient[v1.PatchRequest, v1.PatchResponse]
 	Delete(context.Context, *connect.Request[v1.DeleteRequest]) (*connect.Response[v1.DeleteResponse], error)
+	Move(context.Context, *connect.Request[v1.MoveRequest]) (*connect.Response[v1.MoveResponse], error)
+	Download(context.Context, *connect.Request[v1.DownloadRequest]) (*connect.ServerStreamForClient[v1.DownloadResponse], error)
+	DownloadPatch(context.Context, *connect.Request[v1.DownloadPatchRequest]) (*connect.ServerStreamForClient[v1.DownloadPatchResponse], error)
 }
 
 // NewSyncServiceClient constructs a client for the svc.sync.v1.SyncService service. By default, it
@@ -109,6 +142,12 @@ func NewSyncServiceClient(httpClient connect.HTTPClient, baseURL string, opts ..
 			connect.WithSchema(syncServiceCreateProjectMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		getProject: connect.NewClient[v1.GetProjectRequest, v1.GetProjectResponse](
+			httpClient,
+			baseURL+SyncServiceGetProjectProcedure,
+			connect.WithSchema(syncServiceGetProjectMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 		stat: connect.NewClient[v1.StatRequest, v1.StatResponse](
 			httpClient,
 			baseURL+SyncServiceStatProcedure,
@@ -133,12 +172,30 @@ func NewSyncServiceClient(httpClient connect.HTTPClient, baseURL string, opts ..
 			connect.WithSchema(syncServiceGetFileSumMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		getTree: connect.NewClient[v1.GetTreeRequest, v1.GetTreeResponse](
+			httpClient,
+			baseURL+SyncServiceGetTreeProcedure,
+			connect.WithSchema(syncServiceGetTreeMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getFileSums: connect.NewClient[v1.GetFileSumsRequest, v1.GetFileSumsResponse](
+			httpClient,
+			baseURL+SyncServiceGetFileSumsProcedure,
+			connect.WithSchema(syncServiceGetFileSumsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 		create: connect.NewClient[v1.CreateRequest, v1.CreateResponse](
 			httpClient,
 			baseURL+SyncServiceCreateProcedure,
 			connect.WithSchema(syncServiceCreateMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		getUpload: connect.NewClient[v1.GetUploadRequest, v1.GetUploadResponse](
+			httpClient,
+			baseURL+SyncServiceGetUploadProcedure,
+			connect.WithSchema(syncServiceGetUploadMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 		patch: connect.NewClient[v1.PatchRequest, v1.PatchResponse](
 			httpClient,
 			baseURL+SyncServicePatchProcedure,
@@ -151,6 +208,24 @@ func NewSyncServiceClient(httpClient connect.HTTPClient, baseURL string, opts ..
 			connect.WithSchema(syncServiceDeleteMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		move: connect.NewClient[v1.MoveRequest, v1.MoveResponse](
+			httpClient,
+			baseURL+SyncServiceMoveProcedure,
+			connect.WithSchema(syncServiceMoveMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		download: connect.NewClient[v1.DownloadRequest, v1.DownloadResponse](
+			httpClient,
+			baseURL+SyncServiceDownloadProcedure,
+			connect.WithSchema(syncServiceDownloadMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		downloadPatch: connect.NewClient[v1.DownloadPatchRequest, v1.DownloadPatchResponse](
+			httpClient,
+			baseURL+SyncServiceDownloadPatchProcedure,
+			connect.WithSchema(syncServiceDownloadPatchMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -158,13 +233,20 @@ func NewSyncServiceClient(httpClient connect.HTTPClient, baseURL string, opts ..
 type syncServiceClient struct {
 	createAccount *connect.Client[v1.CreateAccountRequest, v1.CreateAccountResponse]
 	createProject *connect.Client[v1.CreateProjectRequest, v1.CreateProjectResponse]
+	getProject    *connect.Client[v1.GetProjectRequest, v1.GetProjectResponse]
 	stat          *connect.Client[v1.StatRequest, v1.StatResponse]
 	listDir       *connect.Client[v1.ListDirRequest, v1.ListDirResponse]
 	getSignature  *connect.Client[v1.GetSignatureRequest, v1.GetSignatureResponse]
 	getFileSum    *connect.Client[v1.GetFileSumRequest, v1.GetFileSumResponse]
+	getTree       *connect.Client[v1.GetTreeRequest, v1.GetTreeResponse]
+	getFileSums   *connect.Client[v1.GetFileSumsRequest, v1.GetFileSumsResponse]
 	create        *connect.Client[v1.CreateRequest, v1.CreateResponse]
+	getUpload     *connect.Client[v1.GetUploadRequest, v1.GetUploadResponse]
 	patch         *connect.Client[v1.PatchRequest, v1.PatchResponse]
 	delete        *connect.Client[v1.DeleteRequest, v1.DeleteResponse]
+	move          *connect.Client[v1.MoveRequest, v1.MoveResponse]
+	download      *connect.Client[v1.DownloadRequest, v1.DownloadResponse]
+	downloadPatch *connect.Client[v1.DownloadPatchRequest, v1.DownloadPatchResponse]
 }
 
 // CreateAccount calls svc.sync.v1.SyncService.CreateAccount.
@@ -177,6 +259,11 @@ func (c *syncServiceClient) CreateProject(ctx context.Context, req *connect.Requ
 	return c.createProject.CallUnary(ctx, req)
 }
 
+// GetProject calls svc.sync.v1.SyncService.GetProject.
+func (c *syncServiceClient) GetProject(ctx context.Context, req *connect.Request[v1.GetProjectRequest]) (*connect.Response[v1.GetProjectResponse], error) {
+	return c.getProject.CallUnary(ctx, req)
+}
+
 // Stat calls svc.sync.v1.SyncService.Stat.
 func (c *syncServiceClient) Stat(ctx context.Context, req *connect.Request[v1.StatRequest]) (*connect.Response[v1.StatResponse], error) {
 	return c.stat.CallUnary(ctx, req)
@@ -197,11 +284,26 @@ func (c *syncServiceClient) GetFileSum(ctx context.Context, req *connect.Request
 	return c.getFileSum.CallUnary(ctx, req)
 }
 
+// GetTree calls svc.sync.v1.SyncService.GetTree.
+func (c *syncServiceClient) GetTree(ctx context.Context, req *connect.Request[v1.GetTreeRequest]) (*connect.Response[v1.GetTreeResponse], error) {
+	return c.getTree.CallUnary(ctx, req)
+}
+
+// GetFileSums calls svc.sync.v1.SyncService.GetFileSums.
+func (c *syncServiceClient) GetFileSums(ctx context.Context, req *connect.Request[v1.GetFileSumsRequest]) (*connect.Response[v1.GetFileSumsResponse], error) {
+	return c.getFileSums.CallUnary(ctx, req)
+}
+
 // Create calls svc.sync.v1.SyncService.Create.
 func (c *syncServiceClient) Create(ctx context.Context) *connect.ClientStreamForClient[v1.CreateRequest, v1.CreateResponse] {
 	return c.create.CallClientStream(ctx)
 }
 
+// GetUpload calls svc.sync.v1.SyncService.GetUpload.
+func (c *syncServiceClient) GetUpload(ctx context.Context, req *connect.Request[v1.GetUploadRequest]) (*connect.Response[v1.GetUploadResponse], error) {
+	return c.getUpload.CallUnary(ctx, req)
+}
+
 // Patch calls svc.sync.v1.SyncService.Patch.
 func (c *syncServiceClient) Patch(ctx context.Context) *connect.ClientStreamForClient[v1.PatchRequest, v1.PatchResponse] {
 	return c.patch.CallClientStream(ctx)
@@ -212,11 +314,27 @@ func (c *syncServiceClient) Delete(ctx context.Context, req *connect.Request[v1.
 	return c.delete.CallUnary(ctx, req)
 }
 
+// Move calls svc.sync.v1.SyncService.Move.
+func (c *syncServiceClient) Move(ctx context.Context, req *connect.Request[v1.MoveRequest]) (*connect.Response[v1.MoveResponse], error) {
+	return c.move.CallUnary(ctx, req)
+}
+
+// Download calls svc.sync.v1.SyncService.Download.
+func (c *syncServiceClient) Download(ctx context.Context, req *connect.Request[v1.DownloadRequest]) (*connect.ServerStreamForClient[v1.DownloadResponse], error) {
+	return c.download.CallServerStream(ctx, req)
+}
+
+// DownloadPatch calls svc.sync.v1.SyncService.DownloadPatch.
+func (c *syncServiceClient) DownloadPatch(ctx context.Context, req *connect.Request[v1.DownloadPatchRequest]) (*connect.ServerStreamForClient[v1.DownloadPatchResponse], error) {
+	return c.downloadPatch.CallServerStream(ctx, req)
+}
+
 // SyncServiceHandler is an implementation of the svc.sync.v1.SyncService service.
 type SyncServiceHandler interface {
 	// mgmt
 	CreateAccount(context.Context, *connect.Request[v1.CreateAccountRequest]) (*connect.Response[v1.CreateAccountResponse], error)
 	CreateProject(context.Context, *connect.Request[v1.CreateProjectRequest]) (*connect.Response[v1.CreateProjectResponse], error)
+	GetProject(context.Context, *connect.Request[v1.GetProjectRequest]) (*connect.Response[v1.GetProjectResponse], error)
 	// info
 	Stat(context.Context, *connect.Request[v1.StatRequest]) (*connect.Response[v1.StatResponse], error)
 	ListDir(context.Context, *connect.Request[v1.ListDirRequest]) (*connect.Response[v1.ListDirResponse], error)
@@ -224,9 +342,19 @@ type SyncServiceHandler interface {
 	// TODO: split in a separate service definition
 	GetSignature(context.Context, *connect.Request[v1.GetSignatureRequest]) (*connect.Response[v1.GetSignatureResponse], error)
 	GetFileSum(context.Context, *connect.Request[v1.GetFileSumRequest]) (*connect.Response[v1.GetFileSumResponse], error)
+	// GetTree is GetSignature without the sums of the blocks of files, which
+	// are fetched with GetFileSums for the files that need them.
+	GetTree(context.Context, *connect.Request[v1.GetTreeRequest]) (*connect.Response[v1.GetTreeResponse], error)
+	GetFileSums(context.Context, *connect.Request[v1.GetFileSumsRequest]) (*connect.Response[v1.GetFileSumsResponse], error)
 	Create(context.Context, *connect.ClientStream[v1.CreateRequest]) (*connect.Response[v1.CreateResponse], error)
+	// GetUpload is how much of a resumable upload was kept, see
+	// `CreateRequest.Creating.upload_id`.
+	GetUpload(context.Context, *connect.Request[v1.GetUploadRequest]) (*connect.Response[v1.GetUploadResponse], error)
 	Patch(context.Context, *connect.ClientStream[v1.PatchRequest]) (*connect.Response[v1.PatchResponse], error)
 	Delete(context.Context, *connect.Request[v1.DeleteRequest]) (*connect.Response[v1.DeleteResponse], error)
+	Move(context.Context, *connect.Request[v1.MoveRequest]) (*connect.Response[v1.MoveResponse], error)
+	Download(context.Context, *connect.Request[v1.DownloadRequest], *connect.ServerStream[v1.DownloadResponse]) error
+	DownloadPatch(context.Context, *connect.Request[v1.DownloadPatchRequest], *connect.ServerStream[v1.DownloadPatchResponse]) error
 }
 
 // NewSyncServiceHandler builds an HTTP handler from the service implementation. It returns the path
@@ -247,6 +375,12 @@ func NewSyncServiceHandler(svc SyncServiceHandler, opts ...connect.HandlerOption
 		connect.WithSchema(syncServiceCreateProjectMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	syncServiceGetProjectHandler := connect.NewUnaryHandler(
+		SyncServiceGetProjectProcedure,
+		svc.GetProject,
+		connect.WithSchema(syncServiceGetProjectMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	syncServiceStatHandler := connect.NewUnaryHandler(
 		SyncServiceStatProcedure,
 		svc.Stat,
@@ -271,12 +405,30 @@ func NewSyncServiceHandler(svc SyncServiceHandler, opts ...connect.HandlerOption
 		connect.WithSchema(syncServiceGetFileSumMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	syncServiceGetTreeHandler := connect.NewUnaryHandler(
+		SyncServiceGetTreeProcedure,
+		svc.GetTree,
+		connect.WithSchema(syncServiceGetTreeMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	syncServiceGetFileSumsHandler := connect.NewUnaryHandler(
+		SyncServiceGetFileSumsProcedure,
+		svc.GetFileSums,
+		connect.WithSchema(syncServiceGetFileSumsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	syncServiceCreateHandler := connect.NewClientStreamHandler(
 		SyncServiceCreateProcedure,
 		svc.Create,
 		connect.WithSchema(syncServiceCreateMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	syncServiceGetUploadHandler := connect.NewUnaryHandler(
+		SyncServiceGetUploadProcedure,
+		svc.GetUpload,
+		connect.WithSchema(syncServiceGetUploadMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	syncServicePatchHandler := connect.NewClientStreamHandler(
 		SyncServicePatchProcedure,
 		svc.Patch,
@@ -289,12 +441,32 @@ func NewSyncServiceHandler(svc SyncServiceHandler, opts ...connect.HandlerOption
 		connect.WithSchema(syncServiceDeleteMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	syncServiceMoveHandler := connect.NewUnaryHandler(
+		SyncServiceMoveProcedure,
+		svc.Move,
+		connect.WithSchema(syncServiceMoveMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	syncServiceDownloadHandler := connect.NewServerStreamHandler(
+		SyncServiceDownloadProcedure,
+		svc.Download,
+		connect.WithSchema(syncServiceDownloadMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	syncServiceDownloadPatchHandler := connect.NewServerStreamHandler(
+		SyncServiceDownloadPatchProcedure,
+		svc.DownloadPatch,
+		connect.WithSchema(syncServiceDownloadPatchMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/svc.sync.v1.SyncService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case SyncServiceCreateAccountProcedure:
 			syncServiceCreateAccountHandler.ServeHTTP(w, r)
 		case SyncServiceCreateProjectProcedure:
 			syncServiceCreateProjectHandler.ServeHTTP(w, r)
+		case SyncServiceGetProjectProcedure:
+			syncServiceGetProjectHandler.ServeHTTP(w, r)
 		case SyncServiceStatProcedure:
 			syncServiceStatHandler.ServeHTTP(w, r)
 		case SyncServiceListDirProcedure:
@@ -303,12 +475,24 @@ func NewSyncServiceHandler(svc SyncServiceHandler, opts ...connect.HandlerOption
 			syncServiceGetSignatureHandler.ServeHTTP(w, r)
 		case SyncServiceGetFileSumProcedure:
 			syncServiceGetFileSumHandler.ServeHTTP(w, r)
+		case SyncServiceGetTreeProcedure:
+			syncServiceGetTreeHandler.ServeHTTP(w, r)
+		case SyncServiceGetFileSumsProcedure:
+			syncServiceGetFileSumsHandler.ServeHTTP(w, r)
 		case SyncServiceCreateProcedure:
 			syncServiceCreateHandler.ServeHTTP(w, r)
+		case SyncServiceGetUploadProcedure:
+			syncServiceGetUploadHandler.ServeHTTP(w, r)
 		case SyncServicePatchProcedure:
 			syncServicePatchHandler.ServeHTTP(w, r)
 		case SyncServiceDeleteProcedure:
 			syncServiceDeleteHandler.ServeHTTP(w, r)
+		case SyncServiceMoveProcedure:
+			syncServiceMoveHandler.ServeHTTP(w, r)
+		case SyncServiceDownloadProcedure:
+			syncServiceDownloadHandler.ServeHTTP(w, r)
+		case SyncServiceDownloadPatchProcedure:
+			syncServiceDownloadPatchHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -326,6 +510,10 @@ func (UnimplementedSyncServiceHandler) CreateProject(context.Context, *connect.R
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("svc.sync.v1.SyncService.CreateProject is not implemented"))
 }
 
+func (UnimplementedSyncServiceHandler) GetProject(context.Context, *connect.Request[v1.GetProjectRequest]) (*connect.Response[v1.GetProjectResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("svc.sync.v1.SyncService.GetProject is not implemented"))
+}
+
 func (UnimplementedSyncServiceHandler) Stat(context.Context, *connect.Request[v1.StatRequest]) (*connect.Response[v1.StatResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("svc.sync.v1.SyncService.Stat is not implemented"))
 }
@@ -342,10 +530,22 @@ func (UnimplementedSyncServiceHandler) GetFileSum(context.Context, *connect.Requ
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("svc.sync.v1.SyncService.GetFileSum is not implemented"))
 }
 
+func (UnimplementedSyncServiceHandler) GetTree(context.Context, *connect.Request[v1.GetTreeRequest]) (*connect.Response[v1.GetTreeResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("svc.sync.v1.SyncService.GetTree is not implemented"))
+}
+
+func (UnimplementedSyncServiceHandler) GetFileSums(context.Context, *connect.Request[v1.GetFileSumsRequest]) (*connect.Response[v1.GetFileSumsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("svc.sync.v1.SyncService.GetFileSums is not implemented"))
+}
+
 func (UnimplementedSyncServiceHandler) Create(context.Context, *connect.ClientStream[v1.CreateRequest]) (*connect.Response[v1.CreateResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("svc.sync.v1.SyncService.Create is not implemented"))
 }
 
+func (UnimplementedSyncServiceHandler) GetUpload(context.Context, *connect.Request[v1.GetUploadRequest]) (*connect.Response[v1.GetUploadResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("svc.sync.v1.SyncService.GetUpload is not implemented"))
+}
+
 func (UnimplementedSyncServiceHandler) Patch(context.Context, *connect.ClientStream[v1.PatchRequest]) (*connect.Response[v1.PatchResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("svc.sync.v1.SyncService.Patch is not implemented"))
 }
@@ -353,3 +553,15 @@ func (UnimplementedSyncServiceHandler) Patch(context.Context, *connect.ClientStr
 func (UnimplementedSyncServiceHandler) Delete(context.Context, *connect.Request[v1.DeleteRequest]) (*connect.Response[v1.DeleteResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("svc.sync.v1.SyncService.Delete is not implemented"))
 }
+
+func (UnimplementedSyncServiceHandler) Move(context.Context, *connect.Request[v1.MoveRequest]) (*connect.Response[v1.MoveResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("svc.sync.v1.SyncService.Move is not implemented"))
+}
+
+func (UnimplementedSyncServiceHandler) Download(context.Context, *connect.Request[v1.DownloadRequest], *connect.ServerStream[v1.DownloadResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("svc.sync.v1.SyncService.Download is not implemented"))
+}
+
+func (UnimplementedSyncServiceHandler) DownloadPatch(context.Context, *connect.Request[v1.DownloadPatchRequest], *connect.ServerStream[v1.DownloadPatchResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("svc.sync.v1.SyncService.DownloadPatch is not implemented"))
+}
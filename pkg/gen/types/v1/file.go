@@ -17,6 +17,12 @@ func FileInfoFromFS(fi fs.FileInfo) *FileInfo {
 	}
 }
 
+// IsSymlink tells if the file is a symlink, in which case its target
+// is in `LinkTarget`.
+func (fi *FileInfo) IsSymlink() bool {
+	return fs.FileMode(fi.GetMode())&fs.ModeSymlink != 0
+}
+
 func Uint256FromArray32Byte(arr [32]byte) *Uint256 {
 	return &Uint256{
 		A: binary.LittleEndian.Uint64(arr[0:8]),
@@ -86,6 +86,15 @@ type FileInfo struct {
 	Mode    uint32                 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`
 	ModTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
 	IsDir   bool                   `protobuf:"varint,5,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	// set for symlinks (mode has `fs.ModeSymlink`), the target as
+	// it was read from the link, which is never followed
+	LinkTarget string `protobuf:"bytes,6,opt,name=link_target,json=linkTarget,proto3" json:"link_target,omitempty"`
+	// set for files that are hard links to another file of the tree, the
+	// path from the root of that file, whose content they share
+	HardLink *Path `protobuf:"bytes,7,opt,name=hard_link,json=hardLink,proto3" json:"hard_link,omitempty"`
+	// set for files and dirs when their attributes are synced, see
+	// `FileAttrs`
+	Attrs *FileAttrs `protobuf:"bytes,8,opt,name=attrs,proto3" json:"attrs,omitempty"`
 }
 
 func (x *FileInfo) Reset() {
@@ -155,6 +164,212 @@ func (x *FileInfo) GetIsDir() bool {
 	return false
 }
 
+func (x *FileInfo) GetLinkTarget() string {
+	if x != nil {
+		return x.LinkTarget
+	}
+	return ""
+}
+
+func (x *FileInfo) GetHardLink() *Path {
+	if x != nil {
+		return x.HardLink
+	}
+	return nil
+}
+
+func (x *FileInfo) GetAttrs() *FileAttrs {
+	if x != nil {
+		return x.Attrs
+	}
+	return nil
+}
+
+// attributes of a file that are only synced on request, and stored as
+// metadata but never applied on the server
+type FileAttrs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Owner *FileOwner `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	// ordered by name, POSIX ACLs are among them
+	Xattrs []*Xattr `protobuf:"bytes,2,rep,name=xattrs,proto3" json:"xattrs,omitempty"`
+}
+
+func (x *FileAttrs) Reset() {
+	*x = FileAttrs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_v1_file_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FileAttrs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileAttrs) ProtoMessage() {}
+
+func (x *FileAttrs) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_file_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileAttrs.ProtoReflect.Descriptor instead.
+func (*FileAttrs) Descriptor() ([]byte, []int) {
+	return file_types_v1_file_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FileAttrs) GetOwner() *FileOwner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *FileAttrs) GetXattrs() []*Xattr {
+	if x != nil {
+		return x.Xattrs
+	}
+	return nil
+}
+
+type FileOwner struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid uint32 `protobuf:"varint,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Gid uint32 `protobuf:"varint,2,opt,name=gid,proto3" json:"gid,omitempty"`
+	// names of the user and group, if they could be looked up
+	User  string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Group string `protobuf:"bytes,4,opt,name=group,proto3" json:"group,omitempty"`
+}
+
+func (x *FileOwner) Reset() {
+	*x = FileOwner{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_v1_file_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FileOwner) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileOwner) ProtoMessage() {}
+
+func (x *FileOwner) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_file_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileOwner.ProtoReflect.Descriptor instead.
+func (*FileOwner) Descriptor() ([]byte, []int) {
+	return file_types_v1_file_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FileOwner) GetUid() uint32 {
+	if x != nil {
+		return x.Uid
+	}
+	return 0
+}
+
+func (x *FileOwner) GetGid() uint32 {
+	if x != nil {
+		return x.Gid
+	}
+	return 0
+}
+
+func (x *FileOwner) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *FileOwner) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+type Xattr struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Xattr) Reset() {
+	*x = Xattr{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_v1_file_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Xattr) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Xattr) ProtoMessage() {}
+
+func (x *Xattr) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_file_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Xattr.ProtoReflect.Descriptor instead.
+func (*Xattr) Descriptor() ([]byte, []int) {
+	return file_types_v1_file_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Xattr) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Xattr) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
 type FileSum struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -168,7 +383,7 @@ type FileSum struct {
 func (x *FileSum) Reset() {
 	*x = FileSum{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_types_v1_file_proto_msgTypes[2]
+		mi := &file_types_v1_file_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -181,7 +396,7 @@ func (x *FileSum) String() string {
 func (*FileSum) ProtoMessage() {}
 
 func (x *FileSum) ProtoReflect() protoreflect.Message {
-	mi := &file_types_v1_file_proto_msgTypes[2]
+	mi := &file_types_v1_file_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -194,7 +409,7 @@ func (x *FileSum) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileSum.ProtoReflect.Descriptor instead.
 func (*FileSum) Descriptor() ([]byte, []int) {
-	return file_types_v1_file_proto_rawDescGZIP(), []int{2}
+	return file_types_v1_file_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *FileSum) GetInfo() *FileInfo {
@@ -231,7 +446,7 @@ type FileSumBlock struct {
 func (x *FileSumBlock) Reset() {
 	*x = FileSumBlock{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_types_v1_file_proto_msgTypes[3]
+		mi := &file_types_v1_file_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -244,7 +459,7 @@ func (x *FileSumBlock) String() string {
 func (*FileSumBlock) ProtoMessage() {}
 
 func (x *FileSumBlock) ProtoReflect() protoreflect.Message {
-	mi := &file_types_v1_file_proto_msgTypes[3]
+	mi := &file_types_v1_file_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -257,7 +472,7 @@ func (x *FileSumBlock) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileSumBlock.ProtoReflect.Descriptor instead.
 func (*FileSumBlock) Descriptor() ([]byte, []int) {
-	return file_types_v1_file_proto_rawDescGZIP(), []int{3}
+	return file_types_v1_file_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *FileSumBlock) GetFastSig() uint32 {
@@ -293,7 +508,7 @@ type FilePatch struct {
 func (x *FilePatch) Reset() {
 	*x = FilePatch{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_types_v1_file_proto_msgTypes[4]
+		mi := &file_types_v1_file_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -306,7 +521,7 @@ func (x *FilePatch) String() string {
 func (*FilePatch) ProtoMessage() {}
 
 func (x *FilePatch) ProtoReflect() protoreflect.Message {
-	mi := &file_types_v1_file_proto_msgTypes[4]
+	mi := &file_types_v1_file_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -319,7 +534,7 @@ func (x *FilePatch) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FilePatch.ProtoReflect.Descriptor instead.
 func (*FilePatch) Descriptor() ([]byte, []int) {
-	return file_types_v1_file_proto_rawDescGZIP(), []int{4}
+	return file_types_v1_file_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *FilePatch) GetInfo() *FileInfo {
@@ -345,13 +560,14 @@ type FileBlockPatch struct {
 	//
 	//	*FileBlockPatch_BlockId
 	//	*FileBlockPatch_Data
+	//	*FileBlockPatch_Hole
 	Patch isFileBlockPatch_Patch `protobuf_oneof:"patch"`
 }
 
 func (x *FileBlockPatch) Reset() {
 	*x = FileBlockPatch{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_types_v1_file_proto_msgTypes[5]
+		mi := &file_types_v1_file_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -364,7 +580,7 @@ func (x *FileBlockPatch) String() string {
 func (*FileBlockPatch) ProtoMessage() {}
 
 func (x *FileBlockPatch) ProtoReflect() protoreflect.Message {
-	mi := &file_types_v1_file_proto_msgTypes[5]
+	mi := &file_types_v1_file_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -377,7 +593,7 @@ func (x *FileBlockPatch) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileBlockPatch.ProtoReflect.Descriptor instead.
 func (*FileBlockPatch) Descriptor() ([]byte, []int) {
-	return file_types_v1_file_proto_rawDescGZIP(), []int{5}
+	return file_types_v1_file_proto_rawDescGZIP(), []int{8}
 }
 
 func (m *FileBlockPatch) GetPatch() isFileBlockPatch_Patch {
@@ -401,6 +617,13 @@ func (x *FileBlockPatch) GetData() []byte {
 	return nil
 }
 
+func (x *FileBlockPatch) GetHole() uint64 {
+	if x, ok := x.GetPatch().(*FileBlockPatch_Hole); ok {
+		return x.Hole
+	}
+	return 0
+}
+
 type isFileBlockPatch_Patch interface {
 	isFileBlockPatch_Patch()
 }
@@ -413,10 +636,16 @@ type FileBlockPatch_Data struct {
 	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"` // actual data to add
 }
 
+type FileBlockPatch_Hole struct {
+	Hole uint64 `protobuf:"varint,3,opt,name=hole,proto3,oneof"` // size of a run of zeros, left as a hole where it can be
+}
+
 func (*FileBlockPatch_BlockId) isFileBlockPatch_Patch() {}
 
 func (*FileBlockPatch_Data) isFileBlockPatch_Patch() {}
 
+func (*FileBlockPatch_Hole) isFileBlockPatch_Patch() {}
+
 type Uint256 struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -431,7 +660,7 @@ type Uint256 struct {
 func (x *Uint256) Reset() {
 	*x = Uint256{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_types_v1_file_proto_msgTypes[6]
+		mi := &file_types_v1_file_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -444,7 +673,7 @@ func (x *Uint256) String() string {
 func (*Uint256) ProtoMessage() {}
 
 func (x *Uint256) ProtoReflect() protoreflect.Message {
-	mi := &file_types_v1_file_proto_msgTypes[6]
+	mi := &file_types_v1_file_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -457,7 +686,7 @@ func (x *Uint256) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Uint256.ProtoReflect.Descriptor instead.
 func (*Uint256) Descriptor() ([]byte, []int) {
-	return file_types_v1_file_proto_rawDescGZIP(), []int{6}
+	return file_types_v1_file_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Uint256) GetA() uint64 {
@@ -501,7 +730,7 @@ var file_types_v1_file_proto_rawDesc = []byte{
 	0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52,
 	0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
 	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22,
-	0x94, 0x01, 0x0a, 0x08, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04,
+	0x8d, 0x02, 0x0a, 0x08, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04,
 	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
 	0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04,
 	0x73, 0x69, 0x7a, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01,
@@ -510,48 +739,71 @@ var file_types_v1_file_proto_rawDesc = []byte{
 	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
 	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x6d, 0x6f, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12,
 	0x15, 0x0a, 0x06, 0x69, 0x73, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x05, 0x69, 0x73, 0x44, 0x69, 0x72, 0x22, 0x87, 0x01, 0x0a, 0x07, 0x46, 0x69, 0x6c, 0x65, 0x53,
-	0x75, 0x6d, 0x12, 0x26, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x12, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65,
-	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c,
-	0x6f, 0x63, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09,
-	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x73, 0x75, 0x6d,
-	0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e,
-	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d,
-	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x09, 0x73, 0x75, 0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
-	0x22, 0x6f, 0x0a, 0x0c, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
-	0x12, 0x19, 0x0a, 0x08, 0x66, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x07, 0x66, 0x61, 0x73, 0x74, 0x53, 0x69, 0x67, 0x12, 0x30, 0x0a, 0x0a, 0x73,
-	0x74, 0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x73, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x69, 0x6e, 0x74, 0x32,
-	0x35, 0x36, 0x52, 0x09, 0x73, 0x74, 0x72, 0x6f, 0x6e, 0x67, 0x53, 0x69, 0x67, 0x12, 0x12, 0x0a,
-	0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x73, 0x69, 0x7a,
-	0x65, 0x22, 0x65, 0x0a, 0x09, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x26,
-	0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74,
-	0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f,
-	0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x30, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
-	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76,
-	0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x61, 0x74, 0x63, 0x68,
-	0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x4c, 0x0a, 0x0e, 0x46, 0x69, 0x6c, 0x65,
-	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1b, 0x0a, 0x08, 0x62, 0x6c,
-	0x6f, 0x63, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x07,
-	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x42, 0x07, 0x0a,
-	0x05, 0x70, 0x61, 0x74, 0x63, 0x68, 0x22, 0x41, 0x0a, 0x07, 0x55, 0x69, 0x6e, 0x74, 0x32, 0x35,
-	0x36, 0x12, 0x0c, 0x0a, 0x01, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x01, 0x61, 0x12,
-	0x0c, 0x0a, 0x01, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x01, 0x62, 0x12, 0x0c, 0x0a,
-	0x01, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x01, 0x63, 0x12, 0x0c, 0x0a, 0x01, 0x64,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x01, 0x64, 0x42, 0x8e, 0x01, 0x0a, 0x0c, 0x63, 0x6f,
-	0x6d, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x42, 0x09, 0x46, 0x69, 0x6c, 0x65,
-	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x79, 0x62, 0x61, 0x62, 0x74, 0x6d, 0x65, 0x2f, 0x73, 0x79, 0x6e,
-	0x63, 0x79, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73,
-	0x2f, 0x76, 0x31, 0x3b, 0x74, 0x79, 0x70, 0x65, 0x73, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x54, 0x58,
-	0x58, 0xaa, 0x02, 0x08, 0x54, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x54,
-	0x79, 0x70, 0x65, 0x73, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x14, 0x54, 0x79, 0x70, 0x65, 0x73, 0x5c,
-	0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02,
-	0x09, 0x54, 0x79, 0x70, 0x65, 0x73, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x05, 0x69, 0x73, 0x44, 0x69, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6c, 0x69, 0x6e,
+	0x6b, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x2b, 0x0a, 0x09, 0x68, 0x61, 0x72, 0x64, 0x5f,
+	0x6c, 0x69, 0x6e, 0x6b, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x08, 0x68, 0x61, 0x72, 0x64,
+	0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x29, 0x0a, 0x05, 0x61, 0x74, 0x74, 0x72, 0x73, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46,
+	0x69, 0x6c, 0x65, 0x41, 0x74, 0x74, 0x72, 0x73, 0x52, 0x05, 0x61, 0x74, 0x74, 0x72, 0x73, 0x22,
+	0x5f, 0x0a, 0x09, 0x46, 0x69, 0x6c, 0x65, 0x41, 0x74, 0x74, 0x72, 0x73, 0x12, 0x29, 0x0a, 0x05,
+	0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x77, 0x6e, 0x65, 0x72,
+	0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x27, 0x0a, 0x06, 0x78, 0x61, 0x74, 0x74, 0x72,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x58, 0x61, 0x74, 0x74, 0x72, 0x52, 0x06, 0x78, 0x61, 0x74, 0x74, 0x72, 0x73,
+	0x22, 0x59, 0x0a, 0x09, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x10, 0x0a,
+	0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12,
+	0x10, 0x0a, 0x03, 0x67, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x67, 0x69,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x22, 0x31, 0x0a, 0x05, 0x58,
+	0x61, 0x74, 0x74, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x87,
+	0x01, 0x0a, 0x07, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d, 0x12, 0x26, 0x0a, 0x04, 0x69, 0x6e,
+	0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e,
+	0x66, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x69, 0x7a,
+	0x65, 0x12, 0x35, 0x0a, 0x0a, 0x73, 0x75, 0x6d, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x09, 0x73,
+	0x75, 0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x6f, 0x0a, 0x0c, 0x46, 0x69, 0x6c, 0x65,
+	0x53, 0x75, 0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x19, 0x0a, 0x08, 0x66, 0x61, 0x73, 0x74,
+	0x5f, 0x73, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x66, 0x61, 0x73, 0x74,
+	0x53, 0x69, 0x67, 0x12, 0x30, 0x0a, 0x0a, 0x73, 0x74, 0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x73, 0x69,
+	0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x55, 0x69, 0x6e, 0x74, 0x32, 0x35, 0x36, 0x52, 0x09, 0x73, 0x74, 0x72, 0x6f,
+	0x6e, 0x67, 0x53, 0x69, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x65, 0x0a, 0x09, 0x46, 0x69, 0x6c,
+	0x65, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x26, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x30,
+	0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
+	0x22, 0x62, 0x0a, 0x0e, 0x46, 0x69, 0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x61, 0x74,
+	0x63, 0x68, 0x12, 0x1b, 0x0a, 0x08, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12,
+	0x14, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x04, 0x68, 0x6f, 0x6c, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x04, 0x68, 0x6f, 0x6c, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x70,
+	0x61, 0x74, 0x63, 0x68, 0x22, 0x41, 0x0a, 0x07, 0x55, 0x69, 0x6e, 0x74, 0x32, 0x35, 0x36, 0x12,
+	0x0c, 0x0a, 0x01, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x01, 0x61, 0x12, 0x0c, 0x0a,
+	0x01, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x01, 0x62, 0x12, 0x0c, 0x0a, 0x01, 0x63,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x01, 0x63, 0x12, 0x0c, 0x0a, 0x01, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x01, 0x64, 0x42, 0x8e, 0x01, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x2e,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x42, 0x09, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x61, 0x79, 0x62, 0x61, 0x62, 0x74, 0x6d, 0x65, 0x2f, 0x73, 0x79, 0x6e, 0x63, 0x79,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x76,
+	0x31, 0x3b, 0x74, 0x79, 0x70, 0x65, 0x73, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x54, 0x58, 0x58, 0xaa,
+	0x02, 0x08, 0x54, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x54, 0x79, 0x70,
+	0x65, 0x73, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x14, 0x54, 0x79, 0x70, 0x65, 0x73, 0x5c, 0x56, 0x31,
+	0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x09, 0x54,
+	0x79, 0x70, 0x65, 0x73, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -566,30 +818,38 @@ func file_types_v1_file_proto_rawDescGZIP() []byte {
 	return file_types_v1_file_proto_rawDescData
 }
 
-var file_types_v1_file_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_types_v1_file_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_types_v1_file_proto_goTypes = []interface{}{
 	(*File)(nil),                  // 0: types.v1.File
 	(*FileInfo)(nil),              // 1: types.v1.FileInfo
-	(*FileSum)(nil),               // 2: types.v1.FileSum
-	(*FileSumBlock)(nil),          // 3: types.v1.FileSumBlock
-	(*FilePatch)(nil),             // 4: types.v1.FilePatch
-	(*FileBlockPatch)(nil),        // 5: types.v1.FileBlockPatch
-	(*Uint256)(nil),               // 6: types.v1.Uint256
-	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+	(*FileAttrs)(nil),             // 2: types.v1.FileAttrs
+	(*FileOwner)(nil),             // 3: types.v1.FileOwner
+	(*Xattr)(nil),                 // 4: types.v1.Xattr
+	(*FileSum)(nil),               // 5: types.v1.FileSum
+	(*FileSumBlock)(nil),          // 6: types.v1.FileSumBlock
+	(*FilePatch)(nil),             // 7: types.v1.FilePatch
+	(*FileBlockPatch)(nil),        // 8: types.v1.FileBlockPatch
+	(*Uint256)(nil),               // 9: types.v1.Uint256
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+	(*Path)(nil),                  // 11: types.v1.Path
 }
 var file_types_v1_file_proto_depIdxs = []int32{
-	1, // 0: types.v1.File.info:type_name -> types.v1.FileInfo
-	7, // 1: types.v1.FileInfo.mod_time:type_name -> google.protobuf.Timestamp
-	1, // 2: types.v1.FileSum.info:type_name -> types.v1.FileInfo
-	3, // 3: types.v1.FileSum.sum_blocks:type_name -> types.v1.FileSumBlock
-	6, // 4: types.v1.FileSumBlock.strong_sig:type_name -> types.v1.Uint256
-	1, // 5: types.v1.FilePatch.info:type_name -> types.v1.FileInfo
-	5, // 6: types.v1.FilePatch.blocks:type_name -> types.v1.FileBlockPatch
-	7, // [7:7] is the sub-list for method output_type
-	7, // [7:7] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+	1,  // 0: types.v1.File.info:type_name -> types.v1.FileInfo
+	10, // 1: types.v1.FileInfo.mod_time:type_name -> google.protobuf.Timestamp
+	11, // 2: types.v1.FileInfo.hard_link:type_name -> types.v1.Path
+	2,  // 3: types.v1.FileInfo.attrs:type_name -> types.v1.FileAttrs
+	3,  // 4: types.v1.FileAttrs.owner:type_name -> types.v1.FileOwner
+	4,  // 5: types.v1.FileAttrs.xattrs:type_name -> types.v1.Xattr
+	1,  // 6: types.v1.FileSum.info:type_name -> types.v1.FileInfo
+	6,  // 7: types.v1.FileSum.sum_blocks:type_name -> types.v1.FileSumBlock
+	9,  // 8: types.v1.FileSumBlock.strong_sig:type_name -> types.v1.Uint256
+	1,  // 9: types.v1.FilePatch.info:type_name -> types.v1.FileInfo
+	8,  // 10: types.v1.FilePatch.blocks:type_name -> types.v1.FileBlockPatch
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_types_v1_file_proto_init() }
@@ -624,7 +884,7 @@ func file_types_v1_file_proto_init() {
 			}
 		}
 		file_types_v1_file_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FileSum); i {
+			switch v := v.(*FileAttrs); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -636,7 +896,7 @@ func file_types_v1_file_proto_init() {
 			}
 		}
 		file_types_v1_file_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FileSumBlock); i {
+			switch v := v.(*FileOwner); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -648,7 +908,7 @@ func file_types_v1_file_proto_init() {
 			}
 		}
 		file_types_v1_file_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FilePatch); i {
+			switch v := v.(*Xattr); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -660,7 +920,7 @@ func file_types_v1_file_proto_init() {
 			}
 		}
 		file_types_v1_file_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FileBlockPatch); i {
+			switch v := v.(*FileSum); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -672,6 +932,42 @@ func file_types_v1_file_proto_init() {
 			}
 		}
 		file_types_v1_file_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileSumBlock); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_types_v1_file_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FilePatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_types_v1_file_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileBlockPatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_types_v1_file_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Uint256); i {
 			case 0:
 				return &v.state
@@ -684,9 +980,10 @@ func file_types_v1_file_proto_init() {
 			}
 		}
 	}
-	file_types_v1_file_proto_msgTypes[5].OneofWrappers = []interface{}{
+	file_types_v1_file_proto_msgTypes[8].OneofWrappers = []interface{}{
 		(*FileBlockPatch_BlockId)(nil),
 		(*FileBlockPatch_Data)(nil),
+		(*FileBlockPatch_Hole)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -694,7 +991,7 @@ func file_types_v1_file_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_types_v1_file_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
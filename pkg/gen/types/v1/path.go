@@ -34,7 +34,11 @@ func PathJoin(parent *Path, name string) *Path {
 	if parent == nil {
 		return &Path{Elements: []string{name}}
 	}
-	return &Path{Elements: append(parent.Elements, name)}
+	// copy the elements, otherwise siblings joined on the same parent
+	// could end up sharing (and overwriting) the same backing array
+	elements := make([]string, len(parent.Elements), len(parent.Elements)+1)
+	copy(elements, parent.Elements)
+	return &Path{Elements: append(elements, name)}
 }
 
 func StringFromPath(path *Path) string {
@@ -0,0 +1,984 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: types/v1/plan.proto
+
+package typesv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SyncPlan is the list of ops that a sync would apply to a sink, in the order
+// they would be applied.
+type SyncPlan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ops []*SyncOp `protobuf:"bytes,1,rep,name=ops,proto3" json:"ops,omitempty"`
+	// sum of the `estimated_bytes` of all the ops
+	EstimatedBytes uint64 `protobuf:"varint,2,opt,name=estimated_bytes,json=estimatedBytes,proto3" json:"estimated_bytes,omitempty"`
+	// the dir of the project that the sink is, which the paths of the ops are
+	// relative to. Unset for the root of the project.
+	Root *Path `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`
+}
+
+func (x *SyncPlan) Reset() {
+	*x = SyncPlan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_v1_plan_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncPlan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncPlan) ProtoMessage() {}
+
+func (x *SyncPlan) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_plan_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncPlan.ProtoReflect.Descriptor instead.
+func (*SyncPlan) Descriptor() ([]byte, []int) {
+	return file_types_v1_plan_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SyncPlan) GetOps() []*SyncOp {
+	if x != nil {
+		return x.Ops
+	}
+	return nil
+}
+
+func (x *SyncPlan) GetEstimatedBytes() uint64 {
+	if x != nil {
+		return x.EstimatedBytes
+	}
+	return 0
+}
+
+func (x *SyncPlan) GetRoot() *Path {
+	if x != nil {
+		return x.Root
+	}
+	return nil
+}
+
+type SyncOp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// path of the file or dir affected by the op
+	Path *Path `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// size of the file or dir affected by the op
+	Size uint64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// upper bound on the bytes sent to the sink to apply the op
+	EstimatedBytes uint64 `protobuf:"varint,3,opt,name=estimated_bytes,json=estimatedBytes,proto3" json:"estimated_bytes,omitempty"`
+	// Types that are assignable to Op:
+	//
+	//	*SyncOp_Create_
+	//	*SyncOp_Patch_
+	//	*SyncOp_Delete_
+	//	*SyncOp_Move_
+	Op isSyncOp_Op `protobuf_oneof:"op"`
+}
+
+func (x *SyncOp) Reset() {
+	*x = SyncOp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_v1_plan_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncOp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncOp) ProtoMessage() {}
+
+func (x *SyncOp) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_plan_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncOp.ProtoReflect.Descriptor instead.
+func (*SyncOp) Descriptor() ([]byte, []int) {
+	return file_types_v1_plan_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SyncOp) GetPath() *Path {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *SyncOp) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *SyncOp) GetEstimatedBytes() uint64 {
+	if x != nil {
+		return x.EstimatedBytes
+	}
+	return 0
+}
+
+func (m *SyncOp) GetOp() isSyncOp_Op {
+	if m != nil {
+		return m.Op
+	}
+	return nil
+}
+
+func (x *SyncOp) GetCreate() *SyncOp_Create {
+	if x, ok := x.GetOp().(*SyncOp_Create_); ok {
+		return x.Create
+	}
+	return nil
+}
+
+func (x *SyncOp) GetPatch() *SyncOp_Patch {
+	if x, ok := x.GetOp().(*SyncOp_Patch_); ok {
+		return x.Patch
+	}
+	return nil
+}
+
+func (x *SyncOp) GetDelete() *SyncOp_Delete {
+	if x, ok := x.GetOp().(*SyncOp_Delete_); ok {
+		return x.Delete
+	}
+	return nil
+}
+
+func (x *SyncOp) GetMove() *SyncOp_Move {
+	if x, ok := x.GetOp().(*SyncOp_Move_); ok {
+		return x.Move
+	}
+	return nil
+}
+
+type isSyncOp_Op interface {
+	isSyncOp_Op()
+}
+
+type SyncOp_Create_ struct {
+	Create *SyncOp_Create `protobuf:"bytes,4,opt,name=create,proto3,oneof"`
+}
+
+type SyncOp_Patch_ struct {
+	Patch *SyncOp_Patch `protobuf:"bytes,5,opt,name=patch,proto3,oneof"`
+}
+
+type SyncOp_Delete_ struct {
+	Delete *SyncOp_Delete `protobuf:"bytes,6,opt,name=delete,proto3,oneof"`
+}
+
+type SyncOp_Move_ struct {
+	Move *SyncOp_Move `protobuf:"bytes,7,opt,name=move,proto3,oneof"`
+}
+
+func (*SyncOp_Create_) isSyncOp_Op() {}
+
+func (*SyncOp_Patch_) isSyncOp_Op() {}
+
+func (*SyncOp_Delete_) isSyncOp_Op() {}
+
+func (*SyncOp_Move_) isSyncOp_Op() {}
+
+// JournalEntry is an entry of the journal of a sync, which records its plan
+// as it's computed, and the ops of the plan as they're applied.
+type JournalEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Entry:
+	//
+	//	*JournalEntry_Plan
+	//	*JournalEntry_Applied_
+	//	*JournalEntry_Op
+	//	*JournalEntry_Planned_
+	Entry isJournalEntry_Entry `protobuf_oneof:"entry"`
+}
+
+func (x *JournalEntry) Reset() {
+	*x = JournalEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_v1_plan_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JournalEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JournalEntry) ProtoMessage() {}
+
+func (x *JournalEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_plan_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JournalEntry.ProtoReflect.Descriptor instead.
+func (*JournalEntry) Descriptor() ([]byte, []int) {
+	return file_types_v1_plan_proto_rawDescGZIP(), []int{2}
+}
+
+func (m *JournalEntry) GetEntry() isJournalEntry_Entry {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+func (x *JournalEntry) GetPlan() *SyncPlan {
+	if x, ok := x.GetEntry().(*JournalEntry_Plan); ok {
+		return x.Plan
+	}
+	return nil
+}
+
+func (x *JournalEntry) GetApplied() *JournalEntry_Applied {
+	if x, ok := x.GetEntry().(*JournalEntry_Applied_); ok {
+		return x.Applied
+	}
+	return nil
+}
+
+func (x *JournalEntry) GetOp() *SyncOp {
+	if x, ok := x.GetEntry().(*JournalEntry_Op); ok {
+		return x.Op
+	}
+	return nil
+}
+
+func (x *JournalEntry) GetPlanned() *JournalEntry_Planned {
+	if x, ok := x.GetEntry().(*JournalEntry_Planned_); ok {
+		return x.Planned
+	}
+	return nil
+}
+
+type isJournalEntry_Entry interface {
+	isJournalEntry_Entry()
+}
+
+type JournalEntry_Plan struct {
+	// the first entry, its ops are the ones planned before it was recorded
+	Plan *SyncPlan `protobuf:"bytes,1,opt,name=plan,proto3,oneof"`
+}
+
+type JournalEntry_Applied_ struct {
+	Applied *JournalEntry_Applied `protobuf:"bytes,2,opt,name=applied,proto3,oneof"`
+}
+
+type JournalEntry_Op struct {
+	// an op of the plan, recorded before it's applied
+	Op *SyncOp `protobuf:"bytes,3,opt,name=op,proto3,oneof"`
+}
+
+type JournalEntry_Planned_ struct {
+	Planned *JournalEntry_Planned `protobuf:"bytes,4,opt,name=planned,proto3,oneof"`
+}
+
+func (*JournalEntry_Plan) isJournalEntry_Entry() {}
+
+func (*JournalEntry_Applied_) isJournalEntry_Entry() {}
+
+func (*JournalEntry_Op) isJournalEntry_Entry() {}
+
+func (*JournalEntry_Planned_) isJournalEntry_Entry() {}
+
+type SyncOp_Create struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ParentDir *Path     `protobuf:"bytes,1,opt,name=parent_dir,json=parentDir,proto3" json:"parent_dir,omitempty"`
+	Info      *FileInfo `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+}
+
+func (x *SyncOp_Create) Reset() {
+	*x = SyncOp_Create{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_v1_plan_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncOp_Create) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncOp_Create) ProtoMessage() {}
+
+func (x *SyncOp_Create) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_plan_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncOp_Create.ProtoReflect.Descriptor instead.
+func (*SyncOp_Create) Descriptor() ([]byte, []int) {
+	return file_types_v1_plan_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *SyncOp_Create) GetParentDir() *Path {
+	if x != nil {
+		return x.ParentDir
+	}
+	return nil
+}
+
+func (x *SyncOp_Create) GetInfo() *FileInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+type SyncOp_Patch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dir *Path `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
+	// info of the source file or dir
+	Info *FileInfo `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	// sum of the file on the sink, unset when patching a dir
+	Sum *FileSum `protobuf:"bytes,3,opt,name=sum,proto3" json:"sum,omitempty"`
+}
+
+func (x *SyncOp_Patch) Reset() {
+	*x = SyncOp_Patch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_v1_plan_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncOp_Patch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncOp_Patch) ProtoMessage() {}
+
+func (x *SyncOp_Patch) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_plan_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncOp_Patch.ProtoReflect.Descriptor instead.
+func (*SyncOp_Patch) Descriptor() ([]byte, []int) {
+	return file_types_v1_plan_proto_rawDescGZIP(), []int{1, 1}
+}
+
+func (x *SyncOp_Patch) GetDir() *Path {
+	if x != nil {
+		return x.Dir
+	}
+	return nil
+}
+
+func (x *SyncOp_Patch) GetInfo() *FileInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *SyncOp_Patch) GetSum() *FileSum {
+	if x != nil {
+		return x.Sum
+	}
+	return nil
+}
+
+type SyncOp_Delete struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path *Path     `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Info *FileInfo `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+}
+
+func (x *SyncOp_Delete) Reset() {
+	*x = SyncOp_Delete{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_v1_plan_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncOp_Delete) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncOp_Delete) ProtoMessage() {}
+
+func (x *SyncOp_Delete) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_plan_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncOp_Delete.ProtoReflect.Descriptor instead.
+func (*SyncOp_Delete) Descriptor() ([]byte, []int) {
+	return file_types_v1_plan_proto_rawDescGZIP(), []int{1, 2}
+}
+
+func (x *SyncOp_Delete) GetPath() *Path {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *SyncOp_Delete) GetInfo() *FileInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+type SyncOp_Move struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// where the file or dir is on the sink, and its info there
+	From      *Path     `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	FromInfo  *FileInfo `protobuf:"bytes,2,opt,name=from_info,json=fromInfo,proto3" json:"from_info,omitempty"`
+	ParentDir *Path     `protobuf:"bytes,3,opt,name=parent_dir,json=parentDir,proto3" json:"parent_dir,omitempty"`
+	// info of the source file or dir
+	Info *FileInfo `protobuf:"bytes,4,opt,name=info,proto3" json:"info,omitempty"`
+}
+
+func (x *SyncOp_Move) Reset() {
+	*x = SyncOp_Move{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_v1_plan_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncOp_Move) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncOp_Move) ProtoMessage() {}
+
+func (x *SyncOp_Move) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_plan_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncOp_Move.ProtoReflect.Descriptor instead.
+func (*SyncOp_Move) Descriptor() ([]byte, []int) {
+	return file_types_v1_plan_proto_rawDescGZIP(), []int{1, 3}
+}
+
+func (x *SyncOp_Move) GetFrom() *Path {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *SyncOp_Move) GetFromInfo() *FileInfo {
+	if x != nil {
+		return x.FromInfo
+	}
+	return nil
+}
+
+func (x *SyncOp_Move) GetParentDir() *Path {
+	if x != nil {
+		return x.ParentDir
+	}
+	return nil
+}
+
+func (x *SyncOp_Move) GetInfo() *FileInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+type JournalEntry_Applied struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// "create", "patch", "delete" or "move"
+	Op   string `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"`
+	Path *Path  `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (x *JournalEntry_Applied) Reset() {
+	*x = JournalEntry_Applied{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_v1_plan_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JournalEntry_Applied) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JournalEntry_Applied) ProtoMessage() {}
+
+func (x *JournalEntry_Applied) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_plan_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JournalEntry_Applied.ProtoReflect.Descriptor instead.
+func (*JournalEntry_Applied) Descriptor() ([]byte, []int) {
+	return file_types_v1_plan_proto_rawDescGZIP(), []int{2, 0}
+}
+
+func (x *JournalEntry_Applied) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *JournalEntry_Applied) GetPath() *Path {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+// Planned marks the end of the plan, all its ops were recorded.
+type JournalEntry_Planned struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *JournalEntry_Planned) Reset() {
+	*x = JournalEntry_Planned{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_v1_plan_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JournalEntry_Planned) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JournalEntry_Planned) ProtoMessage() {}
+
+func (x *JournalEntry_Planned) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_plan_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JournalEntry_Planned.ProtoReflect.Descriptor instead.
+func (*JournalEntry_Planned) Descriptor() ([]byte, []int) {
+	return file_types_v1_plan_proto_rawDescGZIP(), []int{2, 1}
+}
+
+var File_types_v1_plan_proto protoreflect.FileDescriptor
+
+var file_types_v1_plan_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x6c, 0x61, 0x6e, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x1a,
+	0x13, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x61, 0x74, 0x68, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x13, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x66,
+	0x69, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x7b, 0x0a, 0x08, 0x53, 0x79, 0x6e,
+	0x63, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x22, 0x0a, 0x03, 0x6f, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79,
+	0x6e, 0x63, 0x4f, 0x70, 0x52, 0x03, 0x6f, 0x70, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x73, 0x74,
+	0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0e, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x22, 0x0a, 0x04, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68,
+	0x52, 0x04, 0x72, 0x6f, 0x6f, 0x74, 0x22, 0x96, 0x06, 0x0a, 0x06, 0x53, 0x79, 0x6e, 0x63, 0x4f,
+	0x70, 0x12, 0x22, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x73, 0x74,
+	0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0e, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x31, 0x0a, 0x06, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79,
+	0x6e, 0x63, 0x4f, 0x70, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x06, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x63, 0x68, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x79, 0x6e, 0x63, 0x4f, 0x70, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x48, 0x00, 0x52, 0x05,
+	0x70, 0x61, 0x74, 0x63, 0x68, 0x12, 0x31, 0x0a, 0x06, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x79, 0x6e, 0x63, 0x4f, 0x70, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x48, 0x00,
+	0x52, 0x06, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x2b, 0x0a, 0x04, 0x6d, 0x6f, 0x76, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x4f, 0x70, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x48, 0x00, 0x52,
+	0x04, 0x6d, 0x6f, 0x76, 0x65, 0x1a, 0x5f, 0x0a, 0x06, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12,
+	0x2d, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x61, 0x74, 0x68, 0x52, 0x09, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x72, 0x12, 0x26,
+	0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x1a, 0x76, 0x0a, 0x05, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12,
+	0x20, 0x0a, 0x03, 0x64, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x03, 0x64, 0x69,
+	0x72, 0x12, 0x26, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x23, 0x0a, 0x03, 0x73, 0x75, 0x6d,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x75, 0x6d, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x1a, 0x54,
+	0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x26, 0x0a, 0x04,
+	0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04,
+	0x69, 0x6e, 0x66, 0x6f, 0x1a, 0xb2, 0x01, 0x0a, 0x04, 0x4d, 0x6f, 0x76, 0x65, 0x12, 0x22, 0x0a,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x12, 0x2f, 0x0a, 0x09, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x66, 0x72, 0x6f, 0x6d, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x2d, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x64, 0x69, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x09, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x44, 0x69,
+	0x72, 0x12, 0x26, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x42, 0x04, 0x0a, 0x02, 0x6f, 0x70, 0x22,
+	0xa7, 0x02, 0x0a, 0x0c, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x28, 0x0a, 0x04, 0x70, 0x6c, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x50, 0x6c,
+	0x61, 0x6e, 0x48, 0x00, 0x52, 0x04, 0x70, 0x6c, 0x61, 0x6e, 0x12, 0x3a, 0x0a, 0x07, 0x61, 0x70,
+	0x70, 0x6c, 0x69, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x48, 0x00, 0x52, 0x07, 0x61,
+	0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x12, 0x22, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79,
+	0x6e, 0x63, 0x4f, 0x70, 0x48, 0x00, 0x52, 0x02, 0x6f, 0x70, 0x12, 0x3a, 0x0a, 0x07, 0x70, 0x6c,
+	0x61, 0x6e, 0x6e, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x48, 0x00, 0x52, 0x07, 0x70,
+	0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x1a, 0x3d, 0x0a, 0x07, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65,
+	0x64, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x6f,
+	0x70, 0x12, 0x22, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x1a, 0x09, 0x0a, 0x07, 0x50, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x64,
+	0x42, 0x07, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x42, 0x8e, 0x01, 0x0a, 0x0c, 0x63, 0x6f,
+	0x6d, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x42, 0x09, 0x50, 0x6c, 0x61, 0x6e,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x79, 0x62, 0x61, 0x62, 0x74, 0x6d, 0x65, 0x2f, 0x73, 0x79, 0x6e,
+	0x63, 0x79, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73,
+	0x2f, 0x76, 0x31, 0x3b, 0x74, 0x79, 0x70, 0x65, 0x73, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x54, 0x58,
+	0x58, 0xaa, 0x02, 0x08, 0x54, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x54,
+	0x79, 0x70, 0x65, 0x73, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x14, 0x54, 0x79, 0x70, 0x65, 0x73, 0x5c,
+	0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02,
+	0x09, 0x54, 0x79, 0x70, 0x65, 0x73, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_types_v1_plan_proto_rawDescOnce sync.Once
+	file_types_v1_plan_proto_rawDescData = file_types_v1_plan_proto_rawDesc
+)
+
+func file_types_v1_plan_proto_rawDescGZIP() []byte {
+	file_types_v1_plan_proto_rawDescOnce.Do(func() {
+		file_types_v1_plan_proto_rawDescData = protoimpl.X.CompressGZIP(file_types_v1_plan_proto_rawDescData)
+	})
+	return file_types_v1_plan_proto_rawDescData
+}
+
+var file_types_v1_plan_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_types_v1_plan_proto_goTypes = []interface{}{
+	(*SyncPlan)(nil),             // 0: types.v1.SyncPlan
+	(*SyncOp)(nil),               // 1: types.v1.SyncOp
+	(*JournalEntry)(nil),         // 2: types.v1.JournalEntry
+	(*SyncOp_Create)(nil),        // 3: types.v1.SyncOp.Create
+	(*SyncOp_Patch)(nil),         // 4: types.v1.SyncOp.Patch
+	(*SyncOp_Delete)(nil),        // 5: types.v1.SyncOp.Delete
+	(*SyncOp_Move)(nil),          // 6: types.v1.SyncOp.Move
+	(*JournalEntry_Applied)(nil), // 7: types.v1.JournalEntry.Applied
+	(*JournalEntry_Planned)(nil), // 8: types.v1.JournalEntry.Planned
+	(*Path)(nil),                 // 9: types.v1.Path
+	(*FileInfo)(nil),             // 10: types.v1.FileInfo
+	(*FileSum)(nil),              // 11: types.v1.FileSum
+}
+var file_types_v1_plan_proto_depIdxs = []int32{
+	1,  // 0: types.v1.SyncPlan.ops:type_name -> types.v1.SyncOp
+	9,  // 1: types.v1.SyncPlan.root:type_name -> types.v1.Path
+	9,  // 2: types.v1.SyncOp.path:type_name -> types.v1.Path
+	3,  // 3: types.v1.SyncOp.create:type_name -> types.v1.SyncOp.Create
+	4,  // 4: types.v1.SyncOp.patch:type_name -> types.v1.SyncOp.Patch
+	5,  // 5: types.v1.SyncOp.delete:type_name -> types.v1.SyncOp.Delete
+	6,  // 6: types.v1.SyncOp.move:type_name -> types.v1.SyncOp.Move
+	0,  // 7: types.v1.JournalEntry.plan:type_name -> types.v1.SyncPlan
+	7,  // 8: types.v1.JournalEntry.applied:type_name -> types.v1.JournalEntry.Applied
+	1,  // 9: types.v1.JournalEntry.op:type_name -> types.v1.SyncOp
+	8,  // 10: types.v1.JournalEntry.planned:type_name -> types.v1.JournalEntry.Planned
+	9,  // 11: types.v1.SyncOp.Create.parent_dir:type_name -> types.v1.Path
+	10, // 12: types.v1.SyncOp.Create.info:type_name -> types.v1.FileInfo
+	9,  // 13: types.v1.SyncOp.Patch.dir:type_name -> types.v1.Path
+	10, // 14: types.v1.SyncOp.Patch.info:type_name -> types.v1.FileInfo
+	11, // 15: types.v1.SyncOp.Patch.sum:type_name -> types.v1.FileSum
+	9,  // 16: types.v1.SyncOp.Delete.path:type_name -> types.v1.Path
+	10, // 17: types.v1.SyncOp.Delete.info:type_name -> types.v1.FileInfo
+	9,  // 18: types.v1.SyncOp.Move.from:type_name -> types.v1.Path
+	10, // 19: types.v1.SyncOp.Move.from_info:type_name -> types.v1.FileInfo
+	9,  // 20: types.v1.SyncOp.Move.parent_dir:type_name -> types.v1.Path
+	10, // 21: types.v1.SyncOp.Move.info:type_name -> types.v1.FileInfo
+	9,  // 22: types.v1.JournalEntry.Applied.path:type_name -> types.v1.Path
+	23, // [23:23] is the sub-list for method output_type
+	23, // [23:23] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
+}
+
+func init() { file_types_v1_plan_proto_init() }
+func file_types_v1_plan_proto_init() {
+	if File_types_v1_plan_proto != nil {
+		return
+	}
+	file_types_v1_path_proto_init()
+	file_types_v1_file_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_types_v1_plan_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncPlan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_types_v1_plan_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncOp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_types_v1_plan_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JournalEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_types_v1_plan_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncOp_Create); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_types_v1_plan_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncOp_Patch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_types_v1_plan_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncOp_Delete); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_types_v1_plan_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncOp_Move); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_types_v1_plan_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JournalEntry_Applied); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_types_v1_plan_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JournalEntry_Planned); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_types_v1_plan_proto_msgTypes[1].OneofWrappers = []interface{}{
+		(*SyncOp_Create_)(nil),
+		(*SyncOp_Patch_)(nil),
+		(*SyncOp_Delete_)(nil),
+		(*SyncOp_Move_)(nil),
+	}
+	file_types_v1_plan_proto_msgTypes[2].OneofWrappers = []interface{}{
+		(*JournalEntry_Plan)(nil),
+		(*JournalEntry_Applied_)(nil),
+		(*JournalEntry_Op)(nil),
+		(*JournalEntry_Planned_)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_types_v1_plan_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_types_v1_plan_proto_goTypes,
+		DependencyIndexes: file_types_v1_plan_proto_depIdxs,
+		MessageInfos:      file_types_v1_plan_proto_msgTypes,
+	}.Build()
+	File_types_v1_plan_proto = out.File
+	file_types_v1_plan_proto_rawDesc = nil
+	file_types_v1_plan_proto_goTypes = nil
+	file_types_v1_plan_proto_depIdxs = nil
+}
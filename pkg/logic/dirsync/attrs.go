@@ -0,0 +1,144 @@
+package dirsync
+
+import (
+	"fmt"
+	"io/fs"
+	"os/user"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+)
+
+// Attrs are attributes of files and dirs that can be synced on top of their
+// mode and mod time, see `typesv1.FileAttrs`. They're never synced for
+// symlinks.
+type Attrs int
+
+const (
+	// AttrOwner is the uid and gid of files, with the names of their user
+	// and group. Owners are applied by id.
+	AttrOwner Attrs = 1 << iota
+	// AttrXattrs are the extended attributes of files, POSIX ACLs included.
+	AttrXattrs
+)
+
+func (attrs Attrs) String() string {
+	var names []string
+	if attrs&AttrOwner != 0 {
+		names = append(names, "owner")
+	}
+	if attrs&AttrXattrs != 0 {
+		names = append(names, "xattrs")
+	}
+	if rest := attrs &^ (AttrOwner | AttrXattrs); rest != 0 {
+		names = append(names, fmt.Sprintf("Attrs(%d)", int(rest)))
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ",")
+}
+
+// AttrSource is a `Source` that reads the attributes of its files itself.
+// Otherwise, they're the ones of the `typesv1.FileInfo` its `fs.FileInfo`s
+// may have as `Sys()`.
+type AttrSource interface {
+	Source
+	ReadAttrs(name string, attrs Attrs) (*typesv1.FileAttrs, error)
+}
+
+// AttrSink is a `Sink` that can't keep every attribute. Other sinks keep
+// them all.
+type AttrSink interface {
+	Sink
+	// WithAttrs returns the sink keeping the ones of `attrs` it can, and
+	// reporting them in its signatures. It returns which ones it keeps.
+	WithAttrs(attrs Attrs) (Sink, Attrs)
+}
+
+// withSinkAttrs narrows `params.Attrs` to the ones that `sink` keeps, the
+// others would never match.
+func withSinkAttrs(sink Sink, params Params) (Sink, Params) {
+	if params.Attrs == 0 {
+		return sink, params
+	}
+	if as, ok := sink.(AttrSink); ok {
+		sink, params.Attrs = as.WithAttrs(params.Attrs)
+	}
+	return sink, params
+}
+
+// readAttrs reads the attributes of the file `name` of the source that
+// `params` ask for.
+func (tr *sourceTracer) readAttrs(name string, fsfi fs.FileInfo) (*typesv1.FileAttrs, error) {
+	if tr.params.Attrs == 0 {
+		return nil, nil
+	}
+	if as, ok := tr.src.(AttrSource); ok {
+		return as.ReadAttrs(name, tr.params.Attrs)
+	}
+	if sys, ok := fsfi.Sys().(*typesv1.FileInfo); ok {
+		return filterAttrs(sys.Attrs, tr.params.Attrs), nil
+	}
+	return nil, nil
+}
+
+// filterAttrs keeps the ones of `attrs` in `fa`.
+func filterAttrs(fa *typesv1.FileAttrs, attrs Attrs) *typesv1.FileAttrs {
+	out := new(typesv1.FileAttrs)
+	if attrs&AttrOwner != 0 {
+		out.Owner = fa.GetOwner()
+	}
+	if attrs&AttrXattrs != 0 {
+		out.Xattrs = fa.GetXattrs()
+	}
+	return nonEmptyAttrs(out)
+}
+
+// nonEmptyAttrs is nil for attributes without any, so that they compare
+// equal to ones that weren't read at all.
+func nonEmptyAttrs(fa *typesv1.FileAttrs) *typesv1.FileAttrs {
+	if fa.Owner == nil && len(fa.Xattrs) == 0 {
+		return nil
+	}
+	slices.SortFunc(fa.Xattrs, func(a, b *typesv1.Xattr) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return fa
+}
+
+// ownerNames caches the names of users and groups, which are looked up for
+// every file.
+var ownerNames = struct {
+	mu     sync.Mutex
+	users  map[uint32]string
+	groups map[uint32]string
+}{
+	users:  make(map[uint32]string),
+	groups: make(map[uint32]string),
+}
+
+// lookupOwner is the owner with ids `uid` and `gid`, and their names if
+// they're known.
+func lookupOwner(uid, gid uint32) *typesv1.FileOwner {
+	ownerNames.mu.Lock()
+	defer ownerNames.mu.Unlock()
+	name, ok := ownerNames.users[uid]
+	if !ok {
+		if u, err := user.LookupId(strconv.FormatUint(uint64(uid), 10)); err == nil {
+			name = u.Username
+		}
+		ownerNames.users[uid] = name
+	}
+	group, ok := ownerNames.groups[gid]
+	if !ok {
+		if g, err := user.LookupGroupId(strconv.FormatUint(uint64(gid), 10)); err == nil {
+			group = g.Name
+		}
+		ownerNames.groups[gid] = group
+	}
+	return &typesv1.FileOwner{Uid: uid, Gid: gid, User: name, Group: group}
+}
@@ -0,0 +1,93 @@
+//go:build linux
+
+package dirsync
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestAttrsString(t *testing.T) {
+	tests := []struct {
+		attrs Attrs
+		want  string
+	}{
+		{attrs: 0, want: "none"},
+		{attrs: AttrOwner, want: "owner"},
+		{attrs: AttrXattrs, want: "xattrs"},
+		{attrs: AttrOwner | AttrXattrs, want: "owner,xattrs"},
+		{attrs: AttrXattrs | 8, want: "xattrs,Attrs(8)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.attrs.String())
+		})
+	}
+}
+
+func TestSyncXattrs(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a"), []byte("a"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(srcDir, "dir"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "dir", "b"), []byte("b"), 0644))
+	err := unix.Setxattr(filepath.Join(srcDir, "a"), "user.color", []byte("red"), 0)
+	if errors.Is(err, unix.ENOTSUP) {
+		t.Skip("no xattrs on the filesystem of temp dirs")
+	}
+	require.NoError(t, err)
+	require.NoError(t, unix.Setxattr(filepath.Join(srcDir, "dir"), "user.color", []byte("blue"), 0))
+
+	xattrs := func(dir, name string) []*typesv1.Xattr {
+		got, err := readXattrs(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return got
+	}
+	requireSameXattrs := func() {
+		for _, name := range []string{"a", "dir", "dir/b"} {
+			require.Equal(t, xattrs(srcDir, name), xattrs(sinkDir, name), name)
+		}
+	}
+	params := Params{Attrs: AttrXattrs}
+
+	// without asking for them, they're not synced
+	_, err = Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+	require.Empty(t, xattrs(sinkDir, "a"))
+
+	_, err = Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), params)
+	require.NoError(t, err)
+	requireSameXattrs()
+
+	stats, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), params)
+	require.NoError(t, err)
+	require.Zero(t, stats.FilesCreated+stats.FilesPatched+stats.DirsPatched)
+
+	// changing only xattrs is a change, and extra ones are removed
+	require.NoError(t, unix.Setxattr(filepath.Join(srcDir, "a"), "user.color", []byte("green"), 0))
+	require.NoError(t, unix.Removexattr(filepath.Join(srcDir, "dir"), "user.color"))
+	require.NoError(t, unix.Setxattr(filepath.Join(srcDir, "dir", "b"), "user.shape", []byte("round"), 0))
+	stats, err = Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), params)
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.FilesPatched)
+	requireSameXattrs()
+	require.Equal(t, "round", string(xattrs(sinkDir, "dir/b")[0].Value))
+}
+
+func TestLocalSinkKeptAttrs(t *testing.T) {
+	sink, attrs := NewLocalSink(t.TempDir()).WithAttrs(AttrOwner | AttrXattrs)
+	require.IsType(t, &LocalSink{}, sink)
+	if os.Geteuid() == 0 {
+		require.Equal(t, AttrOwner|AttrXattrs, attrs)
+	} else {
+		// owners can't be set, they'd never match
+		require.Equal(t, AttrXattrs, attrs)
+	}
+}
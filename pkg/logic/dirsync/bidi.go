@@ -0,0 +1,560 @@
+package dirsync
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Side is one side of a bidirectional sync: the changes made to it are read
+// from its `Source`, and the ones made to the other side are applied to its
+// `Sink`. Both are for the same tree.
+type Side struct {
+	Source Source
+	Sink   Sink
+}
+
+// ConflictResolution is what was done about a path that changed on both
+// sides.
+type ConflictResolution int
+
+const (
+	// ConflictKeptBoth keeps the remote version at the path, and the local
+	// version next to it in a conflict copy, on both sides.
+	ConflictKeptBoth ConflictResolution = iota
+	// ConflictKeptModified undoes the deletion of a path that was modified
+	// on the other side.
+	ConflictKeptModified
+	// ConflictSkipped leaves the path as it is on both sides, to be resolved
+	// by hand. It's what happens when a side has a dir where the other has
+	// a file.
+	ConflictSkipped
+)
+
+func (cr ConflictResolution) String() string {
+	switch cr {
+	case ConflictKeptBoth:
+		return "kept both"
+	case ConflictKeptModified:
+		return "kept modified"
+	case ConflictSkipped:
+		return "skipped"
+	default:
+		return fmt.Sprintf("ConflictResolution(%d)", int(cr))
+	}
+}
+
+// Conflict is a path that changed on both sides since the last sync.
+type Conflict struct {
+	Path *typesv1.Path
+	// Local and Remote are the infos on each side, nil where the path
+	// was deleted
+	Local  *typesv1.FileInfo
+	Remote *typesv1.FileInfo
+
+	Resolution ConflictResolution
+	// CopyName is the name of the conflict copy, with `ConflictKeptBoth`
+	CopyName string
+}
+
+// SyncBidirectional applies the changes made to each side since the last
+// sync to the other side. The changes are found by diffing each side against
+// `base`, the signatures of the remote as of the last sync, or nil if there
+// was none, in which case nothing is deleted.
+//
+// It returns the base to use next time, along with the conflicts that were
+// found.
+func SyncBidirectional(ctx context.Context, root string, local, remote Side, base *typesv1.DirSum, params Params) (*typesv1.DirSum, []Conflict, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	params.Attrs = 0
+	if base == nil {
+		base = &typesv1.DirSum{Info: &typesv1.FileInfo{IsDir: true}}
+	}
+	localChanges, err := traceChanges(ctx, root, local.Source, base, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("finding local changes: %w", err)
+	}
+	remoteChanges, err := traceChanges(ctx, root, remote.Source, base, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("finding remote changes: %w", err)
+	}
+
+	sched := newScheduler(ctx, params.MaxParallelFileStreams)
+	rc := &reconciler{
+		sched:  sched,
+		params: params,
+		now:    time.Now(),
+		local:  local,
+		remote: remote,
+
+		localChanges:  localChanges,
+		remoteChanges: remoteChanges,
+	}
+	rc.toLocal.create, rc.toLocal.patch, rc.toLocal.delete = scheduleOps(sched, remote.Source, local.Sink, params)
+	rc.toRemote.create, rc.toRemote.patch, rc.toRemote.delete = scheduleOps(sched, local.Source, remote.Sink, params)
+
+	reconcileErr := rc.reconcile(ctx)
+	if err := awaitOps(sched, reconcileErr); err != nil {
+		return nil, nil, err
+	}
+	if reconcileErr != nil {
+		return nil, nil, fmt.Errorf("reconciling changes: %w", reconcileErr)
+	}
+
+	newBase, err := remote.Sink.GetSignatures(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting signatures from remote: %w", err)
+	}
+	for _, path := range rc.skipped {
+		// as if it was never synced, so it's a conflict again next time
+		// instead of the change of one side overwriting the other
+		removeFromDirSum(newBase, path.Elements)
+	}
+	return newBase, rc.conflicts, nil
+}
+
+// ConflictName is the name of the conflict copy of `name`, made on `machine`
+// at `at`. It keeps the extension of `name`.
+func ConflictName(name, machine string, at time.Time) string {
+	ext := path.Ext(name)
+	if ext == name {
+		// a dotfile, like `.profile`
+		ext = ""
+	}
+	if machine == "" {
+		machine = "local"
+	}
+	machine = strings.ReplaceAll(machine, "/", "-")
+	stamp := at.UTC().Format("20060102T150405")
+	return strings.TrimSuffix(name, ext) + ".conflict-" + machine + "-" + stamp + ext
+}
+
+// changeSet is what changed on a side since the last sync.
+type changeSet struct {
+	tree   *SourceDir
+	byPath map[string]*change
+	// dirty are the dirs with something created or modified under them
+	dirty map[string]bool
+}
+
+// change is what happened to a path, as the ops that would apply it to the
+// base.
+type change struct {
+	path *typesv1.Path
+	ops  []changeOp
+}
+
+// oneof: create, patch or delete
+type changeOp struct {
+	create *CreateOp
+	patch  *PatchOp
+	delete *DeleteOp
+}
+
+func traceChanges(ctx context.Context, root string, src Source, base *typesv1.DirSum, params Params) (*changeSet, error) {
+	tree, err := TraceSource(ctx, root, src, params)
+	if err != nil {
+		return nil, fmt.Errorf("enumerating files: %w", err)
+	}
+	cs := &changeSet{
+		tree:   tree,
+		byPath: make(map[string]*change),
+		dirty:  make(map[string]bool),
+	}
+	record := func(path *typesv1.Path, op changeOp) error {
+		key := pathKey(path)
+		ch, ok := cs.byPath[key]
+		if !ok {
+			ch = &change{path: path}
+			cs.byPath[key] = ch
+		}
+		ch.ops = append(ch.ops, op)
+		if op.create != nil || (op.patch != nil && op.patch.File != nil) {
+			for n := len(path.Elements) - 1; n > 0; n-- {
+				cs.dirty[strings.Join(path.Elements[:n], "/")] = true
+			}
+		}
+		return nil
+	}
+	// the diff sets sizes on the dirs it's given
+	base = proto.Clone(base).(*typesv1.DirSum)
+	// every change is detected, the deletes aren't applied here
+	params.NoDelete, params.MaxDeletes, params.MaxDeletePercent = false, 0, 0
+	err = computeTreeDiff(ctx, src, tree, base, params,
+		func(op CreateOp) error { return record(createOpPath(op), changeOp{create: &op}) },
+		func(op PatchOp) error { return record(patchOpPath(op), changeOp{patch: &op}) },
+		func(op DeleteOp) error { return record(op.Path, changeOp{delete: &op}) },
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("diffing against last sync: %w", err)
+	}
+	return cs, nil
+}
+
+// info is what the path is after the change, nil if it was deleted.
+func (ch *change) info() *typesv1.FileInfo {
+	last := ch.ops[len(ch.ops)-1]
+	switch {
+	case last.create != nil:
+		return last.create.FileInfo
+	case last.patch != nil:
+		return last.patch.Info
+	default:
+		return nil
+	}
+}
+
+func (ch *change) deleted() bool {
+	return ch.info() == nil
+}
+
+func (ch *change) deletesDir() bool {
+	for _, op := range ch.ops {
+		if op.delete != nil && op.delete.FileInfo.IsDir {
+			return true
+		}
+	}
+	return false
+}
+
+// modifies tells if the change is more than a change to the info of a dir,
+// which happens whenever entries are added to or removed from it.
+func (ch *change) modifies() bool {
+	for _, op := range ch.ops {
+		if op.create != nil || (op.patch != nil && op.patch.File != nil) {
+			return true
+		}
+	}
+	return false
+}
+
+type emitters struct {
+	create func(CreateOp) error
+	patch  func(PatchOp) error
+	delete func(DeleteOp) error
+}
+
+type reconciler struct {
+	sched  *scheduler
+	params Params
+	now    time.Time
+	local  Side
+	remote Side
+
+	localChanges  *changeSet
+	remoteChanges *changeSet
+	toLocal       emitters
+	toRemote      emitters
+
+	conflicts []Conflict
+	skipped   []*typesv1.Path
+}
+
+func (rc *reconciler) reconcile(ctx context.Context) error {
+	var paths []*typesv1.Path
+	for _, ch := range rc.localChanges.byPath {
+		paths = append(paths, ch.path)
+	}
+	for key, ch := range rc.remoteChanges.byPath {
+		if _, ok := rc.localChanges.byPath[key]; !ok {
+			paths = append(paths, ch.path)
+		}
+	}
+	// parents come before their children
+	slices.SortFunc(paths, func(a, b *typesv1.Path) int {
+		return slices.Compare(a.Elements, b.Elements)
+	})
+
+	// a path that's reconciled with everything under it, the changes
+	// under it are ignored
+	var handled *typesv1.Path
+	for _, path := range paths {
+		if handled != nil && isUnder(path, handled) {
+			continue
+		}
+		key := pathKey(path)
+		local, remote := rc.localChanges.byPath[key], rc.remoteChanges.byPath[key]
+		var (
+			whole bool
+			err   error
+		)
+		switch {
+		case local != nil && remote != nil:
+			whole, err = rc.reconcileBoth(ctx, path, local, remote)
+		case local != nil:
+			whole, err = rc.reconcileOne(path, local, true)
+		default:
+			whole, err = rc.reconcileOne(path, remote, false)
+		}
+		if err != nil {
+			return fmt.Errorf("reconciling %q: %w", key, err)
+		}
+		if whole {
+			handled = path
+		}
+	}
+	return nil
+}
+
+// reconcileOne applies a change made on one side only. It tells if
+// everything under the path was taken care of.
+func (rc *reconciler) reconcileOne(path *typesv1.Path, ch *change, isLocal bool) (bool, error) {
+	to, other := rc.toRemote, rc.remoteChanges
+	if !isLocal {
+		to, other = rc.toLocal, rc.localChanges
+	}
+	if ch.deletesDir() && other.dirty[pathKey(path)] {
+		// the other side changed what's in the dir that was deleted
+		if !ch.deleted() {
+			return true, rc.skip(path)
+		}
+		return true, rc.undoDelete(path, isLocal)
+	}
+	return ch.deletesDir(), replay(ch, to)
+}
+
+// reconcileBoth reconciles a path that changed on both sides. It tells if
+// everything under the path was taken care of.
+func (rc *reconciler) reconcileBoth(ctx context.Context, path *typesv1.Path, local, remote *change) (bool, error) {
+	switch {
+	case local.deleted() && remote.deleted():
+		return local.deletesDir() || remote.deletesDir(), nil
+	case local.deleted():
+		return true, rc.reconcileDelete(path, local, remote, true)
+	case remote.deleted():
+		return true, rc.reconcileDelete(path, remote, local, false)
+	}
+	localInfo, remoteInfo := local.info(), remote.info()
+	switch {
+	case localInfo.IsDir && remoteInfo.IsDir:
+		// their entries are reconciled one by one
+		return false, nil
+	case localInfo.IsDir != remoteInfo.IsDir:
+		return true, rc.skip(path)
+	}
+	same, err := rc.sameContent(ctx, path, localInfo, remoteInfo)
+	if err != nil {
+		return false, fmt.Errorf("comparing versions: %w", err)
+	}
+	if same {
+		// the same change was made on both sides, if only their infos
+		// differ it's sorted out by the next sync
+		return false, nil
+	}
+	return false, rc.keepBoth(path, localInfo, remoteInfo)
+}
+
+// reconcileDelete reconciles a path deleted on a side, and changed on the
+// other.
+func (rc *reconciler) reconcileDelete(path *typesv1.Path, deleted, changed *change, deletedLocally bool) error {
+	other, to := rc.remoteChanges, rc.toRemote
+	if !deletedLocally {
+		other, to = rc.localChanges, rc.toLocal
+	}
+	if changed.modifies() || other.dirty[pathKey(path)] {
+		return rc.undoDelete(path, deletedLocally)
+	}
+	// only the info of the dir changed, the deletion wins
+	return replay(deleted, to)
+}
+
+// undoDelete puts back what was deleted on a side, from the other side.
+func (rc *reconciler) undoDelete(path *typesv1.Path, deletedLocally bool) error {
+	from, to := rc.remoteChanges, rc.toLocal
+	if !deletedLocally {
+		from, to = rc.localChanges, rc.toRemote
+	}
+	conflict := Conflict{Path: path, Resolution: ConflictKeptModified}
+	parent := typesv1.DirOf(path)
+	if dir := findSourceDir(from.tree, path.Elements); dir != nil {
+		if deletedLocally {
+			conflict.Remote = dir.Info
+		} else {
+			conflict.Local = dir.Info
+		}
+		rc.conflicts = append(rc.conflicts, conflict)
+		return emitCreateOpsForDir(parent, dir, nil, to.create, to.patch)
+	}
+	fi := findSourceFile(from.tree, path.Elements)
+	if fi == nil {
+		return fmt.Errorf("missing from the side it wasn't deleted from")
+	}
+	if deletedLocally {
+		conflict.Remote = fi
+	} else {
+		conflict.Local = fi
+	}
+	rc.conflicts = append(rc.conflicts, conflict)
+	return to.create(createFileOp(parent, fi))
+}
+
+func (rc *reconciler) skip(path *typesv1.Path) error {
+	rc.conflicts = append(rc.conflicts, Conflict{
+		Path:       path,
+		Local:      findInfo(rc.localChanges.tree, path.Elements),
+		Remote:     findInfo(rc.remoteChanges.tree, path.Elements),
+		Resolution: ConflictSkipped,
+	})
+	rc.skipped = append(rc.skipped, path)
+	return nil
+}
+
+// keepBoth keeps the remote version at `path`, and moves the local version
+// to a conflict copy, on both sides.
+func (rc *reconciler) keepBoth(path *typesv1.Path, localInfo, remoteInfo *typesv1.FileInfo) error {
+	parent := typesv1.DirOf(path)
+	copyInfo := proto.Clone(localInfo).(*typesv1.FileInfo)
+	copyInfo.Name = ConflictName(localInfo.Name, rc.params.Machine, rc.now)
+	rc.conflicts = append(rc.conflicts, Conflict{
+		Path:       path,
+		Local:      localInfo,
+		Remote:     remoteInfo,
+		Resolution: ConflictKeptBoth,
+		CopyName:   copyInfo.Name,
+	})
+	spath := typesv1.StringFromPath(path)
+	return rc.sched.Submit(func(ctx context.Context) error {
+		if err := copyFile(ctx, rc.local.Source, spath, rc.remote.Sink, parent, copyInfo); err != nil {
+			return fmt.Errorf("creating remote conflict copy: %w", err)
+		}
+		if err := copyFile(ctx, rc.local.Source, spath, rc.local.Sink, parent, copyInfo); err != nil {
+			return fmt.Errorf("creating local conflict copy: %w", err)
+		}
+		if err := rc.local.Sink.DeleteFile(ctx, DeleteOp{Path: path, FileInfo: localInfo}); err != nil {
+			return fmt.Errorf("deleting local version: %w", err)
+		}
+		return upload(ctx, rc.remote.Source, rc.local.Sink, createFileOp(parent, remoteInfo), rc.params.UnstableRetries)
+	}, path, typesv1.PathJoin(parent, copyInfo.Name))
+}
+
+// sameContent tells if both versions of a file have the same content.
+func (rc *reconciler) sameContent(ctx context.Context, path *typesv1.Path, localInfo, remoteInfo *typesv1.FileInfo) (bool, error) {
+	if localInfo.IsSymlink() || remoteInfo.IsSymlink() {
+		return localInfo.IsSymlink() == remoteInfo.IsSymlink() && localInfo.LinkTarget == remoteInfo.LinkTarget, nil
+	}
+	if localInfo.Size != remoteInfo.Size {
+		return false, nil
+	}
+	spath := typesv1.StringFromPath(path)
+	lf, err := rc.local.Source.Open(spath)
+	if err != nil {
+		return false, fmt.Errorf("opening local version: %w", err)
+	}
+	defer lf.Close()
+	sum, err := ComputeFileSum(ctx, lf, localInfo)
+	if err != nil {
+		return false, fmt.Errorf("summing local version: %w", err)
+	}
+	rf, err := rc.remote.Source.Open(spath)
+	if err != nil {
+		return false, fmt.Errorf("opening remote version: %w", err)
+	}
+	defer rf.Close()
+	if remoteFile, ok := rf.(RemoteFile); ok {
+		return remoteFile.MatchesFileSum(ctx, sum)
+	}
+	return FileMatchesFileSum(ctx, sum, rf, remoteInfo.Size)
+}
+
+func replay(ch *change, to emitters) error {
+	for _, op := range ch.ops {
+		var err error
+		switch {
+		case op.create != nil:
+			err = to.create(*op.create)
+		case op.patch != nil:
+			err = to.patch(*op.patch)
+		case op.delete != nil:
+			err = to.delete(*op.delete)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFile creates the file at `path` on `src` in the `dir` of `sink`, with
+// the info `fi`.
+func copyFile(ctx context.Context, src Source, path string, sink Sink, dir *typesv1.Path, fi *typesv1.FileInfo) error {
+	if fi.IsSymlink() {
+		return sink.CreateFile(ctx, dir, fi, nil)
+	}
+	f, err := src.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening %q: %w", path, err)
+	}
+	defer f.Close()
+	return sink.CreateFile(ctx, dir, fi, f)
+}
+
+func pathKey(path *typesv1.Path) string {
+	return strings.Join(path.Elements, "/")
+}
+
+// isUnder tells if `path` is strictly under `dir`.
+func isUnder(path, dir *typesv1.Path) bool {
+	return len(path.Elements) > len(dir.Elements) && slices.Equal(path.Elements[:len(dir.Elements)], dir.Elements)
+}
+
+func findSourceDir(dir *SourceDir, elements []string) *SourceDir {
+	for _, name := range elements {
+		i := slices.IndexFunc(dir.Dirs, func(child *SourceDir) bool { return child.Info.Name == name })
+		if i < 0 {
+			return nil
+		}
+		dir = dir.Dirs[i]
+	}
+	return dir
+}
+
+func findSourceFile(dir *SourceDir, elements []string) *typesv1.FileInfo {
+	if len(elements) == 0 {
+		return nil
+	}
+	parent := findSourceDir(dir, elements[:len(elements)-1])
+	if parent == nil {
+		return nil
+	}
+	name := elements[len(elements)-1]
+	i := slices.IndexFunc(parent.Files, func(file *SourceFile) bool { return file.Info.Name == name })
+	if i < 0 {
+		return nil
+	}
+	return parent.Files[i].Info
+}
+
+func findInfo(dir *SourceDir, elements []string) *typesv1.FileInfo {
+	if found := findSourceDir(dir, elements); found != nil {
+		return found.Info
+	}
+	return findSourceFile(dir, elements)
+}
+
+// removeFromDirSum removes an entry from the tree, updating the digests of
+// the dirs above it.
+func removeFromDirSum(dir *typesv1.DirSum, elements []string) {
+	if len(elements) == 0 {
+		return
+	}
+	name := elements[0]
+	if len(elements) > 1 {
+		i := slices.IndexFunc(dir.Dirs, func(child *typesv1.DirSum) bool { return child.Info.Name == name })
+		if i < 0 {
+			return
+		}
+		removeFromDirSum(dir.Dirs[i], elements[1:])
+	} else {
+		dir.Dirs = slices.DeleteFunc(dir.Dirs, func(child *typesv1.DirSum) bool { return child.Info.Name == name })
+		dir.Files = slices.DeleteFunc(dir.Files, func(file *typesv1.FileSum) bool { return file.Info.Name == name })
+	}
+	dir.Digest = sinkDirDigest(dir)
+}
@@ -0,0 +1,98 @@
+package dirsync
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncBidirectional(t *testing.T) {
+	localDir, remoteDir := t.TempDir(), t.TempDir()
+	mkfile := func(root, name, content string) {
+		filename := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+	}
+	readfile := func(root, name string) string {
+		content, err := os.ReadFile(filepath.Join(root, name))
+		require.NoError(t, err, name)
+		return string(content)
+	}
+	local := Side{Source: NewLocalSource(localDir), Sink: NewLocalSink(localDir)}
+	remote := Side{Source: NewLocalSource(remoteDir), Sink: NewLocalSink(remoteDir)}
+	params := Params{Machine: "laptop"}
+	ctx := context.Background()
+
+	mkfile(localDir, "a/one", "one")
+	mkfile(localDir, "a/two", "two")
+	mkfile(localDir, "both", "both")
+	mkfile(localDir, "gone/file", "file")
+	mkfile(localDir, "delete-me", "bye")
+
+	// without a base, it's all new to the remote
+	base, conflicts, err := SyncBidirectional(ctx, ".", local, remote, nil, params)
+	require.NoError(t, err)
+	require.Empty(t, conflicts)
+	require.Equal(t, "two", readfile(remoteDir, "a/two"))
+	require.Equal(t, "file", readfile(remoteDir, "gone/file"))
+
+	mkfile(localDir, "a/one", "one, changed locally")
+	mkfile(remoteDir, "a/two", "two, changed remotely")
+	mkfile(localDir, "both", "both, changed locally")
+	mkfile(remoteDir, "both", "both, changed remotely too")
+	require.NoError(t, os.RemoveAll(filepath.Join(localDir, "gone")))
+	mkfile(remoteDir, "gone/new", "new")
+	require.NoError(t, os.Remove(filepath.Join(localDir, "delete-me")))
+	mkfile(remoteDir, "r/new", "from remote")
+
+	base, conflicts, err = SyncBidirectional(ctx, ".", local, remote, base, params)
+	require.NoError(t, err)
+	require.Len(t, conflicts, 2)
+	require.Equal(t, []string{"both"}, conflicts[0].Path.Elements)
+	require.Equal(t, ConflictKeptBoth, conflicts[0].Resolution)
+	require.Equal(t, []string{"gone"}, conflicts[1].Path.Elements)
+	require.Equal(t, ConflictKeptModified, conflicts[1].Resolution)
+	copyName := conflicts[0].CopyName
+
+	for _, dir := range []string{localDir, remoteDir} {
+		require.Equal(t, "one, changed locally", readfile(dir, "a/one"), dir)
+		require.Equal(t, "two, changed remotely", readfile(dir, "a/two"), dir)
+		require.Equal(t, "both, changed remotely too", readfile(dir, "both"), dir)
+		require.Equal(t, "both, changed locally", readfile(dir, copyName), dir)
+		require.Equal(t, "file", readfile(dir, "gone/file"), dir)
+		require.Equal(t, "new", readfile(dir, "gone/new"), dir)
+		require.Equal(t, "from remote", readfile(dir, "r/new"), dir)
+		_, err := os.Stat(filepath.Join(dir, "delete-me"))
+		require.ErrorIs(t, err, fs.ErrNotExist, dir)
+	}
+
+	// both sides are the same, there's nothing left to do
+	_, conflicts, err = SyncBidirectional(ctx, ".", local, remote, base, params)
+	require.NoError(t, err)
+	require.Empty(t, conflicts)
+}
+
+func TestConflictName(t *testing.T) {
+	at := time.Date(2024, 4, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name    string
+		machine string
+		want    string
+	}{
+		{name: "notes.txt", machine: "laptop", want: "notes.conflict-laptop-20240402T150405.txt"},
+		{name: "archive.tar.gz", machine: "laptop", want: "archive.tar.conflict-laptop-20240402T150405.gz"},
+		{name: "Makefile", machine: "laptop", want: "Makefile.conflict-laptop-20240402T150405"},
+		{name: ".profile", machine: "laptop", want: ".profile.conflict-laptop-20240402T150405"},
+		{name: "notes.txt", machine: "", want: "notes.conflict-local-20240402T150405.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.machine, func(t *testing.T) {
+			require.Equal(t, tt.want, ConflictName(tt.name, tt.machine, at))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package dirsync
+
+import (
+	"fmt"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Compare is how files that are on both sides are found to differ.
+type Compare int
+
+const (
+	// CompareDefault patches the files whose info differs, and reads the
+	// others to compare their content with their sum on the sink.
+	CompareDefault Compare = iota
+	// CompareChecksum ignores mod times, and reads every file with the same
+	// info otherwise to compare its content. It's for trees whose mod times
+	// were lost, like copies made without keeping them. The mod times of
+	// files with the same content are left as they are on the sink.
+	CompareChecksum
+	// CompareSizeOnly ignores mod times, and patches the files whose info
+	// differs otherwise. Content is never read.
+	CompareSizeOnly
+	// CompareTrustMtime patches the files whose info differs, and trusts the
+	// others to be the same. Content is never read.
+	CompareTrustMtime
+)
+
+func (c Compare) String() string {
+	switch c {
+	case CompareDefault:
+		return "default"
+	case CompareChecksum:
+		return "checksum"
+	case CompareSizeOnly:
+		return "size-only"
+	case CompareTrustMtime:
+		return "trust-mtime"
+	default:
+		return fmt.Sprintf("Compare(%d)", int(c))
+	}
+}
+
+// infoDiffers tells if the infos of a file on both sides differ, per `c`.
+func (c Compare) infoDiffers(src, sink *typesv1.FileInfo) bool {
+	switch c {
+	case CompareChecksum, CompareSizeOnly:
+		return !equalButModTime(src, sink)
+	default:
+		return !proto.Equal(src, sink)
+	}
+}
+
+// readsContent tells if the content of files with the same info is compared.
+func (c Compare) readsContent() bool {
+	return c == CompareDefault || c == CompareChecksum
+}
+
+// equalButModTime tells if `a` and `b` are equal, mod times aside.
+func equalButModTime(a, b *typesv1.FileInfo) bool {
+	if proto.Equal(a.ModTime, b.ModTime) {
+		return proto.Equal(a, b)
+	}
+	a = proto.Clone(a).(*typesv1.FileInfo)
+	a.ModTime = b.ModTime
+	return proto.Equal(a, b)
+}
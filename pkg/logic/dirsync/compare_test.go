@@ -0,0 +1,89 @@
+package dirsync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncCompare(t *testing.T) {
+	ctx := context.Background()
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name    string
+		compare Compare
+		// touched has the same content on both sides but another mod time,
+		// edited has another content of the same size and mod time
+		wantPatched []string
+		// what's read to compare and to patch, of files of 4 bytes
+		wantBytesRead uint64
+	}{
+		{name: "default", compare: CompareDefault, wantPatched: []string{"edited", "touched"}, wantBytesRead: 3 * 4},
+		{name: "checksum", compare: CompareChecksum, wantPatched: []string{"edited"}, wantBytesRead: 3 * 4},
+		{name: "size only", compare: CompareSizeOnly},
+		{name: "trust mtime", compare: CompareTrustMtime, wantPatched: []string{"touched"}, wantBytesRead: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srcDir, sinkDir := t.TempDir(), t.TempDir()
+			write := func(dir, name, content string, modTime time.Time) {
+				path := filepath.Join(dir, name)
+				require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+				require.NoError(t, os.Chtimes(path, modTime, modTime))
+			}
+			write(srcDir, "touched", "same", modTime.Add(time.Hour))
+			write(sinkDir, "touched", "same", modTime)
+			write(srcDir, "edited", "new!", modTime)
+			write(sinkDir, "edited", "old!", modTime)
+
+			stats, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{Compare: tt.compare})
+			require.NoError(t, err)
+			require.Equal(t, len(tt.wantPatched), stats.FilesPatched)
+			require.Equal(t, tt.wantBytesRead, stats.BytesRead)
+			for _, name := range tt.wantPatched {
+				got, err := os.ReadFile(filepath.Join(sinkDir, name))
+				require.NoError(t, err)
+				want, err := os.ReadFile(filepath.Join(srcDir, name))
+				require.NoError(t, err)
+				require.Equal(t, string(want), string(got))
+			}
+		})
+	}
+}
+
+func TestCompareString(t *testing.T) {
+	require.Equal(t, "checksum", CompareChecksum.String())
+	require.Equal(t, "size-only", CompareSizeOnly.String())
+	require.Equal(t, "trust-mtime", CompareTrustMtime.String())
+	require.Equal(t, "Compare(42)", Compare(42).String())
+}
+
+func TestSyncChecksumBelowRoot(t *testing.T) {
+	ctx := context.Background()
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	for dir, content := range map[string]string{srcDir: "new!", sinkDir: "old!"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "a", "b"), 0755))
+		path := filepath.Join(dir, "a", "b", "edited")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		for _, name := range []string{"a/b/edited", "a/b", "a"} {
+			require.NoError(t, os.Chtimes(filepath.Join(dir, name), modTime, modTime))
+		}
+	}
+
+	// no info differs, the digests of the subtrees are the same
+	stats, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+	require.Zero(t, stats.FilesPatched)
+
+	stats, err = Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{Compare: CompareChecksum})
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.FilesPatched)
+	got, err := os.ReadFile(filepath.Join(sinkDir, "a", "b", "edited"))
+	require.NoError(t, err)
+	require.Equal(t, "new!", string(got))
+}
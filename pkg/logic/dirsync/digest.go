@@ -0,0 +1,187 @@
+package dirsync
+
+import (
+	"encoding/binary"
+	"strings"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"google.golang.org/protobuf/proto"
+	"lukechampine.com/blake3"
+)
+
+// DirDigest is a merkle digest of the entries of a dir: the metadata of its
+// files, and the metadata and digests of its subdirs. The metadata of the dir
+// itself is not part of its digest, it's part of its parent's.
+//
+// The content of files isn't hashed: like for a single file, a subtree where
+// every entry has the same name, size, mode, mod time and attributes is
+// assumed to be unchanged, unless checksums are compared.
+type DirDigest struct {
+	h *blake3.Hasher
+}
+
+func NewDirDigest() *DirDigest {
+	return &DirDigest{h: blake3.New(32, nil)}
+}
+
+// AddDir adds a subdir to the digest. Entries must be added in the same
+// order on both sides for their digests to match, by convention dirs then
+// files, each ordered by name.
+func (dd *DirDigest) AddDir(fi *typesv1.FileInfo, digest []byte) {
+	dd.add('d', fi)
+	dd.write(digest)
+}
+
+// AddFile adds a file to the digest.
+func (dd *DirDigest) AddFile(fi *typesv1.FileInfo) {
+	if fi.IsSymlink() {
+		dd.add('l', fi)
+		dd.write([]byte(fi.LinkTarget))
+		return
+	}
+	if fi.HardLink != nil {
+		// a file that becomes a link to another, or stops being one,
+		// differs even with the same info
+		dd.add('h', fi)
+		_, _ = dd.h.Write(binary.BigEndian.AppendUint64(nil, fi.Size))
+		dd.write([]byte(strings.Join(fi.HardLink.Elements, "/")))
+		return
+	}
+	dd.add('f', fi)
+	buf := binary.BigEndian.AppendUint64(nil, fi.Size)
+	_, _ = dd.h.Write(buf)
+}
+
+func (dd *DirDigest) Sum() []byte {
+	return dd.h.Sum(nil)
+}
+
+func (dd *DirDigest) add(kind byte, fi *typesv1.FileInfo) {
+	buf := []byte{kind}
+	buf = binary.BigEndian.AppendUint32(buf, fi.Mode)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(fi.GetModTime().GetSeconds()))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(fi.GetModTime().GetNanos()))
+	_, _ = dd.h.Write(buf)
+	dd.write([]byte(fi.Name))
+	if fi.Attrs != nil {
+		// left out otherwise, for digests to stay the same when no
+		// attributes are synced
+		attrs, _ := proto.MarshalOptions{Deterministic: true}.Marshal(fi.Attrs)
+		dd.write(attrs)
+	}
+}
+
+// write writes length-prefixed bytes, so that entries can't be confused
+// with one another.
+func (dd *DirDigest) write(p []byte) {
+	buf := binary.BigEndian.AppendUint64(nil, uint64(len(p)))
+	_, _ = dd.h.Write(buf)
+	_, _ = dd.h.Write(p)
+}
+
+func sourceDirDigest(dir *SourceDir) []byte {
+	dd := NewDirDigest()
+	for _, child := range dir.Dirs {
+		dd.AddDir(child.Info, child.Digest)
+	}
+	for _, file := range dir.Files {
+		dd.AddFile(file.Info)
+	}
+	return dd.Sum()
+}
+
+// rehashSource sets the digests of `dir` and its subdirs again, once the
+// info of their files changed.
+func rehashSource(dir *SourceDir) {
+	for _, child := range dir.Dirs {
+		rehashSource(child)
+	}
+	dir.Digest = sourceDirDigest(dir)
+}
+
+func sinkDirDigest(dir *typesv1.DirSum) []byte {
+	dd := NewDirDigest()
+	for _, child := range dir.Dirs {
+		dd.AddDir(child.Info, child.Digest)
+	}
+	for _, file := range dir.Files {
+		dd.AddFile(file.Info)
+	}
+	return dd.Sum()
+}
+
+// rehashSink is like `rehashSource`, for the signatures of a sink.
+func rehashSink(dir *typesv1.DirSum) {
+	for _, child := range dir.Dirs {
+		rehashSink(child)
+	}
+	dir.Digest = sinkDirDigest(dir)
+}
+
+// setKeptDigests sets the digests that the dirs of `src` have on `sink` once
+// it's synced, with the entries that the sink keeps though they aren't on
+// the source: the excluded and skipped ones. Otherwise, every dir above a
+// kept entry would differ from the source, and be compared on every sync.
+func setKeptDigests(params Params, path *typesv1.Path, src *SourceDir, sink *typesv1.DirSum) {
+	src.keptDigest = nil
+	if len(sink.GetDigest()) == 0 {
+		return
+	}
+	changed := false
+	for _, child := range src.Dirs {
+		if sinkChild, found := sinkHasDirNamed(sink, child.Info.Name); found {
+			setKeptDigests(params, typesv1.PathJoin(path, child.Info.Name), child, sinkChild)
+			changed = changed || child.keptDigest != nil
+		}
+	}
+	var keptDirs []*typesv1.DirSum
+	for _, sinkDir := range sink.Dirs {
+		if keepOnSink(params, src, typesv1.PathJoin(path, sinkDir.Info.Name), sinkDir.Info) {
+			keptDirs = append(keptDirs, sinkDir)
+		}
+	}
+	var keptFiles []*typesv1.FileSum
+	for _, sinkFile := range sink.Files {
+		if keepOnSink(params, src, typesv1.PathJoin(path, sinkFile.Info.Name), sinkFile.Info) {
+			keptFiles = append(keptFiles, sinkFile)
+		}
+	}
+	if !changed && len(keptDirs) == 0 && len(keptFiles) == 0 {
+		return
+	}
+
+	// entries are added in the order of the sink, by name
+	dd := NewDirDigest()
+	dirs := src.Dirs
+	for _, kept := range keptDirs {
+		for len(dirs) > 0 && strings.Compare(dirs[0].Info.Name, kept.Info.Name) < 0 {
+			dd.AddDir(dirs[0].Info, dirs[0].syncedDigest())
+			dirs = dirs[1:]
+		}
+		dd.AddDir(kept.Info, kept.Digest)
+	}
+	for _, dir := range dirs {
+		dd.AddDir(dir.Info, dir.syncedDigest())
+	}
+	files := src.Files
+	for _, kept := range keptFiles {
+		for len(files) > 0 && strings.Compare(files[0].Info.Name, kept.Info.Name) < 0 {
+			dd.AddFile(files[0].Info)
+			files = files[1:]
+		}
+		dd.AddFile(kept.Info)
+	}
+	for _, file := range files {
+		dd.AddFile(file.Info)
+	}
+	src.keptDigest = dd.Sum()
+}
+
+// syncedDigest is the digest of the dir on the sink once it's synced, see
+// `setKeptDigests`.
+func (dir *SourceDir) syncedDigest() []byte {
+	if dir.keptDigest != nil {
+		return dir.keptDigest
+	}
+	return dir.Digest
+}
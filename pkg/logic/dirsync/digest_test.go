@@ -0,0 +1,101 @@
+package dirsync
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDigestPrunesUnchangedSubtrees(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	src := fstest.MapFS{
+		"a/x":   &fstest.MapFile{Data: []byte("hello"), ModTime: now},
+		"a/b/y": &fstest.MapFile{Data: []byte("hello world"), ModTime: now},
+		"c/z":   &fstest.MapFile{Data: []byte("le monde"), ModTime: now},
+	}
+	srcDir, err := TraceSource(ctx, ".", src, Params{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		change func(sigs *typesv1.DirSum)
+		want   []string
+	}{
+		{
+			name: "no changes",
+			want: nil,
+		},
+		{
+			name: "file changed in a subtree",
+			change: func(sigs *typesv1.DirSum) {
+				// c/z
+				sigs.Dirs[1].Files[0].Info.ModTime = timestamppb.New(now.Add(-time.Hour))
+			},
+			want: []string{"patch c/z"},
+		},
+		{
+			name: "file removed deep in a subtree",
+			change: func(sigs *typesv1.DirSum) {
+				// a/b/y
+				sigs.Dirs[0].Dirs[0].Files = nil
+			},
+			// `a` is descended into, so its files are verified against
+			// their sums, which this sink doesn't have
+			want: []string{"create a/b/y", "patch a/x"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sigs := sinkFromSource(nil, srcDir)
+			if tt.change != nil {
+				tt.change(sigs)
+				rehashSink(sigs)
+			}
+			var got []string
+			err := ComputeTreeDiff(ctx, typesv1.PathFromString("."), src, sigs, Params{},
+				func(co CreateOp) error {
+					got = append(got, "create "+typesv1.StringFromPath(createOpPath(co)))
+					return nil
+				},
+				func(po PatchOp) error {
+					got = append(got, "patch "+typesv1.StringFromPath(patchOpPath(po)))
+					return nil
+				},
+				func(do DeleteOp) error {
+					got = append(got, "delete "+typesv1.StringFromPath(do.Path))
+					return nil
+				},
+				nil,
+			)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// sinkFromSource mirrors a traced source as the signatures a sink holding
+// the same files would return, without the content sums.
+func sinkFromSource(parent *typesv1.Path, dir *SourceDir) *typesv1.DirSum {
+	out := &typesv1.DirSum{
+		Path:   parent,
+		Info:   proto.Clone(dir.Info).(*typesv1.FileInfo),
+		Digest: dir.Digest,
+	}
+	path := typesv1.PathJoin(parent, dir.Info.Name)
+	for _, child := range dir.Dirs {
+		out.Dirs = append(out.Dirs, sinkFromSource(path, child))
+	}
+	for _, file := range dir.Files {
+		out.Files = append(out.Files, &typesv1.FileSum{
+			Info: proto.Clone(file.Info).(*typesv1.FileInfo),
+		})
+	}
+	return out
+}
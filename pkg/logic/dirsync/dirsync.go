@@ -1,11 +1,15 @@
 package dirsync
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"slices"
 	"strings"
+	"time"
 
 	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
 	"google.golang.org/protobuf/proto"
@@ -23,53 +27,315 @@ func assert(msg string, cond bool) {
 }
 
 type Params struct {
+	// MaxParallelFileStreams is the max number of ops (uploads, patches,
+	// deletes) being applied to the sink at any given time.
 	MaxParallelFileStreams int
+	// Symlinks is what to do with the symlinks found on the source.
+	Symlinks SymlinkPolicy
+	// Exclude are gitignore-style patterns of paths not to sync, on top of
+	// the ones in `IgnoreFile`s. They're relative to the root.
+	Exclude []string
+	// Include are gitignore-style patterns of paths to sync even if they
+	// are excluded. Like with gitignore, paths in an excluded dir can't be
+	// included back.
+	Include []string
+	// DeleteExcluded deletes the excluded paths from the sink, instead of
+	// leaving them alone.
+	DeleteExcluded bool
+	// Machine is the name of the machine syncing, used to name the conflict
+	// copies of bidirectional syncs.
+	Machine string
+	// Index, if set, spares reading the files that are unchanged since the
+	// last sync, unless checksums are compared. It's updated once a sync
+	// succeeds.
+	Index *Index
+	// Streaming diffs and applies the changes dir by dir as the trees are
+	// walked, instead of tracing them whole first. It's for trees too large
+	// to hold in memory: subtrees that are the same on both sides are still
+	// walked, and neither renames nor hard links are detected.
+	Streaming bool
+	// Journal, if set, records the ops of the sync as they're applied, for
+	// the sync to be finished with `Resume` if it's interrupted. Large files
+	// whose upload is interrupted are resumed where they stopped, if the
+	// sink is a `ResumableSink`. Streaming syncs ignore it.
+	Journal *Journal
+	// NoDelete leaves alone what's on the sink but not on the source, for
+	// the sink to only ever be added to. What's moved on the source is copied
+	// rather than moved. Entries replaced by one of another kind are still
+	// deleted. Bidirectional syncs ignore it and the limits below.
+	NoDelete bool
+	// MaxDeletes, if set, fails the sync before anything is deleted if it
+	// would delete more than this many files from the sink.
+	MaxDeletes int
+	// MaxDeletePercent, if set, fails the sync before anything is deleted if
+	// it would delete more than this percentage of the files of the sink.
+	MaxDeletePercent float64
+	// Attrs are the attributes of files synced on top of their mode and mod
+	// time. The ones the sink can't apply are left out, like owners on a
+	// local dir that isn't written to as root. Bidirectional syncs ignore
+	// them.
+	Attrs Attrs
+	// UnstableRetries is how many more times a file is sent if it changed
+	// since it was traced, or changes while it's sent. Files that keep
+	// changing are skipped, see `SyncStats.Unstable`.
+	UnstableRetries int
+	// Compare is how files that are on both sides are found to differ.
+	Compare Compare
+	// SpecialFiles is what to do with the entries of the source that are
+	// neither files, dirs nor symlinks.
+	SpecialFiles ErrorPolicy
+	// Unreadable is what to do with the files and dirs of the source that
+	// can't be read for lack of permissions.
+	Unreadable ErrorPolicy
+	// EscapingSymlinks is what to do with the symlinks of the source whose
+	// target isn't in the tree being synced. They're never synced.
+	EscapingSymlinks ErrorPolicy
+	// Observer, if set, is told about the ops of `Sync` and `ExecutePlan` as
+	// they're planned and applied.
+	Observer Observer
 }
 
-func Sync(ctx context.Context, root string, src Source, sink Sink, params Params) error {
+// Sync makes the sink like the source. It returns what was done, even if it
+// fails part way.
+func Sync(ctx context.Context, root string, src Source, sink Sink, params Params) (*SyncStats, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
+	rec := &statsRecorder{start: time.Now()}
+	ctx = withObserver(withStats(ctx, rec), params.Observer)
+	sink, params = withSinkAttrs(sink, params)
+	src, err := withSinkNames(ctx, src, sink)
+	if err != nil {
+		return rec.done(), err
+	}
+	if params.Streaming {
+		err = streamSync(ctx, root, src, sink, params, nil)
+	} else {
+		err = syncTree(ctx, root, src, sink, params)
+	}
+	return rec.done(), err
+}
+
+func syncTree(ctx context.Context, root string, src Source, sink Sink, params Params) error {
 	// We diff two trees instead of a list of items. By diffing trees top-down, we can issue
 	// 1 deletes for an entire tree, instead of a list of deletes for each file under a tree.
-	// It also allows for the opportunity (future) to make merkle trees to efficiently identify
-	// branches in the tree that have changes (not done here).
-	sigs, err := sink.GetSignatures(ctx)
+	// Both trees carry merkle digests, so that branches without changes are skipped entirely.
+	sigs, lazy, err := getSignatures(ctx, sink)
 	if err != nil {
 		return fmt.Errorf("getting signatures from sink: %w", err)
 	}
-
-	rootp := typesv1.PathFromString(root)
-	err = ComputeTreeDiff(ctx, rootp, src, sigs,
-		func(co CreateOp) error {
-			return upload(ctx, src, sink, co)
-		},
-		func(co PatchOp) error {
-			return patch(ctx, src, sink, co)
-		},
-		func(co DeleteOp) error {
-			return sink.DeleteFile(ctx, co)
-		},
-	)
+	srcDir, err := TraceSource(ctx, root, src, params)
 	if err != nil {
-		return fmt.Errorf("computing tree diff: %w", err)
+		return fmt.Errorf("enumerating files on source: %w", err)
+	}
+	setKeptDigests(params, &typesv1.Path{}, srcDir, sigs)
+	moveSink, canMove := moveSinkOf(sink, params)
+	if lazy != nil {
+		if err := fetchFileSums(ctx, lazy, diffFileSums(params, srcDir, sigs, canMove)); err != nil {
+			return fmt.Errorf("getting file sums from sink: %w", err)
+		}
+	}
+	statsFrom(ctx).traced()
+
+	// ops are executed as they are emitted by the diff, in parallel when
+	// they don't touch overlapping paths
+	ctx = withJournal(ctx, params.Journal)
+	sched := newScheduler(ctx, params.MaxParallelFileStreams)
+	emitCreate, emitPatch, emitDelete := scheduleOps(sched, src, sink, params)
+
+	var emitMove func(MoveOp) error
+	if canMove {
+		emitMove = scheduleMoves(sched, moveSink)
+	}
+
+	if params.Journal != nil {
+		if err := params.Journal.begin(&typesv1.SyncPlan{}); err != nil {
+			return fmt.Errorf("journaling plan: %w", err)
+		}
+		emitCreate, emitPatch, emitDelete, emitMove = journalOps(params.Journal, emitCreate, emitPatch, emitDelete, emitMove)
+	}
+	diffErr := computeTreeDiff(ctx, src, srcDir, sigs, params, emitCreate, emitPatch, emitDelete, emitMove)
+	if diffErr == nil {
+		diffErr = params.Journal.planned()
+	}
+	if err := awaitOps(sched, diffErr); err != nil {
+		return err
+	}
+	if diffErr != nil {
+		return fmt.Errorf("computing tree diff: %w", diffErr)
+	}
+	params.Index.update(srcDir)
+	if err := params.Journal.finish(); err != nil {
+		return fmt.Errorf("finishing journal: %w", err)
 	}
 	return nil
 }
 
-func ComputeTreeDiff(ctx context.Context, root *typesv1.Path, src Source, sinkDir *typesv1.DirSum,
+// scheduleOps returns emitters that submit the ops to `sched`, to be applied
+// from `src` onto `sink`. Files that change as they're sent are tried again,
+// and the ones that can't be read are skipped, per `params`.
+func scheduleOps(sched *scheduler, src Source, sink Sink, params Params) (
 	emitCreate func(CreateOp) error,
 	emitPatch func(PatchOp) error,
 	emitDelete func(DeleteOp) error,
+) {
+	emitCreate = func(co CreateOp) error {
+		ev := Event{Op: "create", Path: createOpPath(co), IsDir: co.FileInfo.IsDir, Size: co.FileInfo.Size}
+		observe(sched.ctx, plannedEvent(ev))
+		return sched.Submit(func(ctx context.Context) error {
+			return runOp(ctx, ev, func(ctx context.Context) error {
+				return skipOp(ctx, params, ev.Path, upload(ctx, src, sink, co, params.UnstableRetries))
+			})
+		}, opPaths(ev.Path, co.FileInfo)...)
+	}
+	emitPatch = func(co PatchOp) error {
+		ev := Event{Op: "patch", Path: patchOpPath(co), IsDir: co.Dir != nil}
+		if !ev.IsDir {
+			ev.Size = co.Info.Size
+		}
+		observe(sched.ctx, plannedEvent(ev))
+		return sched.Submit(func(ctx context.Context) error {
+			return runOp(ctx, ev, func(ctx context.Context) error {
+				return skipOp(ctx, params, ev.Path, patch(ctx, src, sink, co, params.UnstableRetries))
+			})
+		}, opPaths(ev.Path, co.Info)...)
+	}
+	emitDelete = func(co DeleteOp) error {
+		ev := Event{Op: "delete", Path: co.Path, IsDir: co.FileInfo.IsDir}
+		observe(sched.ctx, plannedEvent(ev))
+		return sched.Submit(func(ctx context.Context) error {
+			return runOp(ctx, ev, func(ctx context.Context) error {
+				return sink.DeleteFile(ctx, co)
+			})
+		}, ev.Path)
+	}
+	return emitCreate, emitPatch, emitDelete
+}
+
+// moveSinkOf returns `sink` if it can move entries and `params` let it,
+// moves deleting what they move away.
+func moveSinkOf(sink Sink, params Params) (MoveSink, bool) {
+	if params.NoDelete {
+		return nil, false
+	}
+	moveSink, ok := sink.(MoveSink)
+	return moveSink, ok
+}
+
+// awaitOps waits for the ops submitted to `sched` to be done. If emitting
+// the ops failed with `emitErr`, the ops that are still pending are canceled.
+// It fails if an op did, in which case emitting them may have failed only
+// because `sched` was stopped, and `emitErr` is left out.
+func awaitOps(sched *scheduler, emitErr error) error {
+	var stopped *stoppedError
+	if emitErr != nil && !errors.As(emitErr, &stopped) {
+		sched.stop(emitErr)
+	}
+	if err := sched.Wait(); err != nil && err != emitErr {
+		return fmt.Errorf("applying changes to sink: %w", err)
+	}
+	return nil
+}
+
+func createOpPath(op CreateOp) *typesv1.Path {
+	return typesv1.PathJoin(op.ParentDir, op.FileInfo.Name)
+}
+
+func patchOpPath(op PatchOp) *typesv1.Path {
+	if op.Dir != nil {
+		return typesv1.PathJoin(op.Path, op.Info.Name)
+	}
+	return typesv1.PathJoin(op.Path, op.File.Sum.Info.Name)
+}
+
+// ComputeTreeDiff emits the ops that make `sinkDir` like the source. If
+// `emitMove` is set, what's moved on the source is moved on the sink too,
+// otherwise it's deleted and created anew.
+func ComputeTreeDiff(ctx context.Context, root *typesv1.Path, src Source, sinkDir *typesv1.DirSum, params Params,
+	emitCreate func(CreateOp) error,
+	emitPatch func(PatchOp) error,
+	emitDelete func(DeleteOp) error,
+	emitMove func(MoveOp) error,
 ) error {
 	rootp := typesv1.StringFromPath(root)
-	srcDir, err := TraceSource(ctx, rootp, src)
+	srcDir, err := TraceSource(ctx, rootp, src, params)
 	if err != nil {
 		return fmt.Errorf("enumerating files on source: %w", err)
 	}
-	return computeDirDiff(ctx, src, &typesv1.Path{}, srcDir, sinkDir, emitCreate, emitPatch, emitDelete)
+	setKeptDigests(params, &typesv1.Path{}, srcDir, sinkDir)
+	return computeTreeDiff(ctx, src, srcDir, sinkDir, params, emitCreate, emitPatch, emitDelete, emitMove)
+}
+
+// computeTreeDiff is `ComputeTreeDiff` for a source that's traced already.
+func computeTreeDiff(ctx context.Context, src Source, srcDir *SourceDir, sinkDir *typesv1.DirSum, params Params,
+	emitCreate func(CreateOp) error,
+	emitPatch func(PatchOp) error,
+	emitDelete func(DeleteOp) error,
+	emitMove func(MoveOp) error,
+) error {
+	if sameSubtree(params.Compare, srcDir, sinkDir) {
+		return nil
+	}
+	var mv *moves
+	if emitMove != nil {
+		var err error
+		mv, err = findMoves(ctx, src, srcDir, sinkDir, params, emitMove)
+		if err != nil {
+			return fmt.Errorf("finding moves: %w", err)
+		}
+	}
+	guard := newDeleteGuard(params, func(ctx context.Context, path *typesv1.Path) (int, error) {
+		// what's moved out of the dir isn't deleted with it
+		dir, _, _ := sinkLookup(sinkDir, path)
+		return countSinkFiles(path, dir, func(path *typesv1.Path) bool {
+			return mv != nil && mv.movedFrom(path)
+		}), nil
+	})
+	// hard links are made once the files they link to are on the sink
+	links := &linkOps{emitCreate: emitCreate, emitPatch: emitPatch}
+	var walk dirWalk
+	walk = dirWalk{
+		diff: func(ctx context.Context, path *typesv1.Path, srcDir *SourceDir, sinkDir *typesv1.DirSum) error {
+			return computeDirDiff(ctx, src, params, path, srcDir, sinkDir, mv, walk, links.create, links.patch, emitDelete)
+		},
+		create: func(ctx context.Context, path *typesv1.Path, dir *SourceDir) error {
+			return emitCreateOpsForDir(path, dir, mv, links.create, links.patch)
+		},
+		remove: guard.removeWith(params, emitDelete),
+	}
+	walk.replace, walk.replaced = guard.replaceWith(emitDelete)
+	if err := walk.diff(ctx, &typesv1.Path{}, srcDir, sinkDir); err != nil {
+		return err
+	}
+	err := mv.emitDeferred(func(op DeleteOp) error { return walk.remove(ctx, op) })
+	if err != nil {
+		return err
+	}
+	// before the links, some may be to files held back by the guard
+	if err := guard.release(countSinkFiles(&typesv1.Path{}, sinkDir, nil), emitDelete); err != nil {
+		return err
+	}
+	return links.emit()
+}
+
+// dirWalk is how the diff of a dir goes on with its subdirs, which are
+// either in memory already or listed as they're walked.
+type dirWalk struct {
+	// diff emits the ops for the subdir at `path`, found on both sides
+	diff func(ctx context.Context, path *typesv1.Path, src *SourceDir, sink *typesv1.DirSum) error
+	// create emits the ops for the subdir `dir` of `path`, missing from
+	// the sink
+	create func(ctx context.Context, path *typesv1.Path, dir *SourceDir) error
+	// remove emits the delete of an entry of the sink that's not on the
+	// source
+	remove func(ctx context.Context, op DeleteOp) error
+	// replace emits the delete of a dir of the sink that a file of the
+	// source replaces, and replaced emits the ops that make that file,
+	// once the dir is deleted
+	replace  func(ctx context.Context, op DeleteOp) error
+	replaced func(create func() error) error
 }
 
-func computeDirDiff(ctx context.Context, fs fs.FS, path *typesv1.Path, src *SourceDir, sink *typesv1.DirSum,
+func computeDirDiff(ctx context.Context, fs fs.FS, params Params, path *typesv1.Path, src *SourceDir, sink *typesv1.DirSum, mv *moves, walk dirWalk,
 	emitCreate func(CreateOp) error,
 	emitPatch func(PatchOp) error,
 	emitDelete func(DeleteOp) error,
@@ -81,10 +347,18 @@ func computeDirDiff(ctx context.Context, fs fs.FS, path *typesv1.Path, src *Sour
 		// set(Src_dir) - set(Sink_dir)
 		sinkDir, found := sinkHasDirNamed(sink, srcDir.Info.Name)
 		if !found {
+			// a file with the same name must be removed before the
+			// dir can take its place
+			if sinkFile, found := sinkHasFileNamed(sink, srcDir.Info.Name); found {
+				op := deleteFileOp(path, sinkFile)
+				if err := emitDelete(op); err != nil {
+					return fmt.Errorf("emitting delete file: %w", err)
+				}
+			}
 			// the entire dir is missing, so we can stop looking for
 			// patches and deletes and just generate a list of creates
-			// for this entire dir
-			err := emitCreateOpsForDir(path, srcDir, emitCreate)
+			// for this entire dir, or move it from elsewhere
+			err := walk.create(ctx, path, srcDir)
 			if err != nil {
 				dirPath := typesv1.PathJoin(path, srcDir.Info.Name)
 				return fmt.Errorf("emitting diff for directory %q: %w", dirPath, err)
@@ -93,9 +367,11 @@ func computeDirDiff(ctx context.Context, fs fs.FS, path *typesv1.Path, src *Sour
 		}
 		// set(Src_dir) ∩ set(Sink_dir)
 		dirPath := typesv1.PathJoin(path, srcDir.Info.Name)
-		err := computeDirDiff(ctx, fs, dirPath, srcDir, sinkDir, emitCreate, emitPatch, emitDelete)
-		if err != nil {
-			return fmt.Errorf("computing diff for directory %q: %w", dirPath, err)
+		if !sameSubtree(params.Compare, srcDir, sinkDir) {
+			err := walk.diff(ctx, dirPath, srcDir, sinkDir)
+			if err != nil {
+				return fmt.Errorf("computing diff for directory %q: %w", dirPath, err)
+			}
 		}
 
 		// check if the dir itself needs a patch too
@@ -110,8 +386,22 @@ func computeDirDiff(ctx context.Context, fs fs.FS, path *typesv1.Path, src *Sour
 		// set(Sink_dir) - set(Src_dir)
 		if !srcHasDirNamed(src, sinkDir.Info.Name) {
 			dirPath := typesv1.PathJoin(path, sinkDir.Info.Name)
-			op := deleteDirOp(dirPath, sink.Info)
-			if err := emitDelete(op); err != nil {
+			if keepOnSink(params, src, dirPath, sinkDir.Info) {
+				continue
+			}
+			op := deleteDirOp(dirPath, sinkDir.Info)
+			if !mv.delete(op) {
+				continue
+			}
+			if srcHasFileNamed(src, sinkDir.Info.Name) {
+				// replaced by a file, which can't be created until the
+				// dir is deleted
+				if err := walk.replace(ctx, op); err != nil {
+					return fmt.Errorf("emitting delete dir: %w", err)
+				}
+				continue
+			}
+			if err := walk.remove(ctx, op); err != nil {
 				return fmt.Errorf("emitting delete dir: %w", err)
 			}
 		}
@@ -122,27 +412,57 @@ func computeDirDiff(ctx context.Context, fs fs.FS, path *typesv1.Path, src *Sour
 		// set(Src_file) - set(Sink_file)
 		sinkFile, found := sinkHasFileNamed(sink, srcFile.Info.Name)
 		if !found {
-			op := createFileOp(path, srcFile.Info)
-			if err := emitCreate(op); err != nil {
-				return fmt.Errorf("emitting create file: %w", err)
+			create := func() error {
+				if moved, err := mv.emitMoveTo(path, srcFile.Info); err != nil || moved {
+					return err
+				}
+				op := createFileOp(path, srcFile.Info)
+				if err := emitCreate(op); err != nil {
+					return fmt.Errorf("emitting create file: %w", err)
+				}
+				return nil
+			}
+			if _, found := sinkHasDirNamed(sink, srcFile.Info.Name); found {
+				if err := walk.replaced(create); err != nil {
+					return err
+				}
+			} else if err := create(); err != nil {
+				return err
 			}
 			continue
 		}
 		// set(Src_file) ∩ set(Sink_file)
-		if diff, err := makeFileDiff(ctx, fs, path, srcFile, sinkFile); err != nil {
+		if srcFile.Info.IsSymlink() != sinkFile.Info.IsSymlink() {
+			// a symlink can't be patched into a file or vice versa
+			if err := emitDelete(deleteFileOp(path, sinkFile)); err != nil {
+				return fmt.Errorf("emitting delete file: %w", err)
+			}
+			if err := emitCreate(createFileOp(path, srcFile.Info)); err != nil {
+				return fmt.Errorf("emitting create file: %w", err)
+			}
+			continue
+		}
+		if diff, err := makeFileDiff(ctx, fs, params, path, srcFile, sinkFile); err != nil {
 			return fmt.Errorf("computing diff for file %q: %w", srcFile.Info.Name, err)
 		} else if diff != nil {
-			op := patchFileOp(path, diff)
+			op := patchFileOp(path, srcFile.Info, diff)
 			if err := emitPatch(op); err != nil {
 				return fmt.Errorf("emitting patch file: %w", err)
 			}
 		}
 	}
 	for _, sinkFile := range sink.Files {
-		// set(Sink_file) - set(Src_file)
-		if !srcHasFileNamed(src, sinkFile.Info.Name) {
+		// set(Sink_file) - set(Src_file), minus the files replaced
+		// by a dir, which were deleted already
+		if !srcHasFileNamed(src, sinkFile.Info.Name) && !srcHasDirNamed(src, sinkFile.Info.Name) {
+			if keepOnSink(params, src, typesv1.PathJoin(path, sinkFile.Info.Name), sinkFile.Info) {
+				continue
+			}
 			op := deleteFileOp(path, sinkFile)
-			if err := emitDelete(op); err != nil {
+			if !mv.delete(op) {
+				continue
+			}
+			if err := walk.remove(ctx, op); err != nil {
 				return fmt.Errorf("emitting delete file: %w", err)
 			}
 		}
@@ -153,8 +473,13 @@ func computeDirDiff(ctx context.Context, fs fs.FS, path *typesv1.Path, src *Sour
 func emitCreateOpsForDir(
 	path *typesv1.Path,
 	dir *SourceDir,
+	mv *moves,
 	emitCreate func(CreateOp) error,
+	emitPatch func(PatchOp) error,
 ) error {
+	if moved, err := mv.emitMoveTo(path, dir.Info); err != nil || moved {
+		return err
+	}
 	currentDirOp := createDirOp(path, dir)
 	if err := emitCreate(currentDirOp); err != nil {
 		return fmt.Errorf("emiting current dir: %w", err)
@@ -164,17 +489,28 @@ func emitCreateOpsForDir(
 	// have entire directories to transfer at once, which will help expedite
 	// the search
 	for _, file := range dir.Files {
+		if moved, err := mv.emitMoveTo(currentDir, file.Info); err != nil || moved {
+			if err != nil {
+				return fmt.Errorf("emiting opds for file: %w", err)
+			}
+			continue
+		}
 		op := createFileOp(currentDir, file.Info)
 		if err := emitCreate(op); err != nil {
 			return fmt.Errorf("emiting opds for file: %w", err)
 		}
 	}
 	for _, dir := range dir.Dirs {
-		err := emitCreateOpsForDir(currentDir, dir, emitCreate)
+		err := emitCreateOpsForDir(currentDir, dir, mv, emitCreate, emitPatch)
 		if err != nil {
 			return fmt.Errorf("emiting opds for subdir: %w", err)
 		}
 	}
+	// creating the entries of the dir touches it, and a dir that's not
+	// writable can't have any created, its info is applied last
+	if err := emitPatch(patchDirOp(path, dir.Info, &DirPatchOp{})); err != nil {
+		return fmt.Errorf("emiting patch of current dir: %w", err)
+	}
 	return nil
 }
 
@@ -228,6 +564,37 @@ func srcHasFileNamed(src *SourceDir, name string) bool {
 	return found
 }
 
+// keepOnSink tells if a path that's on the sink but not on the source
+// is to be left alone, because it's excluded from the sync or was skipped on
+// the source. A path is only left alone if nothing on the source takes its
+// place.
+func keepOnSink(params Params, src *SourceDir, path *typesv1.Path, fi *typesv1.FileInfo) bool {
+	if srcHasFileNamed(src, fi.Name) || srcHasDirNamed(src, fi.Name) {
+		return false
+	}
+	if srcSkipped(src, fi.Name) {
+		// skipped entries are left as they are
+		return true
+	}
+	if params.DeleteExcluded {
+		return false
+	}
+	return src.rules.excluded(strings.Join(path.Elements, "/"), fi.IsDir)
+}
+
+// sameDigest tells if two subtrees are known to be the same. Sinks that
+// don't compute digests never match.
+func sameDigest(src, sink []byte) bool {
+	return len(sink) != 0 && bytes.Equal(src, sink)
+}
+
+// sameSubtree tells if a subtree of the source is known to be the same as on
+// the sink, to be left out of the diff. Digests are of the info of files,
+// comparing checksums reads them whatever their info.
+func sameSubtree(compare Compare, src *SourceDir, sink *typesv1.DirSum) bool {
+	return compare != CompareChecksum && sameDigest(src.syncedDigest(), sink.Digest)
+}
+
 func createDirOp(path *typesv1.Path, dir *SourceDir) CreateOp {
 	return CreateOp{
 		ParentDir: path,
@@ -272,58 +639,101 @@ func deleteFileOp(path *typesv1.Path, sink *typesv1.FileSum) DeleteOp {
 	}
 }
 
-func patchFileOp(path *typesv1.Path, diff *FilePatchOp) PatchOp {
+func patchFileOp(path *typesv1.Path, fi *typesv1.FileInfo, diff *FilePatchOp) PatchOp {
 	return PatchOp{
 		Path: path,
+		Info: fi,
 		File: diff,
 	}
 }
 
-func makeFileDiff(ctx context.Context, fs fs.FS, path *typesv1.Path, src *SourceFile, sink *typesv1.FileSum) (*FilePatchOp, error) {
+func makeFileDiff(ctx context.Context, fs fs.FS, params Params, path *typesv1.Path, src *SourceFile, sink *typesv1.FileSum) (*FilePatchOp, error) {
 	// compute mod time, size
-	if !proto.Equal(src.Info, sink.Info) {
+	if params.Compare.infoDiffers(src.Info, sink.Info) {
 		// obviously changed, we don't need to sum the content to figure as such
 		return &FilePatchOp{
 			Sum: sink,
 		}, nil
 	}
+	if !params.Compare.readsContent() {
+		return nil, nil
+	}
+	if src.Info.IsSymlink() {
+		// the target is part of the info, there's no content to compare
+		return nil, nil
+	}
 	filepath := typesv1.PathJoin(path, src.Info.Name)
+	idx := params.Index
+	// comparing checksums reads every file, whatever the index knows
+	if params.Compare != CompareChecksum && idx.trusts(filepath, src, sink) {
+		return nil, nil
+	}
 	f, err := fs.Open(typesv1.StringFromPath(filepath))
 	if err != nil {
-		return nil, fmt.Errorf("opening source file: %w", err)
+		// a file that can't be read is left as it is, if it's skipped
+		return nil, skipEntry(ctx, params, filepath, fmt.Errorf("opening source file: %w", unreadable(err)))
 	}
 	defer f.Close()
 
-	matches, err := FileMatchesFileSum(ctx, sink, f, src.Info.Size)
+	var matches bool
+	if rf, ok := f.(RemoteFile); ok {
+		matches, err = rf.MatchesFileSum(ctx, sink)
+	} else {
+		matches, err = fileMatchesFileSum(ctx, sink, countReads(ctx, f), blockSize(src.Info.Size))
+	}
 	if err != nil {
 		return nil, fmt.Errorf("opening source file: %w", err)
 	}
 	if !matches {
 		return &FilePatchOp{Sum: sink}, nil
 	}
+	idx.record(filepath, src, sink)
 	return nil, nil
 }
 
-func upload(ctx context.Context, src Source, sink Sink, createOp CreateOp) error {
+// upload creates the file of `createOp` on `sink`, see `sendStable` for
+// `retries`.
+func upload(ctx context.Context, src Source, sink Sink, createOp CreateOp, retries int) error {
 	path := typesv1.StringFromPath(typesv1.PathJoin(createOp.ParentDir, createOp.FileInfo.Name))
-	f, err := src.Open(path)
-	if err != nil {
-		return fmt.Errorf("opening %q on source for upload: %w", path, err)
-	}
-	defer f.Close()
-	fi, err := f.Stat()
-	if err != nil {
-		return fmt.Errorf("stating %q on source: %w", path, err)
+	if createOp.FileInfo.HardLink != nil {
+		// the content is the one of the file it links to, which is on the
+		// sink already
+		err := sink.CreateFile(ctx, createOp.ParentDir, createOp.FileInfo, nil)
+		if err != nil {
+			return fmt.Errorf("creating hard link on sink: %w", err)
+		}
+		return nil
 	}
-
-	err = sink.CreateFile(ctx, createOp.ParentDir, typesv1.FileInfoFromFS(fi), f)
-	if err != nil {
-		return fmt.Errorf("creating file on sink: %w", err)
+	if createOp.FileInfo.IsSymlink() {
+		fi, err := readSymlink(src, path)
+		if err != nil {
+			return fmt.Errorf("reading %q on source for upload: %w", path, err)
+		}
+		err = sink.CreateFile(ctx, createOp.ParentDir, fi, nil)
+		if err != nil {
+			return fmt.Errorf("creating symlink on sink: %w", err)
+		}
+		return nil
 	}
-	return nil
+	sinkProgress(ctx, sink)
+	return sendStable(ctx, src, path, createOp.FileInfo, retries, func(fi fs.FileInfo, r io.Reader) error {
+		info := tracedInfo(fi, createOp.FileInfo)
+		var err error
+		if rs, ok := resumableSinkOf(ctx, sink, info); ok {
+			err = rs.CreateFileResumable(ctx, createOp.ParentDir, info, countUpload(ctx, r))
+		} else {
+			err = sink.CreateFile(ctx, createOp.ParentDir, info, countUpload(ctx, r))
+		}
+		if err != nil {
+			return fmt.Errorf("creating file on sink: %w", err)
+		}
+		return nil
+	})
 }
 
-func patch(ctx context.Context, src Source, sink Sink, patchOp PatchOp) error {
+// patch patches the file of `patchOp` on `sink`, see `sendStable` for
+// `retries`.
+func patch(ctx context.Context, src Source, sink Sink, patchOp PatchOp, retries int) error {
 	if patchOp.Dir != nil {
 		// patch a dir
 		path := typesv1.PathJoin(patchOp.Path, patchOp.Info.Name)
@@ -333,22 +743,33 @@ func patch(ctx context.Context, src Source, sink Sink, patchOp PatchOp) error {
 		if err != nil {
 			return fmt.Errorf("stating dir %q on source: %w", spath, err)
 		}
-		return sink.PatchFile(ctx, patchOp.Path, typesv1.FileInfoFromFS(fi), nil, nil)
+		return sink.PatchFile(ctx, patchOp.Path, tracedInfo(fi, patchOp.Info), nil, nil)
 	}
 
 	path := typesv1.PathJoin(patchOp.Path, patchOp.File.Sum.Info.Name)
 	fileDiff := patchOp.File
 	spath := typesv1.StringFromPath(path)
-	f, err := src.Open(spath)
-	if err != nil {
-		return fmt.Errorf("opening file %q on source: %w", spath, err)
+	if patchOp.Info.HardLink != nil {
+		return sink.PatchFile(ctx, patchOp.Path, patchOp.Info, fileDiff.Sum, nil)
 	}
-	defer f.Close()
-
-	fi, err := f.Stat()
-	if err != nil {
-		return fmt.Errorf("stating file %q on source: %w", spath, err)
+	if patchOp.Info.IsSymlink() {
+		fi, err := readSymlink(src, spath)
+		if err != nil {
+			return fmt.Errorf("reading symlink %q on source: %w", spath, err)
+		}
+		return sink.PatchFile(ctx, patchOp.Path, fi, fileDiff.Sum, nil)
 	}
+	sinkProgress(ctx, sink)
+	return sendStable(ctx, src, spath, patchOp.Info, retries, func(fi fs.FileInfo, r io.Reader) error {
+		return sink.PatchFile(ctx, patchOp.Path, tracedInfo(fi, patchOp.Info), fileDiff.Sum, countReads(ctx, r))
+	})
+}
 
-	return sink.PatchFile(ctx, patchOp.Path, typesv1.FileInfoFromFS(fi), fileDiff.Sum, f)
+// tracedInfo is the info of a file as it's applied, with the name and
+// attributes it was traced with: they're not part of what's stated.
+func tracedInfo(fi fs.FileInfo, traced *typesv1.FileInfo) *typesv1.FileInfo {
+	info := typesv1.FileInfoFromFS(fi)
+	info.Name = traced.GetName()
+	info.Attrs = traced.GetAttrs()
+	return info
 }
@@ -0,0 +1,146 @@
+package dirsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+)
+
+// ErrTooManyDeletes is returned when a sync would delete more of the sink
+// than `Params.MaxDeletes` or `Params.MaxDeletePercent` allow.
+var ErrTooManyDeletes = errors.New("too many files would be deleted from the sink")
+
+// deleteGuard holds back what the diff removes from the sink, until the diff
+// is done and it's known not to remove too much of it. Dirs replaced by a file
+// are held back too, with the files that replace them: the files that were in
+// them are deleted all the same.
+type deleteGuard struct {
+	params Params
+	// countFiles counts the files under the dir at `path` on the sink
+	countFiles func(ctx context.Context, path *typesv1.Path) (int, error)
+
+	held []DeleteOp
+	// the ops that wait for held deletes, the creates of files that
+	// replace dirs
+	after []func() error
+	files int
+	// the files of `files` that are in deleted dirs
+	inDirs int
+}
+
+// newDeleteGuard returns a guard if `params` limit the deletes, nil
+// otherwise.
+func newDeleteGuard(params Params, countFiles func(ctx context.Context, path *typesv1.Path) (int, error)) *deleteGuard {
+	if params.MaxDeletes <= 0 && params.MaxDeletePercent <= 0 {
+		return nil
+	}
+	return &deleteGuard{params: params, countFiles: countFiles}
+}
+
+// removeWith returns how the diff removes entries from the sink: not at all
+// with `Params.NoDelete`, held back by the guard if there's one, or with
+// `emitDelete` right away.
+func (g *deleteGuard) removeWith(params Params, emitDelete func(DeleteOp) error) func(ctx context.Context, op DeleteOp) error {
+	switch {
+	case params.NoDelete:
+		return func(context.Context, DeleteOp) error { return nil }
+	case g != nil:
+		return g.hold
+	default:
+		return func(_ context.Context, op DeleteOp) error { return emitDelete(op) }
+	}
+}
+
+// replaceWith returns how the diff deletes the dirs of the sink that files
+// replace, and then makes those files: right away, or once the guard is
+// released if there's one. Unlike removals, it's done with `Params.NoDelete`.
+func (g *deleteGuard) replaceWith(emitDelete func(DeleteOp) error) (replace func(ctx context.Context, op DeleteOp) error, replaced func(create func() error) error) {
+	if g == nil {
+		return func(_ context.Context, op DeleteOp) error { return emitDelete(op) },
+			func(create func() error) error { return create() }
+	}
+	return g.hold, func(create func() error) error {
+		g.after = append(g.after, create)
+		return nil
+	}
+}
+
+func (g *deleteGuard) hold(ctx context.Context, op DeleteOp) error {
+	if !op.FileInfo.IsDir {
+		g.files++
+	} else {
+		n, err := g.countFiles(ctx, op.Path)
+		if err != nil {
+			return fmt.Errorf("counting files to delete in %q: %w", typesv1.StringFromPath(op.Path), err)
+		}
+		g.files += n
+		g.inDirs += n
+	}
+	g.held = append(g.held, op)
+	return nil
+}
+
+// filesInDirs is how many files are in the dirs held back.
+func (g *deleteGuard) filesInDirs() int {
+	if g == nil {
+		return 0
+	}
+	return g.inDirs
+}
+
+// release emits the deletes held back and then the ops that waited for them,
+// unless they remove too many of the `sinkFiles` files of the sink.
+func (g *deleteGuard) release(sinkFiles int, emitDelete func(DeleteOp) error) error {
+	if g == nil {
+		return nil
+	}
+	if err := checkDeletes(g.params, g.files, sinkFiles); err != nil {
+		return err
+	}
+	for _, op := range g.held {
+		if err := emitDelete(op); err != nil {
+			return fmt.Errorf("emitting delete: %w", err)
+		}
+	}
+	for _, emit := range g.after {
+		if err := emit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDeletes fails if deleting `files` of the `sinkFiles` files of the sink
+// is more than `params` allow.
+func checkDeletes(params Params, files, sinkFiles int) error {
+	if params.MaxDeletes > 0 && files > params.MaxDeletes {
+		return fmt.Errorf("%w: %d files, the limit is %d", ErrTooManyDeletes, files, params.MaxDeletes)
+	}
+	if params.MaxDeletePercent > 0 && sinkFiles > 0 {
+		percent := 100 * float64(files) / float64(sinkFiles)
+		if percent > params.MaxDeletePercent {
+			return fmt.Errorf("%w: %d of its %d files (%.1f%%), the limit is %.1f%%", ErrTooManyDeletes, files, sinkFiles, percent, params.MaxDeletePercent)
+		}
+	}
+	return nil
+}
+
+// countSinkFiles counts the files of the dir of a sink and of its subdirs,
+// but the ones for which `skip` is true.
+func countSinkFiles(path *typesv1.Path, dir *typesv1.DirSum, skip func(path *typesv1.Path) bool) int {
+	n := 0
+	for _, file := range dir.Files {
+		if skip == nil || !skip(typesv1.PathJoin(path, file.Info.Name)) {
+			n++
+		}
+	}
+	for _, child := range dir.Dirs {
+		childPath := typesv1.PathJoin(path, child.Info.Name)
+		if skip == nil || !skip(childPath) {
+			n += countSinkFiles(childPath, child, skip)
+		}
+	}
+	return n
+}
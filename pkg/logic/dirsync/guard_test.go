@@ -0,0 +1,147 @@
+package dirsync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncDeleteGuard(t *testing.T) {
+	sinkFiles := []string{"a/1", "a/2", "a/3", "b/1", "c", "d", "e", "f", "g", "h"}
+	tests := []struct {
+		name      string
+		srcFiles  []string
+		params    Params
+		wantErr   error
+		wantFiles []string
+	}{
+		{
+			name:      "no delete",
+			srcFiles:  []string{"new"},
+			params:    Params{NoDelete: true},
+			wantFiles: append([]string{"new"}, sinkFiles...),
+		},
+		{
+			name:      "deletes within limits",
+			srcFiles:  []string{"a/1", "a/2", "a/3", "c", "d", "e", "f", "g"},
+			params:    Params{MaxDeletes: 2, MaxDeletePercent: 20},
+			wantFiles: []string{"a/1", "a/2", "a/3", "c", "d", "e", "f", "g"},
+		},
+		{
+			name:      "too many deletes",
+			srcFiles:  []string{"b/1", "c", "d", "e", "f", "g", "h"},
+			params:    Params{MaxDeletes: 2},
+			wantErr:   ErrTooManyDeletes,
+			wantFiles: sinkFiles,
+		},
+		{
+			name:      "dir replaced by a file, no delete",
+			srcFiles:  []string{"a", "b/1", "c", "d", "e", "f", "g", "h"},
+			params:    Params{NoDelete: true},
+			wantFiles: []string{"a", "b/1", "c", "d", "e", "f", "g", "h"},
+		},
+		{
+			name:      "dir replaced by a file, guarded",
+			srcFiles:  []string{"a", "b/1", "c", "d", "e", "f", "g", "h"},
+			params:    Params{MaxDeletes: 3, MaxDeletePercent: 30},
+			wantFiles: []string{"a", "b/1", "c", "d", "e", "f", "g", "h"},
+		},
+		{
+			// the files of the dir are deleted all the same
+			name:      "dir replaced by a file, too many deletes",
+			srcFiles:  []string{"a", "b/1", "c", "d", "e", "f", "g", "h"},
+			params:    Params{MaxDeletes: 2},
+			wantErr:   ErrTooManyDeletes,
+			wantFiles: sinkFiles,
+		},
+		{
+			name:      "too much of the sink deleted",
+			srcFiles:  nil,
+			params:    Params{MaxDeletePercent: 50},
+			wantErr:   ErrTooManyDeletes,
+			wantFiles: sinkFiles,
+		},
+	}
+	for _, tt := range tests {
+		for _, streaming := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/streaming=%v", tt.name, streaming), func(t *testing.T) {
+				ctx := context.Background()
+				srcDir, sinkDir := t.TempDir(), t.TempDir()
+				mkfile := func(root, name string) {
+					filename := filepath.Join(root, name)
+					require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+					require.NoError(t, os.WriteFile(filename, []byte(name), 0644))
+				}
+				for _, name := range sinkFiles {
+					mkfile(sinkDir, name)
+				}
+				for _, name := range tt.srcFiles {
+					mkfile(srcDir, name)
+				}
+				params := tt.params
+				params.Streaming = streaming
+				_, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), params)
+				if tt.wantErr != nil {
+					require.ErrorIs(t, err, tt.wantErr)
+				} else {
+					require.NoError(t, err)
+				}
+				require.ElementsMatch(t, tt.wantFiles, mapKeys(tracedFiles(t, NewLocalSource(sinkDir), Params{})))
+			})
+		}
+	}
+}
+
+func TestExecutePlanDeleteGuard(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	for _, name := range []string{"dir/1", "dir/2", "kept"} {
+		filename := filepath.Join(sinkDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		require.NoError(t, os.WriteFile(filename, []byte(name), 0644))
+	}
+	src, sink := NewLocalSource(srcDir), NewLocalSink(sinkDir)
+	plan, err := Plan(ctx, ".", src, sink, Params{})
+	require.NoError(t, err)
+
+	err = ExecutePlan(ctx, src, sink, plan, Params{MaxDeletes: 2})
+	require.ErrorIs(t, err, ErrTooManyDeletes)
+
+	require.NoError(t, ExecutePlan(ctx, src, sink, plan, Params{NoDelete: true}))
+	require.Len(t, tracedFiles(t, NewLocalSource(sinkDir), Params{}), 3)
+
+	require.NoError(t, ExecutePlan(ctx, src, sink, plan, Params{MaxDeletes: 3}))
+	require.Empty(t, tracedFiles(t, NewLocalSource(sinkDir), Params{}))
+}
+
+func TestExecutePlanDeleteGuardReplacedDir(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(sinkDir, "dir"), 0755))
+	for _, name := range []string{"dir/1", "dir/2"} {
+		require.NoError(t, os.WriteFile(filepath.Join(sinkDir, name), []byte(name), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "dir"), []byte("dir"), 0644))
+	src, sink := NewLocalSource(srcDir), NewLocalSink(sinkDir)
+	plan, err := Plan(ctx, ".", src, sink, Params{})
+	require.NoError(t, err)
+
+	// the files of the dir are deleted, though the dir is replaced
+	err = ExecutePlan(ctx, src, sink, plan, Params{MaxDeletes: 1})
+	require.ErrorIs(t, err, ErrTooManyDeletes)
+
+	require.NoError(t, ExecutePlan(ctx, src, sink, plan, Params{MaxDeletes: 2}))
+	require.Equal(t, tracedFiles(t, src, Params{}), tracedFiles(t, NewLocalSource(sinkDir), Params{}))
+}
+
+func mapKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,219 @@
+package dirsync
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+)
+
+// linkID identifies a file that has many hard links to it.
+type linkID struct {
+	dev, ino uint64
+}
+
+// linkedFile is a file of a tree that's one of the hard links to a file.
+type linkedFile struct {
+	path *typesv1.Path
+	info *typesv1.FileInfo
+}
+
+// hardLinks groups the files of a tree by the file they're hard links to, as
+// they're found.
+type hardLinks struct {
+	groups map[linkID][]linkedFile
+	// files that the source already knows to be hard links, to the path
+	// in their `HardLink`
+	known []linkedFile
+}
+
+// add adds the file at `path` if it's a hard link, per its `fi`.
+func (hl *hardLinks) add(path *typesv1.Path, info *typesv1.FileInfo, fi fs.FileInfo) {
+	if sys, ok := fi.Sys().(*typesv1.FileInfo); ok {
+		if sys.GetHardLink() != nil {
+			hl.known = append(hl.known, linkedFile{path: sys.HardLink, info: info})
+		}
+		return
+	}
+	id, ok := fileLinkID(fi)
+	if !ok {
+		return
+	}
+	if hl.groups == nil {
+		hl.groups = make(map[linkID][]linkedFile)
+	}
+	hl.groups[id] = append(hl.groups[id], linkedFile{path: path, info: info})
+}
+
+// link sets `HardLink` on the files found that are links to the same file.
+// Of each group, the file with the first path is the one the others link to,
+// so that every side picks the same one. Files the source knew to be hard
+// links only are if what they link to is in `root`. It tells if any file was
+// linked.
+func (hl *hardLinks) link(root *SourceDir) bool {
+	linkedAny := false
+	for _, group := range hl.groups {
+		linkedAny = linkGroup(group) || linkedAny
+	}
+	var linked []linkedFile
+	for _, file := range hl.known {
+		target, ok := sourceFileAt(root, file.path)
+		if ok && target.Info.HardLink == nil && !target.Info.IsSymlink() {
+			linked = append(linked, file)
+		}
+	}
+	for _, file := range linked {
+		file.info.HardLink = file.path
+	}
+	return linkedAny || len(linked) > 0
+}
+
+// linkGroup sets `HardLink` on the files of a group but the first by path, to
+// the path of that one. It tells if the group had links.
+func linkGroup(group []linkedFile) bool {
+	if len(group) < 2 {
+		return false
+	}
+	slices.SortFunc(group, func(a, b linkedFile) int {
+		return slices.Compare(a.path.Elements, b.path.Elements)
+	})
+	for _, file := range group[1:] {
+		file.info.HardLink = group[0].path
+	}
+	return true
+}
+
+// sourceFileAt finds the file at `path` under `dir`.
+func sourceFileAt(dir *SourceDir, path *typesv1.Path) (*SourceFile, bool) {
+	n := len(path.Elements)
+	if n == 0 {
+		return nil, false
+	}
+	for _, name := range path.Elements[:n-1] {
+		child, found := srcHasDir(dir, name)
+		if !found {
+			return nil, false
+		}
+		dir = child
+	}
+	i, found := slices.BinarySearchFunc(dir.Files, path.Elements[n-1], func(file *SourceFile, name string) int {
+		return strings.Compare(file.Info.Name, name)
+	})
+	if !found {
+		return nil, false
+	}
+	return dir.Files[i], true
+}
+
+// linkOps holds back the ops that make hard links until the diff is done, so
+// that they're applied after the ops on the files they link to. The other ops
+// are passed on as they come.
+type linkOps struct {
+	emitCreate func(CreateOp) error
+	emitPatch  func(PatchOp) error
+
+	creates []CreateOp
+	patches []PatchOp
+}
+
+func (lo *linkOps) create(op CreateOp) error {
+	if op.FileInfo.HardLink != nil {
+		lo.creates = append(lo.creates, op)
+		return nil
+	}
+	return lo.emitCreate(op)
+}
+
+func (lo *linkOps) patch(op PatchOp) error {
+	if op.Info.GetHardLink() != nil {
+		lo.patches = append(lo.patches, op)
+		return nil
+	}
+	return lo.emitPatch(op)
+}
+
+// emit emits the ops held back.
+func (lo *linkOps) emit() error {
+	for _, op := range lo.creates {
+		if err := lo.emitCreate(op); err != nil {
+			return fmt.Errorf("emitting create hard link: %w", err)
+		}
+	}
+	for _, op := range lo.patches {
+		if err := lo.emitPatch(op); err != nil {
+			return fmt.Errorf("emitting patch hard link: %w", err)
+		}
+	}
+	return nil
+}
+
+// opPaths are the paths that an op on `path` touches: that one, and the file
+// it links to if `fi` is a hard link.
+func opPaths(path *typesv1.Path, fi *typesv1.FileInfo) []*typesv1.Path {
+	if fi.GetHardLink() != nil {
+		return []*typesv1.Path{path, fi.HardLink}
+	}
+	return []*typesv1.Path{path}
+}
+
+// CheckHardLink rejects a hard link `fi` in `dir` that doesn't link to
+// another file of the tree.
+func CheckHardLink(dir *typesv1.Path, fi *typesv1.FileInfo) error {
+	if fi.HardLink == nil {
+		return nil
+	}
+	if fi.IsDir || fi.IsSymlink() {
+		return errors.New("only files can be hard links")
+	}
+	target := strings.Join(fi.HardLink.Elements, "/")
+	if !fs.ValidPath(target) || target == "." {
+		return fmt.Errorf("invalid hard link target %q", target)
+	}
+	if slices.Equal(fi.HardLink.Elements, typesv1.PathJoin(dir, fi.Name).Elements) {
+		return fmt.Errorf("%q is a hard link to itself", target)
+	}
+	return nil
+}
+
+// findSinkHardLinks sets `HardLink` on the files of a local sink's `root` that
+// are hard links to the same file, like the source does.
+func findSinkHardLinks(dirname string, root *typesv1.DirSum) error {
+	var hl hardLinks
+	if err := hl.addSinkDir(dirname, &typesv1.Path{}, root); err != nil {
+		return err
+	}
+	linked := false
+	for _, group := range hl.groups {
+		linked = linkGroup(group) || linked
+	}
+	if linked {
+		// the digests were taken before the links were known
+		rehashSink(root)
+	}
+	return nil
+}
+
+func (hl *hardLinks) addSinkDir(dirname string, path *typesv1.Path, dir *typesv1.DirSum) error {
+	for _, file := range dir.Files {
+		if file.Info.IsSymlink() {
+			continue
+		}
+		filePath := typesv1.PathJoin(path, file.Info.Name)
+		fi, err := os.Lstat(filepath.Join(dirname, typesv1.StringFromPath(filePath)))
+		if err != nil {
+			return fmt.Errorf("stating %q: %w", typesv1.StringFromPath(filePath), err)
+		}
+		hl.add(filePath, file.Info, fi)
+	}
+	for _, child := range dir.Dirs {
+		if err := hl.addSinkDir(dirname, typesv1.PathJoin(path, child.Info.Name), child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
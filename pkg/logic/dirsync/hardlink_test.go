@@ -0,0 +1,138 @@
+//go:build linux || darwin
+
+package dirsync
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncHardLinks(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	content := make([]byte, 64<<10)
+	rand.New(rand.NewSource(42)).Read(content)
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "b"), content, 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(srcDir, "a"), 0755))
+	require.NoError(t, os.Link(filepath.Join(srcDir, "b"), filepath.Join(srcDir, "a", "linked")))
+	require.NoError(t, os.Link(filepath.Join(srcDir, "b"), filepath.Join(srcDir, "c")))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "d"), []byte("d"), 0644))
+
+	sameFile := func(a, b string) bool {
+		fia, err := os.Stat(filepath.Join(sinkDir, a))
+		require.NoError(t, err)
+		fib, err := os.Stat(filepath.Join(sinkDir, b))
+		require.NoError(t, err)
+		return os.SameFile(fia, fib)
+	}
+
+	stats, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+	require.Equal(t, tracedFiles(t, NewLocalSource(srcDir), Params{}), tracedFiles(t, NewLocalSource(sinkDir), Params{}))
+	// the content is sent once, for the first of the links
+	require.Equal(t, uint64(len(content)+len("d")), stats.LiteralBytes)
+	require.True(t, sameFile("a/linked", "b"))
+	require.True(t, sameFile("a/linked", "c"))
+	require.False(t, sameFile("a/linked", "d"))
+
+	stats, err = Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+	require.Zero(t, stats.FilesCreated+stats.FilesPatched)
+
+	// a link replaced by a copy is copied too
+	require.NoError(t, os.Remove(filepath.Join(srcDir, "c")))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "c"), content, 0600))
+	_, err = Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+	require.Equal(t, tracedFiles(t, NewLocalSource(srcDir), Params{}), tracedFiles(t, NewLocalSource(sinkDir), Params{}))
+	require.True(t, sameFile("a/linked", "b"))
+	require.False(t, sameFile("a/linked", "c"))
+}
+
+func TestSyncHardLinksChecksum(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a"), []byte("before"), 0644))
+	require.NoError(t, os.Link(filepath.Join(srcDir, "a"), filepath.Join(srcDir, "b")))
+	_, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{Compare: CompareChecksum})
+	require.NoError(t, err)
+	sigs, err := NewLocalSink(sinkDir).GetSignatures(ctx)
+	require.NoError(t, err)
+
+	// changed in place, with the same size and mod time
+	fi, err := os.Stat(filepath.Join(srcDir, "a"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a"), []byte("after!"), 0644))
+	require.NoError(t, os.Chtimes(filepath.Join(srcDir, "a"), fi.ModTime(), fi.ModTime()))
+
+	// the link is patched too, for sinks that copy what it links to
+	sink := &recordingSink{sigs: sigs}
+	_, err = Sync(ctx, ".", NewLocalSource(srcDir), sink, Params{Compare: CompareChecksum})
+	require.NoError(t, err)
+	require.Equal(t, []string{"patch a", "patch b"}, sink.calls)
+}
+
+func TestSyncHardLinksRelinked(t *testing.T) {
+	ctx := context.Background()
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	dir := filepath.Join(srcDir, "dir")
+	require.NoError(t, os.Mkdir(dir, 0755))
+	for _, name := range []string{"a", "b"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("same"), 0644))
+		require.NoError(t, os.Chtimes(filepath.Join(dir, name), modTime, modTime))
+	}
+	require.NoError(t, os.Chtimes(dir, modTime, modTime))
+	_, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+
+	sameFile := func() bool {
+		fia, err := os.Stat(filepath.Join(sinkDir, "dir", "a"))
+		require.NoError(t, err)
+		fib, err := os.Stat(filepath.Join(sinkDir, "dir", "b"))
+		require.NoError(t, err)
+		return os.SameFile(fia, fib)
+	}
+	require.False(t, sameFile())
+
+	// relinked with the same size, mode and mod time, in a dir that has the
+	// same mod time too
+	require.NoError(t, os.Remove(filepath.Join(dir, "b")))
+	require.NoError(t, os.Link(filepath.Join(dir, "a"), filepath.Join(dir, "b")))
+	require.NoError(t, os.Chtimes(dir, modTime, modTime))
+	_, err = Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+	require.True(t, sameFile())
+
+	// and copied back
+	require.NoError(t, os.Remove(filepath.Join(dir, "b")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b"), []byte("same"), 0644))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "b"), modTime, modTime))
+	require.NoError(t, os.Chtimes(dir, modTime, modTime))
+	_, err = Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+	require.False(t, sameFile())
+}
+
+func TestSyncHardLinksStreaming(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a"), []byte("content"), 0644))
+	require.NoError(t, os.Link(filepath.Join(srcDir, "a"), filepath.Join(srcDir, "b")))
+
+	// streaming syncs don't see the whole tree, links are copied
+	_, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{Streaming: true})
+	require.NoError(t, err)
+	require.Equal(t, tracedFiles(t, NewLocalSource(srcDir), Params{}), tracedFiles(t, NewLocalSource(sinkDir), Params{}))
+	fia, err := os.Stat(filepath.Join(sinkDir, "a"))
+	require.NoError(t, err)
+	fib, err := os.Stat(filepath.Join(sinkDir, "b"))
+	require.NoError(t, err)
+	require.False(t, os.SameFile(fia, fib))
+}
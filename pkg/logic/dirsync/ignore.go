@@ -0,0 +1,197 @@
+package dirsync
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFile is the name of the files listing paths not to sync, using the
+// gitignore syntax. Their patterns are relative to the dir they're found in
+// and apply to everything below it.
+const IgnoreFile = ".syncyignore"
+
+// ignoreRules decides which paths of a dir are excluded from a sync.
+type ignoreRules struct {
+	// rules from `IgnoreFile`s, from the root down to the dir
+	files []ignoreRule
+	// rules from `Params`, which have precedence over the files
+	excludes []ignoreRule
+	includes []ignoreRule
+}
+
+type ignoreRule struct {
+	// dir the rule is relative to, from the root
+	base    string
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+	// anchored rules match the whole path from `base`, others only
+	// match the name of the file or dir
+	anchored bool
+}
+
+func newIgnoreRules(params Params) (*ignoreRules, error) {
+	rules := &ignoreRules{}
+	for _, pattern := range params.Exclude {
+		rule, ok, err := parseIgnoreRule(".", pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+		if ok {
+			rules.excludes = append(rules.excludes, rule)
+		}
+	}
+	for _, pattern := range params.Include {
+		rule, ok, err := parseIgnoreRule(".", pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+		if ok {
+			// an include is an exclude that re-includes
+			rule.negate = !rule.negate
+			rules.includes = append(rules.includes, rule)
+		}
+	}
+	return rules, nil
+}
+
+// withFile returns the rules that apply below `dir`, once the content of
+// the `IgnoreFile` found in it is added.
+func (ir *ignoreRules) withFile(dir string, content []byte) (*ignoreRules, error) {
+	out := &ignoreRules{
+		files:    ir.files[:len(ir.files):len(ir.files)],
+		excludes: ir.excludes,
+		includes: ir.includes,
+	}
+	scan := bufio.NewScanner(bytes.NewReader(content))
+	for i := 1; scan.Scan(); i++ {
+		rule, ok, err := parseIgnoreRule(dir, scan.Text())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i, err)
+		}
+		if ok {
+			out.files = append(out.files, rule)
+		}
+	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (ir *ignoreRules) any() bool {
+	return ir != nil && len(ir.files)+len(ir.excludes)+len(ir.includes) > 0
+}
+
+// excluded tells if the file or dir at `rel`, from the root, is excluded.
+// Like with gitignore, the last rule to match decides.
+func (ir *ignoreRules) excluded(rel string, isDir bool) bool {
+	if ir == nil {
+		return false
+	}
+	excluded := false
+	for _, rules := range [][]ignoreRule{ir.files, ir.excludes, ir.includes} {
+		for _, rule := range rules {
+			if rule.matches(rel, isDir) {
+				excluded = !rule.negate
+			}
+		}
+	}
+	return excluded
+}
+
+func (rule ignoreRule) matches(rel string, isDir bool) bool {
+	if rule.dirOnly && !isDir {
+		return false
+	}
+	if rule.base != "." {
+		if !strings.HasPrefix(rel, rule.base+"/") {
+			return false
+		}
+		rel = rel[len(rule.base)+1:]
+	}
+	if !rule.anchored {
+		rel = path.Base(rel)
+	}
+	return rule.re.MatchString(rel)
+}
+
+// parseIgnoreRule parses a line of gitignore syntax. It's not ok if the
+// line is blank or a comment.
+func parseIgnoreRule(base, line string) (ignoreRule, bool, error) {
+	rule := ignoreRule{base: base}
+	line = strings.TrimRight(line, " \t\r")
+	if strings.HasSuffix(line, "\\") {
+		// the trailing space was escaped
+		line += " "
+	}
+	if line == "" || line[0] == '#' {
+		return rule, false, nil
+	}
+	if line[0] == '!' {
+		rule.negate = true
+		line = line[1:]
+	} else if line[0] == '\\' && len(line) > 1 && (line[1] == '!' || line[1] == '#') {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return rule, false, nil
+	}
+	re, err := regexp.Compile("^" + globToRegexp(line) + "$")
+	if err != nil {
+		return rule, false, fmt.Errorf("parsing pattern: %w", err)
+	}
+	rule.re = re
+	return rule, true, nil
+}
+
+// globToRegexp translates a gitignore glob into a regexp: `*` and `?` don't
+// match `/`, `**` matches any number of dirs.
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '\\' && i+1 < len(glob):
+			i++
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return sb.String()
+}
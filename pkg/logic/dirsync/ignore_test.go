@@ -0,0 +1,239 @@
+package dirsync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules func(t *testing.T) *ignoreRules
+		rel   string
+		isDir bool
+		want  bool
+	}{
+		{name: "no rules", rules: ignoreFiles(), rel: "a/b", want: false},
+		{name: "name anywhere", rules: ignoreFiles(".", "*.log"), rel: "a/b/c.log", want: true},
+		{name: "name no match", rules: ignoreFiles(".", "*.log"), rel: "a/b/c.txt", want: false},
+		{name: "star stops at slash", rules: ignoreFiles(".", "a/*"), rel: "a/b/c", want: false},
+		{name: "anchored", rules: ignoreFiles(".", "/build"), rel: "build", isDir: true, want: true},
+		{name: "anchored not nested", rules: ignoreFiles(".", "/build"), rel: "src/build", isDir: true, want: false},
+		{name: "dir only on dir", rules: ignoreFiles(".", "tmp/"), rel: "a/tmp", isDir: true, want: true},
+		{name: "dir only on file", rules: ignoreFiles(".", "tmp/"), rel: "a/tmp", want: false},
+		{name: "double star", rules: ignoreFiles(".", "a/**/c"), rel: "a/b/b/c", want: true},
+		{name: "double star no dir", rules: ignoreFiles(".", "a/**/c"), rel: "a/c", want: true},
+		{name: "char class", rules: ignoreFiles(".", "file[0-9]"), rel: "file7", want: true},
+		{name: "negated", rules: ignoreFiles(".", "*.log\n!keep.log"), rel: "keep.log", want: false},
+		{name: "last match wins", rules: ignoreFiles(".", "!keep.log\n*.log"), rel: "keep.log", want: true},
+		{name: "comments and blanks", rules: ignoreFiles(".", "# *.log\n\n"), rel: "a.log", want: false},
+		{name: "escaped comment", rules: ignoreFiles(".", `\#notes`), rel: "#notes", want: true},
+		{name: "nested file scoped", rules: ignoreFiles("sub", "/x"), rel: "x", want: false},
+		{name: "nested file applies", rules: ignoreFiles("sub", "/x"), rel: "sub/x", want: true},
+		{name: "nested overrides parent", rules: ignoreFiles(".", "*.log", "sub", "!a.log"), rel: "sub/a.log", want: false},
+		{
+			name:  "exclude over files",
+			rules: ignoreParams(Params{Exclude: []string{"*.txt"}}, ".", "!a.txt"),
+			rel:   "a.txt", want: true,
+		},
+		{
+			name:  "include over exclude",
+			rules: ignoreParams(Params{Exclude: []string{"*.txt"}, Include: []string{"a.txt"}}),
+			rel:   "a.txt", want: false,
+		},
+		{
+			name:  "include only re-includes",
+			rules: ignoreParams(Params{Include: []string{"a.txt"}}),
+			rel:   "b.txt", want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rules(t).excluded(tt.rel, tt.isDir)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// ignoreFiles builds rules from pairs of dir and ignore file content.
+func ignoreFiles(dirAndContent ...string) func(t *testing.T) *ignoreRules {
+	return ignoreParams(Params{}, dirAndContent...)
+}
+
+func ignoreParams(params Params, dirAndContent ...string) func(t *testing.T) *ignoreRules {
+	return func(t *testing.T) *ignoreRules {
+		rules, err := newIgnoreRules(params)
+		require.NoError(t, err)
+		for i := 0; i < len(dirAndContent); i += 2 {
+			rules, err = rules.withFile(dirAndContent[i], []byte(dirAndContent[i+1]))
+			require.NoError(t, err)
+		}
+		return rules
+	}
+}
+
+func TestTraceSourceIgnore(t *testing.T) {
+	src := fstest.MapFS{
+		IgnoreFile:           &fstest.MapFile{Data: []byte("*.log\n/build/\n")},
+		"app.log":            &fstest.MapFile{Data: []byte("log")},
+		"main.go":            &fstest.MapFile{Data: []byte("package main")},
+		"build/main":         &fstest.MapFile{Data: []byte("elf")},
+		"src/build/gen.go":   &fstest.MapFile{Data: []byte("package build")},
+		"src/" + IgnoreFile:  &fstest.MapFile{Data: []byte("!keep.log\nscratch\n")},
+		"src/keep.log":       &fstest.MapFile{Data: []byte("log")},
+		"src/scratch/notes":  &fstest.MapFile{Data: []byte("notes")},
+		"vendor/lib/lib.go":  &fstest.MapFile{Data: []byte("package lib")},
+		"vendor/lib/lib.txt": &fstest.MapFile{Data: []byte("text")},
+	}
+
+	// names of the files and dirs found
+	list := func(dir *SourceDir) []string {
+		var out []string
+		var walk func(prefix string, dir *SourceDir)
+		walk = func(prefix string, dir *SourceDir) {
+			for _, child := range dir.Dirs {
+				out = append(out, prefix+child.Info.Name+"/")
+				walk(prefix+child.Info.Name+"/", child)
+			}
+			for _, file := range dir.Files {
+				out = append(out, prefix+file.Info.Name)
+			}
+		}
+		walk("", dir)
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		params Params
+		want   []string
+	}{
+		{
+			name: "ignore files",
+			want: []string{
+				"src/", "src/build/", "src/build/gen.go", "src/" + IgnoreFile, "src/keep.log",
+				"vendor/", "vendor/lib/", "vendor/lib/lib.go", "vendor/lib/lib.txt",
+				IgnoreFile, "main.go",
+			},
+		},
+		{
+			name:   "exclude and include",
+			params: Params{Exclude: []string{"vendor/", IgnoreFile}, Include: []string{"build"}},
+			want: []string{
+				"build/", "build/main",
+				"src/", "src/build/", "src/build/gen.go", "src/keep.log",
+				"main.go",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			got, err := TraceSource(ctx, ".", src, tt.params)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, list(got))
+		})
+	}
+}
+
+func TestSyncKeepsExcluded(t *testing.T) {
+	src := fstest.MapFS{
+		IgnoreFile: &fstest.MapFile{Data: []byte("*.log\ncache/\n")},
+	}
+	sigs := &typesv1.DirSum{
+		Info: &typesv1.FileInfo{IsDir: true},
+		Dirs: []*typesv1.DirSum{
+			{Info: &typesv1.FileInfo{Name: "cache", IsDir: true}},
+			{Info: &typesv1.FileInfo{Name: "old", IsDir: true}},
+		},
+		Files: []*typesv1.FileSum{
+			{Info: &typesv1.FileInfo{Name: "app.log"}},
+			{Info: &typesv1.FileInfo{Name: "old.txt"}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		params Params
+		want   []string
+	}{
+		{
+			name: "keep excluded",
+			want: []string{"create " + IgnoreFile, "delete old", "delete old.txt"},
+		},
+		{
+			name:   "delete excluded",
+			params: Params{DeleteExcluded: true},
+			want:   []string{"create " + IgnoreFile, "delete cache", "delete old", "delete app.log", "delete old.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			sink := &recordingSink{sigs: sigs}
+			_, err := Sync(ctx, ".", src, sink, tt.params)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.want, sink.calls)
+		})
+	}
+}
+
+func TestSyncPrunesKeptExcluded(t *testing.T) {
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	for _, name := range []string{"app/main.js", "app/lib/util.js", "README"} {
+		filename := filepath.Join(srcDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		require.NoError(t, os.WriteFile(filename, []byte(name), 0644))
+	}
+
+	ctx := context.Background()
+	src := &openCountingSource{LocalSource: NewLocalSource(srcDir)}
+	sink := NewLocalSink(sinkDir)
+	params := Params{Exclude: []string{"node_modules/", "*.log"}}
+	// the files that are read, dirs are listed anyways
+	sync := func() []string {
+		src.opened = nil
+		_, err := Sync(ctx, ".", src, sink, params)
+		require.NoError(t, err)
+		var files []string
+		for _, name := range src.opened {
+			if fi, err := os.Stat(filepath.Join(srcDir, name)); err == nil && !fi.IsDir() {
+				files = append(files, name)
+			}
+		}
+		return files
+	}
+	require.ElementsMatch(t, []string{"app/main.js", "app/lib/util.js", "README"}, sync())
+	// read once more to learn their sums on the sink
+	sync()
+	require.Empty(t, sync())
+
+	// excluded entries that are only on the sink are kept, and don't make
+	// the dirs they're in differ from the source
+	require.NoError(t, os.MkdirAll(filepath.Join(sinkDir, "app/lib/node_modules/dep"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sinkDir, "app/lib/node_modules/dep/index.js"), []byte("dep"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(sinkDir, "app/debug.log"), []byte("log"), 0644))
+	for _, name := range []string{"app", "app/lib"} {
+		// with the mod times they were synced with
+		fi, err := os.Stat(filepath.Join(srcDir, name))
+		require.NoError(t, err)
+		require.NoError(t, os.Chtimes(filepath.Join(sinkDir, name), fi.ModTime(), fi.ModTime()))
+	}
+	require.Empty(t, sync())
+	require.Empty(t, sync())
+	require.FileExists(t, filepath.Join(sinkDir, "app/lib/node_modules/dep/index.js"))
+	require.FileExists(t, filepath.Join(sinkDir, "app/debug.log"))
+
+	// changes next to them are still seen
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "app/lib/util.js"), []byte("changed"), 0644))
+	require.Contains(t, sync(), "app/lib/util.js")
+	content, err := os.ReadFile(filepath.Join(sinkDir, "app/lib/util.js"))
+	require.NoError(t, err)
+	require.Equal(t, "changed", string(content))
+}
@@ -0,0 +1,312 @@
+package dirsync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// indexVersion is the version of the format of saved indexes, bumped
+// whenever what's recorded in them changes meaning.
+const indexVersion = 1
+
+// Index is what's known of the files of a local source as of its last
+// successful sync: their stat data and, once their content is known to
+// match the sink, their sum on the sink.
+//
+// A file whose stat data is the same as in the index, and whose sum on the
+// sink is still the one in the index, is unchanged and isn't read again.
+type Index struct {
+	mu      sync.Mutex
+	entries map[string]*typesv1.IndexEntry
+}
+
+func NewIndex() *Index {
+	return &Index{entries: make(map[string]*typesv1.IndexEntry)}
+}
+
+// LoadIndex reads the index saved in `filename`. An index that's missing,
+// corrupted or of another version is as good as an empty one: every file is
+// read once, like without an index.
+func LoadIndex(filename string) (*Index, error) {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return NewIndex(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading index file %q: %w", filename, err)
+	}
+	saved := new(typesv1.LocalIndex)
+	if err := proto.Unmarshal(data, saved); err != nil || saved.Version != indexVersion {
+		return NewIndex(), nil
+	}
+	idx := NewIndex()
+	for _, entry := range saved.Entries {
+		idx.entries[entry.Path] = entry
+	}
+	return idx, nil
+}
+
+// Save writes the index to `filename`, replacing the previous one at once.
+func (idx *Index) Save(filename string) error {
+	idx.mu.Lock()
+	saved := &typesv1.LocalIndex{Version: indexVersion}
+	for _, entry := range idx.entries {
+		saved.Entries = append(saved.Entries, entry)
+	}
+	idx.mu.Unlock()
+	sort.Slice(saved.Entries, func(i, j int) bool { return saved.Entries[i].Path < saved.Entries[j].Path })
+
+	data, err := proto.Marshal(saved)
+	if err != nil {
+		return fmt.Errorf("encoding index: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return fmt.Errorf("creating index dir: %w", err)
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("writing index file %q: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		return fmt.Errorf("swapping in index file %q: %w", filename, err)
+	}
+	return nil
+}
+
+// Len is the number of files in the index.
+func (idx *Index) Len() int {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return len(idx.entries)
+}
+
+// IndexChangeKind is how a file changed since it was indexed.
+type IndexChangeKind int
+
+const (
+	IndexAdded IndexChangeKind = iota
+	IndexModified
+	IndexDeleted
+)
+
+func (kind IndexChangeKind) String() string {
+	switch kind {
+	case IndexAdded:
+		return "added"
+	case IndexModified:
+		return "modified"
+	case IndexDeleted:
+		return "deleted"
+	default:
+		return fmt.Sprintf("IndexChangeKind(%d)", int(kind))
+	}
+}
+
+// IndexChange is a file that changed since the last sync.
+type IndexChange struct {
+	// Path is slash-separated, from the root
+	Path string
+	Kind IndexChangeKind
+}
+
+// Changes lists the files under `root` that changed since the last sync,
+// ordered by path, without looking at the sink.
+func (idx *Index) Changes(ctx context.Context, root string, src Source, params Params) ([]IndexChange, error) {
+	tree, err := TraceSource(ctx, root, src, params)
+	if err != nil {
+		return nil, fmt.Errorf("enumerating files: %w", err)
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	var changes []IndexChange
+	seen := make(map[string]bool, len(idx.entries))
+	walkSourceFiles(&typesv1.Path{}, tree, func(path *typesv1.Path, file *SourceFile) {
+		key := typesv1.StringFromPath(path)
+		seen[key] = true
+		entry, ok := idx.entries[key]
+		switch {
+		case !ok:
+			changes = append(changes, IndexChange{Path: key, Kind: IndexAdded})
+		case !sameStat(entry, file):
+			changes = append(changes, IndexChange{Path: key, Kind: IndexModified})
+		}
+	})
+	for key := range idx.entries {
+		if !seen[key] {
+			changes = append(changes, IndexChange{Path: key, Kind: IndexDeleted})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// trusts tells if the file is known to have the content summed in `sink`,
+// without reading it.
+func (idx *Index) trusts(path *typesv1.Path, file *SourceFile, sink *typesv1.FileSum) bool {
+	if idx == nil {
+		return false
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	entry, ok := idx.entries[typesv1.StringFromPath(path)]
+	if !ok || entry.Sum == nil || !sameStat(entry, file) {
+		return false
+	}
+	// the sink could have been changed by someone else since
+	return sameBlocks(entry.Sum, sink)
+}
+
+// record notes that the file was found to have the content summed in
+// `sink`.
+func (idx *Index) record(path *typesv1.Path, file *SourceFile, sink *typesv1.FileSum) {
+	if idx == nil {
+		return
+	}
+	entry := indexEntry(typesv1.StringFromPath(path), file)
+	entry.Sum = sink
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.entries[entry.Path] = entry
+}
+
+// update makes the index match the traced source once it's synced.
+func (idx *Index) update(tree *SourceDir) {
+	u := idx.startUpdate()
+	walkSourceFiles(&typesv1.Path{}, tree, u.add)
+	u.commit()
+}
+
+// indexUpdate collects the files of the source as they're traced, to make
+// the index match them once the sync is done. The files that changed since
+// they were recorded lose their sum, they're read once on the next sync to
+// get it back.
+type indexUpdate struct {
+	idx     *Index
+	entries map[string]*typesv1.IndexEntry
+
+	// set for updates of some dirs only, see `startPartialUpdate`
+	listed  map[string]bool
+	removed []string
+}
+
+func (idx *Index) startUpdate() *indexUpdate {
+	if idx == nil {
+		return nil
+	}
+	return &indexUpdate{idx: idx, entries: make(map[string]*typesv1.IndexEntry)}
+}
+
+// startPartialUpdate is `startUpdate` for a sync that lists some dirs of the
+// source only: the files of the other dirs are left as they are in the index.
+func (idx *Index) startPartialUpdate() *indexUpdate {
+	u := idx.startUpdate()
+	if u != nil {
+		u.listed = make(map[string]bool)
+	}
+	return u
+}
+
+// list notes that all the files of the dir at `path` are added.
+func (u *indexUpdate) list(path *typesv1.Path) {
+	if u == nil || u.listed == nil {
+		return
+	}
+	u.listed[typesv1.StringFromPath(path)] = true
+}
+
+// remove notes that nothing is left at `path` on the source.
+func (u *indexUpdate) remove(path *typesv1.Path) {
+	if u == nil || u.listed == nil {
+		return
+	}
+	u.removed = append(u.removed, typesv1.StringFromPath(path))
+}
+
+func (u *indexUpdate) add(path *typesv1.Path, file *SourceFile) {
+	if u == nil {
+		return
+	}
+	key := typesv1.StringFromPath(path)
+	u.idx.mu.Lock()
+	entry, ok := u.idx.entries[key]
+	u.idx.mu.Unlock()
+	if ok && sameStat(entry, file) {
+		u.entries[key] = entry
+		return
+	}
+	u.entries[key] = indexEntry(key, file)
+}
+
+func (u *indexUpdate) commit() {
+	if u == nil {
+		return
+	}
+	u.idx.mu.Lock()
+	defer u.idx.mu.Unlock()
+	if u.listed != nil {
+		for key, entry := range u.idx.entries {
+			if _, ok := u.entries[key]; ok || u.listed[indexDir(key)] || u.wasRemoved(key) {
+				continue
+			}
+			u.entries[key] = entry
+		}
+	}
+	u.idx.entries = u.entries
+}
+
+// indexDir is the key of the dir of the file `key`.
+func indexDir(key string) string {
+	dir := filepath.Dir(key)
+	if dir == "." {
+		return typesv1.StringFromPath(&typesv1.Path{})
+	}
+	return dir
+}
+
+func (u *indexUpdate) wasRemoved(key string) bool {
+	for _, removed := range u.removed {
+		if key == removed || strings.HasPrefix(key, removed+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func indexEntry(key string, file *SourceFile) *typesv1.IndexEntry {
+	return &typesv1.IndexEntry{
+		Path:    key,
+		Size:    file.Info.Size,
+		ModTime: file.Info.GetModTime(),
+		Inode:   file.inode,
+	}
+}
+
+func sameStat(entry *typesv1.IndexEntry, file *SourceFile) bool {
+	return entry.Size == file.Info.Size &&
+		entry.Inode == file.inode &&
+		proto.Equal(entry.ModTime, file.Info.GetModTime())
+}
+
+func sameBlocks(a, b *typesv1.FileSum) bool {
+	return a.BlockSize == b.BlockSize && slices.EqualFunc(a.SumBlocks, b.SumBlocks, func(x, y *typesv1.FileSumBlock) bool {
+		return proto.Equal(x, y)
+	})
+}
+
+func walkSourceFiles(path *typesv1.Path, dir *SourceDir, fn func(*typesv1.Path, *SourceFile)) {
+	for _, child := range dir.Dirs {
+		walkSourceFiles(typesv1.PathJoin(path, child.Info.Name), child, fn)
+	}
+	for _, file := range dir.Files {
+		fn(typesv1.PathJoin(path, file.Info.Name), file)
+	}
+}
@@ -0,0 +1,171 @@
+package dirsync
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexSkipsUnchangedFiles(t *testing.T) {
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	modTime := time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC)
+	mkfile := func(root, name, content string) {
+		filename := filepath.Join(root, name)
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+		require.NoError(t, os.Chtimes(filename, modTime, modTime))
+		modTime = modTime.Add(time.Second)
+	}
+	mkfile(srcDir, "changing", "v1")
+	mkfile(srcDir, "unchanged", "same")
+
+	ctx := context.Background()
+	src := &openCountingSource{LocalSource: NewLocalSource(srcDir)}
+	sink := NewLocalSink(sinkDir)
+	idx := NewIndex()
+	sync := func() []string {
+		src.opened = nil
+		_, err := Sync(ctx, ".", src, sink, Params{Index: idx})
+		require.NoError(t, err)
+		return src.opened
+	}
+
+	require.ElementsMatch(t, []string{"changing", "unchanged"}, sync())
+	require.Equal(t, 2, idx.Len())
+
+	// uploaded files are read once more to learn their sum on the sink
+	mkfile(srcDir, "changing", "v2")
+	require.ElementsMatch(t, []string{"changing", "unchanged"}, sync())
+
+	mkfile(srcDir, "changing", "v3")
+	require.Equal(t, []string{"changing"}, sync())
+
+	// it survives being saved
+	filename := filepath.Join(t.TempDir(), "index.pb")
+	require.NoError(t, idx.Save(filename))
+	idx, err := LoadIndex(filename)
+	require.NoError(t, err)
+	mkfile(srcDir, "changing", "v4")
+	require.Equal(t, []string{"changing"}, sync())
+
+	// the sink changed without its info changing, the index can't be
+	// trusted for that file
+	sinkFile := filepath.Join(sinkDir, "unchanged")
+	fi, err := os.Stat(sinkFile)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(sinkFile, []byte("SAME"), 0644))
+	require.NoError(t, os.Chtimes(sinkFile, fi.ModTime(), fi.ModTime()))
+	mkfile(srcDir, "changing", "v5")
+	// read to compare it, then to patch it
+	require.ElementsMatch(t, []string{"changing", "unchanged", "unchanged"}, sync())
+	content, err := os.ReadFile(sinkFile)
+	require.NoError(t, err)
+	require.Equal(t, "same", string(content))
+}
+
+func TestIndexIgnoredWithChecksum(t *testing.T) {
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	modTime := time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC)
+	for _, name := range []string{"a", "b"} {
+		filename := filepath.Join(srcDir, name)
+		require.NoError(t, os.WriteFile(filename, []byte(name), 0644))
+		require.NoError(t, os.Chtimes(filename, modTime, modTime))
+	}
+
+	ctx := context.Background()
+	src := &openCountingSource{LocalSource: NewLocalSource(srcDir)}
+	sink := NewLocalSink(sinkDir)
+	idx := NewIndex()
+	sync := func(compare Compare) []string {
+		src.opened = nil
+		_, err := Sync(ctx, ".", src, sink, Params{Index: idx, Compare: compare})
+		require.NoError(t, err)
+		return src.opened
+	}
+	require.ElementsMatch(t, []string{"a", "b"}, sync(CompareDefault))
+
+	// mod times are ignored when comparing checksums, and left as they are
+	touched := modTime.Add(time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(sinkDir, "a"), touched, touched))
+	require.ElementsMatch(t, []string{"a", "b"}, sync(CompareChecksum))
+	// the index knows the sums of the files, but they're read all the same
+	require.ElementsMatch(t, []string{"a", "b"}, sync(CompareChecksum))
+}
+
+func TestLoadIndex(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "missing"},
+		{name: "corrupted", content: []byte("not an index")},
+		// field 1, the version, set to 99
+		{name: "other version", content: []byte{0x08, 99}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(dir, tt.name)
+			if tt.content != nil {
+				require.NoError(t, os.WriteFile(filename, tt.content, 0644))
+			}
+			idx, err := LoadIndex(filename)
+			require.NoError(t, err)
+			require.Zero(t, idx.Len())
+		})
+	}
+}
+
+func TestIndexChanges(t *testing.T) {
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	mkfile := func(name, content string) {
+		filename := filepath.Join(srcDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+	}
+	mkfile("a/kept", "kept")
+	mkfile("a/modified", "before")
+	mkfile("deleted", "deleted")
+
+	ctx := context.Background()
+	src := NewLocalSource(srcDir)
+	idx := NewIndex()
+	_, err := Sync(ctx, ".", src, NewLocalSink(sinkDir), Params{Index: idx})
+	require.NoError(t, err)
+
+	changes, err := idx.Changes(ctx, ".", src, Params{})
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	mkfile("a/modified", "after, and longer")
+	mkfile("b/added", "added")
+	require.NoError(t, os.Remove(filepath.Join(srcDir, "deleted")))
+
+	changes, err = idx.Changes(ctx, ".", src, Params{})
+	require.NoError(t, err)
+	require.Equal(t, []IndexChange{
+		{Path: "a/modified", Kind: IndexModified},
+		{Path: "b/added", Kind: IndexAdded},
+		{Path: "deleted", Kind: IndexDeleted},
+	}, changes)
+}
+
+// openCountingSource records the files that are opened.
+type openCountingSource struct {
+	*LocalSource
+
+	mu     sync.Mutex
+	opened []string
+}
+
+func (src *openCountingSource) Open(name string) (fs.File, error) {
+	src.mu.Lock()
+	src.opened = append(src.opened, name)
+	src.mu.Unlock()
+	return src.LocalSource.Open(name)
+}
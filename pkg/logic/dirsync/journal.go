@@ -0,0 +1,377 @@
+package dirsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+)
+
+// Journal records the plan of a sync as it's computed, and the ops of the
+// plan as they're applied, in a file. A sync that's interrupted once it was
+// planned whole is then finished with `Resume`, rather than planned again.
+type Journal struct {
+	mu   sync.Mutex
+	f    *os.File
+	plan *typesv1.SyncPlan
+	// whether all the ops of `plan` were recorded
+	complete bool
+	applied  map[string]bool
+	// the first error of recording an applied op, which doesn't fail the op
+	err error
+}
+
+// OpenJournal opens the journal kept in `filename`, made if it's missing. A
+// journal that's corrupted is as good as an empty one, there's nothing to
+// resume. An entry that's cut short, as it was written when the sync was
+// killed, is dropped.
+func OpenJournal(filename string) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return nil, fmt.Errorf("creating dir of journal: %w", err)
+	}
+	f, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening journal: %w", err)
+	}
+	j := &Journal{f: f, applied: make(map[string]bool)}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("reading journal: %w", err)
+	}
+	r := bytes.NewReader(data)
+	good := 0
+	for {
+		entry := new(typesv1.JournalEntry)
+		if err := protodelim.UnmarshalFrom(r, entry); err != nil {
+			break
+		}
+		switch e := entry.Entry.(type) {
+		case *typesv1.JournalEntry_Plan:
+			if j.plan != nil {
+				j.plan = nil
+			} else {
+				j.plan = e.Plan
+			}
+		case *typesv1.JournalEntry_Op:
+			if j.plan != nil {
+				j.plan.Ops = append(j.plan.Ops, e.Op)
+				j.plan.EstimatedBytes += e.Op.EstimatedBytes
+			}
+		case *typesv1.JournalEntry_Planned_:
+			j.complete = j.plan != nil
+		case *typesv1.JournalEntry_Applied_:
+			if j.plan != nil {
+				j.applied[journalKey(e.Applied.Op, e.Applied.Path)] = true
+			}
+		}
+		if j.plan == nil {
+			// a journal starts with its plan, and only has one
+			break
+		}
+		good = len(data) - r.Len()
+	}
+	if j.plan == nil {
+		good = 0
+		j.complete = false
+		clear(j.applied)
+	}
+	if err := f.Truncate(int64(good)); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("truncating journal: %w", err)
+	}
+	if _, err := f.Seek(int64(good), io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("seeking end of journal: %w", err)
+	}
+	return j, nil
+}
+
+// Pending is the plan of the sync of the journal, with only the ops that
+// weren't applied. It's nil if there's nothing to resume, which is the case
+// of a sync that was interrupted before it was planned whole: it's synced
+// again.
+func (j *Journal) Pending() *typesv1.SyncPlan {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.plan == nil || !j.complete {
+		return nil
+	}
+	pending := &typesv1.SyncPlan{}
+	for _, op := range j.plan.Ops {
+		if j.applied[syncOpKey(op)] {
+			continue
+		}
+		pending.Ops = append(pending.Ops, op)
+		pending.EstimatedBytes += op.EstimatedBytes
+	}
+	if len(pending.Ops) == 0 {
+		return nil
+	}
+	return pending
+}
+
+// Close closes the journal, it fails if recording an op did.
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.f.Close(); err != nil {
+		return fmt.Errorf("closing journal: %w", err)
+	}
+	return j.err
+}
+
+// begin starts the journal of a sync that's about to apply `plan`, and the
+// ops recorded with `op` after it.
+func (j *Journal) begin(plan *typesv1.SyncPlan) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.plan, j.complete, j.applied = nil, false, make(map[string]bool)
+	if err := j.reset(); err != nil {
+		return err
+	}
+	if _, err := protodelim.MarshalTo(j.f, &typesv1.JournalEntry{
+		Entry: &typesv1.JournalEntry_Plan{Plan: plan},
+	}); err != nil {
+		return fmt.Errorf("writing plan: %w", err)
+	}
+	j.plan = plan
+	return nil
+}
+
+// op records that `op` is part of the plan, before it's applied.
+func (j *Journal) op(op *typesv1.SyncOp) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := protodelim.MarshalTo(j.f, &typesv1.JournalEntry{
+		Entry: &typesv1.JournalEntry_Op{Op: op},
+	}); err != nil {
+		return fmt.Errorf("writing op: %w", err)
+	}
+	j.plan.Ops = append(j.plan.Ops, op)
+	j.plan.EstimatedBytes += op.EstimatedBytes
+	return nil
+}
+
+// planned records that all the ops of the plan were, the sync can be
+// resumed.
+func (j *Journal) planned() error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := protodelim.MarshalTo(j.f, &typesv1.JournalEntry{
+		Entry: &typesv1.JournalEntry_Planned_{Planned: &typesv1.JournalEntry_Planned{}},
+	}); err != nil {
+		return fmt.Errorf("journaling end of plan: %w", err)
+	}
+	j.complete = true
+	return nil
+}
+
+// record records that the op of `ev` was applied.
+func (j *Journal) record(ev Event) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.plan == nil || j.err != nil {
+		return
+	}
+	j.applied[journalKey(ev.Op, ev.Path)] = true
+	_, err := protodelim.MarshalTo(j.f, &typesv1.JournalEntry{
+		Entry: &typesv1.JournalEntry_Applied_{Applied: &typesv1.JournalEntry_Applied{
+			Op:   ev.Op,
+			Path: ev.Path,
+		}},
+	})
+	if err != nil {
+		j.err = fmt.Errorf("recording applied op: %w", err)
+	}
+}
+
+// finish empties the journal once its sync is done.
+func (j *Journal) finish() error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.plan, j.complete, j.applied = nil, false, make(map[string]bool)
+	if err := j.reset(); err != nil {
+		return err
+	}
+	return j.err
+}
+
+func (j *Journal) reset() error {
+	if err := j.f.Truncate(0); err != nil {
+		return fmt.Errorf("truncating journal: %w", err)
+	}
+	if _, err := j.f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("seeking start of journal: %w", err)
+	}
+	return nil
+}
+
+// journalKey identifies the op `op` on `path` among the ops of a plan, see
+// `Event`.
+func journalKey(op string, path *typesv1.Path) string {
+	return op + " " + strings.Join(path.GetElements(), "/")
+}
+
+// syncOpKey is the `journalKey` of the op of a plan.
+func syncOpKey(op *typesv1.SyncOp) string {
+	switch o := op.Op.(type) {
+	case *typesv1.SyncOp_Create_:
+		return journalKey("create", op.Path)
+	case *typesv1.SyncOp_Patch_:
+		return journalKey("patch", op.Path)
+	case *typesv1.SyncOp_Delete_:
+		return journalKey("delete", op.Path)
+	case *typesv1.SyncOp_Move_:
+		// moves are observed as what they move
+		return journalKey("move", o.Move.From)
+	default:
+		return ""
+	}
+}
+
+type journalKeyCtx struct{}
+
+func withJournal(ctx context.Context, j *Journal) context.Context {
+	if j == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, journalKeyCtx{}, j)
+}
+
+// journalFrom returns the journal of the sync `ctx` is for, if any. The
+// methods of a nil journal do nothing.
+func journalFrom(ctx context.Context) *Journal {
+	j, _ := ctx.Value(journalKeyCtx{}).(*Journal)
+	return j
+}
+
+// journalOps returns emitters that journal the ops of the plan before they're
+// emitted, the plan is journaled as it's computed.
+func journalOps(j *Journal,
+	emitCreate func(CreateOp) error,
+	emitPatch func(PatchOp) error,
+	emitDelete func(DeleteOp) error,
+	emitMove func(MoveOp) error,
+) (
+	func(CreateOp) error,
+	func(PatchOp) error,
+	func(DeleteOp) error,
+	func(MoveOp) error,
+) {
+	journaled := func(op *typesv1.SyncOp, emit func() error) error {
+		if err := j.op(op); err != nil {
+			return fmt.Errorf("journaling op: %w", err)
+		}
+		return emit()
+	}
+	var journaledMove func(MoveOp) error
+	if emitMove != nil {
+		journaledMove = func(mo MoveOp) error {
+			return journaled(moveOpToProto(mo), func() error { return emitMove(mo) })
+		}
+	}
+	return func(co CreateOp) error {
+			return journaled(createOpToProto(co), func() error { return emitCreate(co) })
+		},
+		func(po PatchOp) error {
+			return journaled(patchOpToProto(po), func() error { return emitPatch(po) })
+		},
+		func(do DeleteOp) error {
+			return journaled(deleteOpToProto(do), func() error { return emitDelete(do) })
+		},
+		journaledMove
+}
+
+// ErrNothingToResume is the error of resuming with a journal that has no
+// pending ops.
+var ErrNothingToResume = errors.New("no interrupted sync to resume")
+
+// Resume finishes the sync of `params.Journal` that was interrupted: the ops
+// of its plan that weren't applied are, like with `ExecutePlan`. The ones
+// that are found applied on the sink, as they were when the sync was
+// interrupted, are skipped.
+func Resume(ctx context.Context, src Source, sink Sink, params Params) (*SyncStats, error) {
+	plan := params.Journal.Pending()
+	if plan == nil {
+		return nil, ErrNothingToResume
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	rec := &statsRecorder{start: time.Now()}
+	ctx = withObserver(withStats(ctx, rec), params.Observer)
+	err := executePlan(withJournal(ctx, params.Journal), src, sink, plan, params, true)
+	if err == nil {
+		err = params.Journal.finish()
+	}
+	return rec.done(), err
+}
+
+// appliedOnSink tells if `op` is applied on the sink already.
+func appliedOnSink(sigs *typesv1.DirSum, op *typesv1.SyncOp) bool {
+	dir, file, found := sinkLookup(sigs, op.Path)
+	switch o := op.Op.(type) {
+	case *typesv1.SyncOp_Create_:
+		if o.Create.Info.IsDir {
+			return dir != nil
+		}
+		return file != nil && proto.Equal(file.Info, o.Create.Info)
+	case *typesv1.SyncOp_Patch_:
+		return file != nil && o.Patch.Sum != nil && proto.Equal(file.Info, o.Patch.Info)
+	case *typesv1.SyncOp_Delete_:
+		return !found
+	case *typesv1.SyncOp_Move_:
+		_, _, fromFound := sinkLookup(sigs, o.Move.From)
+		return found && !fromFound
+	default:
+		return false
+	}
+}
+
+// ResumableSink is a `Sink` that keeps what it received of the files whose
+// upload was interrupted, for their upload to be resumed where it stopped.
+type ResumableSink interface {
+	Sink
+	// CreateFileResumable is `CreateFile`, resuming the upload of the same
+	// file if part of it was kept: only the rest of `r` is sent.
+	CreateFileResumable(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, r io.Reader) error
+}
+
+// minResumableSize is the size from which the uploads of files are resumed.
+const minResumableSize = 8 << 20
+
+// resumableSinkOf returns `sink` if the upload of `fi` to it can be resumed.
+// Only journaled syncs are, an upload that's not resumed soon is better
+// started over.
+func resumableSinkOf(ctx context.Context, sink Sink, fi *typesv1.FileInfo) (ResumableSink, bool) {
+	if journalFrom(ctx) == nil || fi.Size < minResumableSize {
+		return nil, false
+	}
+	rs, ok := sink.(ResumableSink)
+	return rs, ok
+}
@@ -0,0 +1,161 @@
+package dirsync
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncResume(t *testing.T) {
+	tests := []struct {
+		name string
+		// the interrupted op is applied, but not journaled
+		applied bool
+	}{
+		{name: "interrupted op not applied"},
+		{name: "interrupted op applied", applied: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			srcDir, sinkDir := t.TempDir(), t.TempDir()
+			for _, name := range []string{"a.txt", "b.txt", "dir/c.txt", "dir/d.txt"} {
+				path := filepath.Join(srcDir, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, []byte(name), 0644))
+			}
+			require.NoError(t, os.WriteFile(filepath.Join(sinkDir, "gone.txt"), []byte("gone"), 0644))
+
+			journalFile := filepath.Join(t.TempDir(), "journal.pb")
+			journal, err := OpenJournal(journalFile)
+			require.NoError(t, err)
+			require.Nil(t, journal.Pending())
+
+			sink := &interruptingSink{LocalSink: NewLocalSink(sinkDir), name: "c.txt", apply: tt.applied, journal: journal}
+			params := Params{MaxParallelFileStreams: 1, Journal: journal}
+			_, err = Sync(ctx, ".", NewLocalSource(srcDir), sink, params)
+			require.ErrorIs(t, err, errInterrupted)
+			require.NotNil(t, journal.Pending())
+			require.NoError(t, journal.Close())
+
+			// the entry being written as the sync was killed is cut short
+			f, err := os.OpenFile(journalFile, os.O_WRONLY|os.O_APPEND, 0644)
+			require.NoError(t, err)
+			_, err = f.Write([]byte{0x10, 0x0a})
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+
+			journal, err = OpenJournal(journalFile)
+			require.NoError(t, err)
+			pending := journal.Pending()
+			require.NotNil(t, pending)
+			var paths []string
+			for _, op := range pending.Ops {
+				paths = append(paths, typesv1.StringFromPath(op.Path))
+			}
+			require.Contains(t, paths, "dir/c.txt")
+
+			params.Journal = journal
+			_, err = Resume(ctx, NewLocalSource(srcDir), NewLocalSink(sinkDir), params)
+			require.NoError(t, err)
+			require.Equal(t, tracedFiles(t, NewLocalSource(srcDir), Params{}), tracedFiles(t, NewLocalSource(sinkDir), Params{}))
+			require.Nil(t, journal.Pending())
+			require.NoError(t, journal.Close())
+
+			journal, err = OpenJournal(journalFile)
+			require.NoError(t, err)
+			require.Nil(t, journal.Pending())
+			require.NoError(t, journal.Close())
+		})
+	}
+}
+
+func TestJournalIncompletePlan(t *testing.T) {
+	journalFile := filepath.Join(t.TempDir(), "journal.pb")
+	journal, err := OpenJournal(journalFile)
+	require.NoError(t, err)
+	require.NoError(t, journal.begin(&typesv1.SyncPlan{}))
+	op := createOpToProto(createFileOp(&typesv1.Path{}, &typesv1.FileInfo{Name: "file", Size: 4}))
+	require.NoError(t, journal.op(op))
+	require.Nil(t, journal.Pending(), "the plan isn't whole")
+	require.NoError(t, journal.Close())
+
+	// a sync interrupted as it was planned is synced again
+	journal, err = OpenJournal(journalFile)
+	require.NoError(t, err)
+	require.Nil(t, journal.Pending())
+	require.NoError(t, journal.begin(&typesv1.SyncPlan{}))
+	require.NoError(t, journal.op(op))
+	require.NoError(t, journal.planned())
+	require.NoError(t, journal.Close())
+
+	journal, err = OpenJournal(journalFile)
+	require.NoError(t, err)
+	pending := journal.Pending()
+	require.NotNil(t, pending)
+	require.Len(t, pending.Ops, 1)
+	require.Equal(t, uint64(4), pending.EstimatedBytes)
+	require.NoError(t, journal.Close())
+}
+
+func TestResumeNothing(t *testing.T) {
+	journal, err := OpenJournal(filepath.Join(t.TempDir(), "journal.pb"))
+	require.NoError(t, err)
+	defer journal.Close()
+	_, err = Resume(context.Background(), NewLocalSource(t.TempDir()), NewLocalSink(t.TempDir()), Params{Journal: journal})
+	require.ErrorIs(t, err, ErrNothingToResume)
+}
+
+func TestOpenJournalCorrupted(t *testing.T) {
+	journalFile := filepath.Join(t.TempDir(), "journal.pb")
+	require.NoError(t, os.WriteFile(journalFile, []byte("not a journal"), 0644))
+	journal, err := OpenJournal(journalFile)
+	require.NoError(t, err)
+	require.Nil(t, journal.Pending())
+	require.NoError(t, journal.Close())
+}
+
+var errInterrupted = errors.New("interrupted")
+
+// interruptingSink fails to create the files named `name`, after it created
+// them if `apply`, once the plan is in `journal`.
+type interruptingSink struct {
+	*LocalSink
+	name    string
+	apply   bool
+	journal *Journal
+}
+
+func (sk *interruptingSink) CreateFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, r io.Reader) error {
+	if fi.Name != sk.name {
+		return sk.LocalSink.CreateFile(ctx, dir, fi, r)
+	}
+	// the plan is journaled as ops are applied, a sync can't be resumed
+	// if it's interrupted before it's planned whole
+	for !sk.planned() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+	if sk.apply {
+		if err := sk.LocalSink.CreateFile(ctx, dir, fi, r); err != nil {
+			return err
+		}
+	}
+	return errInterrupted
+}
+
+func (sk *interruptingSink) planned() bool {
+	sk.journal.mu.Lock()
+	defer sk.journal.mu.Unlock()
+	return sk.journal.complete
+}
@@ -0,0 +1,144 @@
+package dirsync
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// LazySink is a `Sink` whose signatures are fetched in two steps: first the
+// tree with only the info of its files, then the sums of the files whose
+// content the diff looks at. Fetching signatures then costs in proportion to
+// the metadata of the sink, not to its data.
+type LazySink interface {
+	Sink
+	// GetTree is `GetSignatures` where files only have their info. Dirs
+	// still have their digest.
+	GetTree(ctx context.Context) (*typesv1.DirSum, error)
+	// GetFileSums returns the sums of the files at `paths`, in order.
+	GetFileSums(ctx context.Context, paths []*typesv1.Path) ([]*typesv1.FileSum, error)
+}
+
+// fileSumBatchSize is how many sums are asked of a `LazySink` at once.
+const fileSumBatchSize = 256
+
+// getSignatures gets the signatures of `sink`. If it's a `LazySink`, only
+// its tree is fetched and it's returned, for `fetchFileSums` to complete the
+// tree with the sums it needs.
+func getSignatures(ctx context.Context, sink Sink) (*typesv1.DirSum, LazySink, error) {
+	lazy, ok := sink.(LazySink)
+	if !ok {
+		sigs, err := sink.GetSignatures(ctx)
+		return sigs, nil, err
+	}
+	tree, err := lazy.GetTree(ctx)
+	return tree, lazy, err
+}
+
+// pendingSum is a file of the sink whose sum is to be fetched, to replace
+// `dir.Files[i]`.
+type pendingSum struct {
+	path *typesv1.Path
+	dir  *typesv1.DirSum
+	i    int
+}
+
+// pendingSumAt finds the file at `path` in the tree of a sink.
+func pendingSumAt(tree *typesv1.DirSum, path *typesv1.Path) (pendingSum, bool) {
+	dir, _, found := sinkLookup(tree, typesv1.DirOf(path))
+	if !found || dir == nil || len(path.Elements) == 0 {
+		return pendingSum{}, false
+	}
+	name := path.Elements[len(path.Elements)-1]
+	i, found := slices.BinarySearchFunc(dir.Files, name, func(file *typesv1.FileSum, name string) int {
+		return strings.Compare(file.Info.Name, name)
+	})
+	if !found {
+		return pendingSum{}, false
+	}
+	return pendingSum{path: path, dir: dir, i: i}, true
+}
+
+// fetchFileSums fills the sums of the pending files, asking for them in
+// batches.
+func fetchFileSums(ctx context.Context, sink LazySink, pending []pendingSum) error {
+	for len(pending) > 0 {
+		batch := pending[:min(fileSumBatchSize, len(pending))]
+		pending = pending[len(batch):]
+
+		paths := make([]*typesv1.Path, 0, len(batch))
+		for _, ps := range batch {
+			paths = append(paths, ps.path)
+		}
+		sums, err := sink.GetFileSums(ctx, paths)
+		if err != nil {
+			return fmt.Errorf("getting sums of %d files: %w", len(paths), err)
+		}
+		if len(sums) != len(batch) {
+			return fmt.Errorf("asked for the sums of %d files, got %d", len(batch), len(sums))
+		}
+		for i, ps := range batch {
+			if !proto.Equal(sums[i].GetInfo(), ps.dir.Files[ps.i].Info) {
+				return fmt.Errorf("file %q has changed on sink during the sync", typesv1.StringFromPath(ps.path))
+			}
+			ps.dir.Files[ps.i] = sums[i]
+		}
+	}
+	return nil
+}
+
+// diffFileSums lists the files of the sink whose sums the diff with the
+// source looks at: the files on both sides in subtrees that differ and, if
+// moves are detected, the files gone from the source that could have moved.
+func diffFileSums(params Params, srcDir *SourceDir, sinkDir *typesv1.DirSum, withMoves bool) []pendingSum {
+	pending := appendDiffFileSums(nil, params.Compare, &typesv1.Path{}, srcDir, sinkDir)
+	if !withMoves {
+		return pending
+	}
+	var c moveCandidates
+	c.collect(params, &typesv1.Path{}, srcDir, sinkDir)
+	sizes := make(map[uint64]bool, len(c.newFiles))
+	for _, file := range c.newFiles {
+		sizes[file.file.Info.Size] = true
+	}
+	for _, gone := range c.goneFiles {
+		if !sizes[gone.file.Info.Size] {
+			continue
+		}
+		if ps, ok := pendingSumAt(sinkDir, gone.path); ok {
+			pending = append(pending, ps)
+		}
+	}
+	return pending
+}
+
+func appendDiffFileSums(pending []pendingSum, compare Compare, path *typesv1.Path, src *SourceDir, sink *typesv1.DirSum) []pendingSum {
+	if sameSubtree(compare, src, sink) {
+		return pending
+	}
+	for _, srcDir := range src.Dirs {
+		if sinkDir, found := sinkHasDirNamed(sink, srcDir.Info.Name); found {
+			pending = appendDiffFileSums(pending, compare, typesv1.PathJoin(path, srcDir.Info.Name), srcDir, sinkDir)
+		}
+	}
+	return appendDirFileSums(pending, path, src, sink)
+}
+
+// appendDirFileSums lists the files of a single dir whose sums are needed.
+func appendDirFileSums(pending []pendingSum, path *typesv1.Path, src *SourceDir, sink *typesv1.DirSum) []pendingSum {
+	for i, sinkFile := range sink.Files {
+		if sinkFile.Info.IsSymlink() {
+			// symlinks have no content to sum, their target is in their info
+			continue
+		}
+		if !srcHasFileNamed(src, sinkFile.Info.Name) {
+			continue
+		}
+		pending = append(pending, pendingSum{path: typesv1.PathJoin(path, sinkFile.Info.Name), dir: sink, i: i})
+	}
+	return pending
+}
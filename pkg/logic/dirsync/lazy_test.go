@@ -0,0 +1,108 @@
+package dirsync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncLazySink(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("streaming=%v", streaming), func(t *testing.T) {
+			ctx := context.Background()
+			srcDir, sinkDir := t.TempDir(), t.TempDir()
+			mkfile := func(name, content string) {
+				filename := filepath.Join(srcDir, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+				require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+			}
+			for i := 0; i < fileSumBatchSize+10; i++ {
+				mkfile(fmt.Sprintf("many/%03d", i), fmt.Sprintf("content %d", i))
+			}
+			mkfile("untouched/file", "same")
+			mkfile("changing/kept", "kept")
+			mkfile("changing/edited", "before")
+
+			src := NewLocalSource(srcDir)
+			sink := &lazyLocalSink{Sink: NewLocalSink(sinkDir)}
+			params := Params{Streaming: streaming}
+			_, err := Sync(ctx, ".", src, sink, params)
+			require.NoError(t, err)
+			// everything is new, no sum is needed
+			require.Empty(t, sink.asked)
+
+			mkfile("changing/edited", "after")
+			mkfile("changing/added", "added")
+			_, err = Sync(ctx, ".", src, sink, params)
+			require.NoError(t, err)
+			content, err := os.ReadFile(filepath.Join(sinkDir, "changing", "edited"))
+			require.NoError(t, err)
+			require.Equal(t, "after", string(content))
+			if streaming {
+				// subtrees aren't skipped, sums come in batches for each
+				// dir: 2 for "many", 1 for "changing" and "untouched"
+				require.Len(t, sink.asked, fileSumBatchSize+10+3)
+				require.Equal(t, 4, sink.batches)
+			} else {
+				require.ElementsMatch(t, []string{"changing/edited", "changing/kept"}, sink.asked)
+				require.Equal(t, 1, sink.batches)
+			}
+		})
+	}
+}
+
+// lazyLocalSink is a local sink that gives its files' sums only when asked.
+type lazyLocalSink struct {
+	Sink
+
+	mu      sync.Mutex
+	asked   []string
+	batches int
+}
+
+func (sk *lazyLocalSink) GetTree(ctx context.Context) (*typesv1.DirSum, error) {
+	sk.mu.Lock()
+	sk.asked, sk.batches = nil, 0
+	sk.mu.Unlock()
+	tree, err := sk.Sink.GetSignatures(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var strip func(dir *typesv1.DirSum)
+	strip = func(dir *typesv1.DirSum) {
+		for _, child := range dir.Dirs {
+			strip(child)
+		}
+		for i, file := range dir.Files {
+			dir.Files[i] = &typesv1.FileSum{Info: file.Info}
+		}
+	}
+	strip(tree)
+	return tree, nil
+}
+
+func (sk *lazyLocalSink) GetFileSums(ctx context.Context, paths []*typesv1.Path) ([]*typesv1.FileSum, error) {
+	tree, err := sk.Sink.GetSignatures(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sk.mu.Lock()
+	defer sk.mu.Unlock()
+	sk.batches++
+	var sums []*typesv1.FileSum
+	for _, path := range paths {
+		sk.asked = append(sk.asked, typesv1.StringFromPath(path))
+		_, file, found := sinkLookup(tree, path)
+		if !found || file == nil {
+			return nil, fmt.Errorf("no such file %q", typesv1.StringFromPath(path))
+		}
+		sums = append(sums, file)
+	}
+	return sums, nil
+}
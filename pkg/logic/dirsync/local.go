@@ -0,0 +1,402 @@
+package dirsync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	_ SymlinkSource = (*LocalSource)(nil)
+	_ AttrSource    = (*LocalSource)(nil)
+)
+
+// LocalSource is a `Source` for a dir on the local filesystem.
+type LocalSource struct {
+	Source
+	dir string
+}
+
+func NewLocalSource(dir string) *LocalSource {
+	return &LocalSource{Source: os.DirFS(dir).(Source), dir: dir}
+}
+
+// Open opens files with holes as `SparseReader`s.
+func (ls *LocalSource) Open(name string) (fs.File, error) {
+	f, err := ls.Source.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	osf, ok := f.(*os.File)
+	if !ok {
+		return f, nil
+	}
+	sf, err := SparseFile(osf)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return sf, nil
+}
+
+func (ls *LocalSource) Lstat(name string) (fs.FileInfo, error) {
+	filename, err := ls.join("lstat", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Lstat(filename)
+}
+
+func (ls *LocalSource) ReadLink(name string) (string, error) {
+	filename, err := ls.join("readlink", name)
+	if err != nil {
+		return "", err
+	}
+	return os.Readlink(filename)
+}
+
+func (ls *LocalSource) ReadAttrs(name string, attrs Attrs) (*typesv1.FileAttrs, error) {
+	filename, err := ls.join("readattrs", name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+	return readLocalAttrs(filename, fi, attrs)
+}
+
+func (ls *LocalSource) join(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(ls.dir, filepath.FromSlash(name)), nil
+}
+
+var (
+	_ MoveSink = (*LocalSink)(nil)
+	_ DirSink  = (*LocalSink)(nil)
+	_ AttrSink = (*LocalSink)(nil)
+)
+
+// LocalSink is a `Sink` for a dir on the local filesystem. Unlike the server,
+// it applies the mode and mod time of what it receives, so that the dir ends
+// up like the source.
+type LocalSink struct {
+	dir string
+	// attributes applied and reported
+	attrs Attrs
+}
+
+func NewLocalSink(dir string) *LocalSink {
+	return &LocalSink{dir: dir}
+}
+
+func (ls *LocalSink) GetSignatures(ctx context.Context) (*typesv1.DirSum, error) {
+	sigs, err := TraceSink(ctx, ls.dir, localSumDB{attrs: ls.attrs})
+	if err != nil {
+		return nil, err
+	}
+	if err := findSinkHardLinks(ls.dir, sigs); err != nil {
+		return nil, fmt.Errorf("finding hard links: %w", err)
+	}
+	return sigs, nil
+}
+
+func (ls *LocalSink) GetDirSignatures(ctx context.Context, path *typesv1.Path) (*typesv1.DirSum, bool, error) {
+	return ListSinkDir(ctx, ls.dir, path, localSumDB{attrs: ls.attrs})
+}
+
+// WithAttrs keeps owners only when running as root.
+func (ls *LocalSink) WithAttrs(attrs Attrs) (Sink, Attrs) {
+	attrs = keptAttrs(attrs)
+	return &LocalSink{dir: ls.dir, attrs: attrs}, attrs
+}
+
+func (ls *LocalSink) CreateFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, r io.Reader) error {
+	filename, err := ls.filename("create", typesv1.PathJoin(dir, fi.Name))
+	if err != nil {
+		return err
+	}
+	switch {
+	case fi.IsDir:
+		// the dir gets its info once its entries are created, see
+		// `emitCreateOpsForDir`, until then it's kept writable
+		err := os.Mkdir(filename, fs.FileMode(fi.Mode).Perm()|0700)
+		if err != nil && !os.IsExist(err) {
+			return fmt.Errorf("creating dir %q: %w", filename, err)
+		}
+		return nil
+	case fi.IsSymlink():
+		return ls.symlink(dir, fi)
+	case fi.HardLink != nil:
+		return ls.link(dir, fi)
+	}
+	return ls.writeFile(filename, fi, func(w io.Writer) error {
+		_, err := CopySparse(w, r)
+		return err
+	})
+}
+
+func (ls *LocalSink) PatchFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, sum *typesv1.FileSum, r io.Reader) error {
+	filename, err := ls.filename("patch", typesv1.PathJoin(dir, fi.Name))
+	if err != nil {
+		return err
+	}
+	switch {
+	case fi.IsDir:
+		return ls.setFileInfo(filename, fi)
+	case fi.IsSymlink():
+		return ls.symlink(dir, fi)
+	case fi.HardLink != nil:
+		return ls.link(dir, fi)
+	}
+	orig, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("opening original file: %w", err)
+	}
+	defer orig.Close()
+	gotSum, err := ComputeFileSum(ctx, orig, sum.Info)
+	if err != nil {
+		return fmt.Errorf("computing file sum: %w", err)
+	}
+	if !proto.Equal(sum, gotSum) {
+		return fmt.Errorf("file sum mismatch, %q has changed since it was summed", filename)
+	}
+	return ls.writeFile(filename, fi, func(w io.Writer) error {
+		return PatchInto(ctx, r, orig, sum, w)
+	})
+}
+
+func (ls *LocalSink) DeleteFile(ctx context.Context, op DeleteOp) error {
+	filename, err := ls.filename("delete", op.Path)
+	if err != nil {
+		return err
+	}
+	if op.FileInfo.IsDir {
+		return os.RemoveAll(filename)
+	}
+	return os.Remove(filename)
+}
+
+func (ls *LocalSink) MoveFile(ctx context.Context, op MoveOp) error {
+	from, err := ls.filename("move", op.From)
+	if err != nil {
+		return err
+	}
+	to, err := ls.filename("move", moveOpPath(op))
+	if err != nil {
+		return err
+	}
+	// renaming would silently replace a file
+	if _, err := os.Lstat(to); err == nil {
+		return fmt.Errorf("moving %q: %q already exists", from, to)
+	}
+	if err := os.Rename(from, to); err != nil {
+		return fmt.Errorf("moving %q: %w", from, err)
+	}
+	return ls.setFileInfo(to, op.Info)
+}
+
+// filename is where `path` is in the dir of the sink. Like for
+// `LocalSource`, paths that would be out of it are rejected.
+func (ls *LocalSink) filename(op string, path *typesv1.Path) (string, error) {
+	// not cleaned, for elements like ".." to be rejected
+	name := strings.Join(path.GetElements(), "/")
+	if len(path.GetElements()) == 0 {
+		name = "."
+	}
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(ls.dir, filepath.FromSlash(name)), nil
+}
+
+// writeFile writes a file next to `filename` and swaps it in once it's
+// complete, so that readers never see a partial file.
+func (ls *LocalSink) writeFile(filename string, fi *typesv1.FileInfo, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".syncy-*")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	success := false
+	defer func() {
+		if !success {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if err := write(tmp); err != nil {
+		return fmt.Errorf("writing %q: %w", filename, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("flushing temp file: %w", err)
+	}
+	if err := ls.setFileInfo(tmp.Name(), fi); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("swapping in %q: %w", filename, err)
+	}
+	success = true
+	return nil
+}
+
+// symlink creates or replaces a symlink, the same way `writeFile` does.
+func (ls *LocalSink) symlink(dir *typesv1.Path, fi *typesv1.FileInfo) error {
+	linkPath := typesv1.StringFromPath(typesv1.PathJoin(dir, fi.Name))
+	if _, err := ResolveSymlink(linkPath, fi.LinkTarget); err != nil {
+		return fmt.Errorf("invalid symlink %q: %w", linkPath, err)
+	}
+	filename, err := ls.filename("symlink", typesv1.PathJoin(dir, fi.Name))
+	if err != nil {
+		return err
+	}
+	tmpname := filepath.Join(filepath.Dir(filename), "."+fi.Name+".syncy-link")
+	_ = os.Remove(tmpname)
+	if err := os.Symlink(fi.LinkTarget, tmpname); err != nil {
+		return fmt.Errorf("creating symlink: %w", err)
+	}
+	if err := lchtimes(tmpname, fi.GetModTime().AsTime()); err != nil {
+		_ = os.Remove(tmpname)
+		return fmt.Errorf("setting mod time of symlink: %w", err)
+	}
+	if err := os.Rename(tmpname, filename); err != nil {
+		_ = os.Remove(tmpname)
+		return fmt.Errorf("swapping in %q: %w", filename, err)
+	}
+	return nil
+}
+
+// link creates or replaces a hard link, the same way `writeFile` does. The
+// mode and mod time are the ones of the file it links to.
+func (ls *LocalSink) link(dir *typesv1.Path, fi *typesv1.FileInfo) error {
+	if err := CheckHardLink(dir, fi); err != nil {
+		return err
+	}
+	filename, err := ls.filename("link", typesv1.PathJoin(dir, fi.Name))
+	if err != nil {
+		return err
+	}
+	target, err := ls.filename("link", fi.HardLink)
+	if err != nil {
+		return err
+	}
+	tmpname := filepath.Join(filepath.Dir(filename), "."+fi.Name+".syncy-hardlink")
+	_ = os.Remove(tmpname)
+	if err := os.Link(target, tmpname); err != nil {
+		return fmt.Errorf("creating hard link: %w", err)
+	}
+	// renaming onto a link to the same file leaves both in place
+	defer os.Remove(tmpname)
+	if err := os.Rename(tmpname, filename); err != nil {
+		return fmt.Errorf("swapping in %q: %w", filename, err)
+	}
+	return nil
+}
+
+func (ls *LocalSink) setFileInfo(filename string, fi *typesv1.FileInfo) error {
+	// owners are set first, changing them can clear the setuid bits
+	if err := writeLocalAttrs(filename, fi.Attrs, ls.attrs); err != nil {
+		return err
+	}
+	if err := os.Chmod(filename, fs.FileMode(fi.Mode).Perm()); err != nil {
+		return fmt.Errorf("setting mode of %q: %w", filename, err)
+	}
+	modTime := fi.GetModTime().AsTime()
+	if err := os.Chtimes(filename, modTime, modTime); err != nil {
+		return fmt.Errorf("setting mod time of %q: %w", filename, err)
+	}
+	return nil
+}
+
+// localSumDB sums the files of a local dir, the namespace is the dir.
+type localSumDB struct {
+	// attributes read along with the info of files
+	attrs Attrs
+}
+
+func (db localSumDB) Stat(ctx context.Context, dir string, name string) (*typesv1.FileInfo, bool, error) {
+	filename := filepath.Join(dir, name)
+	fi, err := os.Lstat(filename)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	info := typesv1.FileInfoFromFS(fi)
+	if fi.Mode()&fs.ModeSymlink != 0 {
+		info.LinkTarget, err = os.Readlink(filename)
+		if err != nil {
+			return nil, false, err
+		}
+	} else if db.attrs != 0 {
+		info.Attrs, err = readLocalAttrs(filename, fi, db.attrs)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return info, true, nil
+}
+
+func (db localSumDB) ListDir(ctx context.Context, dir string, name string) ([]*typesv1.FileInfo, bool, error) {
+	dirname := filepath.Join(dir, name)
+	entries, err := os.ReadDir(dirname)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	out := make([]*typesv1.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		fi, err := entry.Info()
+		if err != nil {
+			return nil, true, err
+		}
+		info := typesv1.FileInfoFromFS(fi)
+		switch {
+		case fi.Mode().IsRegular(), fi.IsDir():
+			if db.attrs != 0 {
+				info.Attrs, err = readLocalAttrs(filepath.Join(dirname, entry.Name()), fi, db.attrs)
+				if err != nil {
+					return nil, true, err
+				}
+			}
+		case fi.Mode()&fs.ModeSymlink != 0:
+			info.LinkTarget, err = os.Readlink(filepath.Join(dirname, entry.Name()))
+			if err != nil {
+				return nil, true, err
+			}
+		default:
+			continue
+		}
+		out = append(out, info)
+	}
+	return out, true, nil
+}
+
+func (localSumDB) GetFileSum(ctx context.Context, dir string, name string, fi *typesv1.FileInfo) (*typesv1.FileSum, bool, error) {
+	f, err := os.Open(filepath.Join(dir, name, fi.Name))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+	sum, err := ComputeFileSum(ctx, f, fi)
+	if err != nil {
+		return nil, true, err
+	}
+	return sum, true, nil
+}
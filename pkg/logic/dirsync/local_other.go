@@ -0,0 +1,48 @@
+//go:build !linux && !darwin
+
+package dirsync
+
+import (
+	"io/fs"
+	"os"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+)
+
+// lchtimes is a no-op where symlink times can't be set, such symlinks are
+// patched again on every sync.
+func lchtimes(filename string, modTime time.Time) error {
+	return nil
+}
+
+// fileInode is always 0 where inodes aren't available, files are then only
+// told apart by their size and mod time.
+func fileInode(fi fs.FileInfo) uint64 {
+	return 0
+}
+
+// fileLinkID is always false where hard links can't be told apart, they're
+// then synced as independent files.
+func fileLinkID(fi fs.FileInfo) (linkID, bool) {
+	return linkID{}, false
+}
+
+// fileHoles is always none where holes can't be found, sparse files are
+// then synced whole.
+func fileHoles(f *os.File, fi fs.FileInfo) ([]fileHole, error) {
+	return nil, nil
+}
+
+// keptAttrs is always none where attributes can't be read or applied.
+func keptAttrs(attrs Attrs) Attrs {
+	return 0
+}
+
+func readLocalAttrs(filename string, fi fs.FileInfo, attrs Attrs) (*typesv1.FileAttrs, error) {
+	return nil, nil
+}
+
+func writeLocalAttrs(filename string, fa *typesv1.FileAttrs, attrs Attrs) error {
+	return nil
+}
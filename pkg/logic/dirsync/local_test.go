@@ -0,0 +1,163 @@
+package dirsync
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncLocalDirs(t *testing.T) {
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	mkfile := func(root, name, content string, mode fs.FileMode) {
+		filename := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		require.NoError(t, os.WriteFile(filename, []byte(content), mode))
+		modTime := time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, os.Chtimes(filename, modTime, modTime))
+	}
+	mkfile(srcDir, "a/unchanged", "same", 0644)
+	mkfile(srcDir, "a/changed", "hello world, and more", 0600)
+	mkfile(srcDir, "b/new", "new", 0755)
+	require.NoError(t, os.Symlink("a/changed", filepath.Join(srcDir, "link")))
+
+	mkfile(sinkDir, "a/unchanged", "same", 0644)
+	mkfile(sinkDir, "a/changed", "hello world", 0644)
+	mkfile(sinkDir, "gone/file", "gone", 0644)
+
+	ctx := context.Background()
+	src := &remoteSource{LocalSource: NewLocalSource(srcDir)}
+	_, err := Sync(ctx, ".", src, NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"patch a/changed"}, src.calls)
+
+	for _, name := range []string{"a/unchanged", "a/changed", "b/new"} {
+		want, err := os.Stat(filepath.Join(srcDir, name))
+		require.NoError(t, err)
+		got, err := os.Stat(filepath.Join(sinkDir, name))
+		require.NoError(t, err)
+		require.Equal(t, want.Mode(), got.Mode(), name)
+		require.Equal(t, want.ModTime(), got.ModTime(), name)
+
+		wantContent, err := os.ReadFile(filepath.Join(srcDir, name))
+		require.NoError(t, err)
+		gotContent, err := os.ReadFile(filepath.Join(sinkDir, name))
+		require.NoError(t, err)
+		require.Equal(t, string(wantContent), string(gotContent), name)
+	}
+	target, err := os.Readlink(filepath.Join(sinkDir, "link"))
+	require.NoError(t, err)
+	require.Equal(t, "a/changed", target)
+	_, err = os.Stat(filepath.Join(sinkDir, "gone"))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	// no leftovers from swapping files in
+	entries, err := os.ReadDir(filepath.Join(sinkDir, "a"))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
+
+func TestSyncLocalDirInfo(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		ctx := context.Background()
+		srcDir, sinkDir := t.TempDir(), t.TempDir()
+		for _, name := range []string{"ro/file", "ro/sub/file", "rw/file"} {
+			filename := filepath.Join(srcDir, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+			require.NoError(t, os.WriteFile(filename, []byte(name), 0644))
+		}
+		modTime := time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC)
+		dirs := []string{"ro/sub", "ro", "rw"}
+		for _, name := range dirs {
+			require.NoError(t, os.Chtimes(filepath.Join(srcDir, name), modTime, modTime))
+		}
+		require.NoError(t, os.Chmod(filepath.Join(srcDir, "ro"), 0555))
+		t.Cleanup(func() {
+			// or the temp dirs can't be removed
+			_ = os.Chmod(filepath.Join(srcDir, "ro"), 0755)
+			_ = os.Chmod(filepath.Join(sinkDir, "ro"), 0755)
+		})
+
+		_, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{Streaming: streaming})
+		require.NoError(t, err, "streaming=%v", streaming)
+		for _, name := range dirs {
+			want, err := os.Stat(filepath.Join(srcDir, name))
+			require.NoError(t, err)
+			got, err := os.Stat(filepath.Join(sinkDir, name))
+			require.NoError(t, err)
+			require.Equal(t, want.Mode(), got.Mode(), "%s, streaming=%v", name, streaming)
+			require.Equal(t, want.ModTime(), got.ModTime(), "%s, streaming=%v", name, streaming)
+		}
+	}
+}
+
+// remoteSource opens files as `RemoteFile`s and records when they're used
+// as such.
+type remoteSource struct {
+	*LocalSource
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (src *remoteSource) Open(name string) (fs.File, error) {
+	f, err := src.LocalSource.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteFile{File: f, name: name, src: src}, nil
+}
+
+type remoteFile struct {
+	fs.File
+	name string
+	src  *remoteSource
+}
+
+func (rf *remoteFile) MatchesFileSum(ctx context.Context, sum *typesv1.FileSum) (bool, error) {
+	return FileMatchesFileSum(ctx, sum, rf.File, sum.Info.Size)
+}
+
+func (rf *remoteFile) PatchInto(ctx context.Context, orig io.ReadSeeker, sum *typesv1.FileSum, w io.Writer) error {
+	rf.src.mu.Lock()
+	rf.src.calls = append(rf.src.calls, "patch "+rf.name)
+	rf.src.mu.Unlock()
+	return PatchInto(ctx, rf.File, orig, sum, w)
+}
+
+func TestLocalSinkRejectsPathsOutOfDir(t *testing.T) {
+	ctx := context.Background()
+	parent := t.TempDir()
+	sinkDir := filepath.Join(parent, "sink")
+	require.NoError(t, os.Mkdir(sinkDir, 0755))
+	outside := filepath.Join(parent, "outside")
+	require.NoError(t, os.WriteFile(outside, []byte("outside"), 0644))
+	sink := NewLocalSink(sinkDir)
+
+	for _, path := range []*typesv1.Path{
+		{Elements: []string{"..", "outside"}},
+		{Elements: []string{"a/../../outside"}},
+		{Elements: []string{"a", ""}},
+	} {
+		err := sink.DeleteFile(ctx, DeleteOp{Path: path, FileInfo: &typesv1.FileInfo{Name: "outside"}})
+		require.ErrorIs(t, err, fs.ErrInvalid, path.Elements)
+		err = sink.CreateFile(ctx, typesv1.DirOf(path), &typesv1.FileInfo{Name: path.Elements[len(path.Elements)-1]}, nil)
+		require.ErrorIs(t, err, fs.ErrInvalid, path.Elements)
+	}
+	err := sink.CreateFile(ctx, &typesv1.Path{}, &typesv1.FileInfo{Name: "link", HardLink: typesv1.PathFromString("../outside")}, nil)
+	require.Error(t, err)
+
+	content, err := os.ReadFile(outside)
+	require.NoError(t, err)
+	require.Equal(t, "outside", string(content))
+	entries, err := os.ReadDir(sinkDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
@@ -0,0 +1,202 @@
+//go:build linux || darwin
+
+package dirsync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+
+	"golang.org/x/sys/unix"
+)
+
+// lchtimes sets the mod time of a symlink, rather than of its target.
+func lchtimes(filename string, modTime time.Time) error {
+	ts := unix.NsecToTimespec(modTime.UnixNano())
+	return unix.UtimesNanoAt(unix.AT_FDCWD, filename, []unix.Timespec{ts, ts}, unix.AT_SYMLINK_NOFOLLOW)
+}
+
+// fileInode is the inode of a file, 0 if it isn't known.
+func fileInode(fi fs.FileInfo) uint64 {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Ino)
+	}
+	return 0
+}
+
+// fileLinkID is the file that `fi` is a hard link to, it's false if it's the
+// only link to it.
+func fileLinkID(fi fs.FileInfo) (linkID, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok || st.Nlink < 2 {
+		return linkID{}, false
+	}
+	return linkID{dev: uint64(st.Dev), ino: uint64(st.Ino)}, true
+}
+
+// fileHoles finds the holes of a file, with `SEEK_HOLE` and `SEEK_DATA`.
+// Files that take as much space as their size have none, they're not looked
+// into.
+func fileHoles(f *os.File, fi fs.FileInfo) ([]fileHole, error) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok || st.Blocks*512 >= st.Size {
+		return nil, nil
+	}
+	fd, size := int(f.Fd()), fi.Size()
+	var holes []fileHole
+	for off := int64(0); off < size; {
+		start, err := unix.Seek(fd, off, unix.SEEK_HOLE)
+		if errors.Is(err, unix.EINVAL) || errors.Is(err, unix.ENXIO) {
+			// not supported, or the file shrank
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start >= size {
+			break
+		}
+		end, err := unix.Seek(fd, start, unix.SEEK_DATA)
+		if errors.Is(err, unix.ENXIO) {
+			// the file ends with the hole
+			end = size
+		} else if err != nil {
+			return nil, err
+		}
+		holes = append(holes, fileHole{offset: start, size: end - start})
+		off = end
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return holes, nil
+}
+
+// keptAttrs are the ones of `attrs` that can be applied to local files,
+// owners only can by root.
+func keptAttrs(attrs Attrs) Attrs {
+	if os.Geteuid() != 0 {
+		attrs &^= AttrOwner
+	}
+	return attrs
+}
+
+// readLocalAttrs reads the attributes of a local file, `fi` is what it was
+// stated as.
+func readLocalAttrs(filename string, fi fs.FileInfo, attrs Attrs) (*typesv1.FileAttrs, error) {
+	fa := new(typesv1.FileAttrs)
+	if attrs&AttrOwner != 0 {
+		if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+			fa.Owner = lookupOwner(st.Uid, st.Gid)
+		}
+	}
+	if attrs&AttrXattrs != 0 {
+		xattrs, err := readXattrs(filename)
+		if err != nil {
+			return nil, fmt.Errorf("reading xattrs of %q: %w", filename, err)
+		}
+		fa.Xattrs = xattrs
+	}
+	return nonEmptyAttrs(fa), nil
+}
+
+// writeLocalAttrs applies the ones of `attrs` to a local file: the xattrs it
+// has that `fa` doesn't are removed.
+func writeLocalAttrs(filename string, fa *typesv1.FileAttrs, attrs Attrs) error {
+	if attrs&AttrOwner != 0 && fa.GetOwner() != nil {
+		if err := os.Chown(filename, int(fa.Owner.Uid), int(fa.Owner.Gid)); err != nil {
+			return fmt.Errorf("setting owner of %q: %w", filename, err)
+		}
+	}
+	if attrs&AttrXattrs == 0 {
+		return nil
+	}
+	current, err := readXattrs(filename)
+	if err != nil {
+		return fmt.Errorf("reading xattrs of %q: %w", filename, err)
+	}
+	// only what differs is written, some xattrs take privileges to write
+	// even with the value they have
+	values := make(map[string][]byte, len(current))
+	for _, xattr := range current {
+		values[xattr.Name] = xattr.Value
+	}
+	for _, xattr := range fa.GetXattrs() {
+		value, ok := values[xattr.Name]
+		delete(values, xattr.Name)
+		if ok && bytes.Equal(value, xattr.Value) {
+			continue
+		}
+		if err := unix.Setxattr(filename, xattr.Name, xattr.Value, 0); err != nil {
+			return fmt.Errorf("setting xattr %q of %q: %w", xattr.Name, filename, err)
+		}
+	}
+	for name := range values {
+		if err := unix.Removexattr(filename, name); err != nil {
+			return fmt.Errorf("removing xattr %q of %q: %w", name, filename, err)
+		}
+	}
+	return nil
+}
+
+func readXattrs(filename string) ([]*typesv1.Xattr, error) {
+	names, err := readXattr(func(dest []byte) (int, error) {
+		return unix.Listxattr(filename, dest)
+	})
+	if errors.Is(err, unix.ENOTSUP) {
+		// the filesystem has none
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var xattrs []*typesv1.Xattr
+	for _, name := range strings.Split(string(names), "\x00") {
+		if name == "" {
+			continue
+		}
+		value, err := readXattr(func(dest []byte) (int, error) {
+			return unix.Getxattr(filename, name, dest)
+		})
+		if errors.Is(err, unix.ENODATA) {
+			// removed since it was listed
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %q: %w", name, err)
+		}
+		xattrs = append(xattrs, &typesv1.Xattr{Name: name, Value: value})
+	}
+	return xattrs, nil
+}
+
+// readXattr calls `read` with a buffer large enough for what it reads, which
+// can grow between calls.
+func readXattr(read func(dest []byte) (int, error)) ([]byte, error) {
+	for {
+		size, err := read(nil)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size)
+		n, err := read(buf)
+		if errors.Is(err, unix.ERANGE) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	}
+}
@@ -0,0 +1,319 @@
+package dirsync
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+)
+
+// MoveOp moves a file or dir of the sink to where the source has it under
+// another path, instead of deleting it and creating it anew.
+type MoveOp struct {
+	// From is where the file or dir is on the sink, FromInfo its info there
+	From     *typesv1.Path
+	FromInfo *typesv1.FileInfo
+	// ParentDir is where it goes, Info is its info on the source
+	ParentDir *typesv1.Path
+	Info      *typesv1.FileInfo
+}
+
+// MoveSink is a `Sink` that can move what it has, so that the files and dirs
+// that are renamed or moved on the source aren't uploaded again.
+type MoveSink interface {
+	Sink
+	// MoveFile moves the file or dir and gives it `op.Info`. The entries
+	// of a dir are moved along with it, as they are.
+	MoveFile(ctx context.Context, op MoveOp) error
+}
+
+func moveOpPath(op MoveOp) *typesv1.Path {
+	return typesv1.PathJoin(op.ParentDir, op.Info.Name)
+}
+
+// scheduleMoves returns an emitter that submits the moves to `sched`, to be
+// applied to `sink`.
+func scheduleMoves(sched *scheduler, sink MoveSink) func(MoveOp) error {
+	return func(op MoveOp) error {
+		ev := Event{Op: "move", Path: op.From, IsDir: op.Info.IsDir}
+		observe(sched.ctx, plannedEvent(ev))
+		return sched.Submit(func(ctx context.Context) error {
+			return runOp(ctx, ev, func(ctx context.Context) error {
+				return sink.MoveFile(ctx, op)
+			})
+		}, op.From, moveOpPath(op))
+	}
+}
+
+// moves are the entries of the sink that the diff moves rather than delete,
+// found by `findMoves`.
+type moves struct {
+	emit func(MoveOp) error
+	// by the path they're moved to
+	to map[string]moveFrom
+	// the paths of the sink that are moved away
+	from map[string]bool
+	// the dirs of the sink with something moved out of them, deleting them
+	// waits for the moves
+	emptied  map[string]bool
+	deferred []DeleteOp
+}
+
+type moveFrom struct {
+	path *typesv1.Path
+	info *typesv1.FileInfo
+}
+
+// findMoves matches what's gone from the sink with what's new on the source:
+// dirs by their digest, which covers the metadata of everything under them,
+// and files by their size and content. Only entries whose name is free on
+// the other side are matched, so that nothing is ever created where a move
+// comes from or deleted where it goes.
+func findMoves(ctx context.Context, src Source, srcDir *SourceDir, sinkDir *typesv1.DirSum, params Params, emit func(MoveOp) error) (*moves, error) {
+	mv := &moves{
+		emit:    emit,
+		to:      make(map[string]moveFrom),
+		from:    make(map[string]bool),
+		emptied: make(map[string]bool),
+	}
+	var c moveCandidates
+	c.collect(params, &typesv1.Path{}, srcDir, sinkDir)
+	if len(c.goneDirs)+len(c.goneFiles) == 0 || len(c.newDirs)+len(c.newFiles) == 0 {
+		return mv, nil
+	}
+
+	goneDirs := make(map[string][]goneDir)
+	for _, gone := range c.goneDirs {
+		goneDirs[string(gone.dir.Digest)] = append(goneDirs[string(gone.dir.Digest)], gone)
+	}
+	for _, dir := range c.newDirs {
+		if mv.movedTo(dir.path) {
+			continue
+		}
+		for _, gone := range goneDirs[string(dir.dir.Digest)] {
+			if !mv.movedFrom(gone.path) {
+				mv.add(gone.path, gone.dir.Info, dir.path)
+				break
+			}
+		}
+	}
+
+	goneFiles := make(map[uint64][]goneFile)
+	for _, gone := range c.goneFiles {
+		goneFiles[gone.file.Info.Size] = append(goneFiles[gone.file.Info.Size], gone)
+	}
+	for _, file := range c.newFiles {
+		if mv.movedTo(file.path) {
+			continue
+		}
+		for _, gone := range goneFiles[file.file.Info.Size] {
+			if mv.movedFrom(gone.path) {
+				continue
+			}
+			same, err := fileHasSum(ctx, src, file.path, gone.file)
+			if err != nil {
+				return nil, fmt.Errorf("comparing %q with %q: %w", typesv1.StringFromPath(file.path), typesv1.StringFromPath(gone.path), err)
+			}
+			if same {
+				mv.add(gone.path, gone.file.Info, file.path)
+				break
+			}
+		}
+	}
+	return mv, nil
+}
+
+func (mv *moves) add(from *typesv1.Path, info *typesv1.FileInfo, to *typesv1.Path) {
+	mv.to[pathKey(to)] = moveFrom{path: from, info: info}
+	mv.from[pathKey(from)] = true
+	for n := len(from.Elements) - 1; n > 0; n-- {
+		mv.emptied[strings.Join(from.Elements[:n], "/")] = true
+	}
+}
+
+// movedTo tells if the path, or one of its parents, is where something is
+// moved.
+func (mv *moves) movedTo(path *typesv1.Path) bool {
+	for n := len(path.Elements); n > 0; n-- {
+		if _, ok := mv.to[strings.Join(path.Elements[:n], "/")]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// movedFrom tells if the path, or one of its parents, is moved away.
+func (mv *moves) movedFrom(path *typesv1.Path) bool {
+	for n := len(path.Elements); n > 0; n-- {
+		if mv.from[strings.Join(path.Elements[:n], "/")] {
+			return true
+		}
+	}
+	return false
+}
+
+// emitMoveTo emits the move of what goes to `info` in `dir`, if anything.
+func (mv *moves) emitMoveTo(dir *typesv1.Path, info *typesv1.FileInfo) (bool, error) {
+	if mv == nil {
+		return false, nil
+	}
+	from, ok := mv.to[pathKey(typesv1.PathJoin(dir, info.Name))]
+	if !ok {
+		return false, nil
+	}
+	op := MoveOp{From: from.path, FromInfo: from.info, ParentDir: dir, Info: info}
+	if err := mv.emit(op); err != nil {
+		return true, fmt.Errorf("emitting move: %w", err)
+	}
+	return true, nil
+}
+
+// delete tells if the diff is to delete what's at `path` on the sink, and
+// if so, if it's to do it right away.
+func (mv *moves) delete(op DeleteOp) (now bool) {
+	if mv == nil {
+		return true
+	}
+	key := pathKey(op.Path)
+	if mv.from[key] {
+		return false
+	}
+	if mv.emptied[key] {
+		mv.deferred = append(mv.deferred, op)
+		return false
+	}
+	return true
+}
+
+// emitDeferred emits the deletes that waited for moves, once every move is
+// emitted.
+func (mv *moves) emitDeferred(emitDelete func(DeleteOp) error) error {
+	if mv == nil {
+		return nil
+	}
+	for _, op := range mv.deferred {
+		if err := emitDelete(op); err != nil {
+			return fmt.Errorf("emitting delete dir: %w", err)
+		}
+	}
+	return nil
+}
+
+type goneDir struct {
+	path *typesv1.Path
+	dir  *typesv1.DirSum
+}
+
+type goneFile struct {
+	path *typesv1.Path
+	file *typesv1.FileSum
+}
+
+type newDir struct {
+	path *typesv1.Path
+	dir  *SourceDir
+}
+
+type newFile struct {
+	path *typesv1.Path
+	file *SourceFile
+}
+
+// moveCandidates are the entries only found on one side, parents first.
+// Symlinks, empty files and empty dirs are cheap to create, they're never
+// moved.
+type moveCandidates struct {
+	goneDirs  []goneDir
+	goneFiles []goneFile
+	newDirs   []newDir
+	newFiles  []newFile
+}
+
+// collect walks the dirs found at `path` on each side, either can be nil.
+func (c *moveCandidates) collect(params Params, path *typesv1.Path, src *SourceDir, sink *typesv1.DirSum) {
+	if sink != nil {
+		for _, sinkDir := range sink.Dirs {
+			dirPath := typesv1.PathJoin(path, sinkDir.Info.Name)
+			if src != nil {
+				if srcDir, found := srcHasDir(src, sinkDir.Info.Name); found {
+					if !sameDigest(srcDir.syncedDigest(), sinkDir.Digest) {
+						c.collect(params, dirPath, srcDir, sinkDir)
+					}
+					continue
+				}
+				if srcHasFileNamed(src, sinkDir.Info.Name) || keepOnSink(params, src, dirPath, sinkDir.Info) {
+					continue
+				}
+			}
+			if len(sinkDir.Dirs)+len(sinkDir.Files) > 0 && len(sinkDir.Digest) > 0 {
+				c.goneDirs = append(c.goneDirs, goneDir{path: dirPath, dir: sinkDir})
+			}
+			c.collect(params, dirPath, nil, sinkDir)
+		}
+		for _, sinkFile := range sink.Files {
+			filePath := typesv1.PathJoin(path, sinkFile.Info.Name)
+			// hard links are made anew from the file they link to
+			if sinkFile.Info.IsSymlink() || sinkFile.Info.HardLink != nil || sinkFile.Info.Size == 0 {
+				continue
+			}
+			if src != nil && (srcHasFileNamed(src, sinkFile.Info.Name) || srcHasDirNamed(src, sinkFile.Info.Name) || keepOnSink(params, src, filePath, sinkFile.Info)) {
+				continue
+			}
+			c.goneFiles = append(c.goneFiles, goneFile{path: filePath, file: sinkFile})
+		}
+	}
+	if src != nil {
+		for _, srcDir := range src.Dirs {
+			if sink != nil && sinkHasEntryNamed(sink, srcDir.Info.Name) {
+				// walked along with the sink, or not free
+				continue
+			}
+			dirPath := typesv1.PathJoin(path, srcDir.Info.Name)
+			if len(srcDir.Dirs)+len(srcDir.Files) > 0 {
+				c.newDirs = append(c.newDirs, newDir{path: dirPath, dir: srcDir})
+			}
+			c.collect(params, dirPath, srcDir, nil)
+		}
+		for _, srcFile := range src.Files {
+			if srcFile.Info.IsSymlink() || srcFile.Info.HardLink != nil || srcFile.Info.Size == 0 {
+				continue
+			}
+			if sink != nil && sinkHasEntryNamed(sink, srcFile.Info.Name) {
+				continue
+			}
+			c.newFiles = append(c.newFiles, newFile{path: typesv1.PathJoin(path, srcFile.Info.Name), file: srcFile})
+		}
+	}
+}
+
+func srcHasDir(src *SourceDir, name string) (*SourceDir, bool) {
+	i, found := slices.BinarySearchFunc(src.Dirs, name, func(dir *SourceDir, name string) int {
+		return strings.Compare(dir.Info.Name, name)
+	})
+	if !found {
+		return nil, false
+	}
+	return src.Dirs[i], true
+}
+
+func sinkHasEntryNamed(sink *typesv1.DirSum, name string) bool {
+	_, isDir := sinkHasDirNamed(sink, name)
+	_, isFile := sinkHasFileNamed(sink, name)
+	return isDir || isFile
+}
+
+// fileHasSum tells if the source file at `path` has the content summed in
+// `sum`.
+func fileHasSum(ctx context.Context, src Source, path *typesv1.Path, sum *typesv1.FileSum) (bool, error) {
+	f, err := src.Open(typesv1.StringFromPath(path))
+	if err != nil {
+		return false, fmt.Errorf("opening source file: %w", err)
+	}
+	defer f.Close()
+	if rf, ok := f.(RemoteFile); ok {
+		return rf.MatchesFileSum(ctx, sum)
+	}
+	return fileMatchesFileSum(ctx, sum, countReads(ctx, f), sum.BlockSize)
+}
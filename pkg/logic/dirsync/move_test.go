@@ -0,0 +1,163 @@
+package dirsync
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncMoves(t *testing.T) {
+	tests := []struct {
+		name      string
+		before    map[string]string
+		change    func(t *testing.T, srcDir string)
+		wantCalls []string
+	}{
+		{
+			name: "renamed dir",
+			before: map[string]string{
+				"a/one":     "one",
+				"a/sub/two": "two",
+				"other":     "other",
+			},
+			change: func(t *testing.T, srcDir string) {
+				fi, err := os.Stat(filepath.Join(srcDir, "a"))
+				require.NoError(t, err)
+				require.NoError(t, os.Rename(filepath.Join(srcDir, "a"), filepath.Join(srcDir, "b")))
+				// renaming can touch the dir, depending on the filesystem
+				require.NoError(t, os.Chtimes(filepath.Join(srcDir, "b"), fi.ModTime(), fi.ModTime()))
+			},
+			wantCalls: []string{"move a b"},
+		},
+		{
+			name: "file moved into a new dir",
+			before: map[string]string{
+				"report.txt": "quarterly numbers",
+			},
+			change: func(t *testing.T, srcDir string) {
+				require.NoError(t, os.Mkdir(filepath.Join(srcDir, "archive"), 0755))
+				require.NoError(t, os.Rename(filepath.Join(srcDir, "report.txt"), filepath.Join(srcDir, "archive", "2024.txt")))
+			},
+			wantCalls: []string{"create archive", "move report.txt archive/2024.txt", "patch archive"},
+		},
+		{
+			name: "dir emptied by a move",
+			before: map[string]string{
+				"old/file": "content",
+			},
+			change: func(t *testing.T, srcDir string) {
+				require.NoError(t, os.Rename(filepath.Join(srcDir, "old", "file"), filepath.Join(srcDir, "file")))
+				require.NoError(t, os.Remove(filepath.Join(srcDir, "old")))
+			},
+			wantCalls: []string{"move old/file file", "delete old"},
+		},
+		{
+			name: "new content isn't a move",
+			before: map[string]string{
+				"gone": "before",
+			},
+			change: func(t *testing.T, srcDir string) {
+				require.NoError(t, os.Remove(filepath.Join(srcDir, "gone")))
+				require.NoError(t, os.WriteFile(filepath.Join(srcDir, "new"), []byte("after!"), 0644))
+			},
+			wantCalls: []string{"create new", "delete gone"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			srcDir, sinkDir := t.TempDir(), t.TempDir()
+			for name, content := range tt.before {
+				filename := filepath.Join(srcDir, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+				require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+			}
+			src := NewLocalSource(srcDir)
+			sink := &moveRecordingSink{LocalSink: NewLocalSink(sinkDir)}
+			_, err := Sync(ctx, ".", src, sink, Params{})
+			require.NoError(t, err)
+
+			tt.change(t, srcDir)
+			sink.calls = nil
+			_, err = Sync(ctx, ".", src, sink, Params{})
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.wantCalls, sink.calls)
+
+			// the sink ends up like the source
+			want, err := TraceSource(ctx, ".", src, Params{})
+			require.NoError(t, err)
+			got, err := TraceSource(ctx, ".", NewLocalSource(sinkDir), Params{})
+			require.NoError(t, err)
+			require.Equal(t, want.Digest, got.Digest)
+		})
+	}
+}
+
+func TestPlanMoves(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "a"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a", "file"), []byte("content"), 0644))
+	src := NewLocalSource(srcDir)
+	sink := NewLocalSink(sinkDir)
+	_, err := Sync(ctx, ".", src, sink, Params{})
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(filepath.Join(srcDir, "a"), filepath.Join(srcDir, "b")))
+
+	plan, err := Plan(ctx, ".", src, sink, Params{})
+	require.NoError(t, err)
+	require.Len(t, plan.Ops, 1)
+	move := plan.Ops[0].GetMove()
+	require.NotNil(t, move)
+	require.Equal(t, "a", typesv1.StringFromPath(move.From))
+	require.Equal(t, "b", typesv1.StringFromPath(plan.Ops[0].Path))
+	require.Zero(t, plan.EstimatedBytes)
+
+	require.NoError(t, ExecutePlan(ctx, src, sink, plan, Params{}))
+	content, err := os.ReadFile(filepath.Join(sinkDir, "b", "file"))
+	require.NoError(t, err)
+	require.Equal(t, "content", string(content))
+
+	// once applied, the plan is stale
+	require.Error(t, ExecutePlan(ctx, src, sink, plan, Params{}))
+}
+
+// moveRecordingSink records the changes made to a local sink.
+type moveRecordingSink struct {
+	*LocalSink
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (sk *moveRecordingSink) record(call string) {
+	sk.mu.Lock()
+	defer sk.mu.Unlock()
+	sk.calls = append(sk.calls, call)
+}
+
+func (sk *moveRecordingSink) CreateFile(ctx context.Context, path *typesv1.Path, fi *typesv1.FileInfo, r io.Reader) error {
+	sk.record("create " + typesv1.StringFromPath(typesv1.PathJoin(path, fi.Name)))
+	return sk.LocalSink.CreateFile(ctx, path, fi, r)
+}
+
+func (sk *moveRecordingSink) PatchFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, sum *typesv1.FileSum, r io.Reader) error {
+	sk.record("patch " + typesv1.StringFromPath(typesv1.PathJoin(dir, fi.Name)))
+	return sk.LocalSink.PatchFile(ctx, dir, fi, sum, r)
+}
+
+func (sk *moveRecordingSink) DeleteFile(ctx context.Context, op DeleteOp) error {
+	sk.record("delete " + typesv1.StringFromPath(op.Path))
+	return sk.LocalSink.DeleteFile(ctx, op)
+}
+
+func (sk *moveRecordingSink) MoveFile(ctx context.Context, op MoveOp) error {
+	sk.record("move " + typesv1.StringFromPath(op.From) + " " + typesv1.StringFromPath(moveOpPath(op)))
+	return sk.LocalSink.MoveFile(ctx, op)
+}
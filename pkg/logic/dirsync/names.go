@@ -0,0 +1,308 @@
+package dirsync
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NameCollision are entries of a dir whose names only differ by case or by
+// unicode normalization, like `README.md` and `Readme.md`, or `é` composed
+// (NFC) and decomposed (NFD). They can't all be on a case-insensitive or
+// normalizing filesystem, like the ones of macOS and Windows.
+type NameCollision struct {
+	Dir   *typesv1.Path
+	Names []string
+}
+
+// NormalizeName is `name` in NFC form, the one most tools expect.
+func NormalizeName(name string) string {
+	return norm.NFC.String(name)
+}
+
+// NameKey is the same for names that collide, and only for them.
+func NameKey(name string) string {
+	// folding case can leave a name that isn't normalized
+	return NormalizeName(cases.Fold().String(NormalizeName(name)))
+}
+
+// findNameCollisions finds the names of entries that collide among `names`.
+func findNameCollisions(dir *typesv1.Path, names []string) []NameCollision {
+	groups := make(map[string][]string, len(names))
+	for _, name := range names {
+		key := NameKey(name)
+		groups[key] = append(groups[key], name)
+	}
+	var collisions []NameCollision
+	for _, group := range groups {
+		if len(group) > 1 {
+			slices.Sort(group)
+			collisions = append(collisions, NameCollision{Dir: dir, Names: group})
+		}
+	}
+	slices.SortFunc(collisions, func(a, b NameCollision) int {
+		return slices.Compare(a.Names, b.Names)
+	})
+	return collisions
+}
+
+// dirNameCollisions finds the entries of `dir` whose names collide.
+func dirNameCollisions(path *typesv1.Path, dir *SourceDir) []NameCollision {
+	names := make([]string, 0, len(dir.Files)+len(dir.Dirs))
+	for _, file := range dir.Files {
+		names = append(names, file.Info.Name)
+	}
+	for _, child := range dir.Dirs {
+		names = append(names, child.Info.Name)
+	}
+	return findNameCollisions(path, names)
+}
+
+// NormalizingSink is a `Sink` that may store names in NFC form, see
+// `NormalizeName`. The names of the source are then normalized too, so that
+// an entry has the same name on both sides of the diff.
+type NormalizingSink interface {
+	Sink
+	NormalizesNames(ctx context.Context) (bool, error)
+}
+
+// withSinkNames is `src` with names in NFC form if `sink` stores them so.
+func withSinkNames(ctx context.Context, src Source, sink Sink) (Source, error) {
+	ns, ok := sink.(NormalizingSink)
+	if !ok {
+		return src, nil
+	}
+	normalizes, err := ns.NormalizesNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting name policy of sink: %w", err)
+	}
+	if !normalizes {
+		return src, nil
+	}
+	return &normalizedSource{src: src, rec: statsFrom(ctx), real: make(map[string]string), listed: make(map[string]bool)}, nil
+}
+
+var (
+	_ SymlinkSource = (*normalizedSource)(nil)
+	_ AttrSource    = (*normalizedSource)(nil)
+)
+
+// normalizedSource is a `Source` whose names are in NFC form. They're mapped
+// back to the names on `src` to read the entries.
+type normalizedSource struct {
+	src Source
+	// collisions are reported to it, if set
+	rec *statsRecorder
+
+	mu sync.Mutex
+	// the name on `src` of the entries whose name isn't in NFC form there,
+	// by normalized path
+	real map[string]string
+	// the dirs whose entries are in `real`
+	listed map[string]bool
+}
+
+func (ns *normalizedSource) Open(name string) (fs.File, error) {
+	real, err := ns.realPath("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return ns.src.Open(real)
+}
+
+func (ns *normalizedSource) Stat(name string) (fs.FileInfo, error) {
+	real, err := ns.realPath("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := ns.src.Stat(real)
+	if err != nil {
+		return nil, err
+	}
+	return normalizedInfo{FileInfo: fi}, nil
+}
+
+func (ns *normalizedSource) Lstat(name string) (fs.FileInfo, error) {
+	lsrc, ok := ns.src.(SymlinkSource)
+	if !ok {
+		return nil, fmt.Errorf("source can't read symlinks, they must be skipped")
+	}
+	real, err := ns.realPath("lstat", name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := lsrc.Lstat(real)
+	if err != nil {
+		return nil, err
+	}
+	return normalizedInfo{FileInfo: fi}, nil
+}
+
+func (ns *normalizedSource) ReadLink(name string) (string, error) {
+	lsrc, ok := ns.src.(SymlinkSource)
+	if !ok {
+		return "", fmt.Errorf("source can't read symlinks, they must be skipped")
+	}
+	real, err := ns.realPath("readlink", name)
+	if err != nil {
+		return "", err
+	}
+	return lsrc.ReadLink(real)
+}
+
+func (ns *normalizedSource) ReadAttrs(name string, attrs Attrs) (*typesv1.FileAttrs, error) {
+	if as, ok := ns.src.(AttrSource); ok {
+		real, err := ns.realPath("readattrs", name)
+		if err != nil {
+			return nil, err
+		}
+		return as.ReadAttrs(real, attrs)
+	}
+	// like `readAttrs` does with sources that don't read them
+	fi, err := ns.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if sys, ok := fi.Sys().(*typesv1.FileInfo); ok {
+		return filterAttrs(sys.Attrs, attrs), nil
+	}
+	return nil, nil
+}
+
+// ReadDir lists the entries of the dir `name` with their names in NFC form.
+// Of the entries whose names collide once normalized, only the one whose
+// name is in NFC form already, or else the first, is kept: the sink can't
+// have them all.
+func (ns *normalizedSource) ReadDir(name string) ([]fs.DirEntry, error) {
+	real, err := ns.realPath("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ns.src.ReadDir(real)
+	if err != nil {
+		return nil, err
+	}
+	kept := make([]fs.DirEntry, 0, len(entries))
+	byName := make(map[string]int, len(entries))
+	var collisions []string
+	for _, entry := range entries {
+		normalized := NormalizeName(entry.Name())
+		if i, dup := byName[normalized]; dup {
+			if !slices.Contains(collisions, normalized) {
+				collisions = append(collisions, normalized)
+			}
+			if entry.Name() == normalized {
+				kept[i] = entry
+			}
+			continue
+		}
+		byName[normalized] = len(kept)
+		kept = append(kept, entry)
+	}
+
+	ns.mu.Lock()
+	firstListing := !ns.listed[name]
+	ns.listed[name] = true
+	for i, entry := range kept {
+		normalized := NormalizeName(entry.Name())
+		if normalized == entry.Name() {
+			continue
+		}
+		ns.real[path.Join(name, normalized)] = entry.Name()
+		kept[i] = normalizedEntry{DirEntry: entry}
+	}
+	ns.mu.Unlock()
+	slices.SortFunc(kept, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	if firstListing && len(collisions) != 0 && ns.rec != nil {
+		var found []NameCollision
+		for _, normalized := range collisions {
+			var names []string
+			for _, entry := range entries {
+				if NormalizeName(entry.Name()) == normalized {
+					names = append(names, entry.Name())
+				}
+			}
+			slices.Sort(names)
+			found = append(found, NameCollision{Dir: tracedDir{rel: name}.path(), Names: names})
+		}
+		ns.rec.nameCollisions(found)
+	}
+	return kept, nil
+}
+
+// realPath is the path on `src` of the entry at `name`, with its name in NFC
+// form. The dirs on the way are listed if they weren't yet.
+func (ns *normalizedSource) realPath(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return name, nil
+	}
+	dir, base := path.Split(name)
+	dir = path.Clean(dir)
+	realDir, err := ns.realPath(op, dir)
+	if err != nil {
+		return "", err
+	}
+	ns.mu.Lock()
+	listed := ns.listed[dir]
+	ns.mu.Unlock()
+	if !listed {
+		if _, err := ns.ReadDir(dir); err != nil {
+			return "", err
+		}
+	}
+	ns.mu.Lock()
+	if real, ok := ns.real[name]; ok {
+		base = real
+	}
+	ns.mu.Unlock()
+	return path.Join(realDir, base), nil
+}
+
+// normalizedEntry is a dir entry with its name in NFC form.
+type normalizedEntry struct {
+	fs.DirEntry
+}
+
+func (entry normalizedEntry) Name() string { return NormalizeName(entry.DirEntry.Name()) }
+
+func (entry normalizedEntry) Info() (fs.FileInfo, error) {
+	fi, err := entry.DirEntry.Info()
+	if err != nil {
+		return nil, err
+	}
+	return normalizedInfo{FileInfo: fi}, nil
+}
+
+// normalizedInfo is the info of a file with its name in NFC form.
+type normalizedInfo struct {
+	fs.FileInfo
+}
+
+func (fi normalizedInfo) Name() string { return NormalizeName(fi.FileInfo.Name()) }
+
+// normalizedPaths are `paths` with their names in NFC form.
+func normalizedPaths(paths []*typesv1.Path) []*typesv1.Path {
+	normalized := make([]*typesv1.Path, 0, len(paths))
+	for _, p := range paths {
+		elems := make([]string, 0, len(p.GetElements()))
+		for _, elem := range p.GetElements() {
+			elems = append(elems, NormalizeName(elem))
+		}
+		normalized = append(normalized, &typesv1.Path{Elements: elems})
+	}
+	return normalized
+}
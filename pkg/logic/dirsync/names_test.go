@@ -0,0 +1,147 @@
+package dirsync
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// the same name, composed and decomposed
+const (
+	nfc = "caf\u00e9"
+	nfd = "cafe\u0301"
+)
+
+func TestFindNameCollisions(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  [][]string
+	}{
+		{name: "none", names: []string{"a", "b", "c"}},
+		{name: "case", names: []string{"README.md", "Readme.md", "other"}, want: [][]string{{"README.md", "Readme.md"}}},
+		{name: "normalization", names: []string{nfc, nfd}, want: [][]string{{nfd, nfc}}},
+		{name: "case and normalization", names: []string{"CAFÉ", nfc, "x"}, want: [][]string{{"CAFÉ", nfc}}},
+		{name: "folding", names: []string{"STRASSE", "straße"}, want: [][]string{{"STRASSE", "straße"}}},
+		{name: "many", names: []string{"B", "a", "A", "b"}, want: [][]string{{"A", "a"}, {"B", "b"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]string
+			for _, collision := range findNameCollisions(&typesv1.Path{}, tt.names) {
+				got = append(got, collision.Names)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSyncNameCollisions(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	for _, name := range []string{"README.md", "Readme.md", "docs/" + nfc, "docs/" + nfd, "docs/other"} {
+		path := filepath.Join(srcDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Skipf("filesystem doesn't keep names apart: %v", err)
+		}
+	}
+	entries, err := os.ReadDir(srcDir)
+	require.NoError(t, err)
+	if len(entries) != 3 {
+		t.Skip("filesystem doesn't keep names apart")
+	}
+
+	stats, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+	require.Equal(t, []NameCollision{
+		{Dir: &typesv1.Path{}, Names: []string{"README.md", "Readme.md"}},
+		{Dir: typesv1.PathFromString("docs"), Names: []string{nfd, nfc}},
+	}, stats.NameCollisions)
+	// they're synced nonetheless
+	require.Equal(t, tracedFiles(t, NewLocalSource(srcDir), Params{}), tracedFiles(t, NewLocalSource(sinkDir), Params{}))
+}
+
+func TestSyncNormalizedNames(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		ctx := context.Background()
+		srcDir, sinkDir := t.TempDir(), t.TempDir()
+		mkfile := func(name, content string) {
+			path := filepath.Join(srcDir, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		}
+		mkfile(nfd, "file")
+		mkfile(filepath.Join(nfd+"-dir", nfd), "nested")
+		entries, err := os.ReadDir(srcDir)
+		require.NoError(t, err)
+		if entries[0].Name() != nfd {
+			t.Skip("filesystem normalizes names")
+		}
+
+		src := NewLocalSource(srcDir)
+		sink := &normalizingSink{moveRecordingSink: &moveRecordingSink{LocalSink: NewLocalSink(sinkDir)}}
+		params := Params{Streaming: streaming}
+		_, err = Sync(ctx, ".", src, sink, params)
+		require.NoError(t, err, "streaming=%v", streaming)
+		_, err = os.Stat(filepath.Join(sinkDir, nfc+"-dir", nfc))
+		require.NoError(t, err, "streaming=%v", streaming)
+
+		// the names on the sink are the ones of the source, once normalized
+		sink.calls = nil
+		_, err = Sync(ctx, ".", src, sink, params)
+		require.NoError(t, err, "streaming=%v", streaming)
+		require.Empty(t, sink.calls, "streaming=%v", streaming)
+
+		mkfile(nfd, "changed")
+		_, err = Sync(ctx, ".", src, sink, params)
+		require.NoError(t, err, "streaming=%v", streaming)
+		require.Equal(t, []string{"patch " + nfc}, sink.calls, "streaming=%v", streaming)
+		content, err := os.ReadFile(filepath.Join(sinkDir, nfc))
+		require.NoError(t, err)
+		require.Equal(t, "changed", string(content))
+	}
+}
+
+func TestSyncNormalizedNamesCollision(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	for _, name := range []string{nfd, nfc} {
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, name), []byte(name), 0644))
+	}
+	entries, err := os.ReadDir(srcDir)
+	require.NoError(t, err)
+	if len(entries) != 2 {
+		t.Skip("filesystem doesn't keep names apart")
+	}
+
+	sink := &normalizingSink{moveRecordingSink: &moveRecordingSink{LocalSink: NewLocalSink(sinkDir)}}
+	stats, err := Sync(ctx, ".", NewLocalSource(srcDir), sink, Params{})
+	require.NoError(t, err)
+	require.Equal(t, []NameCollision{{Dir: &typesv1.Path{}, Names: []string{nfd, nfc}}}, stats.NameCollisions)
+	// the one already in NFC form is kept
+	require.Equal(t, []string{"create " + nfc}, sink.calls)
+	content, err := os.ReadFile(filepath.Join(sinkDir, nfc))
+	require.NoError(t, err)
+	require.Equal(t, nfc, string(content))
+}
+
+// normalizingSink stores names in NFC form, like the server does with the
+// projects that normalize names.
+type normalizingSink struct {
+	*moveRecordingSink
+}
+
+func (sk *normalizingSink) NormalizesNames(ctx context.Context) (bool, error) { return true, nil }
+
+func (sk *normalizingSink) CreateFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, r io.Reader) error {
+	fi = proto.Clone(fi).(*typesv1.FileInfo)
+	fi.Name = NormalizeName(fi.Name)
+	return sk.moveRecordingSink.CreateFile(ctx, dir, fi, r)
+}
@@ -0,0 +1,250 @@
+package dirsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+)
+
+// Observer is told about the progress of the ops of a sync, to show it as it
+// goes. It's called from the goroutines applying the ops, concurrently.
+type Observer interface {
+	Observe(ev Event)
+}
+
+// ObserverFunc is an `Observer` that's a func.
+type ObserverFunc func(ev Event)
+
+func (fn ObserverFunc) Observe(ev Event) { fn(ev) }
+
+// EventKind is what happened to an op.
+type EventKind int
+
+const (
+	// EventPlanned is sent when the diff finds an op to apply. With
+	// `Params.Streaming`, ops are planned while others are applied.
+	EventPlanned EventKind = iota
+	// EventStarted is sent when an op starts being applied.
+	EventStarted
+	// EventProgressed is sent as the content of a file is sent, with the
+	// bytes of the file that were sent since the last event. Sinks that are
+	// `ProgressSink`s tell what they sent, otherwise it's what's read of the
+	// file on the source.
+	EventProgressed
+	// EventDone is sent when an op is applied.
+	EventDone
+	// EventFailed is sent when an op fails, or is canceled by the failure of
+	// another.
+	EventFailed
+	// EventSkipped is sent when an op is given up on without failing the
+	// sync, like for a file that keeps changing while it's sent.
+	EventSkipped
+)
+
+func (kind EventKind) String() string {
+	switch kind {
+	case EventPlanned:
+		return "planned"
+	case EventStarted:
+		return "started"
+	case EventProgressed:
+		return "progressed"
+	case EventDone:
+		return "done"
+	case EventFailed:
+		return "failed"
+	case EventSkipped:
+		return "skipped"
+	default:
+		return fmt.Sprintf("EventKind(%d)", int(kind))
+	}
+}
+
+// Event is something that happened to an op.
+type Event struct {
+	Kind EventKind
+	// Op is one of "create", "patch", "delete" or "move"
+	Op    string
+	Path  *typesv1.Path
+	IsDir bool
+	// Size is the size of the file on the source, what's to be sent at most.
+	// Deletes and moves send nothing.
+	Size uint64
+	// Bytes is how much of the file was sent, with `EventProgressed`. What
+	// the sink already had of it, and didn't need to be sent, counts too.
+	Bytes uint64
+	// Err is why the op failed, with `EventFailed`, or was skipped, with
+	// `EventSkipped`.
+	Err error
+}
+
+// progressStep is how many bytes of a file are sent between two
+// `EventProgressed`.
+const progressStep = 256 << 10
+
+type observerKey struct{}
+
+type opKey struct{}
+
+func withObserver(ctx context.Context, obs Observer) context.Context {
+	if obs == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, observerKey{}, obs)
+}
+
+func observerFrom(ctx context.Context) Observer {
+	obs, _ := ctx.Value(observerKey{}).(Observer)
+	return obs
+}
+
+// observe sends `ev` to the observer of the sync, if there's one.
+func observe(ctx context.Context, ev Event) {
+	if obs := observerFrom(ctx); obs != nil {
+		obs.Observe(ev)
+	}
+}
+
+func plannedEvent(ev Event) Event {
+	ev.Kind = EventPlanned
+	return ev
+}
+
+// runOp applies an op, telling the observer and the stats of the sync about
+// it. The op is carried by the context it's applied with, for what it reads
+// of the source to count as its progress.
+func runOp(ctx context.Context, ev Event, apply func(ctx context.Context) error) error {
+	var op *opProgress
+	if obs := observerFrom(ctx); obs != nil {
+		op = &opProgress{obs: obs, ev: ev}
+		ctx = context.WithValue(ctx, opKey{}, op)
+		ev.Kind = EventStarted
+		obs.Observe(ev)
+	}
+	err := apply(ctx)
+	// files that keep changing or can't be read are skipped, the sync goes
+	// on
+	skipped := errors.Is(err, errUnstable) || errors.Is(err, errSkipped)
+	switch {
+	case errors.Is(err, errUnstable):
+		statsFrom(ctx).unstable(ev.Path)
+	case skipped:
+		// reported already, if it's to be
+	default:
+		statsFrom(ctx).opDone(ev.Op, ev.Path, ev.IsDir, err)
+	}
+	if err == nil {
+		journalFrom(ctx).record(ev)
+	}
+	if op != nil {
+		op.flush()
+		switch {
+		case skipped:
+			ev.Kind, ev.Err = EventSkipped, err
+		case err != nil:
+			ev.Kind, ev.Err = EventFailed, err
+		default:
+			ev.Kind = EventDone
+		}
+		op.obs.Observe(ev)
+	}
+	if skipped {
+		return nil
+	}
+	return err
+}
+
+// ProgressSink is a `Sink` that tells how much of a file it sent as it sends
+// it, with `Progressed`. The progress of its creates and patches is what it
+// sent, rather than what's read of the source, which it may read ahead of
+// sending or not send at all.
+type ProgressSink interface {
+	Sink
+	ReportsProgress() bool
+}
+
+// Progressed tells the observer of the sync that `n` more bytes of the file
+// of the op applied with `ctx` were sent, see `ProgressSink`.
+func Progressed(ctx context.Context, n int) {
+	op := opProgressFrom(ctx)
+	if op == nil || n <= 0 {
+		return
+	}
+	op.mu.Lock()
+	sent := op.sent
+	op.mu.Unlock()
+	if sent {
+		op.progress(n)
+	}
+}
+
+// sinkProgress makes the progress of the op applied with `ctx` be what
+// `sink` tells it sent, if it's a `ProgressSink`.
+func sinkProgress(ctx context.Context, sink Sink) {
+	op := opProgressFrom(ctx)
+	if op == nil {
+		return
+	}
+	if ps, ok := sink.(ProgressSink); ok && ps.ReportsProgress() {
+		op.mu.Lock()
+		op.sent = true
+		op.mu.Unlock()
+	}
+}
+
+// opProgress sends the progress of an op by steps.
+type opProgress struct {
+	obs Observer
+	ev  Event
+
+	mu      sync.Mutex
+	pending uint64
+	// the progress is told by the sink, see `ProgressSink`
+	sent bool
+}
+
+func opProgressFrom(ctx context.Context) *opProgress {
+	op, _ := ctx.Value(opKey{}).(*opProgress)
+	return op
+}
+
+// add adds what's read of the file on the source to the progress of the op,
+// unless the sink tells it.
+func (op *opProgress) add(n int) {
+	if op == nil || n <= 0 {
+		return
+	}
+	op.mu.Lock()
+	sent := op.sent
+	op.mu.Unlock()
+	if !sent {
+		op.progress(n)
+	}
+}
+
+func (op *opProgress) progress(n int) {
+	op.mu.Lock()
+	op.pending += uint64(n)
+	if op.pending < progressStep {
+		op.mu.Unlock()
+		return
+	}
+	op.mu.Unlock()
+	op.flush()
+}
+
+func (op *opProgress) flush() {
+	op.mu.Lock()
+	pending := op.pending
+	op.pending = 0
+	op.mu.Unlock()
+	if pending == 0 {
+		return
+	}
+	ev := op.ev
+	ev.Kind, ev.Bytes = EventProgressed, pending
+	op.obs.Observe(ev)
+}
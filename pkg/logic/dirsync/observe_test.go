@@ -0,0 +1,145 @@
+package dirsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncObserver(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		ctx := context.Background()
+		srcDir, sinkDir := t.TempDir(), t.TempDir()
+		mkfile := func(root, name string, content []byte) {
+			filename := filepath.Join(root, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+			require.NoError(t, os.WriteFile(filename, content, 0644))
+		}
+		large := make([]byte, 3*progressStep+10)
+		rand.New(rand.NewSource(42)).Read(large)
+		mkfile(srcDir, "dir/large", large)
+		mkfile(srcDir, "small", []byte("small"))
+		mkfile(sinkDir, "gone", []byte("gone"))
+
+		obs := &recordingObserver{}
+		params := Params{Observer: obs, Streaming: streaming}
+		_, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), params)
+		require.NoError(t, err)
+
+		want := map[string][]string{
+			"create dir":       {"planned", "started", "done"},
+			"patch dir":        {"planned", "started", "done"},
+			"create dir/large": {"planned", "started", "progressed", "progressed", "progressed", "progressed", "done"},
+			"create small":     {"planned", "started", "progressed", "done"},
+			"delete gone":      {"planned", "started", "done"},
+		}
+		require.Equal(t, want, obs.kinds, "streaming=%v", streaming)
+		require.Equal(t, uint64(len(large)), obs.progressed["create dir/large"])
+		require.Equal(t, uint64(len("small")), obs.progressed["create small"])
+
+		// patches progress as the file is read
+		edited := bytes.Clone(large)
+		copy(edited[progressStep:], "edited")
+		mkfile(srcDir, "dir/large", edited)
+		obs = &recordingObserver{}
+		params.Observer = obs
+		_, err = Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), params)
+		require.NoError(t, err)
+		require.Equal(t, uint64(len(edited)), obs.progressed["patch dir/large"])
+	}
+}
+
+func TestSyncObserverFailure(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "file"), []byte("file"), 0644))
+
+	failing := errors.New("sink is full")
+	obs := &recordingObserver{}
+	sink := &failingSink{Sink: NewLocalSink(sinkDir), err: failing}
+	_, err := Sync(ctx, ".", NewLocalSource(srcDir), sink, Params{Observer: obs})
+	require.ErrorIs(t, err, failing)
+	require.Equal(t, []string{"planned", "started", "failed"}, obs.kinds["create file"])
+	require.ErrorIs(t, obs.errs["create file"], failing)
+}
+
+func TestSyncObserverProgressSink(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	large := make([]byte, 3*progressStep+10)
+	rand.New(rand.NewSource(42)).Read(large)
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "large"), large, 0644))
+
+	// the progress is what the sink tells, not what's read of the source
+	obs := &recordingObserver{}
+	sink := &progressSink{LocalSink: NewLocalSink(sinkDir)}
+	_, err := Sync(ctx, ".", NewLocalSource(srcDir), sink, Params{Observer: obs})
+	require.NoError(t, err)
+	require.Equal(t, []string{"planned", "started", "progressed", "done"}, obs.kinds["create large"])
+	require.Equal(t, uint64(len(large)), obs.progressed["create large"])
+
+	edited := bytes.Clone(large)
+	copy(edited[progressStep:], "edited")
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "large"), edited, 0644))
+	obs = &recordingObserver{}
+	_, err = Sync(ctx, ".", NewLocalSource(srcDir), sink, Params{Observer: obs})
+	require.NoError(t, err)
+	require.Equal(t, []string{"planned", "started", "progressed", "done"}, obs.kinds["patch large"])
+	require.Equal(t, uint64(len(edited)), obs.progressed["patch large"])
+}
+
+// progressSink tells the whole file was sent once it's applied.
+type progressSink struct {
+	*LocalSink
+}
+
+func (sink *progressSink) ReportsProgress() bool { return true }
+
+func (sink *progressSink) CreateFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, r io.Reader) error {
+	if err := sink.LocalSink.CreateFile(ctx, dir, fi, r); err != nil {
+		return err
+	}
+	Progressed(ctx, int(fi.Size))
+	return nil
+}
+
+func (sink *progressSink) PatchFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, sum *typesv1.FileSum, r io.Reader) error {
+	if err := sink.LocalSink.PatchFile(ctx, dir, fi, sum, r); err != nil {
+		return err
+	}
+	Progressed(ctx, int(fi.Size))
+	return nil
+}
+
+// recordingObserver records the kinds of events of each op.
+type recordingObserver struct {
+	mu         sync.Mutex
+	kinds      map[string][]string
+	progressed map[string]uint64
+	errs       map[string]error
+}
+
+func (obs *recordingObserver) Observe(ev Event) {
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	if obs.kinds == nil {
+		obs.kinds = make(map[string][]string)
+		obs.progressed = make(map[string]uint64)
+		obs.errs = make(map[string]error)
+	}
+	op := ev.Op + " " + typesv1.StringFromPath(ev.Path)
+	obs.kinds[op] = append(obs.kinds[op], ev.Kind.String())
+	obs.progressed[op] += ev.Bytes
+	if ev.Err != nil {
+		obs.errs[op] = ev.Err
+	}
+}
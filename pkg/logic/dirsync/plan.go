@@ -0,0 +1,382 @@
+package dirsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Plan computes the ops that `Sync` would apply to `sink`, without applying
+// them. The plan can be reviewed, saved and later applied with `ExecutePlan`.
+func Plan(ctx context.Context, root string, src Source, sink Sink, params Params) (*typesv1.SyncPlan, error) {
+	sink, params = withSinkAttrs(sink, params)
+	src, err := withSinkNames(ctx, src, sink)
+	if err != nil {
+		return nil, err
+	}
+	sigs, lazy, err := getSignatures(ctx, sink)
+	if err != nil {
+		return nil, fmt.Errorf("getting signatures from sink: %w", err)
+	}
+	srcDir, err := TraceSource(ctx, root, src, params)
+	if err != nil {
+		return nil, fmt.Errorf("enumerating files on source: %w", err)
+	}
+	setKeptDigests(params, &typesv1.Path{}, srcDir, sigs)
+	_, canMove := moveSinkOf(sink, params)
+	if lazy != nil {
+		if err := fetchFileSums(ctx, lazy, diffFileSums(params, srcDir, sigs, canMove)); err != nil {
+			return nil, fmt.Errorf("getting file sums from sink: %w", err)
+		}
+	}
+	plan := &typesv1.SyncPlan{}
+	emitCreate, emitPatch, emitDelete, emitMove := planOps(plan, canMove)
+	err = computeTreeDiff(ctx, src, srcDir, sigs, params, emitCreate, emitPatch, emitDelete, emitMove)
+	if err != nil {
+		return nil, fmt.Errorf("computing tree diff: %w", err)
+	}
+	return plan, nil
+}
+
+// planOps returns emitters that append the ops to `plan`.
+func planOps(plan *typesv1.SyncPlan, canMove bool) (
+	emitCreate func(CreateOp) error,
+	emitPatch func(PatchOp) error,
+	emitDelete func(DeleteOp) error,
+	emitMove func(MoveOp) error,
+) {
+	appendOp := func(op *typesv1.SyncOp) error {
+		plan.Ops = append(plan.Ops, op)
+		plan.EstimatedBytes += op.EstimatedBytes
+		return nil
+	}
+	if canMove {
+		emitMove = func(mo MoveOp) error { return appendOp(moveOpToProto(mo)) }
+	}
+	return func(co CreateOp) error { return appendOp(createOpToProto(co)) },
+		func(po PatchOp) error { return appendOp(patchOpToProto(po)) },
+		func(do DeleteOp) error { return appendOp(deleteOpToProto(do)) },
+		emitMove
+}
+
+// ExecutePlan applies the ops of `plan` from `src` onto `sink`. It fails
+// without applying anything if the sink has changed in a way that makes
+// the plan stale since it was computed.
+func ExecutePlan(ctx context.Context, src Source, sink Sink, plan *typesv1.SyncPlan, params Params) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	return executePlan(withObserver(ctx, params.Observer), src, sink, plan, params, false)
+}
+
+// ErrStalePlan is the error of executing a plan that no longer fits the
+// sink.
+var ErrStalePlan = errors.New("plan is stale")
+
+// executePlan is `ExecutePlan`. When `resuming` a plan that was interrupted,
+// the ops that were applied to the sink but not journaled are skipped.
+func executePlan(ctx context.Context, src Source, sink Sink, plan *typesv1.SyncPlan, params Params, resuming bool) error {
+	sink, params = withSinkAttrs(sink, params)
+	src, err := withSinkNames(ctx, src, sink)
+	if err != nil {
+		return err
+	}
+
+	sigs, lazy, err := getSignatures(ctx, sink)
+	if err != nil {
+		return fmt.Errorf("getting signatures from sink: %w", err)
+	}
+	if lazy != nil {
+		// only the files to patch are checked against their sum
+		var pending []pendingSum
+		for _, op := range plan.Ops {
+			if op.GetPatch().GetSum() == nil {
+				continue
+			}
+			if ps, ok := pendingSumAt(sigs, op.Path); ok {
+				pending = append(pending, ps)
+			}
+		}
+		if err := fetchFileSums(ctx, lazy, pending); err != nil {
+			return fmt.Errorf("getting file sums from sink: %w", err)
+		}
+	}
+	deleted := make(map[string]bool)
+	created := make(map[string]bool)
+	unapplied := &typesv1.SyncPlan{}
+	for i, op := range plan.Ops {
+		spath := typesv1.StringFromPath(op.Path)
+		err := checkPlanOp(sigs, op, deleted[spath], created[spath])
+		switch {
+		case op.GetDelete() != nil:
+			deleted[spath] = true
+		case op.GetCreate() != nil:
+			created[spath] = true
+		}
+		if err != nil && resuming && appliedOnSink(sigs, op) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%w, op %d on %q: %w", ErrStalePlan, i, spath, err)
+		}
+		unapplied.Ops = append(unapplied.Ops, op)
+	}
+	plan = unapplied
+	removed, err := planRemovals(sigs, plan, params)
+	if err != nil {
+		return err
+	}
+
+	sched := newScheduler(ctx, params.MaxParallelFileStreams)
+	emitCreate, emitPatch, emitDelete := scheduleOps(sched, src, sink, params)
+	var emitMove func(MoveOp) error
+	if moveSink, ok := sink.(MoveSink); ok {
+		emitMove = scheduleMoves(sched, moveSink)
+	}
+
+	emitErr := emitPlan(plan, func(i int) bool { return params.NoDelete && removed[i] },
+		emitCreate, emitPatch, emitDelete, emitMove)
+	if err := awaitOps(sched, emitErr); err != nil {
+		return err
+	}
+	if emitErr != nil {
+		return fmt.Errorf("executing plan: %w", emitErr)
+	}
+	return nil
+}
+
+// emitPlan emits the ops of `plan`, but the ones `skip` tells to skip.
+func emitPlan(plan *typesv1.SyncPlan, skip func(i int) bool,
+	emitCreate func(CreateOp) error,
+	emitPatch func(PatchOp) error,
+	emitDelete func(DeleteOp) error,
+	emitMove func(MoveOp) error,
+) error {
+	for i, op := range plan.Ops {
+		if skip != nil && skip(i) {
+			continue
+		}
+		var err error
+		switch o := op.Op.(type) {
+		case *typesv1.SyncOp_Create_:
+			err = emitCreate(createOpFromProto(o.Create))
+		case *typesv1.SyncOp_Patch_:
+			err = emitPatch(patchOpFromProto(o.Patch))
+		case *typesv1.SyncOp_Delete_:
+			err = emitDelete(deleteOpFromProto(o.Delete))
+		case *typesv1.SyncOp_Move_:
+			if emitMove == nil {
+				return fmt.Errorf("sink can't move files")
+			}
+			err = emitMove(moveOpFromProto(o.Move))
+		default:
+			err = fmt.Errorf("op %d has unknown type %T", i, op.Op)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planRemovals finds the deletes of `plan` that remove entries rather than
+// make room for new ones, and checks that they don't remove more of the sink
+// than `params` allow, the files of dirs replaced by a file included. With
+// `Params.NoDelete`, nothing is removed.
+func planRemovals(sigs *typesv1.DirSum, plan *typesv1.SyncPlan, params Params) (map[int]bool, error) {
+	replaced := make(map[string]bool)
+	movedFrom := make(map[string]bool)
+	for _, op := range plan.Ops {
+		switch o := op.Op.(type) {
+		case *typesv1.SyncOp_Create_:
+			replaced[typesv1.StringFromPath(op.Path)] = true
+		case *typesv1.SyncOp_Move_:
+			replaced[typesv1.StringFromPath(op.Path)] = true
+			movedFrom[typesv1.StringFromPath(o.Move.From)] = true
+		}
+	}
+	removed := make(map[int]bool)
+	files := 0
+	for i, op := range plan.Ops {
+		if op.GetDelete() == nil {
+			continue
+		}
+		dir, _, _ := sinkLookup(sigs, op.Path)
+		if replaced[typesv1.StringFromPath(op.Path)] {
+			// not a removal, but the files of a replaced dir are
+			// deleted all the same
+			if dir == nil {
+				continue
+			}
+		} else {
+			removed[i] = true
+		}
+		if dir == nil {
+			files++
+			continue
+		}
+		// what's moved out of the dir isn't deleted with it
+		files += countSinkFiles(op.Path, dir, func(path *typesv1.Path) bool {
+			return movedFrom[typesv1.StringFromPath(path)]
+		})
+	}
+	if params.NoDelete {
+		return removed, nil
+	}
+	if err := checkDeletes(params, files, countSinkFiles(&typesv1.Path{}, sigs, nil)); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// checkPlanOp verifies that the sink is still in the state the op expects.
+// `deletedBefore` and `createdBefore` tell if an earlier op of the plan
+// deletes or creates the same path.
+func checkPlanOp(sigs *typesv1.DirSum, op *typesv1.SyncOp, deletedBefore, createdBefore bool) error {
+	dir, file, found := sinkLookup(sigs, op.Path)
+	switch o := op.Op.(type) {
+	case *typesv1.SyncOp_Create_:
+		if found && !deletedBefore {
+			return fmt.Errorf("already exists on sink")
+		}
+	case *typesv1.SyncOp_Patch_:
+		if createdBefore && o.Patch.Sum == nil {
+			// the info of a dir the plan creates
+			return nil
+		}
+		if !found {
+			return fmt.Errorf("doesn't exist on sink anymore")
+		}
+		if o.Patch.Sum == nil && dir == nil {
+			return fmt.Errorf("has changed type on sink")
+		}
+		if o.Patch.Sum != nil && (file == nil || !proto.Equal(file, o.Patch.Sum)) {
+			return fmt.Errorf("file has changed on sink")
+		}
+	case *typesv1.SyncOp_Delete_:
+		if !found {
+			return fmt.Errorf("doesn't exist on sink anymore")
+		}
+		if o.Delete.Info.IsDir != (dir != nil) {
+			return fmt.Errorf("has changed type on sink")
+		}
+		if file != nil && !proto.Equal(file.Info, o.Delete.Info) {
+			return fmt.Errorf("file has changed on sink")
+		}
+	case *typesv1.SyncOp_Move_:
+		if found && !deletedBefore {
+			return fmt.Errorf("already exists on sink")
+		}
+		fromDir, fromFile, found := sinkLookup(sigs, o.Move.From)
+		if !found {
+			return fmt.Errorf("what it moves doesn't exist on sink anymore")
+		}
+		if o.Move.FromInfo.IsDir != (fromDir != nil) {
+			return fmt.Errorf("what it moves has changed type on sink")
+		}
+		if fromFile != nil && !proto.Equal(fromFile.Info, o.Move.FromInfo) {
+			return fmt.Errorf("what it moves has changed on sink")
+		}
+	}
+	return nil
+}
+
+// sinkLookup finds the dir or file at `path` in the signatures of a sink.
+func sinkLookup(sigs *typesv1.DirSum, path *typesv1.Path) (*typesv1.DirSum, *typesv1.FileSum, bool) {
+	dir := sigs
+	for i, elem := range path.Elements {
+		if i == len(path.Elements)-1 {
+			if file, found := sinkHasFileNamed(dir, elem); found {
+				return nil, file, true
+			}
+		}
+		child, found := sinkHasDirNamed(dir, elem)
+		if !found {
+			return nil, nil, false
+		}
+		dir = child
+	}
+	return dir, nil, true
+}
+
+func createOpToProto(op CreateOp) *typesv1.SyncOp {
+	out := &typesv1.SyncOp{
+		Path: createOpPath(op),
+		Size: op.FileInfo.Size,
+		Op: &typesv1.SyncOp_Create_{Create: &typesv1.SyncOp_Create{
+			ParentDir: op.ParentDir,
+			Info:      op.FileInfo,
+		}},
+	}
+	if !op.FileInfo.IsDir {
+		out.EstimatedBytes = op.FileInfo.Size
+	}
+	return out
+}
+
+func createOpFromProto(op *typesv1.SyncOp_Create) CreateOp {
+	return CreateOp{ParentDir: op.ParentDir, FileInfo: op.Info}
+}
+
+func patchOpToProto(op PatchOp) *typesv1.SyncOp {
+	patch := &typesv1.SyncOp_Patch{
+		Dir:  op.Path,
+		Info: op.Info,
+	}
+	out := &typesv1.SyncOp{
+		Path: patchOpPath(op),
+		Size: op.Info.Size,
+		Op:   &typesv1.SyncOp_Patch_{Patch: patch},
+	}
+	if op.File != nil {
+		patch.Sum = op.File.Sum
+		// we won't know how much of the file can be reused until
+		// the patch is computed, so assume none of it
+		out.EstimatedBytes = op.Info.Size
+	}
+	return out
+}
+
+func patchOpFromProto(op *typesv1.SyncOp_Patch) PatchOp {
+	out := PatchOp{Path: op.Dir, Info: op.Info}
+	if op.Sum != nil {
+		out.File = &FilePatchOp{Sum: op.Sum}
+	} else {
+		out.Dir = &DirPatchOp{}
+	}
+	return out
+}
+
+func deleteOpToProto(op DeleteOp) *typesv1.SyncOp {
+	return &typesv1.SyncOp{
+		Path: op.Path,
+		Size: op.FileInfo.Size,
+		Op: &typesv1.SyncOp_Delete_{Delete: &typesv1.SyncOp_Delete{
+			Path: op.Path,
+			Info: op.FileInfo,
+		}},
+	}
+}
+
+func deleteOpFromProto(op *typesv1.SyncOp_Delete) DeleteOp {
+	return DeleteOp{Path: op.Path, FileInfo: op.Info}
+}
+
+func moveOpToProto(op MoveOp) *typesv1.SyncOp {
+	return &typesv1.SyncOp{
+		Path: moveOpPath(op),
+		Size: op.Info.Size,
+		Op: &typesv1.SyncOp_Move_{Move: &typesv1.SyncOp_Move{
+			From:      op.From,
+			FromInfo:  op.FromInfo,
+			ParentDir: op.ParentDir,
+			Info:      op.Info,
+		}},
+	}
+}
+
+func moveOpFromProto(op *typesv1.SyncOp_Move) MoveOp {
+	return MoveOp{From: op.From, FromInfo: op.FromInfo, ParentDir: op.ParentDir, Info: op.Info}
+}
@@ -0,0 +1,181 @@
+package dirsync
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPlan(t *testing.T) {
+	ctx := context.Background()
+	src := fstest.MapFS{
+		"docs/new.txt": &fstest.MapFile{Data: []byte("hello")},
+		"changed.txt":  &fstest.MapFile{Data: []byte("hello world")},
+	}
+	sink := &recordingSink{sigs: planTestSigs()}
+
+	plan, err := Plan(ctx, ".", src, sink, Params{})
+	require.NoError(t, err)
+	require.Empty(t, sink.calls, "planning must not change the sink")
+
+	type wantOp struct {
+		kind           string
+		path           string
+		estimatedBytes uint64
+	}
+	var got []wantOp
+	for _, op := range plan.Ops {
+		var kind string
+		switch op.Op.(type) {
+		case *typesv1.SyncOp_Create_:
+			kind = "create"
+		case *typesv1.SyncOp_Patch_:
+			kind = "patch"
+		case *typesv1.SyncOp_Delete_:
+			kind = "delete"
+		}
+		got = append(got, wantOp{kind, typesv1.StringFromPath(op.Path), op.EstimatedBytes})
+	}
+	require.Equal(t, []wantOp{
+		{"create", "docs", 0},
+		{"create", "docs/new.txt", 5},
+		{"patch", "docs", 0},
+		{"delete", "old", 0},
+		{"patch", "changed.txt", 11},
+		{"delete", "gone.txt", 0},
+	}, got)
+	require.Equal(t, uint64(16), plan.EstimatedBytes)
+
+	// plans survive a round trip through their encoding
+	data, err := proto.Marshal(plan)
+	require.NoError(t, err)
+	decoded := new(typesv1.SyncPlan)
+	require.NoError(t, proto.Unmarshal(data, decoded))
+
+	err = ExecutePlan(ctx, src, sink, decoded, Params{MaxParallelFileStreams: 4})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		"create docs",
+		"create docs/new.txt",
+		"patch docs",
+		"delete old",
+		"patch changed.txt",
+		"delete gone.txt",
+	}, sink.calls)
+}
+
+func TestExecutePlanRejectsStalePlan(t *testing.T) {
+	ctx := context.Background()
+	src := fstest.MapFS{
+		"changed.txt": &fstest.MapFile{Data: []byte("hello world")},
+	}
+
+	tests := []struct {
+		name   string
+		change func(sigs *typesv1.DirSum)
+	}{
+		{
+			name: "deleted file was already removed",
+			change: func(sigs *typesv1.DirSum) {
+				sigs.Files = sigs.Files[:1]
+			},
+		},
+		{
+			name: "patched file changed",
+			change: func(sigs *typesv1.DirSum) {
+				sigs.Files[0] = &typesv1.FileSum{
+					Info: &typesv1.FileInfo{Name: "changed.txt", Size: 4},
+				}
+			},
+		},
+		{
+			name: "deleted dir became a file",
+			change: func(sigs *typesv1.DirSum) {
+				sigs.Dirs = nil
+				sigs.Files = append(sigs.Files, &typesv1.FileSum{
+					Info: &typesv1.FileInfo{Name: "old", Size: 2},
+				})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordingSink{sigs: planTestSigs()}
+			plan, err := Plan(ctx, ".", src, sink, Params{})
+			require.NoError(t, err)
+
+			tt.change(sink.sigs)
+
+			err = ExecutePlan(ctx, src, sink, plan, Params{})
+			require.ErrorContains(t, err, "plan is stale")
+			require.Empty(t, sink.calls)
+		})
+	}
+}
+
+func TestCheckPlanOpAllowsReplacingDeletedPath(t *testing.T) {
+	sigs := planTestSigs()
+	op := createOpToProto(CreateOp{
+		ParentDir: &typesv1.Path{},
+		FileInfo:  &typesv1.FileInfo{Name: "gone.txt", IsDir: true},
+	})
+	require.Error(t, checkPlanOp(sigs, op, false, false))
+	require.NoError(t, checkPlanOp(sigs, op, true, false))
+}
+
+func planTestSigs() *typesv1.DirSum {
+	return &typesv1.DirSum{
+		Info: &typesv1.FileInfo{IsDir: true},
+		Dirs: []*typesv1.DirSum{
+			{
+				Path: &typesv1.Path{},
+				Info: &typesv1.FileInfo{Name: "old", IsDir: true},
+			},
+		},
+		Files: []*typesv1.FileSum{
+			{Info: &typesv1.FileInfo{Name: "changed.txt", Size: 3}},
+			{Info: &typesv1.FileInfo{Name: "gone.txt", Size: 7}},
+		},
+	}
+}
+
+// recordingSink returns fixed signatures and records the changes applied to it.
+type recordingSink struct {
+	sigs *typesv1.DirSum
+
+	mu    sync.Mutex
+	calls []string
+}
+
+var _ Sink = (*recordingSink)(nil)
+
+func (sink *recordingSink) record(call string) {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	sink.calls = append(sink.calls, call)
+}
+
+func (sink *recordingSink) GetSignatures(ctx context.Context) (*typesv1.DirSum, error) {
+	return sink.sigs, nil
+}
+
+func (sink *recordingSink) CreateFile(ctx context.Context, path *typesv1.Path, fi *typesv1.FileInfo, r io.Reader) error {
+	sink.record("create " + typesv1.StringFromPath(typesv1.PathJoin(path, fi.Name)))
+	return nil
+}
+
+func (sink *recordingSink) PatchFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, sum *typesv1.FileSum, r io.Reader) error {
+	sink.record("patch " + typesv1.StringFromPath(typesv1.PathJoin(dir, fi.Name)))
+	return nil
+}
+
+func (sink *recordingSink) DeleteFile(ctx context.Context, op DeleteOp) error {
+	sink.record("delete " + typesv1.StringFromPath(op.Path))
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"math"
 
 	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
@@ -14,6 +15,7 @@ import (
 
 type PatchOp struct {
 	Path *typesv1.Path
+	// Info of the dir or file on the source, as traced
 	Info *typesv1.FileInfo
 	// oneof: Dir or File
 	Dir  *DirPatchOp
@@ -27,6 +29,35 @@ type FilePatchOp struct {
 	Sum *typesv1.FileSum
 }
 
+// RemoteFile is a source file that's costly to read, like one on a server.
+// Rather than reading it whole, it's compared and patched against the sink's
+// version of the file where it is, and only what changed is fetched.
+type RemoteFile interface {
+	fs.File
+	// MatchesFileSum tells if the content of the file is the one summed
+	// in `sum`.
+	MatchesFileSum(ctx context.Context, sum *typesv1.FileSum) (bool, error)
+	// PatchInto writes the file to `w`, reusing the blocks of `orig` that
+	// are summed in `sum`.
+	PatchInto(ctx context.Context, orig io.ReadSeeker, sum *typesv1.FileSum, w io.Writer) error
+}
+
+// PatchInto writes the content of `src` to `w`, reusing the blocks of `orig`
+// that are summed in `sum`.
+func PatchInto(ctx context.Context, src io.Reader, orig io.ReadSeeker, sum *typesv1.FileSum, w io.Writer) error {
+	if rf, ok := src.(RemoteFile); ok {
+		orig, w, done := countRemotePatch(ctx, orig, w)
+		if err := rf.PatchInto(ctx, orig, sum, w); err != nil {
+			return err
+		}
+		done()
+		return nil
+	}
+	patcher := NewFilePatcher(orig, w, sum)
+	_, err := Rsync(ctx, src, sum, patcher.WriteData, patcher.WriteBlock, patcher.WriteHole)
+	return err
+}
+
 func blockSize(filesize uint64) uint32 {
 	if filesize < 490000 {
 		return 700
@@ -38,12 +69,11 @@ func blockSize(filesize uint64) uint32 {
 	return blockSize
 }
 
-func Rsync(ctx context.Context, src io.Reader, dstSum *typesv1.FileSum, patchData func([]byte) (int, error), patchBlockID func(uint32) (int, error)) (int, error) {
-	maxBufferSize := 1 << 20 // 1 MiB
-
-	if len(dstSum.SumBlocks) == 0 {
-		return scanAndEmitBlocks(ctx, src, maxBufferSize, patchData)
-	}
+// Rsync emits the patch that turns the file summed in `dstSum` into `src`.
+// If `src` is a `SparseReader`, its holes are emitted with `patchHole`
+// rather than as data.
+func Rsync(ctx context.Context, src io.Reader, dstSum *typesv1.FileSum, patchData func([]byte) (int, error), patchBlockID func(uint32) (int, error), patchHole func(uint64) (int, error)) (int, error) {
+	patchData, patchBlockID = countRsync(ctx, dstSum, patchData, patchBlockID)
 
 	// TODO: if slow, implement a faster lookup algo:
 	// - 32b sig is turned into an index of 16b sig, sorted
@@ -56,6 +86,44 @@ func Rsync(ctx context.Context, src io.Reader, dstSum *typesv1.FileSum, patchDat
 		}
 	}
 
+	sr, ok := src.(SparseReader)
+	if !ok {
+		return rsyncData(ctx, src, dstSum, fastSigIndex, patchData, patchBlockID)
+	}
+	// the data between holes is diffed on its own, blocks aren't matched
+	// across holes
+	written := 0
+	for {
+		data, hole, err := sr.NextHole()
+		if err != nil {
+			return written, fmt.Errorf("finding next hole: %w", err)
+		}
+		if hole == 0 {
+			n, err := rsyncData(ctx, sr, dstSum, fastSigIndex, patchData, patchBlockID)
+			return written + n, err
+		}
+		n, err := rsyncData(ctx, io.LimitReader(sr, data), dstSum, fastSigIndex, patchData, patchBlockID)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		if err := sr.SkipHole(hole); err != nil {
+			return written, fmt.Errorf("skipping hole: %w", err)
+		}
+		n, err = patchHole(uint64(hole))
+		written += n
+		if err != nil {
+			return written, fmt.Errorf("emiting hole: %w", err)
+		}
+	}
+}
+
+func rsyncData(ctx context.Context, src io.Reader, dstSum *typesv1.FileSum, fastSigIndex map[uint32]uint32, patchData func([]byte) (int, error), patchBlockID func(uint32) (int, error)) (int, error) {
+	maxBufferSize := 1 << 20 // 1 MiB
+	if len(dstSum.SumBlocks) == 0 {
+		return scanAndEmitBlocks(ctx, src, maxBufferSize, patchData)
+	}
+
 	blockSize := dstSum.BlockSize
 
 	br := bufio.NewReader(src)
@@ -77,6 +145,9 @@ func Rsync(ctx context.Context, src io.Reader, dstSum *typesv1.FileSum, patchDat
 			}
 			return written, nil
 		}
+		if err != nil {
+			return written, fmt.Errorf("reading source: %w", err)
+		}
 		block = append(block, b)
 		fastSig := fastHash.HashByte(b)
 		blockIdx, ok := fastSigIndex[fastSig]
@@ -198,3 +269,12 @@ func (fp *FilePatcher) Copy(r io.Reader) (int, error) {
 func (fp *FilePatcher) WriteData(p []byte) (int, error) {
 	return fp.target.Write(p)
 }
+
+// WriteHole writes `size` zeros, as a hole if the target can have some, see
+// `WriteHole`.
+func (fp *FilePatcher) WriteHole(size uint64) (int, error) {
+	if err := WriteHole(fp.target, int64(size)); err != nil {
+		return -1, fmt.Errorf("writing hole of %d bytes: %v", size, err)
+	}
+	return int(size), nil
+}
@@ -15,6 +15,7 @@ func TestRsync(t *testing.T) {
 		src         *bytes.Buffer
 		blockSize   uint32
 		init        *bytes.Reader
+		holes       []fileHole
 		wantPatches []any
 	}{
 		{
@@ -51,12 +52,40 @@ func TestRsync(t *testing.T) {
 				uint32(1),
 			},
 		},
+		{
+			name:      "sparse",
+			src:       bytes.NewBuffer([]byte("hello\x00\x00\x00\x00\x00\x00world")),
+			blockSize: 4,
+			init:      bytes.NewReader([]byte("hello world")),
+			holes:     []fileHole{{offset: 5, size: 6}},
+			wantPatches: []any{
+				uint32(0),
+				"o",
+				uint64(6),
+				"world",
+			},
+		},
+		{
+			name:      "sparse without sum",
+			src:       bytes.NewBuffer([]byte("hello\x00\x00\x00\x00")),
+			blockSize: 4,
+			init:      bytes.NewReader(nil),
+			holes:     []fileHole{{offset: 5, size: 4}},
+			wantPatches: []any{
+				"hello",
+				uint64(4),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
 
 			want := tt.src.String()
+			var src io.Reader = tt.src
+			if tt.holes != nil {
+				src = &holeReader{ReadSeeker: bytes.NewReader(tt.src.Bytes()), holes: tt.holes, size: int64(tt.src.Len())}
+			}
 			orig := tt.init
 
 			sum, err := computeFileSum(ctx, orig, nil, tt.blockSize)
@@ -69,7 +98,7 @@ func TestRsync(t *testing.T) {
 			patcher := NewFilePatcher(orig, dst, sum)
 
 			var gotPatches []any
-			_, err = Rsync(ctx, tt.src, sum,
+			_, err = Rsync(ctx, src, sum,
 				func(data []byte) (int, error) {
 					if len(data) == 0 {
 						panic("what is this")
@@ -83,6 +112,10 @@ func TestRsync(t *testing.T) {
 					n, err := patcher.WriteBlock(blockID)
 					return n, err
 				},
+				func(size uint64) (int, error) {
+					gotPatches = append(gotPatches, size)
+					return patcher.WriteHole(size)
+				},
 			)
 			require.NoError(t, err)
 			got := dst.String()
@@ -0,0 +1,225 @@
+package dirsync
+
+import (
+	"context"
+	"sync"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+)
+
+// scheduler runs ops concurrently, up to a limit, while preserving the order
+// in which they were submitted for ops that touch overlapping paths: an op
+// waits for every op submitted before it on the same path, on one of its
+// ancestors or on one of its descendants. This guarantees that a directory is
+// created before its children, that a directory's metadata is patched after
+// its children, and that a delete is not raced by a create at the same place.
+//
+// The first op to fail cancels all the ops that haven't completed yet.
+type scheduler struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// running bounds the number of ops executing at once
+	running chan struct{}
+	// queued bounds the number of ops submitted but not yet done, so that
+	// the producer doesn't outpace the workers by too much
+	queued chan struct{}
+
+	mu      sync.Mutex
+	pending *pathNode
+
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+// pathNode is a trie of the paths with ops that are not done yet.
+type pathNode struct {
+	parent   *pathNode
+	name     string
+	children map[string]*pathNode
+	ops      []*scheduledOp
+}
+
+type scheduledOp struct {
+	done  chan struct{}
+	nodes []*pathNode
+}
+
+func newScheduler(ctx context.Context, maxParallel int) *scheduler {
+	if maxParallel < 1 {
+		maxParallel = 1
+	}
+	maxQueued := 64 * maxParallel
+	ctx, cancel := context.WithCancel(ctx)
+	return &scheduler{
+		ctx:     ctx,
+		cancel:  cancel,
+		running: make(chan struct{}, maxParallel),
+		queued:  make(chan struct{}, maxQueued),
+		pending: &pathNode{},
+	}
+}
+
+// Submit schedules `fn` to run once all the previously submitted ops that
+// overlap with `paths` are done. It blocks if too many ops are already queued,
+// and returns a `stoppedError` if the scheduler has been stopped by a failure.
+func (sch *scheduler) Submit(fn func(context.Context) error, paths ...*typesv1.Path) error {
+	if sch.ctx.Err() != nil {
+		return sch.stoppedErr()
+	}
+	select {
+	case <-sch.ctx.Done():
+		return sch.stoppedErr()
+	case sch.queued <- struct{}{}:
+	}
+
+	sch.mu.Lock()
+	op := &scheduledOp{done: make(chan struct{})}
+	var deps []*scheduledOp
+	for _, path := range paths {
+		deps = sch.collectDeps(deps, path)
+	}
+	for _, path := range paths {
+		node := sch.pending.lookup(path)
+		node.ops = append(node.ops, op)
+		op.nodes = append(op.nodes, node)
+	}
+	sch.mu.Unlock()
+
+	sch.wg.Add(1)
+	go func() {
+		defer sch.wg.Done()
+		defer func() { <-sch.queued }()
+		defer sch.release(op)
+
+		for _, dep := range deps {
+			select {
+			case <-sch.ctx.Done():
+				return
+			case <-dep.done:
+			}
+		}
+		select {
+		case <-sch.ctx.Done():
+			return
+		case sch.running <- struct{}{}:
+		}
+		defer func() { <-sch.running }()
+		if sch.ctx.Err() != nil {
+			// a dep failed, the select can pick either of what's ready
+			return
+		}
+
+		if err := fn(sch.ctx); err != nil {
+			sch.stop(err)
+		}
+	}()
+	return nil
+}
+
+// Wait blocks until all the submitted ops are done, and returns the
+// error that stopped the scheduler, if any.
+func (sch *scheduler) Wait() error {
+	sch.wg.Wait()
+	if err := sch.ctx.Err(); err != nil {
+		// canceled from the outside
+		sch.stop(err)
+	}
+	sch.cancel()
+	return sch.err
+}
+
+// stop cancels all the ops that haven't completed yet, recording `err`
+// as the reason if it's the first failure.
+func (sch *scheduler) stop(err error) {
+	sch.errOnce.Do(func() {
+		sch.err = err
+		sch.cancel()
+	})
+}
+
+func (sch *scheduler) stoppedErr() error {
+	sch.stop(sch.ctx.Err())
+	return &stoppedError{err: sch.err}
+}
+
+// stoppedError is what ops are no longer submitted for, once an op failed or
+// the scheduler was canceled, so that it isn't mistaken for a failure of what
+// submits them.
+type stoppedError struct {
+	err error
+}
+
+func (e *stoppedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stoppedError) Unwrap() error {
+	return e.err
+}
+
+// collectDeps appends the pending ops on `path`, its ancestors and its
+// descendants to `deps`. The caller must hold the lock.
+func (sch *scheduler) collectDeps(deps []*scheduledOp, path *typesv1.Path) []*scheduledOp {
+	node := sch.pending
+	deps = append(deps, node.ops...)
+	for _, elem := range path.Elements {
+		child, ok := node.children[elem]
+		if !ok {
+			return deps
+		}
+		node = child
+		deps = append(deps, node.ops...)
+	}
+	var walk func(n *pathNode)
+	walk = func(n *pathNode) {
+		for _, child := range n.children {
+			deps = append(deps, child.ops...)
+			walk(child)
+		}
+	}
+	walk(node)
+	return deps
+}
+
+// release marks `op` as done and forgets about it.
+func (sch *scheduler) release(op *scheduledOp) {
+	sch.mu.Lock()
+	defer sch.mu.Unlock()
+	for _, node := range op.nodes {
+		for i, other := range node.ops {
+			if other == op {
+				node.ops = append(node.ops[:i], node.ops[i+1:]...)
+				break
+			}
+		}
+		node.prune()
+	}
+	close(op.done)
+}
+
+// lookup returns the node for `path`, creating it if needed.
+func (node *pathNode) lookup(path *typesv1.Path) *pathNode {
+	for _, elem := range path.Elements {
+		child, ok := node.children[elem]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[string]*pathNode)
+			}
+			child = &pathNode{parent: node, name: elem}
+			node.children[elem] = child
+		}
+		node = child
+	}
+	return node
+}
+
+// prune removes the node and its ancestors from the trie for as long
+// as they don't hold any op.
+func (node *pathNode) prune() {
+	for node.parent != nil && len(node.ops) == 0 && len(node.children) == 0 {
+		delete(node.parent.children, node.name)
+		node = node.parent
+	}
+}
@@ -0,0 +1,207 @@
+package dirsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerOrdersOverlappingPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		// pairs of indices into `paths`, where the first must finish
+		// before the second starts
+		wantBefore [][2]int
+	}{
+		{
+			name:       "parent before children",
+			paths:      []string{"a", "a/b", "a/c", "a/b/d"},
+			wantBefore: [][2]int{{0, 1}, {0, 2}, {0, 3}, {1, 3}},
+		},
+		{
+			name:       "children before parent",
+			paths:      []string{"a/b", "a/c", "a"},
+			wantBefore: [][2]int{{0, 2}, {1, 2}},
+		},
+		{
+			name:       "same path",
+			paths:      []string{"a", "a", "a"},
+			wantBefore: [][2]int{{0, 1}, {1, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			sched := newScheduler(ctx, 8)
+
+			var (
+				mu     sync.Mutex
+				starts = make(map[int]time.Time)
+				ends   = make(map[int]time.Time)
+			)
+			for i, path := range tt.paths {
+				i := i
+				err := sched.Submit(func(ctx context.Context) error {
+					mu.Lock()
+					starts[i] = time.Now()
+					mu.Unlock()
+					time.Sleep(5 * time.Millisecond)
+					mu.Lock()
+					ends[i] = time.Now()
+					mu.Unlock()
+					return nil
+				}, typesv1.PathFromString(path))
+				require.NoError(t, err)
+			}
+			require.NoError(t, sched.Wait())
+
+			for _, pair := range tt.wantBefore {
+				before, after := pair[0], pair[1]
+				require.False(t, starts[after].Before(ends[before]),
+					"%q must finish before %q starts", tt.paths[before], tt.paths[after])
+			}
+		})
+	}
+}
+
+func TestSchedulerRunsDisjointPathsInParallel(t *testing.T) {
+	ctx := context.Background()
+	maxParallel := 4
+	sched := newScheduler(ctx, maxParallel)
+
+	var (
+		mu         sync.Mutex
+		running    int
+		maxRunning int
+		// closed once `maxParallel` ops run at once, none finishes before
+		barrier = make(chan struct{})
+	)
+	for _, path := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		err := sched.Submit(func(ctx context.Context) error {
+			mu.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			if running == maxParallel {
+				select {
+				case <-barrier:
+				default:
+					close(barrier)
+				}
+			}
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				running--
+				mu.Unlock()
+			}()
+			select {
+			case <-barrier:
+				return nil
+			case <-time.After(10 * time.Second):
+				return errors.New("ops on disjoint paths didn't run in parallel")
+			}
+		}, typesv1.PathFromString(path))
+		require.NoError(t, err)
+	}
+	require.NoError(t, sched.Wait())
+	require.Equal(t, maxParallel, maxRunning)
+}
+
+func TestSchedulerFirstErrorCancelsTheRest(t *testing.T) {
+	ctx := context.Background()
+	sched := newScheduler(ctx, 1)
+
+	wantErr := errors.New("boom")
+	var (
+		mu  sync.Mutex
+		ran []string
+		// the first op fails once the second one is submitted
+		release = make(chan struct{})
+	)
+	err := sched.Submit(func(ctx context.Context) error {
+		<-release
+		mu.Lock()
+		ran = append(ran, "a")
+		mu.Unlock()
+		return wantErr
+	}, typesv1.PathFromString("a"))
+	require.NoError(t, err)
+	err = sched.Submit(func(ctx context.Context) error {
+		mu.Lock()
+		ran = append(ran, "a/b")
+		mu.Unlock()
+		return nil
+	}, typesv1.PathFromString("a/b"))
+	require.NoError(t, err)
+	close(release)
+
+	require.Equal(t, wantErr, sched.Wait())
+	require.Equal(t, []string{"a"}, ran)
+
+	err = sched.Submit(func(ctx context.Context) error { return nil }, typesv1.PathFromString("c"))
+	require.ErrorIs(t, err, wantErr)
+}
+
+func TestAwaitOps(t *testing.T) {
+	opErr, diffErr := errors.New("op failed"), errors.New("diff failed")
+	tests := []struct {
+		name string
+		// emit submits ops, and returns what the diff fails with
+		emit    func(sched *scheduler) error
+		wantErr string
+	}{
+		{
+			name: "op fails",
+			emit: func(sched *scheduler) error {
+				err := sched.Submit(func(ctx context.Context) error { return opErr }, typesv1.PathFromString("a"))
+				require.NoError(t, err)
+				<-sched.ctx.Done()
+				return sched.Submit(func(ctx context.Context) error { return nil }, typesv1.PathFromString("b"))
+			},
+			wantErr: "applying changes to sink: op failed",
+		},
+		{
+			name: "op fails, wrapped",
+			emit: func(sched *scheduler) error {
+				err := sched.Submit(func(ctx context.Context) error { return opErr }, typesv1.PathFromString("a"))
+				require.NoError(t, err)
+				<-sched.ctx.Done()
+				err = sched.Submit(func(ctx context.Context) error { return nil }, typesv1.PathFromString("b"))
+				return fmt.Errorf("emitting create file: %w", err)
+			},
+			wantErr: "applying changes to sink: op failed",
+		},
+		{
+			name: "diff fails",
+			emit: func(sched *scheduler) error {
+				return diffErr
+			},
+		},
+		{
+			name: "done",
+			emit: func(sched *scheduler) error {
+				return sched.Submit(func(ctx context.Context) error { return nil }, typesv1.PathFromString("a"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched := newScheduler(context.Background(), 1)
+			err := awaitOps(sched, tt.emit(sched))
+			if tt.wantErr == "" {
+				// the caller reports what the diff failed with
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+			require.ErrorIs(t, err, opErr)
+		})
+	}
+}
@@ -26,6 +26,51 @@ func TraceSink(ctx context.Context, root string, sumDB SumDB) (*typesv1.DirSum,
 	return trace(ctx, root, nil, "", sumDB)
 }
 
+// DirSink is a `Sink` that can list its dirs one at a time, so that a
+// streaming sync doesn't need all its signatures at once.
+type DirSink interface {
+	Sink
+	// GetDirSignatures returns the entries of the dir at `path`: the sums
+	// of its files, and its subdirs without their own entries. It's false
+	// if there's no such dir. A sink that's also a `LazySink` may only give
+	// the info of the files.
+	GetDirSignatures(ctx context.Context, path *typesv1.Path) (*typesv1.DirSum, bool, error)
+}
+
+// ListSinkDir is `TraceSink` for the entries of a single dir: its subdirs
+// are left without their own entries.
+func ListSinkDir(ctx context.Context, namespace string, path *typesv1.Path, sumDB SumDB) (*typesv1.DirSum, bool, error) {
+	spath := typesv1.StringFromPath(path)
+	fsEntries, ok, err := sumDB.ListDir(ctx, namespace, spath)
+	if err != nil {
+		return nil, false, fmt.Errorf("reading dir: %w", err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+	dir := &typesv1.DirSum{Path: typesv1.DirOf(path)}
+
+	// `fsEntries`` is guaranteed to be sorted, per `sumDB.ListDir`'s contract
+	for _, fsEntry := range fsEntries {
+		if fsEntry.IsDir {
+			dir.Dirs = append(dir.Dirs, &typesv1.DirSum{Path: path, Info: fsEntry})
+		} else if fsEntry.IsSymlink() {
+			// symlinks have no content to sum, their target is in their info
+			dir.Files = append(dir.Files, &typesv1.FileSum{Info: fsEntry})
+		} else {
+			file, ok, err := sumDB.GetFileSum(ctx, namespace, spath, fsEntry)
+			if err != nil {
+				return nil, false, fmt.Errorf("looking up filesum for file %q in %q: %w", fsEntry.Name, spath, err)
+			}
+			if !ok {
+				return nil, false, fmt.Errorf("missing filesum for file %q in %q", fsEntry.Name, spath)
+			}
+			dir.Files = append(dir.Files, file)
+		}
+	}
+	return dir, true, nil
+}
+
 func trace(ctx context.Context, namespace string, parent *typesv1.Path, base string, sumDB SumDB) (*typesv1.DirSum, error) {
 	path := typesv1.PathJoin(parent, base)
 
@@ -41,43 +86,28 @@ func trace(ctx context.Context, namespace string, parent *typesv1.Path, base str
 	if err != nil {
 		return nil, fmt.Errorf("stating dir: %w", err)
 	}
-	dir := &typesv1.DirSum{
-		Path: parent,
-		Info: fi,
-	}
 
-	fsEntries, ok, err := sumDB.ListDir(ctx, namespace, typesv1.StringFromPath(path))
+	dir, ok, err := ListSinkDir(ctx, namespace, path, sumDB)
 	if err != nil {
-		return nil, fmt.Errorf("reading dir: %w", err)
+		return nil, err
 	}
 	if !ok {
 		return nil, fmt.Errorf("no such dir: %q", typesv1.StringFromPath(path))
 	}
+	dir.Path = parent
+	dir.Info = fi
 
-	// `fsEntries`` is guaranteed to be sorted, per `sumDB.ListDir`'s contract
-	for _, fsEntry := range fsEntries {
-		if fsEntry.IsDir {
-			path := typesv1.PathJoin(parent, base)
-			child, err := trace(ctx, namespace, path, fsEntry.Name, sumDB)
-			if err != nil {
-				return nil, fmt.Errorf("tracing %q, %w", typesv1.StringFromPath(path), err)
-			}
-			dir.Dirs = append(dir.Dirs, child)
-			dir.Info.Size += child.Info.Size
-		} else {
-			path := typesv1.PathJoin(parent, base)
-			spath := typesv1.StringFromPath(path)
-			file, ok, err := sumDB.GetFileSum(ctx, namespace, spath, fsEntry)
-			if err != nil {
-				return nil, fmt.Errorf("looking up filesum for file %q in %q: %w", fsEntry.Name, spath, err)
-			}
-			if !ok {
-				return nil, fmt.Errorf("missing filesum for file %q in %q", fsEntry.Name, spath)
-			}
-
-			dir.Files = append(dir.Files, file)
-			dir.Info.Size += file.Info.Size
+	for i, child := range dir.Dirs {
+		traced, err := trace(ctx, namespace, path, child.Info.Name, sumDB)
+		if err != nil {
+			return nil, fmt.Errorf("tracing %q, %w", typesv1.StringFromPath(path), err)
 		}
+		dir.Dirs[i] = traced
+		dir.Info.Size += traced.Info.Size
+	}
+	for _, file := range dir.Files {
+		dir.Info.Size += file.Info.Size
 	}
+	dir.Digest = sinkDirDigest(dir)
 	return dir, nil
 }
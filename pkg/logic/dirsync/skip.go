@@ -0,0 +1,177 @@
+package dirsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+)
+
+// ErrorPolicy is what to do with the entries of the source that can't be
+// synced, see `SkipReason`. Skipped entries are left as they are on the
+// sink.
+type ErrorPolicy int
+
+const (
+	// ErrorSkip skips the entries, and reports them in `SyncStats.Skipped`.
+	ErrorSkip ErrorPolicy = iota
+	// ErrorFail fails the sync.
+	ErrorFail
+	// ErrorIgnore skips the entries without reporting them.
+	ErrorIgnore
+)
+
+func (ep ErrorPolicy) String() string {
+	switch ep {
+	case ErrorSkip:
+		return "skip"
+	case ErrorFail:
+		return "fail"
+	case ErrorIgnore:
+		return "ignore"
+	default:
+		return fmt.Sprintf("ErrorPolicy(%d)", int(ep))
+	}
+}
+
+// SkipReason is why an entry of the source can't be synced.
+type SkipReason int
+
+const (
+	// SkipSpecial is for entries that are neither files, dirs nor symlinks:
+	// named pipes, sockets and devices.
+	SkipSpecial SkipReason = iota
+	// SkipUnreadable is for files and dirs that can't be read for lack of
+	// permissions.
+	SkipUnreadable
+	// SkipEscapingSymlink is for symlinks whose target isn't in the tree
+	// being synced, see `ErrSymlinkEscapesRoot`.
+	SkipEscapingSymlink
+)
+
+func (sr SkipReason) String() string {
+	switch sr {
+	case SkipSpecial:
+		return "special"
+	case SkipUnreadable:
+		return "unreadable"
+	case SkipEscapingSymlink:
+		return "escaping symlink"
+	default:
+		return fmt.Sprintf("SkipReason(%d)", int(sr))
+	}
+}
+
+// ErrSpecialFile is the error of the entries skipped with `SkipSpecial`.
+var ErrSpecialFile = errors.New("not a file, dir or symlink")
+
+// SkippedEntry is an entry of the source that wasn't synced.
+type SkippedEntry struct {
+	Path   *typesv1.Path
+	Reason SkipReason
+	Err    error
+}
+
+// unreadableError is an error of reading the source for lack of permissions,
+// which sets it apart from the ones of writing to the sink.
+type unreadableError struct {
+	err error
+}
+
+func (e *unreadableError) Error() string { return e.err.Error() }
+func (e *unreadableError) Unwrap() error { return e.err }
+
+// unreadable marks `err`, an error of reading the source, as one of lacking
+// permissions if it is.
+func unreadable(err error) error {
+	if errors.Is(err, fs.ErrPermission) {
+		return &unreadableError{err: err}
+	}
+	return err
+}
+
+// errSkipped is the error of the ops on entries that are skipped, rather
+// than failed.
+var errSkipped = errors.New("skipped")
+
+// errorPolicy is the policy of `params` for the entries that can't be synced
+// for `reason`.
+func (params Params) errorPolicy(reason SkipReason) ErrorPolicy {
+	switch reason {
+	case SkipSpecial:
+		return params.SpecialFiles
+	case SkipUnreadable:
+		return params.Unreadable
+	case SkipEscapingSymlink:
+		return params.EscapingSymlinks
+	default:
+		return ErrorFail
+	}
+}
+
+// skipReason tells if `err` is one for which entries can be skipped.
+func skipReason(err error) (SkipReason, bool) {
+	switch {
+	case errors.Is(err, ErrSpecialFile):
+		return SkipSpecial, true
+	case errors.As(err, new(*unreadableError)):
+		return SkipUnreadable, true
+	case errors.Is(err, ErrSymlinkEscapesRoot):
+		return SkipEscapingSymlink, true
+	default:
+		return 0, false
+	}
+}
+
+// skipEntry handles `err`, the error of the entry at `path` of the source.
+// It's nil if the entry is to be skipped, after it's reported per the policy
+// of `params`. Otherwise it's `err`.
+func skipEntry(ctx context.Context, params Params, path *typesv1.Path, err error) error {
+	reason, ok := skipReason(err)
+	if !ok {
+		return err
+	}
+	switch params.errorPolicy(reason) {
+	case ErrorSkip:
+		statsFrom(ctx).skipped(SkippedEntry{Path: path, Reason: reason, Err: err})
+		return nil
+	case ErrorIgnore:
+		return nil
+	default:
+		return err
+	}
+}
+
+// skipOp is `skipEntry` for the error of an op, which is then one that
+// `runOp` knows to skip.
+func skipOp(ctx context.Context, params Params, path *typesv1.Path, err error) error {
+	if err == nil || skipEntry(ctx, params, path, err) != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %w", errSkipped, err)
+}
+
+// specialFile is the error of `fi`, the info of `name` if it's of a type
+// that can't be synced.
+func specialFile(name string, fi fs.FileInfo) error {
+	return fmt.Errorf("%q of type %v: %w", name, fi.Mode().Type(), ErrSpecialFile)
+}
+
+// skip skips the entry of `dir` that failed with `err`, if the params skip
+// entries for that error. Otherwise, it returns `err`.
+func (tr *sourceTracer) skip(ctx context.Context, dir *SourceDir, entry tracedDir, err error) error {
+	if err := skipEntry(ctx, tr.params, entry.path(), err); err != nil {
+		return err
+	}
+	dir.skipped = append(dir.skipped, path.Base(entry.rel))
+	return nil
+}
+
+// srcSkipped tells if the entry `name` of `src` was skipped.
+func srcSkipped(src *SourceDir, name string) bool {
+	return slices.Contains(src.skipped, name)
+}
@@ -0,0 +1,128 @@
+//go:build linux || darwin
+
+package dirsync
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+// deniedSource can't read the files and dirs it's told to, as if it lacked
+// the permissions to. Tests run as root, which has them all.
+type deniedSource struct {
+	*LocalSource
+	denied []string
+}
+
+func (src *deniedSource) Open(name string) (fs.File, error) {
+	if slices.Contains(src.denied, name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+	return src.LocalSource.Open(name)
+}
+
+func (src *deniedSource) ReadDir(name string) ([]fs.DirEntry, error) {
+	if slices.Contains(src.denied, name) {
+		return nil, &fs.PathError{Op: "readdirent", Path: name, Err: fs.ErrPermission}
+	}
+	return src.LocalSource.ReadDir(name)
+}
+
+func TestSyncSkippedEntries(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    ErrorPolicy
+		streaming bool
+		// the sync fails, or reports the entries it skipped
+		wantErr     bool
+		wantSkipped []string
+	}{
+		{name: "skip", policy: ErrorSkip, wantSkipped: []string{"changed", "new", "pipe", "private", "same"}},
+		{name: "skip streaming", policy: ErrorSkip, streaming: true, wantSkipped: []string{"changed", "new", "pipe", "private", "same"}},
+		{name: "ignore", policy: ErrorIgnore},
+		{name: "ignore streaming", policy: ErrorIgnore, streaming: true},
+		{name: "fail", policy: ErrorFail, wantErr: true},
+		{name: "fail streaming", policy: ErrorFail, streaming: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			srcDir, sinkDir := t.TempDir(), t.TempDir()
+			modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			write := func(dir, name, content string) {
+				path := filepath.Join(dir, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+				require.NoError(t, os.Chtimes(path, modTime, modTime))
+			}
+			write(srcDir, "readable", "readable")
+			write(srcDir, "changed", "new content")
+			write(sinkDir, "changed", "old")
+			write(srcDir, "same", "same size")
+			write(sinkDir, "same", "same SIZE")
+			write(srcDir, "new", "new")
+			write(srcDir, "private/file", "new content")
+			write(sinkDir, "private/file", "old")
+			write(sinkDir, "pipe", "a file replaced by a pipe")
+			require.NoError(t, unix.Mkfifo(filepath.Join(srcDir, "pipe"), 0644))
+			src := &deniedSource{LocalSource: NewLocalSource(srcDir), denied: []string{"changed", "same", "new", "private"}}
+
+			params := Params{SpecialFiles: tt.policy, Unreadable: tt.policy, Streaming: tt.streaming}
+			stats, err := Sync(ctx, ".", src, NewLocalSink(sinkDir), params)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Empty(t, stats.Errors)
+			var skipped []string
+			for _, entry := range stats.Skipped {
+				skipped = append(skipped, typesv1.StringFromPath(entry.Path))
+				wantReason := SkipUnreadable
+				if typesv1.StringFromPath(entry.Path) == "pipe" {
+					wantReason = SkipSpecial
+				}
+				require.Equal(t, wantReason, entry.Reason)
+			}
+			slices.Sort(skipped)
+			require.Equal(t, tt.wantSkipped, skipped)
+
+			// what was skipped is left as it was on the sink
+			want := map[string]string{
+				"readable":     "readable",
+				"changed":      "old",
+				"same":         "same SIZE",
+				"private/file": "old",
+				"pipe":         "a file replaced by a pipe",
+			}
+			got := make(map[string]string)
+			require.NoError(t, filepath.WalkDir(sinkDir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				content, err := os.ReadFile(path)
+				rel, _ := filepath.Rel(sinkDir, path)
+				got[filepath.ToSlash(rel)] = string(content)
+				return err
+			}))
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func TestErrorPolicyString(t *testing.T) {
+	require.Equal(t, "skip", ErrorSkip.String())
+	require.Equal(t, "fail", ErrorFail.String())
+	require.Equal(t, "ignore", ErrorIgnore.String())
+	require.Equal(t, "unreadable", SkipUnreadable.String())
+	require.Equal(t, "escaping symlink", SkipEscapingSymlink.String())
+}
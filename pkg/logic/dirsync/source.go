@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
+	"path"
 	"path/filepath"
 
 	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
@@ -28,58 +29,288 @@ type SourceDir struct {
 	Info  *typesv1.FileInfo
 	Dirs  []*SourceDir
 	Files []*SourceFile
+	// Digest of the subtree, see `DirDigest`
+	Digest []byte
+	// digest of the subtree with the entries kept on the sink, if it has
+	// any, see `setKeptDigests`
+	keptDigest []byte
+
+	// rules that excluded entries of the dir, if any
+	rules *ignoreRules
+	// names of the entries that were skipped, see `ErrorPolicy`
+	skipped []string
+	// where the dir is on the source, until its entries are listed
+	at *tracedDir
 }
 
 type SourceFile struct {
 	Info *typesv1.FileInfo
+
+	// inode of the file, if the source has them
+	inode uint64
 }
 
-func TraceSource(ctx context.Context, root string, src Source) (*SourceDir, error) {
-	dirinfo, err := src.Stat(root)
+// TraceSource lists the dirs and files under `root` that are to be synced,
+// leaving out the ones excluded by `IgnoreFile`s or by `params`.
+func TraceSource(ctx context.Context, root string, src Source, params Params) (*SourceDir, error) {
+	tr, dir, err := startTrace(root, src, params)
 	if err != nil {
-		return nil, fmt.Errorf("stating root: %w", err)
+		return nil, err
 	}
-
-	return traceSource(ctx, root, dirinfo, src)
+	tr.links = &hardLinks{}
+	if err := tr.traceDir(ctx, dir); err != nil {
+		return nil, err
+	}
+	// links are only known once the whole tree is traced, and so are the
+	// digests of the dirs that have some
+	if tr.links.link(dir) {
+		rehashSource(dir)
+	}
+	return dir, nil
 }
 
-func traceSource(ctx context.Context, base string, fi fs.FileInfo, fs Source) (*SourceDir, error) {
+// startTrace returns the root of the source, with its entries yet to be
+// listed.
+func startTrace(root string, src Source, params Params) (*sourceTracer, *SourceDir, error) {
+	dirinfo, err := src.Stat(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("stating root: %w", err)
+	}
+	rules, err := newIgnoreRules(params)
+	if err != nil {
+		return nil, nil, err
+	}
 	dir := &SourceDir{
-		Info: typesv1.FileInfoFromFS(fi),
+		Info: typesv1.FileInfoFromFS(dirinfo),
+		at:   &tracedDir{base: root, rel: ".", real: ".", rules: rules},
 	}
-	if base == "." {
+	if root == "." {
 		dir.Info.Name = ""
 	}
+	return &sourceTracer{src: src, params: params}, dir, nil
+}
+
+type sourceTracer struct {
+	src    Source
+	params Params
+	// files that are hard links, found as dirs are listed, unless they
+	// aren't detected
+	links *hardLinks
+}
+
+// tracedDir is where a dir being traced is.
+type tracedDir struct {
+	// path of the dir in the source
+	base string
+	// path of the dir from the root
+	rel string
+	// path of the dir from the root, once symlinks are resolved
+	real string
+	// `real` paths of the dirs being traced above it
+	parents []string
+	// rules that apply in the dir
+	rules *ignoreRules
+}
+
+func (td tracedDir) child(name string) tracedDir {
+	return tracedDir{
+		base: filepath.Join(td.base, name),
+		rel:  path.Join(td.rel, name),
+		real: path.Join(td.real, name),
+		// siblings are listed before they're traced, they can't share
+		// the array
+		parents: append(td.parents[:len(td.parents):len(td.parents)], td.real),
+		rules:   td.rules,
+	}
+}
 
-	fsEntries, err := fs.ReadDir(base)
+// path is `rel` as a path.
+func (td tracedDir) path() *typesv1.Path {
+	if td.rel == "." {
+		return &typesv1.Path{}
+	}
+	return typesv1.PathFromString(td.rel)
+}
+
+// traceDir lists the entries of `dir` and of all its subdirs.
+func (tr *sourceTracer) traceDir(ctx context.Context, dir *SourceDir) error {
+	if err := tr.listDir(ctx, dir); err != nil {
+		return err
+	}
+	return tr.traceSubdirs(ctx, dir)
+}
+
+// traceSubdirs traces the subdirs of `dir`, once it's listed. The ones that
+// can't be listed are skipped, per the params.
+func (tr *sourceTracer) traceSubdirs(ctx context.Context, dir *SourceDir) error {
+	dirs := dir.Dirs[:0]
+	for _, child := range dir.Dirs {
+		entry := *child.at
+		if err := tr.listDir(ctx, child); err != nil {
+			err = fmt.Errorf("tracing %q, %w", entry.base, err)
+			if err := tr.skip(ctx, dir, entry, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tr.traceSubdirs(ctx, child); err != nil {
+			return fmt.Errorf("tracing %q, %w", entry.base, err)
+		}
+		dir.Info.Size += child.Info.Size
+		dirs = append(dirs, child)
+	}
+	dir.Dirs = dirs
+	dir.Digest = sourceDirDigest(dir)
+	return nil
+}
+
+// listDir lists the entries of `dir`, its subdirs are left to be listed in
+// turn.
+func (tr *sourceTracer) listDir(ctx context.Context, dir *SourceDir) error {
+	td := *dir.at
+	dir.at = nil
+
+	fsEntries, err := tr.src.ReadDir(td.base)
 	if err != nil {
-		return nil, fmt.Errorf("reading dir: %w", err)
+		return fmt.Errorf("reading dir: %w", unreadable(err))
+	}
+	for _, fsEntry := range fsEntries {
+		if fsEntry.Name() == IgnoreFile && fsEntry.Type().IsRegular() {
+			ignorePath := filepath.Join(td.base, IgnoreFile)
+			content, err := fs.ReadFile(tr.src, ignorePath)
+			if err != nil {
+				return fmt.Errorf("reading %q: %w", ignorePath, err)
+			}
+			td.rules, err = td.rules.withFile(td.rel, content)
+			if err != nil {
+				return fmt.Errorf("parsing %q: %w", ignorePath, err)
+			}
+		}
+	}
+	if td.rules.any() {
+		dir.rules = td.rules
 	}
 
 	// `fsEntries`` is guaranteed to be sorted, per `fs.ReadDir`'s contract
 	for _, fsEntry := range fsEntries {
-		entryPath := filepath.Join(base, fsEntry.Name())
-		fsfi, err := fs.Stat(entryPath)
-		if err != nil {
-			return nil, fmt.Errorf("stating %q: %w", entryPath, err)
-		}
+		entry := td.child(fsEntry.Name())
 
-		// TODO: encode symlinks somehow?
-		if fsEntry.IsDir() {
-			child, err := traceSource(ctx, entryPath, fsfi, fs)
+		if fsEntry.Type()&fs.ModeSymlink != 0 {
+			err := tr.traceSymlink(ctx, dir, entry)
 			if err != nil {
-				return nil, fmt.Errorf("tracing %q, %w", entryPath, err)
+				return fmt.Errorf("tracing symlink %q: %w", entry.base, err)
+			}
+			continue
+		}
+		if td.rules.excluded(entry.rel, fsEntry.IsDir()) {
+			continue
+		}
+
+		fsfi, err := tr.src.Stat(entry.base)
+		if err != nil {
+			if err := tr.skip(ctx, dir, entry, fmt.Errorf("stating %q: %w", entry.base, unreadable(err))); err != nil {
+				return err
+			}
+			continue
+		}
+		if !fsEntry.IsDir() && !fsfi.Mode().IsRegular() {
+			if err := tr.skip(ctx, dir, entry, specialFile(entry.base, fsfi)); err != nil {
+				return err
 			}
-			dir.Dirs = append(dir.Dirs, child)
+			continue
+		}
+		info, err := tr.fileInfo(entry.base, fsfi)
+		if err != nil {
+			return err
+		}
 
-			dir.Info.Size += child.Info.Size
-		} else if fsfi.Mode().IsRegular() {
+		if fsEntry.IsDir() {
+			dir.Dirs = append(dir.Dirs, &SourceDir{
+				Info: info,
+				at:   &entry,
+			})
+		} else {
 			file := &SourceFile{
-				Info: typesv1.FileInfoFromFS(fsfi),
+				Info:  info,
+				inode: fileInode(fsfi),
+			}
+			if tr.links != nil {
+				tr.links.add(typesv1.PathFromString(entry.rel), file.Info, fsfi)
 			}
 			dir.Files = append(dir.Files, file)
 			dir.Info.Size += file.Info.Size
 		}
 	}
-	return dir, nil
+	if rec := statsFrom(ctx); rec != nil {
+		rec.nameCollisions(dirNameCollisions(td.path(), dir))
+	}
+	return nil
+}
+
+// fileInfo is the info of the file or dir `name` of the source, with the
+// attributes asked for.
+func (tr *sourceTracer) fileInfo(name string, fsfi fs.FileInfo) (*typesv1.FileInfo, error) {
+	info := typesv1.FileInfoFromFS(fsfi)
+	attrs, err := tr.readAttrs(name, fsfi)
+	if err != nil {
+		return nil, fmt.Errorf("reading attributes of %q: %w", name, err)
+	}
+	info.Attrs = attrs
+	return info, nil
+}
+
+func (tr *sourceTracer) traceSymlink(ctx context.Context, dir *SourceDir, entry tracedDir) error {
+	if tr.params.Symlinks == SymlinkSkip {
+		return nil
+	}
+	if tr.params.Symlinks == SymlinkPreserve && entry.rules.excluded(entry.rel, false) {
+		return nil
+	}
+	info, err := readSymlink(tr.src, entry.base)
+	if err != nil {
+		return err
+	}
+	// relative targets are relative to where the symlink really is
+	target, err := ResolveSymlink(entry.real, info.LinkTarget)
+	if err != nil {
+		return tr.skip(ctx, dir, entry, err)
+	}
+	if tr.params.Symlinks == SymlinkPreserve {
+		dir.Files = append(dir.Files, &SourceFile{Info: info})
+		dir.Info.Size += info.Size
+		return nil
+	}
+
+	fsfi, err := tr.src.Stat(entry.base)
+	if err != nil {
+		return fmt.Errorf("following symlink: %w", unreadable(err))
+	}
+	if entry.rules.excluded(entry.rel, fsfi.IsDir()) {
+		return nil
+	}
+	if !fsfi.IsDir() && !fsfi.Mode().IsRegular() {
+		return tr.skip(ctx, dir, entry, specialFile(entry.base, fsfi))
+	}
+	info, err = tr.fileInfo(entry.base, fsfi)
+	if err != nil {
+		return err
+	}
+	if fsfi.IsDir() {
+		if isSymlinkLoop(entry.parents, target) {
+			return fmt.Errorf("following symlink to %q loops back into a parent dir", target)
+		}
+		entry.real = target
+		dir.Dirs = append(dir.Dirs, &SourceDir{
+			Info: info,
+			at:   &entry,
+		})
+	} else {
+		file := &SourceFile{
+			Info:  info,
+			inode: fileInode(fsfi),
+		}
+		dir.Files = append(dir.Files, file)
+		dir.Info.Size += file.Info.Size
+	}
+	return nil
 }
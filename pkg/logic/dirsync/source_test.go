@@ -2,6 +2,7 @@ package dirsync
 
 import (
 	"context"
+	"encoding/hex"
 	"io/fs"
 	"testing"
 	"testing/fstest"
@@ -40,6 +41,7 @@ func TestTraceSource(t *testing.T) {
 					IsDir:   true,
 					ModTime: nowpb,
 				},
+				Digest: mustDecodeHex("6f3c2d0cf98bba2aa24f3509469fb70b8ef3ae1b5f2a741472ec53b6583e6504"),
 				Dirs: []*SourceDir{
 					{
 						Info: &typesv1.FileInfo{
@@ -49,6 +51,7 @@ func TestTraceSource(t *testing.T) {
 							IsDir:   true,
 							ModTime: nowpb,
 						},
+						Digest: mustDecodeHex("b77cc7c7f319a440e3d9552710a9d35e484d303464572676a51e4950e0964a2b"),
 						Files: []*SourceFile{
 							{Info: &typesv1.FileInfo{
 								Name: "world", Mode: uint32(regFile), Size: 11,
@@ -64,6 +67,7 @@ func TestTraceSource(t *testing.T) {
 							IsDir:   true,
 							ModTime: nowpb,
 						},
+						Digest: mustDecodeHex("fb11a2012554d117c0f182924eafe2fdc9536fee21d577be4dc9eb1e095c4183"),
 						Dirs: []*SourceDir{
 							{
 								Info: &typesv1.FileInfo{
@@ -73,6 +77,7 @@ func TestTraceSource(t *testing.T) {
 									IsDir:   true,
 									ModTime: nowpb,
 								},
+								Digest: mustDecodeHex("4d7103da60de939bc7100478de825aefff6719d3e20f387e612907d4a26a22e4"),
 								Files: []*SourceFile{
 									{Info: &typesv1.FileInfo{
 										Name: "le_monde", Mode: uint32(regFile), Size: 14,
@@ -106,9 +111,17 @@ func TestTraceSource(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
-			got, err := TraceSource(ctx, tt.base, tt.in)
+			got, err := TraceSource(ctx, tt.base, tt.in, Params{})
 			require.NoError(t, err)
 			require.Equal(t, tt.want, got)
 		})
 	}
 }
+
+func mustDecodeHex(h string) []byte {
+	b, err := hex.DecodeString(h)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,216 @@
+package dirsync
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// SparseReader reads a sparse file: one with holes, runs of zeros that the
+// filesystem doesn't store. Holes can be skipped rather than read.
+type SparseReader interface {
+	io.Reader
+	// NextHole is how much there is to read before the next hole, and the
+	// size of that hole. The size is 0 if there are no holes left.
+	NextHole() (data, hole int64, err error)
+	// SkipHole skips the hole that's next to be read, of size `n`.
+	SkipHole(n int64) error
+}
+
+// HoleWriter is a writer that can leave holes rather than write zeros.
+type HoleWriter interface {
+	io.Writer
+	WriteHole(n int64) error
+}
+
+// fileHole is a hole in a file, found with `fileHoles`.
+type fileHole struct {
+	offset, size int64
+}
+
+// SparseFile is `f`, as a `SparseReader` if it's a file with holes.
+func SparseFile(f *os.File) (fs.File, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !fi.Mode().IsRegular() {
+		return f, nil
+	}
+	holes, err := fileHoles(f, fi)
+	if err != nil {
+		return nil, fmt.Errorf("finding holes of %q: %w", f.Name(), err)
+	}
+	if len(holes) == 0 {
+		return f, nil
+	}
+	return &sparseFile{
+		holeReader: holeReader{ReadSeeker: f, holes: holes, size: fi.Size()},
+		file:       f,
+	}, nil
+}
+
+type sparseFile struct {
+	holeReader
+	file *os.File
+}
+
+func (sf *sparseFile) Stat() (fs.FileInfo, error) { return sf.file.Stat() }
+func (sf *sparseFile) Close() error               { return sf.file.Close() }
+
+// holeReader is a `SparseReader` of a file of `size` whose holes are known.
+type holeReader struct {
+	io.ReadSeeker
+	holes []fileHole
+	size  int64
+}
+
+func (hr *holeReader) NextHole() (int64, int64, error) {
+	pos, err := hr.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, hole := range hr.holes {
+		end := hole.offset + hole.size
+		if end <= pos {
+			continue
+		}
+		start := max(hole.offset, pos)
+		return start - pos, end - start, nil
+	}
+	return max(hr.size-pos, 0), 0, nil
+}
+
+func (hr *holeReader) SkipHole(n int64) error {
+	_, err := hr.Seek(n, io.SeekCurrent)
+	return err
+}
+
+// withHoles is `r`, which reads from `base`, as a `SparseReader` if `base`
+// is one. Holes are skipped on `base`, after `onHole` is told about them.
+func withHoles(r, base io.Reader, onHole func(n int64) error) io.Reader {
+	sr, ok := base.(SparseReader)
+	if !ok {
+		return r
+	}
+	return &holeSkipper{Reader: r, base: sr, onHole: onHole}
+}
+
+type holeSkipper struct {
+	io.Reader
+	base   SparseReader
+	onHole func(n int64) error
+}
+
+func (hs *holeSkipper) NextHole() (int64, int64, error) { return hs.base.NextHole() }
+
+func (hs *holeSkipper) SkipHole(n int64) error {
+	if hs.onHole != nil {
+		if err := hs.onHole(n); err != nil {
+			return err
+		}
+	}
+	return hs.base.SkipHole(n)
+}
+
+// TeeReader is `io.TeeReader`, keeping `r` a `SparseReader`: the holes it
+// skips are written to `w`.
+func TeeReader(r io.Reader, w io.Writer) io.Reader {
+	return withHoles(io.TeeReader(r, w), r, func(n int64) error {
+		return WriteHole(w, n)
+	})
+}
+
+// MultiWriter is `io.MultiWriter`, with holes written to each writer.
+func MultiWriter(writers ...io.Writer) io.Writer {
+	return &multiWriter{Writer: io.MultiWriter(writers...), writers: writers}
+}
+
+type multiWriter struct {
+	io.Writer
+	writers []io.Writer
+}
+
+func (mw *multiWriter) WriteHole(n int64) error {
+	for _, w := range mw.writers {
+		if err := WriteHole(w, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteHole writes `n` zeros to `w`. They're left as a hole if `w` is a
+// `HoleWriter`, or a file that's being written from start to end.
+func WriteHole(w io.Writer, n int64) error {
+	switch w := w.(type) {
+	case HoleWriter:
+		return w.WriteHole(n)
+	case *os.File:
+		pos, err := w.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		fi, err := w.Stat()
+		if err != nil {
+			return err
+		}
+		if pos < fi.Size() {
+			// zeros must overwrite what's there
+			break
+		}
+		// growing a file doesn't allocate what it grows by
+		if err := w.Truncate(pos + n); err != nil {
+			return fmt.Errorf("growing %q: %w", w.Name(), err)
+		}
+		_, err = w.Seek(pos+n, io.SeekStart)
+		return err
+	}
+	_, err := io.CopyN(w, zeroReader{}, n)
+	return err
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// CopySparse is `io.Copy`, leaving holes in `dst` where `src` has some.
+func CopySparse(dst io.Writer, src io.Reader) (int64, error) {
+	sr, ok := src.(SparseReader)
+	if !ok {
+		return io.Copy(dst, src)
+	}
+	var written int64
+	for {
+		data, hole, err := sr.NextHole()
+		if err != nil {
+			return written, fmt.Errorf("finding next hole: %w", err)
+		}
+		if hole == 0 {
+			// the file may have grown since its holes were found
+			n, err := io.Copy(dst, sr)
+			return written + n, err
+		}
+		n, err := io.CopyN(dst, sr, data)
+		written += n
+		if errors.Is(err, io.EOF) {
+			// the file shrank
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+		if err := sr.SkipHole(hole); err != nil {
+			return written, fmt.Errorf("skipping hole: %w", err)
+		}
+		if err := WriteHole(dst, hole); err != nil {
+			return written, fmt.Errorf("writing hole: %w", err)
+		}
+		written += hole
+	}
+}
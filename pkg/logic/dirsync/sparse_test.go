@@ -0,0 +1,65 @@
+//go:build linux || darwin
+
+package dirsync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncSparseFiles(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	const size = 64 << 20
+	writeSparse := func(tail string) {
+		f, err := os.OpenFile(filepath.Join(srcDir, "disk.img"), os.O_CREATE|os.O_WRONLY, 0644)
+		require.NoError(t, err)
+		_, err = f.WriteAt([]byte("head"), 0)
+		require.NoError(t, err)
+		_, err = f.WriteAt([]byte(tail), size-int64(len(tail)))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+	allocated := func(filename string) int64 {
+		fi, err := os.Stat(filename)
+		require.NoError(t, err)
+		return fi.Sys().(*syscall.Stat_t).Blocks * 512
+	}
+	writeSparse("tail")
+	f, err := os.Open(filepath.Join(srcDir, "disk.img"))
+	require.NoError(t, err)
+	sf, err := SparseFile(f)
+	require.NoError(t, err)
+	defer sf.Close()
+	if _, ok := sf.(SparseReader); !ok {
+		t.Skip("no holes on the filesystem of temp dirs")
+	}
+
+	stats, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+	require.Less(t, stats.LiteralBytes, uint64(1<<20))
+	want, err := os.ReadFile(filepath.Join(srcDir, "disk.img"))
+	require.NoError(t, err)
+	got, err := os.ReadFile(filepath.Join(sinkDir, "disk.img"))
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+	require.Less(t, allocated(filepath.Join(sinkDir, "disk.img")), int64(size/2))
+
+	// patches keep the holes too
+	writeSparse("TAIL")
+	stats, err = Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.FilesPatched)
+	require.Less(t, stats.LiteralBytes, uint64(1<<20))
+	want, err = os.ReadFile(filepath.Join(srcDir, "disk.img"))
+	require.NoError(t, err)
+	got, err = os.ReadFile(filepath.Join(sinkDir, "disk.img"))
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+	require.Less(t, allocated(filepath.Join(sinkDir, "disk.img")), int64(size/2))
+}
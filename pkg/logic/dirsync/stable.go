@@ -0,0 +1,104 @@
+package dirsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// errUnstable is the error of a file that changed while it was synced. The
+// ops that fail with it are skipped rather than failed, see
+// `SyncStats.Unstable`.
+var errUnstable = errors.New("file changed while it was synced")
+
+// unstableRetryWait is how long to wait before trying a file that changed
+// again, times the number of tries so far.
+const unstableRetryWait = 100 * time.Millisecond
+
+// sendStable opens the file `path` of `src` and sends it with `send`. If it's
+// no longer as it was `traced`, or it changes while it's read, it's tried
+// again up to `retries` times, as it is then. Sinks are expected not to keep
+// a file if reading it fails.
+func sendStable(ctx context.Context, src Source, path string, traced *typesv1.FileInfo, retries int, send func(fi fs.FileInfo, r io.Reader) error) error {
+	want := traced
+	for try := 1; ; try++ {
+		err := sendOnce(src, path, want, send)
+		if !errors.Is(err, errUnstable) || try > retries {
+			return err
+		}
+		// the version sent next is the one that's there once it stops
+		// changing
+		want = nil
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(try) * unstableRetryWait):
+		}
+	}
+}
+
+// sendOnce sends the file at `path`, if it's as in `want` when it's set.
+func sendOnce(src Source, path string, want *typesv1.FileInfo, send func(fi fs.FileInfo, r io.Reader) error) error {
+	f, err := src.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening %q on source: %w", path, unreadable(err))
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stating %q on source: %w", path, err)
+	}
+	if _, ok := f.(RemoteFile); ok || fi.IsDir() {
+		// what's downloaded is checked against the sum of the file instead
+		return send(fi, f)
+	}
+	seen := typesv1.FileInfoFromFS(fi)
+	if want != nil && !unchangedInfo(seen, want) {
+		return fmt.Errorf("%q: %w since it was traced", path, errUnstable)
+	}
+	return send(fi, newStableReader(f, seen))
+}
+
+// unchangedInfo tells if a file looks like it has the same content, per its
+// size and mod time.
+func unchangedInfo(a, b *typesv1.FileInfo) bool {
+	return a.Size == b.Size && proto.Equal(a.ModTime, b.ModTime)
+}
+
+// stableReader reads a file, and fails with `errUnstable` once it's all read
+// if it's no longer as it was when it was opened.
+type stableReader struct {
+	f    fs.File
+	want *typesv1.FileInfo
+	read int64
+}
+
+func newStableReader(f fs.File, want *typesv1.FileInfo) io.Reader {
+	sr := &stableReader{f: f, want: want}
+	return withHoles(sr, f, func(n int64) error {
+		sr.read += n
+		return nil
+	})
+}
+
+func (sr *stableReader) Read(p []byte) (int, error) {
+	n, err := sr.f.Read(p)
+	sr.read += int64(n)
+	if err != io.EOF {
+		return n, err
+	}
+	fi, statErr := sr.f.Stat()
+	if statErr != nil {
+		return n, fmt.Errorf("stating file after reading it: %w", statErr)
+	}
+	if sr.read != int64(sr.want.Size) || !unchangedInfo(typesv1.FileInfoFromFS(fi), sr.want) {
+		return n, fmt.Errorf("%w while it was read", errUnstable)
+	}
+	return n, err
+}
@@ -0,0 +1,123 @@
+package dirsync
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// changingSource appends to the file "log" before it's opened, or as it's
+// read, as many times as it's told to.
+type changingSource struct {
+	*LocalSource
+	t                         *testing.T
+	changeBefore, changeAfter int
+}
+
+func (src *changingSource) Open(name string) (fs.File, error) {
+	if name != "log" {
+		return src.LocalSource.Open(name)
+	}
+	if src.changeBefore > 0 {
+		src.changeBefore--
+		src.change(name)
+	}
+	f, err := src.LocalSource.Open(name)
+	if err != nil || src.changeAfter == 0 {
+		return f, err
+	}
+	src.changeAfter--
+	return &changingFile{File: f, change: func() { src.change(name) }}, nil
+}
+
+type changingFile struct {
+	fs.File
+	change func()
+}
+
+func (f *changingFile) Read(p []byte) (int, error) {
+	if f.change != nil {
+		f.change()
+		f.change = nil
+	}
+	return f.File.Read(p)
+}
+
+func (src *changingSource) change(name string) {
+	f, err := os.OpenFile(filepath.Join(src.dir, name), os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(src.t, err)
+	_, err = f.WriteString("+")
+	require.NoError(src.t, err)
+	require.NoError(src.t, f.Close())
+}
+
+func TestSyncUnstableFiles(t *testing.T) {
+	tests := []struct {
+		name                      string
+		existing                  bool
+		changeBefore, changeAfter int
+		retries                   int
+		wantUnstable              bool
+	}{
+		{name: "changed since traced", changeBefore: 1, retries: 1},
+		{name: "changed while read", changeAfter: 1, retries: 1},
+		{name: "patch changed while read", existing: true, changeAfter: 1, retries: 1},
+		{name: "no retries", changeBefore: 1, wantUnstable: true},
+		{name: "keeps changing", changeBefore: 1, changeAfter: 3, retries: 2, wantUnstable: true},
+		{name: "patch keeps changing", existing: true, changeAfter: 3, retries: 2, wantUnstable: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			srcDir, sinkDir := t.TempDir(), t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(srcDir, "stable"), []byte("stable"), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(srcDir, "log"), []byte("log"), 0644))
+			if tt.existing {
+				require.NoError(t, os.WriteFile(filepath.Join(sinkDir, "log"), []byte("old version"), 0644))
+			}
+
+			src := &changingSource{LocalSource: NewLocalSource(srcDir), t: t}
+			tree, err := TraceSource(ctx, ".", src, Params{})
+			require.NoError(t, err)
+			src.changeBefore, src.changeAfter = tt.changeBefore, tt.changeAfter
+			sigs, err := NewLocalSink(sinkDir).GetSignatures(ctx)
+			require.NoError(t, err)
+
+			sched := newScheduler(withStats(ctx, &statsRecorder{}), 1)
+			rec := statsFrom(sched.ctx)
+			emitCreate, emitPatch, emitDelete := scheduleOps(sched, src, NewLocalSink(sinkDir), Params{UnstableRetries: tt.retries})
+			err = computeTreeDiff(sched.ctx, src, tree, sigs, Params{}, emitCreate, emitPatch, emitDelete, nil)
+			require.NoError(t, awaitOps(sched, err))
+			require.NoError(t, err)
+			stats := rec.done()
+			require.Empty(t, stats.Errors)
+
+			got, err := os.ReadFile(filepath.Join(sinkDir, "stable"))
+			require.NoError(t, err)
+			require.Equal(t, "stable", string(got))
+			if tt.wantUnstable {
+				require.Equal(t, []*typesv1.Path{typesv1.PathFromString("log")}, stats.Unstable)
+				// the sink keeps what it had
+				got, err := os.ReadFile(filepath.Join(sinkDir, "log"))
+				if tt.existing {
+					require.NoError(t, err)
+					require.Equal(t, "old version", string(got))
+				} else {
+					require.ErrorIs(t, err, fs.ErrNotExist)
+				}
+				return
+			}
+			require.Empty(t, stats.Unstable)
+			want, err := os.ReadFile(filepath.Join(srcDir, "log"))
+			require.NoError(t, err)
+			got, err = os.ReadFile(filepath.Join(sinkDir, "log"))
+			require.NoError(t, err)
+			require.Equal(t, string(want), string(got))
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package dirsync
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+)
+
+// SyncStats is what a sync did, and how much of the data the rsync deltas
+// spared sending.
+type SyncStats struct {
+	FilesCreated int
+	DirsCreated  int
+	FilesPatched int
+	DirsPatched  int
+	FilesDeleted int
+	DirsDeleted  int
+	Moved        int
+
+	// BytesRead is what was read from the source, to compare, upload and
+	// patch files.
+	BytesRead uint64
+	// LiteralBytes is what was sent as is: uploads and the data of patches.
+	LiteralBytes uint64
+	// ReusedBytes is what wasn't sent, because patches refer to the blocks
+	// of the sink that already have it.
+	ReusedBytes uint64
+
+	// TraceTime is spent getting the signatures and tracing the source,
+	// before any op can be applied. When streaming, tracing goes on during
+	// the transfer and only the start of it is counted.
+	TraceTime time.Duration
+	// TransferTime is spent diffing and applying the ops.
+	TransferTime time.Duration
+
+	// Unstable are the files that kept changing while they were sent. They
+	// were skipped, and are left as they were on the sink.
+	Unstable []*typesv1.Path
+	// Skipped are the entries of the source that couldn't be synced, and
+	// are left as they were on the sink. Only the ones that the policy for
+	// them reports are, see `ErrorPolicy`.
+	Skipped []SkippedEntry
+	// NameCollisions are the entries of the source whose names collide in
+	// their dir, which can't be synced to every filesystem. They're synced
+	// nonetheless.
+	NameCollisions []NameCollision
+
+	// Errors are the ops that failed. The first failure cancels the ops
+	// that haven't completed yet, they're not counted as errors.
+	Errors []OpError
+}
+
+// OpError is an op that failed.
+type OpError struct {
+	// Op is one of "create", "patch", "delete" or "move"
+	Op   string
+	Path *typesv1.Path
+	Err  error
+}
+
+func (e OpError) Error() string {
+	return e.Op + " " + typesv1.StringFromPath(e.Path) + ": " + e.Err.Error()
+}
+
+// statsRecorder gathers the stats of a sync. It's carried by the context of
+// the sync, so that whatever reads or sends data on its behalf, sinks
+// included, can account for it.
+type statsRecorder struct {
+	start time.Time
+
+	mu    sync.Mutex
+	stats SyncStats
+	// paths of `stats.Skipped`, an entry can be skipped both when it's
+	// traced and when it's sent
+	skippedPaths map[string]bool
+}
+
+type statsKey struct{}
+
+func withStats(ctx context.Context, rec *statsRecorder) context.Context {
+	return context.WithValue(ctx, statsKey{}, rec)
+}
+
+// statsFrom returns the recorder of the sync `ctx` is for, if any. The
+// methods of a nil recorder do nothing.
+func statsFrom(ctx context.Context) *statsRecorder {
+	rec, _ := ctx.Value(statsKey{}).(*statsRecorder)
+	return rec
+}
+
+func (rec *statsRecorder) update(fn func(stats *SyncStats)) {
+	if rec == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	fn(&rec.stats)
+}
+
+// traced marks the end of the tracing, the rest of the sync is transfer.
+func (rec *statsRecorder) traced() {
+	rec.update(func(stats *SyncStats) { stats.TraceTime = time.Since(rec.start) })
+}
+
+// done returns the stats once the sync is over.
+func (rec *statsRecorder) done() *SyncStats {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	stats := rec.stats
+	if stats.TraceTime == 0 {
+		// failed while tracing
+		stats.TraceTime = time.Since(rec.start)
+	} else {
+		stats.TransferTime = time.Since(rec.start) - stats.TraceTime
+	}
+	stats.Errors = slices.Clone(rec.stats.Errors)
+	stats.Unstable = slices.Clone(rec.stats.Unstable)
+	stats.Skipped = slices.Clone(rec.stats.Skipped)
+	stats.NameCollisions = slices.Clone(rec.stats.NameCollisions)
+	return &stats
+}
+
+// unstable records the file at `path` as skipped, it kept changing.
+func (rec *statsRecorder) unstable(path *typesv1.Path) {
+	rec.update(func(stats *SyncStats) { stats.Unstable = append(stats.Unstable, path) })
+}
+
+// skipped records an entry of the source that couldn't be synced.
+func (rec *statsRecorder) skipped(entry SkippedEntry) {
+	rec.update(func(stats *SyncStats) {
+		path := typesv1.StringFromPath(entry.Path)
+		if rec.skippedPaths[path] {
+			return
+		}
+		if rec.skippedPaths == nil {
+			rec.skippedPaths = make(map[string]bool)
+		}
+		rec.skippedPaths[path] = true
+		stats.Skipped = append(stats.Skipped, entry)
+	})
+}
+
+// nameCollisions records the collisions found in a dir of the source.
+func (rec *statsRecorder) nameCollisions(collisions []NameCollision) {
+	if len(collisions) == 0 {
+		return
+	}
+	rec.update(func(stats *SyncStats) { stats.NameCollisions = append(stats.NameCollisions, collisions...) })
+}
+
+// opDone counts an op once it's applied, or its error if it failed.
+func (rec *statsRecorder) opDone(op string, path *typesv1.Path, isDir bool, err error) {
+	rec.update(func(stats *SyncStats) {
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				stats.Errors = append(stats.Errors, OpError{Op: op, Path: path, Err: err})
+			}
+			return
+		}
+		var files, dirs *int
+		switch op {
+		case "create":
+			files, dirs = &stats.FilesCreated, &stats.DirsCreated
+		case "patch":
+			files, dirs = &stats.FilesPatched, &stats.DirsPatched
+		case "delete":
+			files, dirs = &stats.FilesDeleted, &stats.DirsDeleted
+		case "move":
+			files, dirs = &stats.Moved, &stats.Moved
+		default:
+			return
+		}
+		if isDir {
+			*dirs++
+		} else {
+			*files++
+		}
+	})
+}
+
+// countReads counts what's read from a source file, as the progress of the
+// op reading it if there's one. Remote files are left as they are, they're
+// compared and patched without being read.
+func countReads(ctx context.Context, r io.Reader) io.Reader {
+	rec, op := statsFrom(ctx), opProgressFrom(ctx)
+	if rec == nil && op == nil {
+		return r
+	}
+	if _, ok := r.(RemoteFile); ok {
+		return r
+	}
+	// holes are skipped, they're not read
+	return withHoles(&countingReader{r: r, count: func(n int) {
+		rec.update(func(stats *SyncStats) { stats.BytesRead += uint64(n) })
+		op.add(n)
+	}}, r, nil)
+}
+
+// countUpload counts what's read from a source file to be uploaded, all of
+// it is sent as is.
+func countUpload(ctx context.Context, r io.Reader) io.Reader {
+	rec, op := statsFrom(ctx), opProgressFrom(ctx)
+	if rec == nil && op == nil {
+		return r
+	}
+	_, remote := r.(RemoteFile)
+	return withHoles(&countingReader{r: r, count: func(n int) {
+		rec.update(func(stats *SyncStats) {
+			if !remote {
+				stats.BytesRead += uint64(n)
+			}
+			stats.LiteralBytes += uint64(n)
+		})
+		op.add(n)
+	}}, r, nil)
+}
+
+// countRemotePatch counts what a remote file patches into `w`: what's read
+// from `orig` is reused, the rest was sent as is.
+func countRemotePatch(ctx context.Context, orig io.ReadSeeker, w io.Writer) (io.ReadSeeker, io.Writer, func()) {
+	rec, op := statsFrom(ctx), opProgressFrom(ctx)
+	if rec == nil && op == nil {
+		return orig, w, func() {}
+	}
+	var reused, written int
+	corig := &countingReadSeeker{ReadSeeker: orig, countingReader: countingReader{r: orig, count: func(n int) { reused += n }}}
+	cw := &holeWriterFunc{w: w, write: func(p []byte) (int, error) {
+		n, err := w.Write(p)
+		written += n
+		op.add(n)
+		return n, err
+	}}
+	return corig, cw, func() {
+		rec.update(func(stats *SyncStats) {
+			stats.ReusedBytes += uint64(reused)
+			stats.LiteralBytes += uint64(max(written-reused, 0))
+		})
+	}
+}
+
+// countRsync counts what a delta sends as is and what it reuses of the
+// blocks of the sink.
+func countRsync(ctx context.Context, sum *typesv1.FileSum, patchData func([]byte) (int, error), patchBlockID func(uint32) (int, error)) (func([]byte) (int, error), func(uint32) (int, error)) {
+	rec := statsFrom(ctx)
+	if rec == nil {
+		return patchData, patchBlockID
+	}
+	countedData := func(b []byte) (int, error) {
+		rec.update(func(stats *SyncStats) { stats.LiteralBytes += uint64(len(b)) })
+		return patchData(b)
+	}
+	countedBlockID := func(id uint32) (int, error) {
+		if int(id) < len(sum.SumBlocks) {
+			rec.update(func(stats *SyncStats) { stats.ReusedBytes += uint64(sum.SumBlocks[id].Size) })
+		}
+		return patchBlockID(id)
+	}
+	return countedData, countedBlockID
+}
+
+type countingReader struct {
+	r     io.Reader
+	count func(n int)
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.count(n)
+	return n, err
+}
+
+type countingReadSeeker struct {
+	io.ReadSeeker
+	countingReader
+}
+
+func (crs *countingReadSeeker) Read(p []byte) (int, error) {
+	return crs.countingReader.Read(p)
+}
+
+// holeWriterFunc writes with `write`, and holes to `w` as they are.
+type holeWriterFunc struct {
+	w     io.Writer
+	write func(p []byte) (int, error)
+}
+
+func (hw *holeWriterFunc) Write(p []byte) (int, error) { return hw.write(p) }
+func (hw *holeWriterFunc) WriteHole(n int64) error     { return WriteHole(hw.w, n) }
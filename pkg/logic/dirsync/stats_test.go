@@ -0,0 +1,89 @@
+package dirsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncStats(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	mkfile := func(root, name string, content []byte) {
+		filename := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		require.NoError(t, os.WriteFile(filename, content, 0644))
+	}
+	large := make([]byte, 64<<10)
+	rand.New(rand.NewSource(42)).Read(large)
+	edited := bytes.Clone(large)
+	copy(edited[32<<10:], "edited in the middle")
+
+	mkfile(sinkDir, "large", large)
+	mkfile(srcDir, "large", edited)
+	mkfile(srcDir, "new/file", []byte("new"))
+	mkfile(sinkDir, "gone", []byte("gone"))
+	// the file is obviously changed, it's not read to be compared
+	changed := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(srcDir, "large"), changed, changed))
+
+	stats, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.FilesCreated)
+	require.Equal(t, 1, stats.DirsCreated)
+	require.Equal(t, 1, stats.FilesPatched)
+	require.Equal(t, 1, stats.FilesDeleted)
+	require.Zero(t, stats.DirsDeleted)
+	require.Empty(t, stats.Errors)
+
+	// the large file is read to be patched, the new one to be uploaded
+	require.Equal(t, uint64(len(edited)+len("new")), stats.BytesRead)
+	// most of the large file is reused, all the bytes are accounted for
+	require.Greater(t, stats.ReusedBytes, uint64(len(large)/2))
+	require.Less(t, stats.LiteralBytes, uint64(len(large)/2))
+	require.Equal(t, uint64(len(edited)+len("new")), stats.ReusedBytes+stats.LiteralBytes)
+	require.Positive(t, stats.TraceTime)
+	require.Positive(t, stats.TransferTime)
+
+	// nothing is left to do
+	stats, err = Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{})
+	require.NoError(t, err)
+	require.Zero(t, stats.FilesCreated+stats.FilesPatched+stats.FilesDeleted)
+	require.Zero(t, stats.LiteralBytes+stats.ReusedBytes)
+}
+
+func TestSyncStatsErrors(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "file"), []byte("file"), 0644))
+
+	failing := errors.New("sink is full")
+	sink := &failingSink{Sink: NewLocalSink(sinkDir), err: failing}
+	stats, err := Sync(ctx, ".", NewLocalSource(srcDir), sink, Params{})
+	require.ErrorIs(t, err, failing)
+	require.NotNil(t, stats)
+	require.Zero(t, stats.FilesCreated)
+	require.Len(t, stats.Errors, 1)
+	require.Equal(t, "create", stats.Errors[0].Op)
+	require.Equal(t, "file", typesv1.StringFromPath(stats.Errors[0].Path))
+	require.ErrorIs(t, stats.Errors[0].Err, failing)
+}
+
+// failingSink fails to create files.
+type failingSink struct {
+	Sink
+	err error
+}
+
+func (sk *failingSink) CreateFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, r io.Reader) error {
+	return sk.err
+}
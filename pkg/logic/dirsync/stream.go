@@ -0,0 +1,252 @@
+package dirsync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+)
+
+// streamSync is `Sync` for trees too large to hold in memory. The source
+// and the sink are walked a dir at a time, and each dir is diffed as soon as
+// it's listed: memory is bounded by the width of dirs rather than by the
+// size of the tree, and ops are applied while the walk goes on.
+//
+// Without whole trees, unchanged subtrees can't be skipped by their digest
+// and moves aren't detected.
+//
+// Only the dirs in `changed` are walked into, all of them if it's nil.
+func streamSync(ctx context.Context, root string, src Source, sink Sink, params Params, changed changedDirs) error {
+	dirSink, ok := sink.(DirSink)
+	lazy, _ := sink.(LazySink)
+	if !ok {
+		// the sink's signatures are all in memory, but not the source's
+		sigs, lazyTree, err := getSignatures(ctx, sink)
+		if err != nil {
+			return fmt.Errorf("getting signatures from sink: %w", err)
+		}
+		dirSink, lazy = &tracedDirSink{Sink: sink, root: sigs}, lazyTree
+	}
+	tr, srcDir, err := startTrace(root, src, params)
+	if err != nil {
+		return fmt.Errorf("enumerating files on source: %w", err)
+	}
+	statsFrom(ctx).traced()
+
+	sched := newScheduler(ctx, params.MaxParallelFileStreams)
+	emitCreate, emitPatch, emitDelete := scheduleOps(sched, src, sink, params)
+	st := &treeStream{
+		tr:         tr,
+		sink:       dirSink,
+		lazy:       lazy,
+		params:     params,
+		changed:    changed,
+		index:      params.Index.startUpdate(),
+		emitCreate: emitCreate,
+		emitPatch:  emitPatch,
+		emitDelete: emitDelete,
+	}
+	if changed != nil {
+		st.index = params.Index.startPartialUpdate()
+	}
+	guard := newDeleteGuard(params, st.countFiles)
+	remove := guard.removeWith(params, emitDelete)
+	st.walk = dirWalk{diff: st.diffChanged, create: st.create, remove: func(ctx context.Context, op DeleteOp) error {
+		st.index.remove(op.Path)
+		return remove(ctx, op)
+	}}
+	st.walk.replace, st.walk.replaced = guard.replaceWith(emitDelete)
+
+	diffErr := st.diff(ctx, &typesv1.Path{}, srcDir, nil)
+	if diffErr == nil {
+		// the deletes are only known once the whole tree is walked, and
+		// the files of the sink once the dirs it deletes are counted too
+		diffErr = guard.release(st.sinkFiles+guard.filesInDirs(), emitDelete)
+	}
+	if err := awaitOps(sched, diffErr); err != nil {
+		return err
+	}
+	if diffErr != nil {
+		return fmt.Errorf("computing tree diff: %w", diffErr)
+	}
+	st.index.commit()
+	return nil
+}
+
+// SyncDirs is `Sync` for when only the entries of the dirs at `dirs` changed
+// since the last sync, like a watcher tells: only these dirs, and the ones on
+// the way to them, are listed and diffed on both sides rather than the whole
+// trees. The dirs that are new on the source are synced whole.
+//
+// Like streaming syncs, moves aren't detected. The deletion limits are
+// relative to the files of the dirs listed rather than to the whole sink, a
+// sync that fails with `ErrTooManyDeletes` might not with `Sync`. The
+// index, if any, only changes for the dirs listed.
+func SyncDirs(ctx context.Context, root string, src Source, sink Sink, dirs []*typesv1.Path, params Params) (*SyncStats, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	rec := &statsRecorder{start: time.Now()}
+	ctx = withObserver(withStats(ctx, rec), params.Observer)
+	sink, params = withSinkAttrs(sink, params)
+	src, err := withSinkNames(ctx, src, sink)
+	if err != nil {
+		return rec.done(), err
+	}
+	if _, ok := src.(*normalizedSource); ok {
+		dirs = normalizedPaths(dirs)
+	}
+	err = streamSync(ctx, root, src, sink, params, newChangedDirs(dirs))
+	return rec.done(), err
+}
+
+// changedDirs are the dirs that changed since the last sync, and the dirs on
+// the way to them.
+type changedDirs map[string]bool
+
+func newChangedDirs(dirs []*typesv1.Path) changedDirs {
+	changed := changedDirs{typesv1.StringFromPath(&typesv1.Path{}): true}
+	for _, dir := range dirs {
+		for i := range dir.GetElements() {
+			changed[typesv1.StringFromPath(&typesv1.Path{Elements: dir.Elements[:i+1]})] = true
+		}
+	}
+	return changed
+}
+
+// has tells if the dir at `path` is walked into, they all are if `changed`
+// is nil.
+func (changed changedDirs) has(path *typesv1.Path) bool {
+	return changed == nil || changed[typesv1.StringFromPath(path)]
+}
+
+// treeStream diffs the dirs of the source and the sink as they're listed.
+type treeStream struct {
+	tr   *sourceTracer
+	sink DirSink
+	// set if the sink lists files without their sums
+	lazy   LazySink
+	params Params
+	// the dirs to walk into, all of them if nil
+	changed changedDirs
+	index   *indexUpdate
+	walk    dirWalk
+
+	emitCreate func(CreateOp) error
+	emitPatch  func(PatchOp) error
+	emitDelete func(DeleteOp) error
+
+	// the files of the sink found in the dirs that were diffed
+	sinkFiles int
+}
+
+// diff lists the dir at `path` on both sides and diffs it, the dir found on
+// the sink is only known by its info until then.
+func (st *treeStream) diff(ctx context.Context, path *typesv1.Path, src *SourceDir, _ *typesv1.DirSum) error {
+	entries, ok, err := st.sink.GetDirSignatures(ctx, path)
+	if err != nil {
+		return fmt.Errorf("getting signatures of dir from sink: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("dir %q is gone from the sink", typesv1.StringFromPath(path))
+	}
+	if err := st.tr.listDir(ctx, src); err != nil {
+		// what's in a dir that can't be listed is left as it is
+		return skipEntry(ctx, st.params, path, err)
+	}
+	st.sinkFiles += len(entries.Files)
+	if st.lazy != nil {
+		if err := fetchFileSums(ctx, st.lazy, appendDirFileSums(nil, path, src, entries)); err != nil {
+			return fmt.Errorf("getting file sums from sink: %w", err)
+		}
+	}
+	err = computeDirDiff(ctx, st.tr.src, st.params, path, src, entries, nil, st.walk, st.emitCreate, st.emitPatch, st.emitDelete)
+	if err != nil {
+		return err
+	}
+	st.release(path, src)
+	return nil
+}
+
+// diffChanged is `diff` for the dirs that are walked into.
+func (st *treeStream) diffChanged(ctx context.Context, path *typesv1.Path, src *SourceDir, sink *typesv1.DirSum) error {
+	if !st.changed.has(path) {
+		return nil
+	}
+	return st.diff(ctx, path, src, sink)
+}
+
+func (st *treeStream) create(ctx context.Context, path *typesv1.Path, dir *SourceDir) error {
+	if err := st.emitCreate(createDirOp(path, dir)); err != nil {
+		return fmt.Errorf("emiting current dir: %w", err)
+	}
+	currentDir := typesv1.PathJoin(path, dir.Info.Name)
+	if err := st.tr.listDir(ctx, dir); err != nil {
+		return skipEntry(ctx, st.params, currentDir, err)
+	}
+	for _, file := range dir.Files {
+		if err := st.emitCreate(createFileOp(currentDir, file.Info)); err != nil {
+			return fmt.Errorf("emiting opds for file: %w", err)
+		}
+	}
+	for _, child := range dir.Dirs {
+		if err := st.create(ctx, currentDir, child); err != nil {
+			return fmt.Errorf("emiting opds for subdir: %w", err)
+		}
+	}
+	// like `emitCreateOpsForDir`, the info of the dir is applied last
+	if err := st.emitPatch(patchDirOp(path, dir.Info, &DirPatchOp{})); err != nil {
+		return fmt.Errorf("emiting patch of current dir: %w", err)
+	}
+	st.release(currentDir, dir)
+	return nil
+}
+
+// countFiles counts the files under the dir at `path` on the sink, listing
+// its subdirs.
+func (st *treeStream) countFiles(ctx context.Context, path *typesv1.Path) (int, error) {
+	entries, ok, err := st.sink.GetDirSignatures(ctx, path)
+	if err != nil {
+		return 0, fmt.Errorf("getting signatures of dir from sink: %w", err)
+	}
+	if !ok {
+		return 0, nil
+	}
+	n := len(entries.Files)
+	for _, dir := range entries.Dirs {
+		m, err := st.countFiles(ctx, typesv1.PathJoin(path, dir.Info.Name))
+		if err != nil {
+			return 0, err
+		}
+		n += m
+	}
+	return n, nil
+}
+
+// release forgets the entries of a dir once its diff is emitted, keeping
+// only what the index needs of them.
+func (st *treeStream) release(path *typesv1.Path, dir *SourceDir) {
+	st.index.list(path)
+	for _, file := range dir.Files {
+		st.index.add(typesv1.PathJoin(path, file.Info.Name), file)
+	}
+	dir.Dirs, dir.Files = nil, nil
+}
+
+// tracedDirSink serves the dirs of a sink that can only be traced at once.
+type tracedDirSink struct {
+	Sink
+	root *typesv1.DirSum
+}
+
+func (sk *tracedDirSink) GetDirSignatures(ctx context.Context, path *typesv1.Path) (*typesv1.DirSum, bool, error) {
+	dir := sk.root
+	for _, name := range path.Elements {
+		child, found := sinkHasDirNamed(dir, name)
+		if !found {
+			return nil, false, nil
+		}
+		dir = child
+	}
+	return dir, true, nil
+}
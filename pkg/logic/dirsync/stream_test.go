@@ -0,0 +1,142 @@
+package dirsync
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamingSync(t *testing.T) {
+	tests := []struct {
+		name     string
+		makeSink func(dir string) Sink
+	}{
+		{
+			name:     "dir sink",
+			makeSink: func(dir string) Sink { return NewLocalSink(dir) },
+		},
+		{
+			name: "sink traced at once",
+			makeSink: func(dir string) Sink {
+				return struct{ Sink }{NewLocalSink(dir)}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			srcDir, sinkDir := t.TempDir(), t.TempDir()
+			mkfile := func(root, name, content string) {
+				filename := filepath.Join(root, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+				require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+			}
+			mkfile(srcDir, "a/b/c/deep", "deep")
+			mkfile(srcDir, "a/one", "one")
+			mkfile(srcDir, "changed", "before")
+			mkfile(srcDir, "replaced/file", "a dir on the sink")
+			mkfile(srcDir, "excluded/keep", "not synced")
+			mkfile(sinkDir, "gone/file", "deleted")
+			mkfile(sinkDir, "changed", "after, on the sink")
+			mkfile(sinkDir, "replaced", "a file on the sink")
+			mkfile(sinkDir, "excluded/kept", "left alone")
+
+			src := NewLocalSource(srcDir)
+			sink := tt.makeSink(sinkDir)
+			idx := NewIndex()
+			params := Params{Streaming: true, Index: idx, Exclude: []string{"excluded/"}, MaxParallelFileStreams: 4}
+			_, err := Sync(ctx, ".", src, sink, params)
+			require.NoError(t, err)
+
+			require.Equal(t, tracedFiles(t, src, params), tracedFiles(t, NewLocalSource(sinkDir), params))
+			content, err := os.ReadFile(filepath.Join(sinkDir, "excluded", "kept"))
+			require.NoError(t, err)
+			require.Equal(t, "left alone", string(content))
+
+			// the index is updated like with whole trees
+			require.Equal(t, 4, idx.Len())
+			changes, err := idx.Changes(ctx, ".", src, params)
+			require.NoError(t, err)
+			require.Empty(t, changes)
+
+			// nothing is left to sync
+			recorder := &moveRecordingSink{LocalSink: NewLocalSink(sinkDir)}
+			_, err = Sync(ctx, ".", src, recorder, params)
+			require.NoError(t, err)
+			require.Empty(t, recorder.calls)
+		})
+	}
+}
+
+// tracedFiles describes the files found on `src`, by path.
+func tracedFiles(t *testing.T, src Source, params Params) map[string]string {
+	tree, err := TraceSource(context.Background(), ".", src, params)
+	require.NoError(t, err)
+	files := make(map[string]string)
+	walkSourceFiles(&typesv1.Path{}, tree, func(path *typesv1.Path, file *SourceFile) {
+		files[typesv1.StringFromPath(path)] = fmt.Sprintf("size=%d mode=%v mtime=%v", file.Info.Size, fs.FileMode(file.Info.Mode), file.Info.GetModTime().AsTime())
+	})
+	return files
+}
+
+func TestSyncDirs(t *testing.T) {
+	ctx := context.Background()
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	mkfile := func(root, name, content string) {
+		filename := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+	}
+	mkfile(srcDir, "a/b/file", "b")
+	mkfile(srcDir, "a/gone", "gone")
+	mkfile(srcDir, "other/file", "other")
+	src := NewLocalSource(srcDir)
+	idx := NewIndex()
+	params := Params{Index: idx}
+	_, err := Sync(ctx, ".", src, NewLocalSink(sinkDir), params)
+	require.NoError(t, err)
+	require.Equal(t, 3, idx.Len())
+
+	mkfile(srcDir, "a/b/file", "b, changed")
+	mkfile(srcDir, "a/b/new/file", "new")
+	require.NoError(t, os.Remove(filepath.Join(srcDir, "a", "gone")))
+	// not in the dirs that changed, it's not seen
+	mkfile(srcDir, "other/file", "other, changed")
+
+	recorder := &moveRecordingSink{LocalSink: NewLocalSink(sinkDir)}
+	_, err = SyncDirs(ctx, ".", src, recorder, []*typesv1.Path{
+		typesv1.PathFromString("a"),
+		typesv1.PathFromString("a/b"),
+	}, params)
+	require.NoError(t, err)
+
+	for _, name := range []string{"a/b/file", "a/b/new/file"} {
+		content, err := os.ReadFile(filepath.Join(srcDir, name))
+		require.NoError(t, err)
+		synced, err := os.ReadFile(filepath.Join(sinkDir, name))
+		require.NoError(t, err)
+		require.Equal(t, string(content), string(synced))
+	}
+	_, err = os.Stat(filepath.Join(sinkDir, "a", "gone"))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	content, err := os.ReadFile(filepath.Join(sinkDir, "other", "file"))
+	require.NoError(t, err)
+	require.Equal(t, "other", string(content))
+	for _, call := range recorder.calls {
+		require.NotContains(t, call, "other/")
+	}
+
+	// the files of the dirs that weren't listed stay in the index as they
+	// were
+	require.Equal(t, 3, idx.Len())
+	changes, err := idx.Changes(ctx, ".", src, params)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, "other/file", changes[0].Path)
+}
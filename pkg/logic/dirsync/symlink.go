@@ -0,0 +1,108 @@
+package dirsync
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+)
+
+// SymlinkPolicy is what to do with the symlinks found on the source.
+type SymlinkPolicy int
+
+const (
+	// SymlinkPreserve syncs symlinks as symlinks, without following them.
+	SymlinkPreserve SymlinkPolicy = iota
+	// SymlinkFollow syncs what symlinks point to, as if it was found at the
+	// place of the symlink.
+	SymlinkFollow
+	// SymlinkSkip ignores symlinks.
+	SymlinkSkip
+)
+
+func (sp SymlinkPolicy) String() string {
+	switch sp {
+	case SymlinkPreserve:
+		return "preserve"
+	case SymlinkFollow:
+		return "follow"
+	case SymlinkSkip:
+		return "skip"
+	default:
+		return fmt.Sprintf("SymlinkPolicy(%d)", int(sp))
+	}
+}
+
+// ErrSymlinkEscapesRoot is returned for symlinks with a target that isn't
+// in the tree being synced. Whatever the policy, syncing them would expose
+// files that aren't meant to be synced: they're skipped, per
+// `Params.EscapingSymlinks`.
+var ErrSymlinkEscapesRoot = errors.New("symlink target is outside of the sync root")
+
+// SymlinkSource is a `Source` that can read symlinks without following them.
+// Sources that don't implement it can only be synced with `SymlinkSkip`.
+type SymlinkSource interface {
+	Source
+	Lstat(name string) (fs.FileInfo, error)
+	ReadLink(name string) (string, error)
+}
+
+// ResolveSymlink returns the path that a symlink at `linkPath` points to,
+// both relative to the sync root. Targets that are absolute or that lead
+// outside of the root fail with `ErrSymlinkEscapesRoot`.
+//
+// The resolution is lexical: a target that goes through other symlinks is
+// only safe if those symlinks are themselves checked, which is the case for
+// all the symlinks in a synced tree.
+func ResolveSymlink(linkPath, target string) (string, error) {
+	if target == "" {
+		return "", fmt.Errorf("symlink has an empty target")
+	}
+	if path.IsAbs(target) {
+		return "", fmt.Errorf("%w: %q is absolute", ErrSymlinkEscapesRoot, target)
+	}
+	resolved := path.Join(path.Dir(linkPath), target)
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return "", fmt.Errorf("%w: %q", ErrSymlinkEscapesRoot, target)
+	}
+	return resolved, nil
+}
+
+// readSymlink returns the info of the symlink at `name`, with its target.
+func readSymlink(src Source, name string) (*typesv1.FileInfo, error) {
+	lsrc, ok := src.(SymlinkSource)
+	if !ok {
+		return nil, fmt.Errorf("source can't read symlinks, they must be skipped")
+	}
+	fi, err := lsrc.Lstat(name)
+	if err != nil {
+		return nil, fmt.Errorf("lstating %q: %w", name, err)
+	}
+	if fi.Mode()&fs.ModeSymlink == 0 {
+		return nil, fmt.Errorf("%q is not a symlink anymore", name)
+	}
+	target, err := lsrc.ReadLink(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading symlink %q: %w", name, err)
+	}
+	info := typesv1.FileInfoFromFS(fi)
+	info.LinkTarget = target
+	return info, nil
+}
+
+// isSymlinkLoop tells if following a symlink to the dir at `target` would
+// lead back into one of the `parents` being traced.
+func isSymlinkLoop(parents []string, target string) bool {
+	if target == "." {
+		return true
+	}
+	for _, parent := range parents {
+		if parent == target || strings.HasPrefix(parent, target+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package dirsync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSymlink(t *testing.T) {
+	tests := []struct {
+		name     string
+		linkPath string
+		target   string
+		want     string
+		wantErr  error
+	}{
+		{name: "sibling", linkPath: "a/link", target: "file", want: "a/file"},
+		{name: "parent", linkPath: "a/link", target: "../file", want: "file"},
+		{name: "dir", linkPath: "a/b/link", target: "../../c/d", want: "c/d"},
+		{name: "root", linkPath: "a/link", target: "..", want: "."},
+		{name: "escapes", linkPath: "a/link", target: "../../file", wantErr: ErrSymlinkEscapesRoot},
+		{name: "escapes and comes back", linkPath: "link", target: "../root/file", wantErr: ErrSymlinkEscapesRoot},
+		{name: "absolute", linkPath: "a/link", target: "/etc/passwd", wantErr: ErrSymlinkEscapesRoot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveSymlink(tt.linkPath, tt.target)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTraceSourceSymlinks(t *testing.T) {
+	root := t.TempDir()
+	mkfile := func(name, content string) {
+		filename := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+	}
+	mkfile("bin/tool", "#!/bin/sh")
+	mkfile("lib/lib.so", "elf")
+	require.NoError(t, os.Symlink("bin/tool", filepath.Join(root, "tool")))
+	require.NoError(t, os.Symlink("../lib", filepath.Join(root, "bin/lib")))
+
+	// names of the files and dirs found, with the target of symlinks
+	list := func(dir *SourceDir) []string {
+		var out []string
+		var walk func(prefix string, dir *SourceDir)
+		walk = func(prefix string, dir *SourceDir) {
+			for _, child := range dir.Dirs {
+				out = append(out, prefix+child.Info.Name+"/")
+				walk(prefix+child.Info.Name+"/", child)
+			}
+			for _, file := range dir.Files {
+				if file.Info.IsSymlink() {
+					out = append(out, prefix+file.Info.Name+" -> "+file.Info.LinkTarget)
+				} else {
+					out = append(out, prefix+file.Info.Name)
+				}
+			}
+		}
+		walk("", dir)
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		policy SymlinkPolicy
+		want   []string
+	}{
+		{
+			name:   "preserve",
+			policy: SymlinkPreserve,
+			want:   []string{"bin/", "bin/lib -> ../lib", "bin/tool", "lib/", "lib/lib.so", "tool -> bin/tool"},
+		},
+		{
+			name:   "follow",
+			policy: SymlinkFollow,
+			want:   []string{"bin/", "bin/lib/", "bin/lib/lib.so", "bin/tool", "lib/", "lib/lib.so", "tool"},
+		},
+		{
+			name:   "skip",
+			policy: SymlinkSkip,
+			want:   []string{"bin/", "bin/tool", "lib/", "lib/lib.so"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			got, err := TraceSource(ctx, ".", NewLocalSource(root), Params{Symlinks: tt.policy})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, list(got))
+		})
+	}
+}
+
+func TestTraceSourceRejectsSymlinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		policy  SymlinkPolicy
+		wantErr string
+	}{
+		{name: "escaping preserved", target: "../../outside", policy: SymlinkPreserve, wantErr: ErrSymlinkEscapesRoot.Error()},
+		{name: "escaping followed", target: "/etc", policy: SymlinkFollow, wantErr: ErrSymlinkEscapesRoot.Error()},
+		{name: "loop followed", target: "..", policy: SymlinkFollow, wantErr: "loops back"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			require.NoError(t, os.Mkdir(filepath.Join(root, "dir"), 0755))
+			require.NoError(t, os.Symlink(tt.target, filepath.Join(root, "dir", "link")))
+
+			ctx := context.Background()
+			_, err := TraceSource(ctx, ".", NewLocalSource(root), Params{Symlinks: tt.policy, EscapingSymlinks: ErrorFail})
+			require.ErrorContains(t, err, tt.wantErr)
+
+			// skipped symlinks are never looked at
+			_, err = TraceSource(ctx, ".", NewLocalSource(root), Params{Symlinks: SymlinkSkip})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSyncEscapingSymlinks(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    SymlinkPolicy
+		escaping  ErrorPolicy
+		streaming bool
+		// the sync fails, or reports the symlinks it skipped
+		wantErr     bool
+		wantSkipped []string
+	}{
+		{name: "skip preserved", escaping: ErrorSkip, wantSkipped: []string{"dir/absolute", "dir/outside"}},
+		{name: "skip followed", policy: SymlinkFollow, escaping: ErrorSkip, wantSkipped: []string{"dir/absolute", "dir/outside"}},
+		{name: "skip streaming", escaping: ErrorSkip, streaming: true, wantSkipped: []string{"dir/absolute", "dir/outside"}},
+		{name: "ignore", escaping: ErrorIgnore},
+		{name: "fail", escaping: ErrorFail, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srcDir, sinkDir := t.TempDir(), t.TempDir()
+			require.NoError(t, os.Mkdir(filepath.Join(srcDir, "dir"), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(srcDir, "dir", "file"), []byte("hello"), 0644))
+			require.NoError(t, os.Symlink("../../outside", filepath.Join(srcDir, "dir", "outside")))
+			require.NoError(t, os.Symlink("/etc", filepath.Join(srcDir, "dir", "absolute")))
+
+			ctx := context.Background()
+			params := Params{Symlinks: tt.policy, EscapingSymlinks: tt.escaping, Streaming: tt.streaming}
+			stats, err := Sync(ctx, ".", NewLocalSource(srcDir), NewLocalSink(sinkDir), params)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrSymlinkEscapesRoot)
+				return
+			}
+			require.NoError(t, err)
+			var skipped []string
+			for _, entry := range stats.Skipped {
+				require.Equal(t, SkipEscapingSymlink, entry.Reason)
+				skipped = append(skipped, typesv1.StringFromPath(entry.Path))
+			}
+			slices.Sort(skipped)
+			require.Equal(t, tt.wantSkipped, skipped)
+
+			// the rest of the tree is synced, without the symlinks
+			content, err := os.ReadFile(filepath.Join(sinkDir, "dir", "file"))
+			require.NoError(t, err)
+			require.Equal(t, "hello", string(content))
+			entries, err := os.ReadDir(filepath.Join(sinkDir, "dir"))
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+		})
+	}
+}
+
+func TestSyncSymlinks(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file"), []byte("hello"), 0644))
+	require.NoError(t, os.Symlink("file", filepath.Join(root, "same")))
+	require.NoError(t, os.Symlink("file", filepath.Join(root, "retargeted")))
+	require.NoError(t, os.Symlink("file", filepath.Join(root, "was_a_file")))
+
+	src := NewLocalSource(root)
+	ctx := context.Background()
+	traced, err := TraceSource(ctx, ".", src, Params{})
+	require.NoError(t, err)
+	sigs := sinkFromSource(nil, traced)
+	for _, file := range sigs.Files {
+		switch file.Info.Name {
+		case "retargeted":
+			file.Info.LinkTarget = "other"
+		case "was_a_file":
+			file.Info.Mode = 0644
+			file.Info.LinkTarget = ""
+		}
+	}
+	rehashSink(sigs)
+
+	sink := &recordingSink{sigs: sigs}
+	_, err = Sync(ctx, ".", src, sink, Params{})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		"patch retargeted",
+		"delete was_a_file",
+		"create was_a_file",
+		// has no sums on this sink
+		"patch file",
+	}, sink.calls)
+}
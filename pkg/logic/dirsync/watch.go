@@ -0,0 +1,203 @@
+package dirsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+)
+
+var (
+	// errWatchUnsupported is the error of watching a dir on a platform that
+	// can't.
+	errWatchUnsupported = errors.New("watching dirs isn't supported on this platform")
+	// errWatchLimit is the error of watching more dirs than the system
+	// allows.
+	errWatchLimit = errors.New("out of watches, raise fs.inotify.max_user_watches to watch the whole tree")
+)
+
+// watchEvent is what a `dirWatcher` saw.
+type watchEvent struct {
+	// Dir is the dir whose entries changed.
+	Dir *typesv1.Path
+	// Rescan is set when changes were missed, the whole tree has to be
+	// synced.
+	Rescan bool
+	// Err is set if the watcher stopped, it sends nothing after.
+	Err error
+}
+
+// dirWatcher watches the dirs of a tree for changes.
+type dirWatcher interface {
+	Events() <-chan watchEvent
+	Close() error
+}
+
+// WatchParams are the params of `Watch`, on top of the ones of the syncs it
+// does.
+type WatchParams struct {
+	// Debounce is how long changes have to settle before they're synced, a
+	// burst of changes is synced at once. Changes that don't settle are
+	// synced after `maxDebounce` times as long.
+	Debounce time.Duration
+	// ScanInterval is how often the whole tree is synced when it can't be
+	// watched.
+	ScanInterval time.Duration
+	// MinBackoff is how long to wait before a sync that failed is tried
+	// again, doubling every time it fails again up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// OnPass, if set, is told about each sync once it's done.
+	OnPass func(WatchPass)
+	// OnScan, if set, is told why the tree is scanned periodically rather
+	// than watched.
+	OnScan func(err error)
+}
+
+// maxDebounce bounds how long changes that don't settle wait, in `Debounce`s.
+const maxDebounce = 10
+
+func (wp WatchParams) withDefaults() WatchParams {
+	if wp.Debounce <= 0 {
+		wp.Debounce = time.Second
+	}
+	if wp.ScanInterval <= 0 {
+		wp.ScanInterval = time.Minute
+	}
+	if wp.MinBackoff <= 0 {
+		wp.MinBackoff = time.Second
+	}
+	if wp.MaxBackoff < wp.MinBackoff {
+		wp.MaxBackoff = max(wp.MinBackoff, 5*time.Minute)
+	}
+	return wp
+}
+
+// WatchPass is a sync done by `Watch`.
+type WatchPass struct {
+	// Full is set if the whole tree was synced, rather than the dirs that
+	// changed.
+	Full bool
+	// Dirs are the dirs that changed, if the pass isn't full.
+	Dirs  []*typesv1.Path
+	Stats *SyncStats
+	Err   error
+	// Retry is how long until a pass that failed is tried again.
+	Retry time.Duration
+}
+
+// Watch keeps `sink` like the local dir `dir` until `ctx` is done. The
+// whole tree is synced first, then the dirs that change as they're seen
+// changing, with `SyncDirs`. If the tree can't be watched, it's synced whole
+// every `WatchParams.ScanInterval` instead. Syncs that fail, like when the
+// sink is unreachable, are tried again with a backoff. A sync of the dirs
+// that changed that would delete too much is tried again as a full one, see
+// `SyncDirs`.
+func Watch(ctx context.Context, dir string, sink Sink, params Params, wparams WatchParams) error {
+	w, err := newDirWatcher(dir)
+	switch {
+	case errors.Is(err, errWatchUnsupported), errors.Is(err, errWatchLimit):
+		w = nil
+		if wparams.OnScan != nil {
+			wparams.OnScan(err)
+		}
+	case err != nil:
+		return fmt.Errorf("watching %q: %w", dir, err)
+	}
+	return watch(ctx, NewLocalSource(dir), sink, params, wparams.withDefaults(), w)
+}
+
+// watch is `Watch` with the changes seen by `w`, the tree is scanned if it's
+// nil.
+func watch(ctx context.Context, src Source, sink Sink, params Params, wparams WatchParams, w dirWatcher) error {
+	var events <-chan watchEvent
+	if w != nil {
+		defer w.Close()
+		events = w.Events()
+	}
+	// the first pass syncs the whole tree
+	full := true
+	changed := make(map[string]*typesv1.Path)
+	var (
+		since   time.Time
+		backoff time.Duration
+	)
+	due := time.NewTimer(0)
+	defer due.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev := <-events:
+			switch {
+			case ev.Err != nil:
+				_ = w.Close()
+				w, events = nil, nil
+				if wparams.OnScan != nil {
+					wparams.OnScan(ev.Err)
+				}
+				// what changed since it stopped is unknown
+				full = true
+			case ev.Rescan:
+				full = true
+			default:
+				changed[typesv1.StringFromPath(ev.Dir)] = ev.Dir
+			}
+			if backoff != 0 {
+				// the pass that failed is tried again with these changes
+				continue
+			}
+			if since.IsZero() {
+				since = time.Now()
+			}
+			wait := min(wparams.Debounce, time.Until(since.Add(maxDebounce*wparams.Debounce)))
+			resetTimer(due, max(wait, 0))
+		case <-due.C:
+			pass := WatchPass{Full: full || w == nil}
+			if pass.Full {
+				pass.Stats, pass.Err = Sync(ctx, ".", src, sink, params)
+			} else {
+				for _, dir := range changed {
+					pass.Dirs = append(pass.Dirs, dir)
+				}
+				pass.Stats, pass.Err = SyncDirs(ctx, ".", src, sink, pass.Dirs, params)
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			switch {
+			case !pass.Full && errors.Is(pass.Err, ErrTooManyDeletes):
+				// the limits of a pass that isn't full are relative to the
+				// dirs it listed, only a full one tells
+				full = true
+				resetTimer(due, 0)
+			case pass.Err != nil:
+				backoff = min(max(2*backoff, wparams.MinBackoff), wparams.MaxBackoff)
+				pass.Retry = backoff
+				resetTimer(due, backoff)
+			default:
+				full, backoff, since = false, 0, time.Time{}
+				clear(changed)
+				if w == nil {
+					resetTimer(due, wparams.ScanInterval)
+				}
+			}
+			if wparams.OnPass != nil {
+				wparams.OnPass(pass)
+			}
+		}
+	}
+}
+
+// resetTimer resets `t` to fire in `d`, whether it fired or not.
+func resetTimer(t *time.Timer, d time.Duration) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	t.Reset(d)
+}
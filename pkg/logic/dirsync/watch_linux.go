@@ -0,0 +1,177 @@
+//go:build linux
+
+package dirsync
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+
+	"golang.org/x/sys/unix"
+)
+
+// the changes to the entries of a dir, the dir itself is watched in its
+// parent
+const inotifyMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY | unix.IN_ATTRIB |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_ONLYDIR | unix.IN_EXCL_UNLINK
+
+// inotifyWatcher watches the dirs of a tree with inotify, one watch per dir.
+// The dirs that are added to the tree are watched as they're seen.
+type inotifyWatcher struct {
+	root   string
+	fd     int
+	f      *os.File
+	events chan watchEvent
+	done   chan struct{}
+	closed sync.Once
+
+	// the dirs watched, by watch descriptor, only used by `run` once it
+	// started
+	dirs map[int32]*typesv1.Path
+}
+
+func newDirWatcher(root string) (dirWatcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("initializing inotify: %w", err)
+	}
+	w := &inotifyWatcher{
+		root: root,
+		fd:   fd,
+		// non-blocking, for reads to be interrupted when it's closed
+		f:      os.NewFile(uintptr(fd), "inotify"),
+		events: make(chan watchEvent),
+		done:   make(chan struct{}),
+		dirs:   make(map[int32]*typesv1.Path),
+	}
+	if _, err := w.addTree(&typesv1.Path{}); err != nil {
+		_ = w.f.Close()
+		return nil, err
+	}
+	go w.run()
+	return w, nil
+}
+
+func (w *inotifyWatcher) Events() <-chan watchEvent { return w.events }
+
+func (w *inotifyWatcher) Close() error {
+	var err error
+	w.closed.Do(func() {
+		close(w.done)
+		err = w.f.Close()
+	})
+	return err
+}
+
+// addTree watches the dir at `path` and its subdirs, and returns them.
+func (w *inotifyWatcher) addTree(path *typesv1.Path) ([]*typesv1.Path, error) {
+	base := filepath.Join(w.root, typesv1.StringFromPath(path))
+	var added []*typesv1.Path
+	err := filepath.WalkDir(base, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// dirs that are gone or can't be read aren't watched
+			if (name != base || len(path.GetElements()) > 0) && (errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission)) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		wd, err := unix.InotifyAddWatch(w.fd, name, inotifyMask)
+		switch {
+		case errors.Is(err, unix.ENOSPC):
+			return errWatchLimit
+		case errors.Is(err, unix.ENOENT), errors.Is(err, unix.EACCES):
+			return fs.SkipDir
+		case err != nil:
+			return fmt.Errorf("watching %q: %w", name, err)
+		}
+		rel, err := filepath.Rel(w.root, name)
+		if err != nil {
+			return err
+		}
+		dir := &typesv1.Path{}
+		if rel != "." {
+			dir = typesv1.PathFromString(filepath.ToSlash(rel))
+		}
+		w.dirs[int32(wd)] = dir
+		added = append(added, dir)
+		return nil
+	})
+	return added, err
+}
+
+func (w *inotifyWatcher) run() {
+	buf := make([]byte, 64<<10)
+	for {
+		n, err := w.f.Read(buf)
+		if errors.Is(err, os.ErrClosed) {
+			return
+		}
+		if err != nil {
+			w.send(watchEvent{Err: fmt.Errorf("reading inotify events: %w", err)})
+			return
+		}
+		for off := 0; off+unix.SizeofInotifyEvent <= n; {
+			wd := int32(binary.NativeEndian.Uint32(buf[off:]))
+			mask := binary.NativeEndian.Uint32(buf[off+4:])
+			size := int(binary.NativeEndian.Uint32(buf[off+12:]))
+			off += unix.SizeofInotifyEvent
+			name := strings.TrimRight(string(buf[off:off+size]), "\x00")
+			off += size
+			for _, ev := range w.handle(wd, mask, name) {
+				if !w.send(ev) || ev.Err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// handle is what the event `mask` about the entry `name` of the dir watched
+// by `wd` means.
+func (w *inotifyWatcher) handle(wd int32, mask uint32, name string) []watchEvent {
+	if mask&unix.IN_Q_OVERFLOW != 0 {
+		return []watchEvent{{Rescan: true}}
+	}
+	dir, ok := w.dirs[wd]
+	if !ok {
+		return nil
+	}
+	if mask&unix.IN_IGNORED != 0 {
+		delete(w.dirs, wd)
+		return nil
+	}
+	if name == "" {
+		return nil
+	}
+	events := []watchEvent{{Dir: dir}}
+	if mask&unix.IN_ISDIR != 0 && mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 {
+		// what's in a dir that's moved in hasn't been seen either
+		added, err := w.addTree(typesv1.PathJoin(dir, name))
+		for _, child := range added {
+			events = append(events, watchEvent{Dir: child})
+		}
+		if err != nil {
+			events = append(events, watchEvent{Err: err})
+		}
+	}
+	return events
+}
+
+func (w *inotifyWatcher) send(ev watchEvent) bool {
+	select {
+	case w.events <- ev:
+		return true
+	case <-w.done:
+		return false
+	}
+}
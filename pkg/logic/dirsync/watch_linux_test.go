@@ -0,0 +1,56 @@
+//go:build linux
+
+package dirsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInotifyWatcher(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "a"), 0755))
+	w, err := newDirWatcher(root)
+	require.NoError(t, err)
+	defer w.Close()
+
+	// waits for the events of the dir at `want`, and returns the dirs seen
+	// changing until then
+	seen := func(want string) map[string]bool {
+		dirs := make(map[string]bool)
+		timeout := time.After(5 * time.Second)
+		for !dirs[want] {
+			select {
+			case ev := <-w.Events():
+				require.NoError(t, ev.Err)
+				require.False(t, ev.Rescan)
+				dirs[typesv1.StringFromPath(ev.Dir)] = true
+			case <-timeout:
+				t.Fatalf("no event for %q, saw %v", want, dirs)
+			}
+		}
+		return dirs
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", "file"), []byte("content"), 0644))
+	seen("a")
+
+	// dirs that are moved in are watched, with what's in them
+	outside := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(outside, "b", "c"), 0755))
+	require.NoError(t, os.Rename(filepath.Join(outside, "b"), filepath.Join(root, "a", "b")))
+	dirs := seen("a/b/c")
+	require.True(t, dirs["a"])
+	require.True(t, dirs["a/b"])
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", "b", "c", "file"), []byte("content"), 0644))
+	seen("a/b/c")
+
+	require.NoError(t, os.Remove(filepath.Join(root, "a", "file")))
+	seen("a")
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package dirsync
+
+func newDirWatcher(root string) (dirWatcher, error) {
+	return nil, errWatchUnsupported
+}
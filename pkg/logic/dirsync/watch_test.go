@@ -0,0 +1,224 @@
+package dirsync
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	tests := []struct {
+		name string
+		// what the watcher sees once the first pass is done
+		events []watchEvent
+		// how many passes of each kind are done
+		wantFull, wantDirs int
+		// the dirs synced by the passes that aren't full
+		wantSynced []string
+	}{
+		{
+			name:       "changes are synced at once",
+			events:     []watchEvent{{Dir: typesv1.PathFromString("a")}, {Dir: typesv1.PathFromString("a")}, {Dir: &typesv1.Path{}}},
+			wantFull:   1,
+			wantDirs:   1,
+			wantSynced: []string{"a", ""},
+		},
+		{
+			name:     "changes are missed",
+			events:   []watchEvent{{Dir: typesv1.PathFromString("a")}, {Rescan: true}},
+			wantFull: 2,
+		},
+		{
+			name:     "out of watches",
+			events:   []watchEvent{{Err: errWatchLimit}},
+			wantFull: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srcDir, sinkDir := t.TempDir(), t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "a"), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a", "file"), []byte("before"), 0644))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			w := &fakeWatcher{events: make(chan watchEvent)}
+			passes := make(chan WatchPass, 10)
+			var scanErr error
+			wparams := WatchParams{
+				Debounce:     50 * time.Millisecond,
+				ScanInterval: time.Hour,
+				OnPass:       func(pass WatchPass) { passes <- pass },
+				OnScan:       func(err error) { scanErr = err },
+			}
+			done := make(chan error)
+			go func() {
+				done <- watch(ctx, NewLocalSource(srcDir), NewLocalSink(sinkDir), Params{}, wparams.withDefaults(), w)
+			}()
+
+			full, dirs := 0, 0
+			var synced []string
+			countPass := func() {
+				pass := <-passes
+				require.NoError(t, pass.Err)
+				if pass.Full {
+					full++
+					return
+				}
+				dirs++
+				for _, dir := range pass.Dirs {
+					synced = append(synced, typesv1.StringFromPath(dir))
+				}
+			}
+			countPass()
+
+			require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a", "file"), []byte("after"), 0644))
+			for _, ev := range tt.events {
+				w.events <- ev
+			}
+			for full+dirs < tt.wantFull+tt.wantDirs {
+				countPass()
+			}
+			cancel()
+			require.NoError(t, <-done)
+			require.Equal(t, tt.wantFull, full)
+			require.Equal(t, tt.wantDirs, dirs)
+			require.ElementsMatch(t, tt.wantSynced, synced)
+			content, err := os.ReadFile(filepath.Join(sinkDir, "a", "file"))
+			require.NoError(t, err)
+			require.Equal(t, "after", string(content))
+			if tt.events[len(tt.events)-1].Err != nil {
+				require.ErrorIs(t, scanErr, errWatchLimit)
+				require.True(t, w.isClosed())
+			}
+		})
+	}
+}
+
+func TestWatchRetries(t *testing.T) {
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "file"), []byte("content"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sink := &outageSink{LocalSink: NewLocalSink(sinkDir), failures: 2}
+	passes := make(chan WatchPass, 10)
+	wparams := WatchParams{
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 3 * time.Millisecond,
+		OnPass:     func(pass WatchPass) { passes <- pass },
+	}
+	done := make(chan error)
+	go func() {
+		done <- watch(ctx, NewLocalSource(srcDir), sink, Params{}, wparams.withDefaults(), nil)
+	}()
+
+	var retries []time.Duration
+	for pass := range passes {
+		require.True(t, pass.Full)
+		if pass.Err == nil {
+			break
+		}
+		require.ErrorIs(t, pass.Err, errOutage)
+		retries = append(retries, pass.Retry)
+	}
+	cancel()
+	require.NoError(t, <-done)
+	require.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, retries)
+	content, err := os.ReadFile(filepath.Join(sinkDir, "file"))
+	require.NoError(t, err)
+	require.Equal(t, "content", string(content))
+}
+
+// fakeWatcher sees the events sent to it.
+type fakeWatcher struct {
+	events chan watchEvent
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (w *fakeWatcher) Events() <-chan watchEvent { return w.events }
+
+func (w *fakeWatcher) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	return nil
+}
+
+func (w *fakeWatcher) isClosed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.closed
+}
+
+var errOutage = errors.New("sink is unreachable")
+
+// outageSink can't be reached the first `failures` times it's synced with.
+type outageSink struct {
+	*LocalSink
+
+	mu       sync.Mutex
+	failures int
+}
+
+func (sk *outageSink) GetSignatures(ctx context.Context) (*typesv1.DirSum, error) {
+	sk.mu.Lock()
+	defer sk.mu.Unlock()
+	if sk.failures > 0 {
+		sk.failures--
+		return nil, errOutage
+	}
+	return sk.LocalSink.GetSignatures(ctx)
+}
+
+func TestWatchDeleteGuard(t *testing.T) {
+	srcDir, sinkDir := t.TempDir(), t.TempDir()
+	for _, name := range []string{"a/1", "a/2", "a/3", "z/b", "z/c", "z/d", "z/e"} {
+		filename := filepath.Join(srcDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		require.NoError(t, os.WriteFile(filename, []byte(name), 0644))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := &fakeWatcher{events: make(chan watchEvent)}
+	passes := make(chan WatchPass, 10)
+	wparams := WatchParams{
+		Debounce: 10 * time.Millisecond,
+		OnPass:   func(pass WatchPass) { passes <- pass },
+	}
+	params := Params{MaxDeletePercent: 50}
+	done := make(chan error)
+	go func() {
+		done <- watch(ctx, NewLocalSource(srcDir), NewLocalSink(sinkDir), params, wparams.withDefaults(), w)
+	}()
+	pass := <-passes
+	require.True(t, pass.Full)
+	require.NoError(t, pass.Err)
+
+	// 2 of the 3 files of the dir, but 2 of the 7 of the sink
+	require.NoError(t, os.Remove(filepath.Join(srcDir, "a", "1")))
+	require.NoError(t, os.Remove(filepath.Join(srcDir, "a", "2")))
+	w.events <- watchEvent{Dir: typesv1.PathFromString("a")}
+
+	pass = <-passes
+	require.False(t, pass.Full)
+	require.ErrorIs(t, pass.Err, ErrTooManyDeletes)
+	require.Zero(t, pass.Retry)
+	pass = <-passes
+	require.True(t, pass.Full)
+	require.NoError(t, pass.Err)
+	cancel()
+	require.NoError(t, <-done)
+
+	require.ElementsMatch(t, []string{"a/3", "z/b", "z/c", "z/d", "z/e"}, mapKeys(tracedFiles(t, NewLocalSource(sinkDir), Params{})))
+}
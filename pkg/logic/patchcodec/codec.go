@@ -11,6 +11,7 @@ import (
 // a uint64 header.
 // if header <= max_uint32, uint32(header)==block ID
 // if header >  max_uint32, header-max_uint32 == len(data), followed by data
+// if header >  2*max_uint32, header-2*max_uint32 == size of a hole
 
 type Encoder struct {
 	w      io.Writer
@@ -42,6 +43,15 @@ func (enc *Encoder) WriteBlock(data []byte) (int, error) {
 	return 8 + len(data), enc.err
 }
 
+func (enc *Encoder) WriteHole(size uint64) (int, error) {
+	if size == 0 || size > math.MaxUint64-2*math.MaxUint32 {
+		return -1, fmt.Errorf("invalid hole size: %d", size)
+	}
+	binary.LittleEndian.PutUint64(enc.header, size+2*math.MaxUint32)
+	_, enc.err = enc.w.Write(enc.header) // n <- uint64 == 8 bytes
+	return 8, enc.err
+}
+
 type Decoder struct {
 	r         io.Reader
 	headerBuf []byte
@@ -54,6 +64,7 @@ func NewDecoder(r io.Reader) *Decoder {
 func (dec *Decoder) Decode(
 	onBlockID func(uint32) (int, error),
 	onData func(io.Reader) (int, error),
+	onHole func(uint64) (int, error),
 ) (int, error) {
 	var (
 		header   uint64
@@ -79,6 +90,14 @@ func (dec *Decoder) Decode(
 			}
 			continue
 		}
+		if header > 2*uint64(math.MaxUint32) {
+			n, err = onHole(header - 2*uint64(math.MaxUint32))
+			written += n
+			if err != nil {
+				return written, err
+			}
+			continue
+		}
 		blocklen = int64(header - uint64(math.MaxUint32))
 		n, err = onData(io.LimitReader(dec.r, blocklen))
 		written += n
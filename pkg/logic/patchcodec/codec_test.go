@@ -3,6 +3,7 @@ package patchcodec
 import (
 	"bytes"
 	"io"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -22,6 +23,15 @@ func TestCodec(t *testing.T) {
 				uint32(4),
 			},
 		},
+		{
+			name: "holes",
+			want: []any{
+				"hello",
+				uint64(1 << 40),
+				uint32(math.MaxUint32),
+				uint64(1),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -38,6 +48,10 @@ func TestCodec(t *testing.T) {
 					_, err := enc.WriteBlock([]byte(p))
 					require.NoError(t, err)
 					wantN += len(p)
+				case uint64:
+					_, err := enc.WriteHole(p)
+					require.NoError(t, err)
+					wantN += 8
 				}
 			}
 			wantBytes := w.String()
@@ -53,6 +67,10 @@ func TestCodec(t *testing.T) {
 					got = append(got, string(block))
 					return len(block), nil
 				},
+				func(size uint64) (int, error) {
+					got = append(got, size)
+					return 8, nil
+				},
 			)
 			require.NoError(t, err)
 			require.Equal(t, wantN, gotN)
@@ -70,6 +88,9 @@ func TestCodec(t *testing.T) {
 				case string:
 					_, err := enc.WriteBlock([]byte(p))
 					require.NoError(t, err)
+				case uint64:
+					_, err := enc.WriteHole(p)
+					require.NoError(t, err)
 				}
 			}
 			gotBytes := w.String()
@@ -2,9 +2,13 @@ package syncclient
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log/slog"
+	"sort"
+	"sync"
 
 	"connectrpc.com/connect"
 	syncv1 "github.com/aybabtme/syncy/pkg/gen/svc/sync/v1"
@@ -14,13 +18,26 @@ import (
 	"lukechampine.com/blake3"
 )
 
-var _ dirsync.Sink = (*Sink)(nil)
+var (
+	_ dirsync.Sink     = (*Sink)(nil)
+	_ dirsync.MoveSink = (*Sink)(nil)
+	_ dirsync.DirSink  = (*Sink)(nil)
+	_ dirsync.LazySink = (*Sink)(nil)
+
+	_ dirsync.ResumableSink   = (*Sink)(nil)
+	_ dirsync.NormalizingSink = (*Sink)(nil)
+)
 
 type Sink struct {
 	ll              *slog.Logger
 	client          syncv1connect.SyncServiceClient
 	createBlockSize uint
 	meta            *typesv1.ReqMeta
+
+	// the dir of the project that's synced, see `Under`
+	root     *typesv1.Path
+	rootMu   sync.Mutex
+	rootMade bool
 }
 
 const minCreateBlockSize = 10 * 1 << 10
@@ -32,6 +49,20 @@ func ClientAdapter(ll *slog.Logger, client syncv1connect.SyncServiceClient, meta
 	return &Sink{ll: ll, client: client, createBlockSize: createBlockSize, meta: meta}, nil
 }
 
+// Under is a sink for the dir at `root` of the project, rather than for the
+// whole project: only what's under `root` is compared and deleted. The dir
+// and its parents are made when the first file is created, if they're
+// missing.
+func (sk *Sink) Under(root *typesv1.Path) *Sink {
+	return &Sink{
+		ll:              sk.ll.With(slog.String("root", typesv1.StringFromPath(root))),
+		client:          sk.client,
+		createBlockSize: sk.createBlockSize,
+		meta:            sk.meta,
+		root:            root,
+	}
+}
+
 func (sk *Sink) GetSignatures(ctx context.Context) (*typesv1.DirSum, error) {
 	res, err := sk.client.GetSignature(ctx, connect.NewRequest(&syncv1.GetSignatureRequest{
 		Meta: sk.meta,
@@ -39,14 +70,134 @@ func (sk *Sink) GetSignatures(ctx context.Context) (*typesv1.DirSum, error) {
 	if err != nil {
 		return nil, err
 	}
-	return res.Msg.GetRoot(), nil
+	return sk.subtree(res.Msg.GetRoot()), nil
+}
+
+// ReportsProgress is true, the progress of uploads is what's sent of them.
+func (sk *Sink) ReportsProgress() bool { return true }
+
+// NormalizesNames tells if the project stores names in NFC form.
+func (sk *Sink) NormalizesNames(ctx context.Context) (bool, error) {
+	res, err := sk.client.GetProject(ctx, connect.NewRequest(&syncv1.GetProjectRequest{
+		Meta: sk.meta,
+	}))
+	if err != nil {
+		return false, fmt.Errorf("getting project: %w", err)
+	}
+	return res.Msg.NamePolicy == syncv1.NamePolicy_normalize, nil
+}
+
+func (sk *Sink) GetDirSignatures(ctx context.Context, path *typesv1.Path) (*typesv1.DirSum, bool, error) {
+	res, err := sk.client.ListDir(ctx, connect.NewRequest(&syncv1.ListDirRequest{
+		Meta: sk.meta,
+		Path: sk.remote(path),
+	}))
+	if connect.CodeOf(err) == connect.CodeNotFound {
+		if len(path.GetElements()) == 0 && len(sk.root.GetElements()) != 0 {
+			// the root of the sink is made with the first file created in it
+			return &typesv1.DirSum{}, true, nil
+		}
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("listing dir: %w", err)
+	}
+	entries := res.Msg.DirEntries
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	// as a lazy sink, the sums of files are fetched when they're needed
+	dir := &typesv1.DirSum{Path: typesv1.DirOf(path)}
+	for _, fi := range entries {
+		sk.localInfo(fi)
+		if fi.IsDir {
+			dir.Dirs = append(dir.Dirs, &typesv1.DirSum{Path: path, Info: fi})
+		} else {
+			dir.Files = append(dir.Files, &typesv1.FileSum{Info: fi})
+		}
+	}
+	return dir, true, nil
+}
+
+func (sk *Sink) GetTree(ctx context.Context) (*typesv1.DirSum, error) {
+	res, err := sk.client.GetTree(ctx, connect.NewRequest(&syncv1.GetTreeRequest{
+		Meta: sk.meta,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return sk.subtree(res.Msg.GetRoot()), nil
+}
+
+func (sk *Sink) GetFileSums(ctx context.Context, paths []*typesv1.Path) ([]*typesv1.FileSum, error) {
+	remotePaths := make([]*typesv1.Path, 0, len(paths))
+	for _, path := range paths {
+		remotePaths = append(remotePaths, sk.remote(path))
+	}
+	res, err := sk.client.GetFileSums(ctx, connect.NewRequest(&syncv1.GetFileSumsRequest{
+		Meta:  sk.meta,
+		Paths: remotePaths,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	sums := res.Msg.GetSums()
+	for _, sum := range sums {
+		sk.localInfo(sum.GetInfo())
+	}
+	return sums, nil
 }
 
 func (sk *Sink) CreateFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, r io.Reader) error {
+	if err := sk.makeRoot(ctx); err != nil {
+		return fmt.Errorf("making remote path: %w", err)
+	}
+	return sk.createFile(ctx, sk.remote(dir), sk.remoteInfo(fi), r, "")
+}
+
+// CreateFileResumable is `CreateFile`, as an upload the server keeps until
+// it's done. If an upload of the same file was interrupted, it's resumed
+// from what the server kept of it.
+func (sk *Sink) CreateFileResumable(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, r io.Reader) error {
+	if err := sk.makeRoot(ctx); err != nil {
+		return fmt.Errorf("making remote path: %w", err)
+	}
+	dir, fi = sk.remote(dir), sk.remoteInfo(fi)
+	return sk.createFile(ctx, dir, fi, r, uploadIDOf(dir, fi))
+}
+
+// uploadIDOf names the upload of `fi` into `dir`. A file that changed is
+// another upload.
+func uploadIDOf(dir *typesv1.Path, fi *typesv1.FileInfo) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d\x00%d",
+		typesv1.StringFromPath(dir), fi.Name, fi.Size, fi.GetModTime().AsTime().UnixNano())
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// createFile is `CreateFile` in the dir `dir` of the project. With an
+// `uploadID`, the upload is resumed from what the server kept of it.
+func (sk *Sink) createFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, r io.Reader, uploadID string) error {
 	ll := sk.ll.With(
 		slog.String("path", typesv1.StringFromPath(dir)),
 		slog.String("file", fi.Name),
 	)
+	var offset uint64
+	if uploadID != "" {
+		res, err := sk.client.GetUpload(ctx, connect.NewRequest(&syncv1.GetUploadRequest{
+			Meta:     sk.meta,
+			UploadId: uploadID,
+		}))
+		if err != nil {
+			return fmt.Errorf("getting upload: %w", err)
+		}
+		// past the size of the file, what was kept is of something else
+		if res.Msg.Size <= uint64(fi.Size) {
+			offset = res.Msg.Size
+		}
+		if offset > 0 {
+			ll.InfoContext(ctx, "resuming upload", slog.Uint64("offset", offset))
+		}
+	}
 	success := false
 	ll.DebugContext(ctx, "creating file")
 	stream := sk.client.Create(ctx)
@@ -63,9 +214,11 @@ func (sk *Sink) CreateFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.F
 		Meta: sk.meta,
 		Step: &syncv1.CreateRequest_Creating_{
 			Creating: &syncv1.CreateRequest_Creating{
-				Path:   dir,
-				Info:   fi,
-				Hasher: hasher,
+				Path:     dir,
+				Info:     fi,
+				Hasher:   hasher,
+				UploadId: uploadID,
+				Offset:   offset,
 			},
 		},
 	}
@@ -75,7 +228,8 @@ func (sk *Sink) CreateFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.F
 	}
 	ll.DebugContext(ctx, "done step create")
 
-	if fi.IsDir {
+	// hard links are made from the file they link to, on the server
+	if fi.IsDir || fi.IsSymlink() || fi.HardLink != nil {
 		closing := &syncv1.CreateRequest{
 			Step: &syncv1.CreateRequest_Closing_{Closing: &syncv1.CreateRequest_Closing{}},
 		}
@@ -102,7 +256,12 @@ func (sk *Sink) CreateFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.F
 		blockSize = 1024
 	}
 	h := blake3.New(64, nil)
-	r = io.TeeReader(r, h)
+	r = dirsync.TeeReader(r, h)
+	// what the server kept isn't sent again, but it's part of the sum
+	if _, err := io.CopyN(io.Discard, r, int64(offset)); err != nil {
+		return fmt.Errorf("reading file on source: %w", err)
+	}
+	dirsync.Progressed(ctx, int(offset))
 
 	writingStep := &syncv1.CreateRequest_Writing{}
 	writing := &syncv1.CreateRequest{
@@ -111,26 +270,60 @@ func (sk *Sink) CreateFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.F
 		},
 	}
 	buf := make([]byte, blockSize)
-	more := true
-	for more {
-		ll.DebugContext(ctx, "reading data", slog.Uint64("blocksize", uint64(blockSize)))
-		n, err := io.ReadFull(r, buf)
-		switch err {
-		case io.EOF, io.ErrUnexpectedEOF:
-			more = false
-		case nil:
-			// continue
-		default:
-			return fmt.Errorf("reading file on source: %w", err)
-		}
-		if n > 0 {
-			writingStep.ContentBlock = buf[:n]
-			ll.DebugContext(ctx, "starting step writing")
-			if err := stream.Send(writing); err != nil {
-				return fmt.Errorf("writing file on sink: %w", err)
+	sendContent := func(r io.Reader) error {
+		more := true
+		for more {
+			ll.DebugContext(ctx, "reading data", slog.Uint64("blocksize", uint64(blockSize)))
+			n, err := io.ReadFull(r, buf)
+			switch err {
+			case io.EOF, io.ErrUnexpectedEOF:
+				more = false
+			case nil:
+				// continue
+			default:
+				return fmt.Errorf("reading file on source: %w", err)
+			}
+			if n > 0 {
+				writingStep.ContentBlock = buf[:n]
+				ll.DebugContext(ctx, "starting step writing")
+				if err := stream.Send(writing); err != nil {
+					return fmt.Errorf("writing file on sink: %w", err)
+				}
+				dirsync.Progressed(ctx, n)
+				ll.DebugContext(ctx, "done step writing")
 			}
-			ll.DebugContext(ctx, "done step writing")
 		}
+		return nil
+	}
+	// the holes of sparse files are sent as their size
+	sr, sparse := r.(dirsync.SparseReader)
+	for sparse {
+		data, hole, err := sr.NextHole()
+		if err != nil {
+			return fmt.Errorf("finding holes of file on source: %w", err)
+		}
+		if hole == 0 {
+			break
+		}
+		if err := sendContent(io.LimitReader(r, data)); err != nil {
+			return err
+		}
+		if err := sr.SkipHole(hole); err != nil {
+			return fmt.Errorf("skipping hole of file on source: %w", err)
+		}
+		ll.DebugContext(ctx, "starting step writing hole")
+		err = stream.Send(&syncv1.CreateRequest{
+			Step: &syncv1.CreateRequest_Writing_{
+				Writing: &syncv1.CreateRequest_Writing{Hole: uint64(hole)},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("writing file on sink: %w", err)
+		}
+		ll.DebugContext(ctx, "done step writing hole")
+	}
+	if err := sendContent(r); err != nil {
+		return err
 	}
 
 	sum := h.Sum(nil)
@@ -160,6 +353,7 @@ func (sk *Sink) CreateFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.F
 }
 
 func (sk *Sink) PatchFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.FileInfo, sum *typesv1.FileSum, r io.Reader) error {
+	dir, fi = sk.remote(dir), sk.remoteInfo(fi)
 	ll := sk.ll.With(
 		slog.String("path", typesv1.StringFromPath(dir)),
 		slog.String("file", fi.Name),
@@ -193,7 +387,7 @@ func (sk *Sink) PatchFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.Fi
 	}
 	ll.DebugContext(ctx, "done step open")
 
-	if fi.IsDir {
+	if fi.IsDir || fi.IsSymlink() || fi.HardLink != nil {
 		closing := &syncv1.PatchRequest{
 			Step: &syncv1.PatchRequest_Closing_{Closing: &syncv1.PatchRequest_Closing{}},
 		}
@@ -220,11 +414,12 @@ func (sk *Sink) PatchFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.Fi
 		blockSize = 1024
 	}
 	h := blake3.New(64, nil)
-	r = io.TeeReader(r, h)
+	r = dirsync.TeeReader(r, h)
 
 	patch := &typesv1.FileBlockPatch{Patch: nil}
 	dataPatch := &typesv1.FileBlockPatch_Data{}
 	blockIDPatch := &typesv1.FileBlockPatch_BlockId{}
+	holePatch := &typesv1.FileBlockPatch_Hole{}
 	patching := &syncv1.PatchRequest{
 		Step: &syncv1.PatchRequest_Patching_{
 			Patching: &syncv1.PatchRequest_Patching{
@@ -239,6 +434,9 @@ func (sk *Sink) PatchFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.Fi
 			dataPatch.Data = b
 			patch.Patch = dataPatch
 			err := stream.Send(patching)
+			if err == nil {
+				dirsync.Progressed(ctx, len(b))
+			}
 			ll.DebugContext(ctx, "done block data patching")
 			return len(b), err
 		},
@@ -247,9 +445,21 @@ func (sk *Sink) PatchFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.Fi
 			blockIDPatch.BlockId = u
 			patch.Patch = blockIDPatch
 			err := stream.Send(patching)
+			// the block is on the sink already
+			if err == nil && int(u) < len(sum.SumBlocks) {
+				dirsync.Progressed(ctx, int(sum.SumBlocks[u].Size))
+			}
 			ll.DebugContext(ctx, "done block id patching")
 			return 4, err
 		},
+		func(size uint64) (int, error) {
+			ll.DebugContext(ctx, "starting hole patching")
+			holePatch.Hole = size
+			patch.Patch = holePatch
+			err := stream.Send(patching)
+			ll.DebugContext(ctx, "done hole patching")
+			return 8, err
+		},
 	)
 	if err != nil {
 		return fmt.Errorf("sending block patches: %w", err)
@@ -284,8 +494,19 @@ func (sk *Sink) PatchFile(ctx context.Context, dir *typesv1.Path, fi *typesv1.Fi
 func (sk *Sink) DeleteFile(ctx context.Context, op dirsync.DeleteOp) error {
 	_, err := sk.client.Delete(ctx, connect.NewRequest(&syncv1.DeleteRequest{
 		Meta: sk.meta,
-		Path: op.Path,
-		Info: op.FileInfo,
+		Path: sk.remote(op.Path),
+		Info: sk.remoteInfo(op.FileInfo),
+	}))
+	return err
+}
+
+func (sk *Sink) MoveFile(ctx context.Context, op dirsync.MoveOp) error {
+	_, err := sk.client.Move(ctx, connect.NewRequest(&syncv1.MoveRequest{
+		Meta:      sk.meta,
+		From:      sk.remote(op.From),
+		FromInfo:  sk.remoteInfo(op.FromInfo),
+		ParentDir: sk.remote(op.ParentDir),
+		Info:      sk.remoteInfo(op.Info),
 	}))
 	return err
 }
@@ -0,0 +1,167 @@
+package syncclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/aybabtme/syncy/pkg/gen/svc/sync/v1/syncv1connect"
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/aybabtme/syncy/pkg/logic/dirsync"
+	"github.com/aybabtme/syncy/pkg/storage"
+	"github.com/aybabtme/syncy/pkg/storage/blobdb"
+	"github.com/aybabtme/syncy/pkg/svc/syncsvc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateFileResumable(t *testing.T) {
+	ctx := context.Background()
+	ll := slog.New(slog.NewTextHandler(io.Discard, nil))
+	lfs, err := blobdb.NewLocalFS(t.TempDir(), t.TempDir())
+	require.NoError(t, err)
+	db := &uploadDB{State: storage.NewState(ll, nil, lfs), files: make(map[string][]byte)}
+
+	sink := serveSink(t, syncsvc.NewHandler(ll, db))
+	meta := sink.meta
+	// the root is already made
+	sink.rootMade = true
+
+	content := bytes.Repeat([]byte("0123456789abcdef"), 8*minCreateBlockSize/16)
+	changed := bytes.ToUpper(content)
+	dir := typesv1.PathFromString("")
+	fi := &typesv1.FileInfo{Name: "file", Size: uint64(len(content))}
+	uploadID := uploadIDOf(dir, fi)
+	uploadSize := func() uint64 {
+		size, err := db.UploadSize(ctx, meta.AccountId, meta.ProjectId, uploadID)
+		require.NoError(t, err)
+		return size
+	}
+
+	// interrupted after 3 blocks were sent
+	err = sink.CreateFileResumable(ctx, dir, fi, &failingReader{r: bytes.NewReader(content), left: 3 * minCreateBlockSize})
+	require.ErrorIs(t, err, errInterrupted)
+	require.EqualValues(t, 3*minCreateBlockSize, uploadSize())
+	require.NotContains(t, db.files, "file")
+
+	// resumed from what the server kept
+	err = sink.CreateFileResumable(ctx, dir, fi, bytes.NewReader(content))
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0, 3 * minCreateBlockSize}, db.offsets)
+	require.Equal(t, content, db.files["file"])
+	require.Zero(t, uploadSize(), "upload is removed once it's done")
+
+	// what was kept of a file that changed since isn't reused
+	db.offsets = nil
+	err = sink.CreateFileResumable(ctx, dir, fi, &failingReader{r: bytes.NewReader(content), left: 3 * minCreateBlockSize})
+	require.ErrorIs(t, err, errInterrupted)
+	err = sink.CreateFileResumable(ctx, dir, fi, bytes.NewReader(changed))
+	require.Error(t, err, "sum mismatch")
+	require.Zero(t, uploadSize(), "mismatching upload is removed")
+	err = sink.CreateFileResumable(ctx, dir, fi, bytes.NewReader(changed))
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0, 3 * minCreateBlockSize, 0}, db.offsets)
+	require.Equal(t, changed, db.files["file"])
+}
+
+func TestCreateFileProgress(t *testing.T) {
+	ctx := context.Background()
+	srcDir := t.TempDir()
+	content := bytes.Repeat([]byte("0123456789abcdef"), 64*minCreateBlockSize/16)
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "file"), content, 0644))
+
+	svc := &fakeService{
+		tree: &typesv1.DirSum{Info: &typesv1.FileInfo{IsDir: true}},
+		dirs: map[string][]*typesv1.FileInfo{"": nil},
+	}
+	var (
+		mu         sync.Mutex
+		progressed []uint64
+	)
+	obs := dirsync.ObserverFunc(func(ev dirsync.Event) {
+		if ev.Kind == dirsync.EventProgressed {
+			mu.Lock()
+			progressed = append(progressed, ev.Bytes)
+			mu.Unlock()
+		}
+	})
+	_, err := dirsync.Sync(ctx, ".", dirsync.NewLocalSource(srcDir), serveSink(t, svc), dirsync.Params{Observer: obs})
+	require.NoError(t, err)
+	require.Equal(t, []string{"file"}, svc.created)
+	// by steps, as the blocks are sent
+	var total uint64
+	for _, n := range progressed {
+		total += n
+	}
+	require.Greater(t, len(progressed), 1)
+	require.EqualValues(t, len(content), total)
+}
+
+// serveSink is a sink for a project that's served by `hdl`.
+func serveSink(t *testing.T, hdl syncv1connect.SyncServiceHandler) *Sink {
+	ll := slog.New(slog.NewTextHandler(io.Discard, nil))
+	sink, err := ClientAdapter(ll, serve(t, hdl), testMeta, minCreateBlockSize)
+	require.NoError(t, err)
+	return sink
+}
+
+var testMeta = &typesv1.ReqMeta{AccountId: "account", ProjectId: "project"}
+
+// serve is a client of `hdl`.
+func serve(t *testing.T, hdl syncv1connect.SyncServiceHandler) syncv1connect.SyncServiceClient {
+	mux := http.NewServeMux()
+	mux.Handle(syncv1connect.NewSyncServiceHandler(hdl))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return syncv1connect.NewSyncServiceClient(srv.Client(), srv.URL)
+}
+
+var errInterrupted = errors.New("interrupted")
+
+// failingReader fails once `left` bytes were read.
+type failingReader struct {
+	r    io.Reader
+	left int
+}
+
+func (fr *failingReader) Read(p []byte) (int, error) {
+	if fr.left == 0 {
+		return 0, errInterrupted
+	}
+	if len(p) > fr.left {
+		p = p[:fr.left]
+	}
+	n, err := fr.r.Read(p)
+	fr.left -= n
+	return n, err
+}
+
+// uploadDB keeps uploads like the server does, and the files that are
+// created in memory.
+type uploadDB struct {
+	*storage.State
+	files map[string][]byte
+	// the offsets uploads were opened at
+	offsets []uint64
+}
+
+func (db *uploadDB) OpenUpload(ctx context.Context, accountPublicID, projectPublicID, uploadID string, offset uint64) (*storage.Upload, error) {
+	db.offsets = append(db.offsets, offset)
+	return db.State.OpenUpload(ctx, accountPublicID, projectPublicID, uploadID, offset)
+}
+
+func (db *uploadDB) CreatePath(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fi *typesv1.FileInfo, fn blobdb.CreateFunc) error {
+	var buf bytes.Buffer
+	if _, err := fn(&buf); err != nil {
+		return err
+	}
+	db.files[fi.Name] = buf.Bytes()
+	return nil
+}
@@ -0,0 +1,150 @@
+package syncclient
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"slices"
+
+	"connectrpc.com/connect"
+	syncv1 "github.com/aybabtme/syncy/pkg/gen/svc/sync/v1"
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Root is the dir of the project that the sink is, see `Under`. It's empty
+// for the root of the project.
+func (sk *Sink) Root() *typesv1.Path {
+	return sk.root
+}
+
+// remote is `path`, relative to the root of the sink, as a path of the
+// project.
+func (sk *Sink) remote(path *typesv1.Path) *typesv1.Path {
+	if len(sk.root.GetElements()) == 0 {
+		return path
+	}
+	elements := make([]string, 0, len(sk.root.Elements)+len(path.GetElements()))
+	elements = append(elements, sk.root.Elements...)
+	return &typesv1.Path{Elements: append(elements, path.GetElements()...)}
+}
+
+// local is `path`, a path of the project, relative to the root of the sink.
+// It's false if `path` isn't under the root.
+func (sk *Sink) local(path *typesv1.Path) (*typesv1.Path, bool) {
+	n := len(sk.root.GetElements())
+	if n == 0 {
+		return path, true
+	}
+	elements := path.GetElements()
+	if len(elements) < n || !slices.Equal(elements[:n], sk.root.Elements) {
+		return nil, false
+	}
+	return &typesv1.Path{Elements: slices.Clone(elements[n:])}, true
+}
+
+// remoteInfo is `fi` with its hard link as a path of the project.
+func (sk *Sink) remoteInfo(fi *typesv1.FileInfo) *typesv1.FileInfo {
+	if fi.GetHardLink() == nil || len(sk.root.GetElements()) == 0 {
+		return fi
+	}
+	fi = proto.Clone(fi).(*typesv1.FileInfo)
+	fi.HardLink = sk.remote(fi.HardLink)
+	return fi
+}
+
+// localInfo makes the hard link of `fi` relative to the root of the sink.
+// Links to files that aren't under the root are dropped, as far as the sink
+// knows there's no such file. It tells if there was a link.
+func (sk *Sink) localInfo(fi *typesv1.FileInfo) bool {
+	if fi.GetHardLink() == nil || len(sk.root.GetElements()) == 0 {
+		return false
+	}
+	fi.HardLink, _ = sk.local(fi.HardLink)
+	return true
+}
+
+// subtree is the part of `tree`, the tree of the project, that's under the
+// root of the sink, with its paths relative to the root. It's empty if
+// there's no such dir yet.
+func (sk *Sink) subtree(tree *typesv1.DirSum) *typesv1.DirSum {
+	if len(sk.root.GetElements()) == 0 {
+		return tree
+	}
+	dir := tree
+	for _, name := range sk.root.Elements {
+		i := slices.IndexFunc(dir.GetDirs(), func(child *typesv1.DirSum) bool {
+			return child.GetInfo().GetName() == name
+		})
+		if i < 0 {
+			return &typesv1.DirSum{Info: &typesv1.FileInfo{IsDir: true}}
+		}
+		dir = dir.Dirs[i]
+	}
+	sk.rebase(dir)
+	// it's the root of the sink, like the root of a project
+	dir.Path = nil
+	dir.Info = &typesv1.FileInfo{IsDir: true, Size: dir.GetInfo().GetSize()}
+	return dir
+}
+
+// rebase makes the paths of `dir` relative to the root of the sink. It tells
+// if hard links were, in which case the digests of their dirs no longer
+// hold and are dropped.
+func (sk *Sink) rebase(dir *typesv1.DirSum) bool {
+	linked := false
+	for _, file := range dir.Files {
+		linked = sk.localInfo(file.GetInfo()) || linked
+	}
+	for _, child := range dir.Dirs {
+		if path, ok := sk.local(child.Path); ok {
+			child.Path = path
+		}
+		linked = sk.rebase(child) || linked
+	}
+	if linked {
+		dir.Digest = nil
+	}
+	return linked
+}
+
+// makeRoot makes the root of the sink and its parents, the ones that are
+// missing, once.
+func (sk *Sink) makeRoot(ctx context.Context) error {
+	sk.rootMu.Lock()
+	defer sk.rootMu.Unlock()
+	if sk.rootMade {
+		return nil
+	}
+	parent := &typesv1.Path{}
+	for _, name := range sk.root.GetElements() {
+		path := typesv1.PathJoin(parent, name)
+		res, err := sk.client.ListDir(ctx, connect.NewRequest(&syncv1.ListDirRequest{
+			Meta: sk.meta,
+			Path: parent,
+		}))
+		if err != nil {
+			return fmt.Errorf("listing dir %q: %w", typesv1.StringFromPath(parent), err)
+		}
+		entries := res.Msg.DirEntries
+		i := slices.IndexFunc(entries, func(fi *typesv1.FileInfo) bool { return fi.Name == name })
+		switch {
+		case i < 0:
+			fi := &typesv1.FileInfo{
+				Name:    name,
+				Mode:    uint32(fs.ModeDir | 0755),
+				ModTime: timestamppb.Now(),
+				IsDir:   true,
+			}
+			if err := sk.createFile(ctx, parent, fi, nil, ""); err != nil {
+				return fmt.Errorf("creating dir %q: %w", typesv1.StringFromPath(path), err)
+			}
+		case !entries[i].IsDir:
+			return fmt.Errorf("%q isn't a dir", typesv1.StringFromPath(path))
+		}
+		parent = path
+	}
+	sk.rootMade = true
+	return nil
+}
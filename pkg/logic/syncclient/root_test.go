@@ -0,0 +1,240 @@
+package syncclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	syncv1 "github.com/aybabtme/syncy/pkg/gen/svc/sync/v1"
+	"github.com/aybabtme/syncy/pkg/gen/svc/sync/v1/syncv1connect"
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSinkRemoteLocal(t *testing.T) {
+	tests := []struct {
+		name string
+		root string
+		// a path of the sink, and the one of the project it is
+		path   string
+		remote string
+		// the path isn't under the root
+		outside bool
+	}{
+		{name: "project", root: "", path: "a/file", remote: "a/file"},
+		{name: "root of project", root: "", path: "", remote: ""},
+		{name: "under", root: "x/y", path: "a/file", remote: "x/y/a/file"},
+		{name: "root of sink", root: "x/y", path: "", remote: "x/y"},
+		{name: "sibling", root: "x/y", remote: "x/z/file", outside: true},
+		{name: "parent", root: "x/y", remote: "x", outside: true},
+		{name: "prefix of name", root: "x/y", remote: "x/yy/file", outside: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &Sink{root: typesv1.PathFromString(tt.root)}
+			local, ok := sink.local(typesv1.PathFromString(tt.remote))
+			if tt.outside {
+				require.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, tt.path, typesv1.StringFromPath(local))
+			remote := sink.remote(typesv1.PathFromString(tt.path))
+			require.Equal(t, tt.remote, typesv1.StringFromPath(remote))
+		})
+	}
+}
+
+func TestSinkSubtree(t *testing.T) {
+	file := func(name string, hardLink string) *typesv1.FileSum {
+		fi := &typesv1.FileInfo{Name: name, Size: 1}
+		if hardLink != "" {
+			fi.HardLink = typesv1.PathFromString(hardLink)
+		}
+		return &typesv1.FileSum{Info: fi}
+	}
+	dir := func(parent, name string, dirs []*typesv1.DirSum, files ...*typesv1.FileSum) *typesv1.DirSum {
+		return &typesv1.DirSum{
+			Path:   typesv1.PathFromString(parent),
+			Info:   &typesv1.FileInfo{Name: name, IsDir: true, Size: uint64(len(files))},
+			Dirs:   dirs,
+			Files:  files,
+			Digest: []byte(name),
+		}
+	}
+	// x/y/{file, linked, outside, z/{file}}, x/other
+	tree := dir("", "", []*typesv1.DirSum{
+		dir("", "x", []*typesv1.DirSum{
+			dir("x", "y", []*typesv1.DirSum{
+				dir("x/y", "z", nil, file("file", "")),
+			}, file("file", ""), file("linked", "x/y/z/file"), file("outside", "x/other")),
+		}, file("other", "")),
+	})
+
+	tests := []struct {
+		name string
+		root string
+		want *typesv1.DirSum
+	}{
+		{name: "project", root: "", want: tree},
+		{name: "missing", root: "x/nope", want: &typesv1.DirSum{Info: &typesv1.FileInfo{IsDir: true}}},
+		{name: "file", root: "x/other", want: &typesv1.DirSum{Info: &typesv1.FileInfo{IsDir: true}}},
+		{
+			name: "dir",
+			root: "x/y",
+			// links are relative to the root, and the ones out of it are
+			// dropped, with the digests of the dirs they're in
+			want: &typesv1.DirSum{
+				Info: &typesv1.FileInfo{IsDir: true, Size: 3},
+				Dirs: []*typesv1.DirSum{
+					dir("", "z", nil, file("file", "")),
+				},
+				Files: []*typesv1.FileSum{file("file", ""), file("linked", "z/file"), {Info: &typesv1.FileInfo{Name: "outside", Size: 1}}},
+			},
+		},
+		{
+			name: "dir without links",
+			root: "x/y/z",
+			want: &typesv1.DirSum{
+				Info:   &typesv1.FileInfo{IsDir: true, Size: 1},
+				Files:  []*typesv1.FileSum{file("file", "")},
+				Digest: []byte("z"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeService{tree: tree}
+			sink := serveSink(t, svc).Under(typesv1.PathFromString(tt.root))
+			got, err := sink.GetSignatures(context.Background())
+			require.NoError(t, err)
+			require.True(t, proto.Equal(tt.want, got), "want %v\ngot  %v", tt.want, got)
+		})
+	}
+}
+
+func TestSinkMakeRoot(t *testing.T) {
+	tests := []struct {
+		name     string
+		root     string
+		existing []string
+		// the dirs made, then the file
+		want    []string
+		wantErr string
+	}{
+		{name: "project", root: "", want: []string{"file"}},
+		{name: "existing", root: "x/y", existing: []string{"x/", "x/y/"}, want: []string{"x/y/file"}},
+		{name: "missing", root: "x/y/z", existing: []string{"x/"}, want: []string{"x/y/", "x/y/z/", "x/y/z/file"}},
+		{name: "not a dir", root: "x/y", existing: []string{"x"}, wantErr: `"x" isn't a dir`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc := &fakeService{dirs: map[string][]*typesv1.FileInfo{"": nil}}
+			for _, name := range tt.existing {
+				trimmed := strings.TrimSuffix(name, "/")
+				parent := typesv1.DirOf(typesv1.PathFromString(trimmed))
+				svc.add(parent, &typesv1.FileInfo{Name: path.Base(trimmed), IsDir: trimmed != name})
+			}
+			sink := serveSink(t, svc).Under(typesv1.PathFromString(tt.root))
+
+			// a root that's not made yet is empty
+			dir, found, err := sink.GetDirSignatures(ctx, &typesv1.Path{})
+			require.NoError(t, err)
+			require.True(t, found)
+			require.Empty(t, dir.Dirs)
+			require.Empty(t, dir.Files)
+
+			fi := &typesv1.FileInfo{Name: "file"}
+			err = sink.CreateFile(ctx, &typesv1.Path{}, fi, bytes.NewReader(nil))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, svc.created)
+
+			// it's made once
+			lists := svc.lists
+			err = sink.CreateFile(ctx, &typesv1.Path{}, fi, bytes.NewReader(nil))
+			require.NoError(t, err)
+			require.Equal(t, lists, svc.lists)
+		})
+	}
+}
+
+// fakeService serves the tree of a project, and keeps what's created in it.
+type fakeService struct {
+	syncv1connect.UnimplementedSyncServiceHandler
+
+	tree *typesv1.DirSum
+
+	mu sync.Mutex
+	// the entries of each dir, by path
+	dirs map[string][]*typesv1.FileInfo
+	// the paths created, dirs with a trailing slash
+	created []string
+	// how many dirs were listed
+	lists int
+}
+
+func (svc *fakeService) add(parent *typesv1.Path, fi *typesv1.FileInfo) {
+	key := typesv1.StringFromPath(parent)
+	svc.dirs[key] = append(svc.dirs[key], fi)
+	if fi.IsDir {
+		svc.dirs[path.Join(key, fi.Name)] = nil
+	}
+}
+
+func (svc *fakeService) GetSignature(ctx context.Context, req *connect.Request[syncv1.GetSignatureRequest]) (*connect.Response[syncv1.GetSignatureResponse], error) {
+	return connect.NewResponse(&syncv1.GetSignatureResponse{Root: svc.tree}), nil
+}
+
+func (svc *fakeService) GetProject(ctx context.Context, req *connect.Request[syncv1.GetProjectRequest]) (*connect.Response[syncv1.GetProjectResponse], error) {
+	return connect.NewResponse(&syncv1.GetProjectResponse{}), nil
+}
+
+func (svc *fakeService) GetTree(ctx context.Context, req *connect.Request[syncv1.GetTreeRequest]) (*connect.Response[syncv1.GetTreeResponse], error) {
+	return connect.NewResponse(&syncv1.GetTreeResponse{Root: svc.tree}), nil
+}
+
+func (svc *fakeService) ListDir(ctx context.Context, req *connect.Request[syncv1.ListDirRequest]) (*connect.Response[syncv1.ListDirResponse], error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.lists++
+	entries, ok := svc.dirs[typesv1.StringFromPath(req.Msg.Path)]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("no such dir"))
+	}
+	return connect.NewResponse(&syncv1.ListDirResponse{DirEntries: entries}), nil
+}
+
+func (svc *fakeService) Create(ctx context.Context, stream *connect.ClientStream[syncv1.CreateRequest]) (*connect.Response[syncv1.CreateResponse], error) {
+	var creating *syncv1.CreateRequest_Creating
+	for stream.Receive() {
+		if step := stream.Msg().GetCreating(); step != nil {
+			creating = step
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	if _, ok := svc.dirs[typesv1.StringFromPath(creating.Path)]; !ok {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("parent directory doesn't exist"))
+	}
+	svc.add(creating.Path, creating.Info)
+	created := path.Join(typesv1.StringFromPath(creating.Path), creating.Info.Name)
+	if creating.Info.IsDir {
+		created += "/"
+	}
+	svc.created = append(svc.created, created)
+	return connect.NewResponse(&syncv1.CreateResponse{}), nil
+}
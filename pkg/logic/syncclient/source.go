@@ -0,0 +1,322 @@
+package syncclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	syncv1 "github.com/aybabtme/syncy/pkg/gen/svc/sync/v1"
+	"github.com/aybabtme/syncy/pkg/gen/svc/sync/v1/syncv1connect"
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/aybabtme/syncy/pkg/logic/dirsync"
+	"google.golang.org/protobuf/proto"
+	"lukechampine.com/blake3"
+)
+
+var (
+	_ dirsync.SymlinkSource = (*Source)(nil)
+	_ dirsync.RemoteFile    = (*remoteFile)(nil)
+)
+
+// Source is a `dirsync.Source` for a project on the server, to pull it into
+// a local dir.
+type Source struct {
+	// `fs.FS` methods don't take a context, the requests they
+	// make use this one
+	ctx    context.Context
+	ll     *slog.Logger
+	client syncv1connect.SyncServiceClient
+	meta   *typesv1.ReqMeta
+}
+
+// maxSymlinkHops is how many symlinks are followed to find a file, before
+// giving up, like ELOOP.
+const maxSymlinkHops = 40
+
+func SourceAdapter(ctx context.Context, ll *slog.Logger, client syncv1connect.SyncServiceClient, meta *typesv1.ReqMeta) *Source {
+	return &Source{ctx: ctx, ll: ll, client: client, meta: meta}
+}
+
+func (src *Source) Open(name string) (fs.File, error) {
+	realName, fi, err := src.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteFile{src: src, name: realName, info: fi}, nil
+}
+
+func (src *Source) Stat(name string) (fs.FileInfo, error) {
+	_, fi, err := src.resolve("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return fi, nil
+}
+
+func (src *Source) Lstat(name string) (fs.FileInfo, error) {
+	fi, err := src.lstat("lstat", name)
+	if err != nil {
+		return nil, err
+	}
+	return fileInfo{fi}, nil
+}
+
+func (src *Source) ReadLink(name string) (string, error) {
+	fi, err := src.lstat("readlink", name)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsSymlink() {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return fi.LinkTarget, nil
+}
+
+func (src *Source) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	res, err := src.client.ListDir(src.ctx, connect.NewRequest(&syncv1.ListDirRequest{
+		Meta: src.meta,
+		Path: typesv1.PathFromString(name),
+	}))
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fromConnectError(err)}
+	}
+	entries := make([]fs.DirEntry, 0, len(res.Msg.DirEntries))
+	for _, fi := range res.Msg.DirEntries {
+		// names are used as paths where the entries are written
+		if !validName(fi.Name) {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: fmt.Errorf("invalid entry name %q", fi.Name)}
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(fileInfo{fi}))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// validName tells if `name` is the name of a single entry of a dir.
+func validName(name string) bool {
+	return fs.ValidPath(name) && name != "." && !strings.Contains(name, "/")
+}
+
+// resolve follows the symlinks leading to `name`, if any, and returns where
+// the file is with its info.
+func (src *Source) resolve(op, name string) (string, fs.FileInfo, error) {
+	realName := name
+	for i := 0; i < maxSymlinkHops; i++ {
+		fi, err := src.lstat(op, realName)
+		if err != nil {
+			return "", nil, err
+		}
+		if !fi.IsSymlink() {
+			// the info is found where it was asked for
+			fi = proto.Clone(fi).(*typesv1.FileInfo)
+			fi.Name = path.Base(name)
+			return realName, fileInfo{fi}, nil
+		}
+		realName, err = dirsync.ResolveSymlink(realName, fi.LinkTarget)
+		if err != nil {
+			return "", nil, &fs.PathError{Op: op, Path: name, Err: err}
+		}
+	}
+	return "", nil, &fs.PathError{Op: op, Path: name, Err: errors.New("too many levels of symbolic links")}
+}
+
+func (src *Source) lstat(op, name string) (*typesv1.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		// the server doesn't keep info about the root of projects
+		return &typesv1.FileInfo{Name: ".", Mode: uint32(fs.ModeDir | 0755), IsDir: true}, nil
+	}
+	res, err := src.client.Stat(src.ctx, connect.NewRequest(&syncv1.StatRequest{
+		Meta: src.meta,
+		Path: typesv1.PathFromString(name),
+	}))
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fromConnectError(err)}
+	}
+	return res.Msg.Info, nil
+}
+
+func fromConnectError(err error) error {
+	if connect.CodeOf(err) == connect.CodeNotFound {
+		return fs.ErrNotExist
+	}
+	return err
+}
+
+// fileInfo is an `fs.FileInfo` for the info the server has about a file.
+type fileInfo struct {
+	fi *typesv1.FileInfo
+}
+
+func (fi fileInfo) Name() string       { return fi.fi.Name }
+func (fi fileInfo) Size() int64        { return int64(fi.fi.Size) }
+func (fi fileInfo) Mode() fs.FileMode  { return fs.FileMode(fi.fi.Mode) }
+func (fi fileInfo) ModTime() time.Time { return fi.fi.GetModTime().AsTime() }
+func (fi fileInfo) IsDir() bool        { return fi.fi.IsDir }
+func (fi fileInfo) Sys() any           { return fi.fi }
+
+// remoteFile downloads the content of a file on the server when it's read,
+// or only the parts that changed when it's patched into a local file.
+type remoteFile struct {
+	src  *Source
+	name string
+	info fs.FileInfo
+
+	// set once the content is being read
+	stream *connect.ServerStreamForClient[syncv1.DownloadResponse]
+	h      hash.Hash
+	buf    []byte
+	// zeros left to read after `buf`, the file has them as a hole
+	hole   uint64
+	closed bool
+}
+
+func (rf *remoteFile) Stat() (fs.FileInfo, error) {
+	return rf.info, nil
+}
+
+func (rf *remoteFile) Read(p []byte) (int, error) {
+	if rf.info.IsDir() {
+		return 0, &fs.PathError{Op: "read", Path: rf.name, Err: errors.New("is a directory")}
+	}
+	if rf.stream == nil {
+		stream, err := rf.src.client.Download(rf.src.ctx, connect.NewRequest(&syncv1.DownloadRequest{
+			Meta:   rf.src.meta,
+			Path:   typesv1.PathFromString(rf.name),
+			Hasher: syncv1.Hasher_blake3_64_256,
+		}))
+		if err != nil {
+			return 0, fmt.Errorf("downloading %q: %w", rf.name, fromConnectError(err))
+		}
+		rf.stream = stream
+		rf.h = blake3.New(64, nil)
+	}
+	for len(rf.buf) == 0 {
+		if rf.hole > 0 {
+			n := int(min(uint64(len(p)), rf.hole))
+			clear(p[:n])
+			rf.hole -= uint64(n)
+			return n, nil
+		}
+		if rf.closed {
+			return 0, io.EOF
+		}
+		if !rf.stream.Receive() {
+			if err := rf.stream.Err(); err != nil {
+				return 0, fmt.Errorf("downloading %q: %w", rf.name, err)
+			}
+			return 0, fmt.Errorf("downloading %q: %w", rf.name, io.ErrUnexpectedEOF)
+		}
+		switch step := rf.stream.Msg().Step.(type) {
+		case *syncv1.DownloadResponse_Writing_:
+			rf.buf, rf.hole = step.Writing.ContentBlock, step.Writing.Hole
+			_, _ = rf.h.Write(rf.buf)
+			_ = dirsync.WriteHole(rf.h, int64(rf.hole))
+		case *syncv1.DownloadResponse_Closing_:
+			if err := checkSum(rf.h, step.Closing.Sum); err != nil {
+				return 0, fmt.Errorf("downloading %q: %w", rf.name, err)
+			}
+			rf.closed = true
+		default:
+			return 0, fmt.Errorf("downloading %q: expecting message of type `writing` or `closing`", rf.name)
+		}
+	}
+	n := copy(p, rf.buf)
+	rf.buf = rf.buf[n:]
+	return n, nil
+}
+
+func (rf *remoteFile) Close() error {
+	if rf.stream == nil {
+		return nil
+	}
+	return rf.stream.Close()
+}
+
+func (rf *remoteFile) MatchesFileSum(ctx context.Context, sum *typesv1.FileSum) (bool, error) {
+	res, err := rf.src.client.GetFileSum(ctx, connect.NewRequest(&syncv1.GetFileSumRequest{
+		Meta: rf.src.meta,
+		Path: typesv1.PathFromString(rf.name),
+	}))
+	if err != nil {
+		return false, fmt.Errorf("getting sum of %q: %w", rf.name, fromConnectError(err))
+	}
+	got := res.Msg.Sum
+	if got.BlockSize != sum.BlockSize || len(got.SumBlocks) != len(sum.SumBlocks) {
+		return false, nil
+	}
+	for i, block := range got.SumBlocks {
+		if !proto.Equal(block, sum.SumBlocks[i]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (rf *remoteFile) PatchInto(ctx context.Context, orig io.ReadSeeker, sum *typesv1.FileSum, w io.Writer) error {
+	ll := rf.src.ll.With(slog.String("file", rf.name))
+	ll.DebugContext(ctx, "downloading patch")
+	stream, err := rf.src.client.DownloadPatch(ctx, connect.NewRequest(&syncv1.DownloadPatchRequest{
+		Meta:   rf.src.meta,
+		Path:   typesv1.PathFromString(rf.name),
+		Hasher: syncv1.Hasher_blake3_64_256,
+		Sum:    sum,
+	}))
+	if err != nil {
+		return fmt.Errorf("downloading patch: %w", fromConnectError(err))
+	}
+	defer stream.Close()
+
+	h := blake3.New(64, nil)
+	patcher := dirsync.NewFilePatcher(orig, dirsync.MultiWriter(w, h), sum)
+	for stream.Receive() {
+		switch step := stream.Msg().Step.(type) {
+		case *syncv1.DownloadPatchResponse_Patching_:
+			switch p := step.Patching.Patch.Patch.(type) {
+			case *typesv1.FileBlockPatch_BlockId:
+				_, err = patcher.WriteBlock(p.BlockId)
+			case *typesv1.FileBlockPatch_Data:
+				_, err = patcher.WriteData(p.Data)
+			case *typesv1.FileBlockPatch_Hole:
+				_, err = patcher.WriteHole(p.Hole)
+			default:
+				return errors.New("expecting patch of type `block_id`, `data` or `hole`")
+			}
+			if err != nil {
+				return fmt.Errorf("applying patch: %w", err)
+			}
+		case *syncv1.DownloadPatchResponse_Closing_:
+			ll.DebugContext(ctx, "done downloading patch")
+			return checkSum(h, step.Closing.Sum)
+		default:
+			return errors.New("expecting message of type `patching` or `closing`")
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return fmt.Errorf("downloading patch: %w", err)
+	}
+	return fmt.Errorf("downloading patch: %w", io.ErrUnexpectedEOF)
+}
+
+func checkSum(h hash.Hash, want []byte) error {
+	got := h.Sum(nil)
+	if !bytes.Equal(got, want) {
+		return fmt.Errorf("received content has a hashsum of %x but the server announced a sum of %x", got, want)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package syncclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"connectrpc.com/connect"
+	syncv1 "github.com/aybabtme/syncy/pkg/gen/svc/sync/v1"
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/aybabtme/syncy/pkg/logic/dirsync"
+	"github.com/aybabtme/syncy/pkg/storage"
+	"github.com/aybabtme/syncy/pkg/storage/blobdb"
+	"github.com/aybabtme/syncy/pkg/svc/syncsvc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadSparse(t *testing.T) {
+	ctx := context.Background()
+	ll := slog.New(slog.NewTextHandler(io.Discard, nil))
+	const size = 8 << 20
+	filename := filepath.Join(t.TempDir(), "disk.img")
+	f, err := os.Create(filename)
+	require.NoError(t, err)
+	_, err = f.WriteAt([]byte("head"), 0)
+	require.NoError(t, err)
+	_, err = f.WriteAt([]byte("tail"), size-4)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	want, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	f, err = os.Open(filename)
+	require.NoError(t, err)
+	sf, err := dirsync.SparseFile(f)
+	require.NoError(t, err)
+	defer sf.Close()
+	if _, ok := sf.(dirsync.SparseReader); !ok {
+		t.Skip("no holes on the filesystem of temp dirs")
+	}
+
+	client := serve(t, syncsvc.NewHandler(ll, &fileDB{filename: filename}))
+	stream, err := client.Download(ctx, connect.NewRequest(&syncv1.DownloadRequest{
+		Meta:   testMeta,
+		Path:   typesv1.PathFromString("disk.img"),
+		Hasher: syncv1.Hasher_blake3_64_256,
+	}))
+	require.NoError(t, err)
+	var content, holes uint64
+	for stream.Receive() {
+		if step := stream.Msg().GetWriting(); step != nil {
+			content += uint64(len(step.ContentBlock))
+			holes += step.Hole
+		}
+	}
+	require.NoError(t, stream.Err())
+	require.EqualValues(t, size, content+holes)
+	require.Less(t, content, uint64(1<<20), "holes aren't sent as zeros")
+
+	// holes are read as zeros, and are part of the sum
+	fi := fileInfo{fi: &typesv1.FileInfo{Name: "disk.img", Size: size}}
+	rf := &remoteFile{src: SourceAdapter(ctx, ll, client, testMeta), name: "disk.img", info: fi}
+	got, err := io.ReadAll(rf)
+	require.NoError(t, err)
+	require.NoError(t, rf.Close())
+	require.True(t, bytes.Equal(want, got))
+}
+
+// fileDB serves the file `filename` for every path.
+type fileDB struct {
+	storage.DB
+	filename string
+}
+
+func (db *fileDB) ReadPath(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fn blobdb.ReadFunc) (bool, error) {
+	f, err := os.Open(db.filename)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	sf, err := dirsync.SparseFile(f)
+	if err != nil {
+		return false, err
+	}
+	return true, fn(sf)
+}
+
+func TestReadDirRejectsInvalidNames(t *testing.T) {
+	ctx := context.Background()
+	ll := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, name := range []string{"..", ".", "", "a/../../x", "a/b"} {
+		svc := &fakeService{dirs: map[string][]*typesv1.FileInfo{
+			"a": {{Name: "fine"}, {Name: name}},
+		}}
+		src := SourceAdapter(ctx, ll, serve(t, svc), testMeta)
+		_, err := src.ReadDir("a")
+		require.ErrorContains(t, err, "invalid entry name", name)
+	}
+
+	svc := &fakeService{dirs: map[string][]*typesv1.FileInfo{"a": {{Name: "fine"}, {Name: "..."}}}}
+	entries, err := SourceAdapter(ctx, ll, serve(t, svc), testMeta).ReadDir("a")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
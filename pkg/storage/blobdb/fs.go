@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
 	"github.com/aybabtme/syncy/pkg/logic/dirsync"
@@ -25,6 +26,14 @@ type Blob interface {
 	CreatePath(ctx context.Context, projectDir string, filename string, isDir bool, fn CreateFunc) (blake3_64_256_sum []byte, err error)
 	PatchPath(ctx context.Context, projectDir string, filename string, isDir bool, sum *typesv1.FileSum, fn PatchFunc) (blake3_64_256_sum []byte, err error)
 	DeletePath(ctx context.Context, projectDir string, filename string, isDir bool) error
+	MovePath(ctx context.Context, projectDir string, from, to string) error
+	ReadPath(ctx context.Context, projectDir string, filename string, fn ReadFunc) error
+	// uploads that can be resumed are kept in `namespace` until they're
+	// removed, see `Upload`
+	UploadSize(ctx context.Context, namespace string, id string) (int64, error)
+	OpenUpload(ctx context.Context, namespace string, id string, offset int64) (*Upload, error)
+	RemoveUpload(ctx context.Context, namespace string, id string) error
+	ExpireUploads(ctx context.Context, before time.Time) (int, error)
 }
 
 var _ Blob = (*LocalFS)(nil)
@@ -58,14 +67,21 @@ func NewLocalFS(root, scratch string) (*LocalFS, error) {
 func (lfs *LocalFS) Stat(ctx context.Context, projectDir, path string) (*typesv1.FileInfo, bool, error) {
 	rootDir := filepath.Join(lfs.root, projectDir)
 	filename := filepath.Join(rootDir, path)
-	fi, err := os.Stat(filename)
+	fi, err := os.Lstat(filename)
 	if os.IsNotExist(err) {
 		return nil, false, nil
 	}
 	if err != nil {
 		return nil, false, fmt.Errorf("localfs: can't stat, %w", err)
 	}
-	return typesv1.FileInfoFromFS(fi), true, nil
+	info := typesv1.FileInfoFromFS(fi)
+	if fi.Mode()&fs.ModeSymlink != 0 {
+		info.LinkTarget, err = os.Readlink(filename)
+		if err != nil {
+			return nil, false, fmt.Errorf("localfs: can't read symlink, %w", err)
+		}
+	}
+	return info, true, nil
 }
 
 func (lfs *LocalFS) ListDir(ctx context.Context, projectDir, path string) ([]*typesv1.FileInfo, bool, error) {
@@ -85,9 +101,16 @@ func (lfs *LocalFS) ListDir(ctx context.Context, projectDir, path string) ([]*ty
 		if err != nil {
 			return nil, true, fmt.Errorf("localfs: can't get dir entry info, %w", err)
 		}
-		// TODO: maybe encode symlinks somehow?
 		if fi.Mode().IsRegular() || fi.IsDir() {
 			out = append(out, typesv1.FileInfoFromFS(fi))
+		} else if fi.Mode()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(filepath.Join(filename, de.Name()))
+			if err != nil {
+				return nil, true, fmt.Errorf("localfs: can't read symlink, %w", err)
+			}
+			info := typesv1.FileInfoFromFS(fi)
+			info.LinkTarget = target
+			out = append(out, info)
 		}
 	}
 	return out, true, nil
@@ -212,6 +235,8 @@ func (lfs *LocalFS) withAtomicFileSwap(rootDir, filename string, fn CreateFunc)
 			_ = os.Remove(tmpFilename)
 		}
 	}()
+	// `fn` gets the file itself, so that holes written with
+	// `dirsync.WriteHole` are kept
 	sum, err := fn(tmpFile)
 	if err != nil {
 		return nil, fmt.Errorf("writing to scratch file: %w", err)
@@ -242,6 +267,51 @@ func (lfs *LocalFS) DeletePath(ctx context.Context, projectDir string, path stri
 	return os.Remove(endPath)
 }
 
+func (lfs *LocalFS) MovePath(ctx context.Context, projectDir string, from, to string) error {
+	rootDir := filepath.Join(lfs.root, projectDir)
+	fromPath, toPath := filepath.Join(rootDir, from), filepath.Join(rootDir, to)
+
+	unlockFrom, locked := lfs.takeLock(fromPath)
+	if !locked {
+		return fmt.Errorf("path is already locked by another request, try again later")
+	}
+	defer unlockFrom()
+	unlockTo, locked := lfs.takeLock(toPath)
+	if !locked {
+		return fmt.Errorf("path is already locked by another request, try again later")
+	}
+	defer unlockTo()
+
+	// renaming would silently replace a file
+	if _, err := os.Lstat(toPath); err == nil {
+		return fmt.Errorf("%q already exists", to)
+	}
+	if err := os.Rename(fromPath, toPath); err != nil {
+		return fmt.Errorf("renaming: %w", err)
+	}
+	return nil
+}
+
+type ReadFunc func(r io.Reader) error
+
+func (lfs *LocalFS) ReadPath(ctx context.Context, projectDir string, path string, fn ReadFunc) error {
+	rootDir := filepath.Join(lfs.root, projectDir)
+	endPath := filepath.Join(rootDir, path)
+
+	// no lock needed, files are swapped atomically when written
+	f, err := os.Open(endPath)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+	// holes can be skipped by readers
+	sf, err := dirsync.SparseFile(f)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+	return fn(sf)
+}
+
 func (lfs *LocalFS) takeLock(path string) (func(), bool) {
 	lfs.mu.Lock()
 	_, locked := lfs.locks[path]
@@ -0,0 +1,147 @@
+package blobdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aybabtme/syncy/pkg/logic/dirsync"
+)
+
+// ErrUploadIncomplete is the error of resuming an upload from further than
+// what was kept of it.
+var ErrUploadIncomplete = errors.New("less of the upload was kept than it's resumed from")
+
+// Upload is what's kept of a file as it's uploaded, for its upload to be
+// resumed if it's interrupted. What was kept before it was opened is read
+// with `Kept`, before anything is written to it.
+type Upload struct {
+	f      *os.File
+	unlock func()
+}
+
+// Kept reads what was kept of the upload before it was resumed, with its
+// holes.
+func (up *Upload) Kept() (io.Reader, error) {
+	if _, err := up.f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return dirsync.SparseFile(up.f)
+}
+
+func (up *Upload) Write(p []byte) (int, error) { return up.f.Write(p) }
+func (up *Upload) WriteHole(n int64) error     { return dirsync.WriteHole(up.f, n) }
+
+func (up *Upload) Close() error {
+	defer up.unlock()
+	return up.f.Close()
+}
+
+func (lfs *LocalFS) uploadPath(namespace, id string) string {
+	return filepath.Join(lfs.scratch, "uploads", namespace, id)
+}
+
+// UploadSize is how much was kept of the upload `id`, 0 if there's no such
+// upload.
+func (lfs *LocalFS) UploadSize(ctx context.Context, namespace, id string) (int64, error) {
+	fi, err := os.Stat(lfs.uploadPath(namespace, id))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("localfs: can't stat upload, %w", err)
+	}
+	return fi.Size(), nil
+}
+
+// OpenUpload opens the upload `id`, keeping what was kept of it up to
+// `offset`, and made if it's missing.
+func (lfs *LocalFS) OpenUpload(ctx context.Context, namespace, id string, offset int64) (*Upload, error) {
+	path := lfs.uploadPath(namespace, id)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating uploads dir: %w", err)
+	}
+	unlock, locked := lfs.takeLock(path)
+	if !locked {
+		return nil, fmt.Errorf("upload is already locked by another request, try again later")
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		unlock()
+		return nil, fmt.Errorf("opening upload: %w", err)
+	}
+	up := &Upload{f: f, unlock: unlock}
+	fi, err := f.Stat()
+	if err == nil && fi.Size() < offset {
+		err = fmt.Errorf("%d bytes kept, resuming from %d: %w", fi.Size(), offset, ErrUploadIncomplete)
+	}
+	if err == nil {
+		// what was received past the offset is sent again
+		err = f.Truncate(offset)
+	}
+	if err != nil {
+		_ = up.Close()
+		return nil, err
+	}
+	return up, nil
+}
+
+// RemoveUpload removes what was kept of the upload `id`.
+func (lfs *LocalFS) RemoveUpload(ctx context.Context, namespace, id string) error {
+	err := os.Remove(lfs.uploadPath(namespace, id))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("localfs: can't remove upload, %w", err)
+	}
+	return nil
+}
+
+// ExpireUploads removes what was kept of the uploads that weren't written to
+// since `before`, like the ones of files that changed before their upload was
+// resumed. It returns how many it removed.
+func (lfs *LocalFS) ExpireUploads(ctx context.Context, before time.Time) (int, error) {
+	removed := 0
+	err := filepath.WalkDir(filepath.Join(lfs.scratch, "uploads"), func(path string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		fi, err := d.Info()
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !fi.ModTime().Before(before) {
+			return nil
+		}
+		unlock, locked := lfs.takeLock(path)
+		if !locked {
+			// being resumed
+			return nil
+		}
+		defer unlock()
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		removed++
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("localfs: can't expire uploads, %w", err)
+	}
+	return removed, nil
+}
@@ -0,0 +1,89 @@
+package blobdb
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenUpload(t *testing.T) {
+	ctx := context.Background()
+	lfs, err := NewLocalFS(t.TempDir(), t.TempDir())
+	require.NoError(t, err)
+
+	up, err := lfs.OpenUpload(ctx, "ns", "id", 0)
+	require.NoError(t, err)
+	_, err = up.Write([]byte("hello world"))
+	require.NoError(t, err)
+	require.NoError(t, up.Close())
+
+	size, err := lfs.UploadSize(ctx, "ns", "id")
+	require.NoError(t, err)
+	require.EqualValues(t, 11, size)
+
+	_, err = lfs.OpenUpload(ctx, "ns", "id", 12)
+	require.ErrorIs(t, err, ErrUploadIncomplete)
+
+	// what's past the offset is sent again
+	up, err = lfs.OpenUpload(ctx, "ns", "id", 5)
+	require.NoError(t, err)
+	kept, err := up.Kept()
+	require.NoError(t, err)
+	got, err := io.ReadAll(kept)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(got))
+	_, err = up.Write([]byte(" there"))
+	require.NoError(t, err)
+	require.NoError(t, up.Close())
+
+	size, err = lfs.UploadSize(ctx, "ns", "id")
+	require.NoError(t, err)
+	require.EqualValues(t, 11, size)
+
+	require.NoError(t, lfs.RemoveUpload(ctx, "ns", "id"))
+	size, err = lfs.UploadSize(ctx, "ns", "id")
+	require.NoError(t, err)
+	require.Zero(t, size)
+}
+
+func TestExpireUploads(t *testing.T) {
+	ctx := context.Background()
+	lfs, err := NewLocalFS(t.TempDir(), t.TempDir())
+	require.NoError(t, err)
+
+	removed, err := lfs.ExpireUploads(ctx, time.Now())
+	require.NoError(t, err, "no uploads yet")
+	require.Zero(t, removed)
+
+	now := time.Now()
+	for id, mtime := range map[string]time.Time{
+		"old":    now.Add(-48 * time.Hour),
+		"recent": now.Add(-time.Hour),
+		"locked": now.Add(-48 * time.Hour),
+	} {
+		up, err := lfs.OpenUpload(ctx, "ns", id, 0)
+		require.NoError(t, err)
+		_, err = up.Write([]byte(id))
+		require.NoError(t, err)
+		require.NoError(t, up.Close())
+		require.NoError(t, os.Chtimes(lfs.uploadPath("ns", id), mtime, mtime))
+	}
+	// being resumed
+	unlock, locked := lfs.takeLock(lfs.uploadPath("ns", "locked"))
+	require.True(t, locked)
+	defer unlock()
+
+	removed, err = lfs.ExpireUploads(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	for id, want := range map[string]int64{"old": 0, "recent": 6, "locked": 6} {
+		size, err := lfs.UploadSize(ctx, "ns", id)
+		require.NoError(t, err)
+		require.Equal(t, want, size, id)
+	}
+}
@@ -1,9 +1,47 @@
 package metadb
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrAccountDoesntExist   = errors.New("account doesn't exist, create one")
 	ErrProjectDoesntExist   = errors.New("project doesn't exist, create one")
 	ErrParentDirDoesntExist = errors.New("parent directory doesn't exist, create it first")
+	ErrNotAFile             = errors.New("not a regular file")
+	ErrFileDoesntExist      = errors.New("file doesn't exist")
+	ErrNameCollision        = errors.New("name collides with the one of a sibling")
 )
+
+// NamePolicy is what's done with the entries of a project whose name
+// collides with the one of a sibling, see `dirsync.NameCollision`.
+type NamePolicy int
+
+const (
+	// NamesAllow stores names as they are.
+	NamesAllow NamePolicy = iota
+	// NamesWarn logs the collisions.
+	NamesWarn
+	// NamesReject fails with `ErrNameCollision`.
+	NamesReject
+	// NamesNormalize stores names in NFC form, and logs the collisions that
+	// are left. Names that were in another form on the source no longer
+	// match it, they're synced again every time.
+	NamesNormalize
+)
+
+func (np NamePolicy) String() string {
+	switch np {
+	case NamesAllow:
+		return "allow"
+	case NamesWarn:
+		return "warn"
+	case NamesReject:
+		return "reject"
+	case NamesNormalize:
+		return "normalize"
+	default:
+		return fmt.Sprintf("NamePolicy(%d)", int(np))
+	}
+}
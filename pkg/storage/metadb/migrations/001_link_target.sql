@@ -0,0 +1,5 @@
+-- symlinks are stored as files with their target, and no blob
+USE syncy;
+
+ALTER TABLE files
+    ADD COLUMN `link_target` VARCHAR(4096) DEFAULT NULL AFTER `blake3_64_256_sum`;
@@ -0,0 +1,5 @@
+-- hard links are stored as files with the path of the file they link to
+USE syncy;
+
+ALTER TABLE files
+    ADD COLUMN `hard_link` VARCHAR(4096) DEFAULT NULL AFTER `link_target`;
@@ -0,0 +1,8 @@
+-- owners and extended attributes of files and dirs, when they're synced
+USE syncy;
+
+ALTER TABLE dirs
+    ADD COLUMN `attrs` BLOB DEFAULT NULL AFTER `mode`;
+
+ALTER TABLE files
+    ADD COLUMN `attrs` BLOB DEFAULT NULL AFTER `hard_link`;
@@ -0,0 +1,6 @@
+-- what's done with the names that collide in a project, existing projects
+-- allow them
+USE syncy;
+
+ALTER TABLE projects
+    ADD COLUMN `name_policy` TINYINT NOT NULL DEFAULT 0 AFTER `created_at`;
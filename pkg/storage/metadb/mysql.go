@@ -14,12 +14,14 @@ import (
 	"github.com/aybabtme/syncy/pkg/logic/dirsync"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/noquark/nanoid"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Metadata interface {
 	CreateAccount(ctx context.Context, accountName string) (accountPublicID string, err error)
-	CreateProject(ctx context.Context, accountPublicID, projectName string, createBlobPath func(path string) error) (projectPublicID string, err error)
+	CreateProject(ctx context.Context, accountPublicID, projectName string, names NamePolicy, createBlobPath func(path string) error) (projectPublicID string, err error)
+	ProjectNamePolicy(ctx context.Context, accountPublicID, projectPublicID string) (NamePolicy, error)
 	Stat(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path) (*typesv1.FileInfo, bool, error)
 	ListDir(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path) ([]*typesv1.FileInfo, bool, error)
 	GetSignature(ctx context.Context, accountPublicID, projectPublicID string, fn ComputeFileSumAction) (*typesv1.DirSum, error)
@@ -27,6 +29,8 @@ type Metadata interface {
 	CreatePathTx(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fi *typesv1.FileInfo, fn FileSaveAction) error
 	PatchPathTx(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fi *typesv1.FileInfo, sum *typesv1.FileSum, fn FileSaveAction) error
 	DeletePath(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fi *typesv1.FileInfo, fn FileDeleteAction) error
+	MovePath(ctx context.Context, accountPublicID, projectPublicID string, from *typesv1.Path, fromInfo *typesv1.FileInfo, to *typesv1.Path, fi *typesv1.FileInfo, fn FileMoveAction) error
+	ReadPath(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fn FileReadAction) (bool, error)
 }
 
 type ComputeFileSumAction func(projectDir, filename string, fi *typesv1.FileInfo) (*typesv1.FileSum, bool, error)
@@ -35,6 +39,10 @@ type FileSaveAction func(projectDir, filepath string) (blake3_64_256_sum []byte,
 
 type FileDeleteAction func(projectDir, filepath string, fi *typesv1.FileInfo) error
 
+type FileReadAction func(projectDir, filepath string) error
+
+type FileMoveAction func(projectDir, fromFilepath, toFilepath string) error
+
 var _ Metadata = (*MySQL)(nil)
 
 type MySQL struct {
@@ -57,7 +65,7 @@ func (ms *MySQL) CreateAccount(ctx context.Context, accountName string) (account
 	return accountPublicID, nil
 }
 
-func (ms *MySQL) CreateProject(ctx context.Context, accountPublicID, projectName string, createBlobPath func(path string) error) (projectPublicID string, err error) {
+func (ms *MySQL) CreateProject(ctx context.Context, accountPublicID, projectName string, names NamePolicy, createBlobPath func(path string) error) (projectPublicID string, err error) {
 	projectPublicID = nanoid.Must(12)
 	return projectPublicID, withTx(ctx, ms.db, func(tx *sql.Tx) error {
 		accountInternalID, ok, err := findAccountID(ctx, tx, accountPublicID)
@@ -68,8 +76,8 @@ func (ms *MySQL) CreateProject(ctx context.Context, accountPublicID, projectName
 			return ErrAccountDoesntExist
 		}
 
-		_, err = tx.ExecContext(ctx, "INSERT INTO projects (`account_id`, `name`, `public_id`) VALUES (?, ?, ?)",
-			accountInternalID, projectName, projectPublicID,
+		_, err = tx.ExecContext(ctx, "INSERT INTO projects (`account_id`, `name`, `public_id`, `name_policy`) VALUES (?, ?, ?, ?)",
+			accountInternalID, projectName, projectPublicID, names,
 		)
 		if err != nil {
 			return fmt.Errorf("inserting project: %w", err)
@@ -80,6 +88,27 @@ func (ms *MySQL) CreateProject(ctx context.Context, accountPublicID, projectName
 	})
 }
 
+// ProjectNamePolicy is the policy the project was created with.
+func (ms *MySQL) ProjectNamePolicy(ctx context.Context, accountPublicID, projectPublicID string) (NamePolicy, error) {
+	var names NamePolicy
+	err := ms.db.QueryRowContext(ctx,
+		"SELECT projects.`name_policy` FROM projects\n"+
+			"JOIN accounts ON (accounts.`id` = projects.`account_id`)\n"+
+			"WHERE accounts.`public_id` = ? AND\n"+
+			"      projects.`public_id` = ?\n"+
+			"LIMIT 1",
+		accountPublicID,
+		projectPublicID,
+	).Scan(&names)
+	if err == sql.ErrNoRows {
+		return 0, ErrProjectDoesntExist
+	}
+	if err != nil {
+		return 0, fmt.Errorf("looking up name policy: %w", err)
+	}
+	return names, nil
+}
+
 func filepathName(path *typesv1.Path, fi *typesv1.FileInfo) string {
 	if fi == nil {
 		return typesv1.StringFromPath(path)
@@ -353,17 +382,19 @@ func getFileInfo(ctx context.Context, ll *slog.Logger, querier querier, projectI
 	return scanFileInfo(row)
 }
 
-const dirInfoColumns = "`name`, `mod_time_unix_ns`, `mode`"
+const dirInfoColumns = "`name`, `mod_time_unix_ns`, `mode`, `attrs`"
 
 func scanDirInfo(row scanner) (*typesv1.FileInfo, bool, error) {
 	var (
 		modTimeUnixNs int64
+		attrs         []byte
 	)
 	fi := new(typesv1.FileInfo)
 	if err := row.Scan(
 		&fi.Name,
 		&modTimeUnixNs,
 		&fi.Mode,
+		&attrs,
 	); err == sql.ErrNoRows {
 		return nil, false, nil
 	} else if err != nil {
@@ -371,14 +402,20 @@ func scanDirInfo(row scanner) (*typesv1.FileInfo, bool, error) {
 	}
 	fi.IsDir = true
 	fi.ModTime = timestamppb.New(time.Unix(0, modTimeUnixNs))
+	if err := scanAttrs(fi, attrs); err != nil {
+		return nil, false, err
+	}
 	return fi, true, nil
 }
 
-const fileInfoColumns = "`name`, `size`, `mod_time_unix_ns`, `mode`"
+const fileInfoColumns = "`name`, `size`, `mod_time_unix_ns`, `mode`, `link_target`, `hard_link`, `attrs`"
 
 func scanFileInfo(row scanner) (*typesv1.FileInfo, bool, error) {
 	var (
 		modTimeUnixNs int64
+		linkTarget    sql.NullString
+		hardLink      sql.NullString
+		attrs         []byte
 	)
 	fi := new(typesv1.FileInfo)
 	if err := row.Scan(
@@ -386,6 +423,9 @@ func scanFileInfo(row scanner) (*typesv1.FileInfo, bool, error) {
 		&fi.Size,
 		&modTimeUnixNs,
 		&fi.Mode,
+		&linkTarget,
+		&hardLink,
+		&attrs,
 	); err == sql.ErrNoRows {
 		return nil, false, nil
 	} else if err != nil {
@@ -393,9 +433,40 @@ func scanFileInfo(row scanner) (*typesv1.FileInfo, bool, error) {
 	}
 	fi.IsDir = false
 	fi.ModTime = timestamppb.New(time.Unix(0, modTimeUnixNs))
+	fi.LinkTarget = linkTarget.String
+	if hardLink.Valid {
+		fi.HardLink = typesv1.PathFromString(hardLink.String)
+	}
+	if err := scanAttrs(fi, attrs); err != nil {
+		return nil, false, err
+	}
 	return fi, true, nil
 }
 
+// attrsColumn encodes the attributes of `fi`, they're only ever read back
+// whole. It's NULL if there are none.
+func attrsColumn(fi *typesv1.FileInfo) ([]byte, error) {
+	if fi.Attrs == nil {
+		return nil, nil
+	}
+	attrs, err := proto.Marshal(fi.Attrs)
+	if err != nil {
+		return nil, fmt.Errorf("encoding attrs: %w", err)
+	}
+	return attrs, nil
+}
+
+func scanAttrs(fi *typesv1.FileInfo, attrs []byte) error {
+	if attrs == nil {
+		return nil
+	}
+	fi.Attrs = new(typesv1.FileAttrs)
+	if err := proto.Unmarshal(attrs, fi.Attrs); err != nil {
+		return fmt.Errorf("decoding attrs: %w", err)
+	}
+	return nil
+}
+
 func (ms *MySQL) GetSignature(ctx context.Context, accountPublicID, projectPublicID string, fn ComputeFileSumAction) (*typesv1.DirSum, error) {
 	ll := ms.ll.With(
 		slog.String("account_pub_id", accountPublicID),
@@ -466,10 +537,14 @@ func (ms *MySQL) GetFileSum(ctx context.Context, accountPublicID, projectPublicI
 	if !ok {
 		return nil, false, nil
 	}
-	return ms.getFileSum(ctx, ll, projectDir, path, fi, fn)
+	return ms.getFileSum(ctx, ll, projectDir, typesv1.DirOf(path), fi, fn)
 }
 
 func (ms *MySQL) getFileSum(ctx context.Context, ll *slog.Logger, projectDir string, path *typesv1.Path, fi *typesv1.FileInfo, fn ComputeFileSumAction) (*typesv1.FileSum, bool, error) {
+	if fi.IsSymlink() {
+		// symlinks have no blob, their target is in their info
+		return &typesv1.FileSum{Info: fi}, true, nil
+	}
 
 	filepath := filepathName(path, fi)
 	ll.DebugContext(ctx, "file found, computing filesum", slog.String("filepath", filepath))
@@ -526,6 +601,14 @@ func (ms *MySQL) CreatePathTx(ctx context.Context, accountPublicID, projectPubli
 			}
 			return nil
 		}
+		if fi.IsSymlink() {
+			// symlinks are only stored here, never in blobs
+			_, err = createSymlink(ctx, tx, projectID, parentDirID, fi.Name, fi)
+			if err != nil {
+				return fmt.Errorf("creating symlink in mysql: %w", err)
+			}
+			return nil
+		}
 
 		pendingFileID, err = createPendingFile(ctx, tx, projectID, parentDirID, fi.Name, fi)
 		if err != nil {
@@ -536,7 +619,7 @@ func (ms *MySQL) CreatePathTx(ctx context.Context, accountPublicID, projectPubli
 	if err != nil {
 		return err
 	}
-	if fi.IsDir {
+	if fi.IsDir || fi.IsSymlink() {
 		return nil // we're done
 	}
 	sum, err := fn(projectDir, filepath)
@@ -601,6 +684,13 @@ func (ms *MySQL) PatchPathTx(ctx context.Context, accountPublicID, projectPublic
 		if !ok {
 			return fmt.Errorf("file doesn't exist, cannot be patched")
 		}
+		if fi.IsSymlink() {
+			err = updateSymlinkInfo(ctx, tx, projectID, fileID, fi)
+			if err != nil {
+				return fmt.Errorf("updating symlink info in mysql: %w", err)
+			}
+			return nil
+		}
 
 		pendingFileID, err = markFileAsPending(ctx, tx, projectID, fileID, fi)
 		if err != nil {
@@ -611,7 +701,7 @@ func (ms *MySQL) PatchPathTx(ctx context.Context, accountPublicID, projectPublic
 	if err != nil {
 		return err
 	}
-	if fi.IsDir {
+	if fi.IsDir || fi.IsSymlink() {
 		return nil // we're done
 	}
 	blake3sum, err := fn(projectDir, filepath)
@@ -647,6 +737,9 @@ func (ms *MySQL) DeletePath(ctx context.Context, accountPublicID, projectPublicI
 		if err != nil {
 			return fmt.Errorf("deleting path in metadata: %w", err)
 		}
+		if fi.IsSymlink() {
+			return nil // never stored in blobs
+		}
 		err = fn(projectDir, filepath, fi)
 		if err != nil {
 			return fmt.Errorf("deleting from blobs: %w", err)
@@ -659,6 +752,127 @@ func (ms *MySQL) DeletePath(ctx context.Context, accountPublicID, projectPublicI
 	return nil
 }
 
+func (ms *MySQL) MovePath(ctx context.Context, accountPublicID, projectPublicID string, from *typesv1.Path, fromInfo *typesv1.FileInfo, to *typesv1.Path, fi *typesv1.FileInfo, fn FileMoveAction) error {
+	ll := ms.ll.With(
+		slog.String("account_pub_id", accountPublicID),
+		slog.String("project_pub_id", projectPublicID),
+		slog.String("from", typesv1.StringFromPath(from)),
+		slog.String("to", typesv1.StringFromPath(to)),
+	)
+	ll.DebugContext(ctx, "MovePath")
+	projectID, ok, err := findProjectID(ctx, ms.db, accountPublicID, projectPublicID)
+	if err != nil {
+		return fmt.Errorf("finding project ID: %w", err)
+	}
+	if !ok {
+		return ErrProjectDoesntExist
+	}
+
+	projectDir := filepath.Join(accountPublicID, projectPublicID)
+	fromFilepath, toFilepath := filepathName(from, nil), filepathName(to, nil)
+	err = withTx(ctx, ms.db, func(tx *sql.Tx) error {
+		fromParentDirID, err := findParentDir(ctx, ll, tx, projectID, from)
+		if err != nil {
+			return fmt.Errorf("looking up parent dir of source: %w", err)
+		}
+		toParentDirID, err := findParentDir(ctx, ll, tx, projectID, to)
+		if err != nil {
+			return fmt.Errorf("looking up parent dir of destination: %w", err)
+		}
+		fromName := from.Elements[len(from.Elements)-1]
+		table, parentColumn := "files", "dir_id"
+		if fromInfo.IsDir {
+			table, parentColumn = "dirs", "parent_id"
+		}
+		err = moveEntry(ctx, tx, table, parentColumn, projectID, fromParentDirID, fromName, toParentDirID, fi)
+		if err != nil {
+			return fmt.Errorf("moving path in metadata: %w", err)
+		}
+		if fromInfo.IsSymlink() {
+			return nil // never stored in blobs
+		}
+		if err := fn(projectDir, fromFilepath, toFilepath); err != nil {
+			return fmt.Errorf("moving in blobs: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("moving path %q to %q: %w", fromFilepath, toFilepath, err)
+	}
+	return nil
+}
+
+// moveEntry moves a row of `table` to another parent, and gives it the name,
+// mod time and mode of `fi`.
+func moveEntry(ctx context.Context, execer execer, table, parentColumn string, projectID uint64, parentDirID *uint64, name string, toParentDirID *uint64, fi *typesv1.FileInfo) error {
+	attrs, err := attrsColumn(fi)
+	if err != nil {
+		return err
+	}
+	query := "UPDATE " + table + "\n" +
+		"SET\n" +
+		"	`" + parentColumn + "`=?,\n" +
+		"	`name`=?,\n" +
+		"	`mod_time_unix_ns`=?,\n" +
+		"	`mode`=?,\n" +
+		"	`attrs`=?\n" +
+		"WHERE `project_id` = ? AND\n"
+	args := []any{toParentDirID, fi.Name, fi.ModTime.AsTime().UnixNano(), fi.Mode, attrs, projectID}
+	if parentDirID != nil {
+		query += "	`" + parentColumn + "` = ? AND\n"
+		args = append(args, *parentDirID)
+	} else {
+		query += "	`" + parentColumn + "` IS NULL AND\n"
+	}
+	query += "	`name` = ?\n" +
+		"LIMIT 1"
+	args = append(args, name)
+	res, err := execer.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("execing update of %s: %w", table, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("counting rows affected: %w", err)
+	}
+	if n != 1 {
+		return fmt.Errorf("no such entry in %s", table)
+	}
+	return nil
+}
+
+func (ms *MySQL) ReadPath(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fn FileReadAction) (bool, error) {
+	ll := ms.ll.With(
+		slog.String("account_pub_id", accountPublicID),
+		slog.String("project_pub_id", projectPublicID),
+		slog.String("path", typesv1.StringFromPath(path)),
+	)
+	ll.DebugContext(ctx, "ReadPath")
+	projectID, ok, err := findProjectID(ctx, ms.db, accountPublicID, projectPublicID)
+	if err != nil {
+		return false, fmt.Errorf("finding project ID: %w", err)
+	}
+	if !ok {
+		return false, ErrProjectDoesntExist
+	}
+	fi, ok, err := ms.stat(ctx, ll, projectID, path)
+	if err != nil {
+		return false, fmt.Errorf("stating file: %w", err)
+	}
+	if !ok {
+		return false, nil
+	}
+	if fi.IsDir || fi.IsSymlink() {
+		// only regular files have content
+		return true, ErrNotAFile
+	}
+	projectDir := filepath.Join(accountPublicID, projectPublicID)
+	if err := fn(projectDir, filepathName(path, nil)); err != nil {
+		return true, fmt.Errorf("reading from blobs: %w", err)
+	}
+	return true, nil
+}
+
 func deletePath(ctx context.Context, ll *slog.Logger, execer execer, projectID uint64, path *typesv1.Path, fi *typesv1.FileInfo) error {
 	n := len(path.Elements)
 	filename := path.Elements[n-1]
@@ -796,28 +1010,33 @@ func findParentDir(ctx context.Context, ll *slog.Logger, execer execer, projectI
 }
 
 func createPendingFile(ctx context.Context, execer execer, projectID uint64, parentDirID *uint64, name string, fi *typesv1.FileInfo) (uint64, error) {
-	var (
-		res sql.Result
-		err error
-	)
+	attrs, err := attrsColumn(fi)
+	if err != nil {
+		return 0, err
+	}
+	var res sql.Result
 	if parentDirID != nil {
 		res, err = execer.ExecContext(ctx,
-			"INSERT INTO files (`project_id`, `dir_id`, `name`, `size`, `mod_time_unix_ns`, `mode`) VALUES (?,?,?,?,?,?)",
+			"INSERT INTO files (`project_id`, `dir_id`, `name`, `size`, `mod_time_unix_ns`, `mode`, `hard_link`, `attrs`) VALUES (?,?,?,?,?,?,?,?)",
 			projectID,
 			parentDirID,
 			name,
 			fi.Size,
 			fi.ModTime.AsTime().UnixNano(),
 			fi.Mode,
+			hardLinkColumn(fi),
+			attrs,
 		)
 	} else {
 		res, err = execer.ExecContext(ctx,
-			"INSERT INTO files (`project_id`, `name`, `size`, `mod_time_unix_ns`, `mode`) VALUES (?,?,?,?,?)",
+			"INSERT INTO files (`project_id`, `name`, `size`, `mod_time_unix_ns`, `mode`, `hard_link`, `attrs`) VALUES (?,?,?,?,?,?,?)",
 			projectID,
 			name,
 			fi.Size,
 			fi.ModTime.AsTime().UnixNano(),
 			fi.Mode,
+			hardLinkColumn(fi),
+			attrs,
 		)
 	}
 	if err != nil {
@@ -838,6 +1057,85 @@ func createPendingFile(ctx context.Context, execer execer, projectID uint64, par
 	return uint64(fileID), nil
 }
 
+// hardLinkColumn is the path of the file that `fi` is a hard link to, NULL
+// if it isn't one.
+func hardLinkColumn(fi *typesv1.FileInfo) sql.NullString {
+	if fi.HardLink == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: strings.Join(fi.HardLink.Elements, "/"), Valid: true}
+}
+
+func createSymlink(ctx context.Context, execer execer, projectID uint64, parentDirID *uint64, name string, fi *typesv1.FileInfo) (uint64, error) {
+	var (
+		res sql.Result
+		err error
+	)
+	if parentDirID != nil {
+		res, err = execer.ExecContext(ctx,
+			"INSERT INTO files (`project_id`, `dir_id`, `name`, `size`, `mod_time_unix_ns`, `mode`, `link_target`) VALUES (?,?,?,?,?,?,?)",
+			projectID,
+			parentDirID,
+			name,
+			fi.Size,
+			fi.ModTime.AsTime().UnixNano(),
+			fi.Mode,
+			fi.LinkTarget,
+		)
+	} else {
+		res, err = execer.ExecContext(ctx,
+			"INSERT INTO files (`project_id`, `name`, `size`, `mod_time_unix_ns`, `mode`, `link_target`) VALUES (?,?,?,?,?,?)",
+			projectID,
+			name,
+			fi.Size,
+			fi.ModTime.AsTime().UnixNano(),
+			fi.Mode,
+			fi.LinkTarget,
+		)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("inserting symlink: %w", err)
+	}
+	fileID, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("getting file ID: %w", err)
+	}
+	return uint64(fileID), nil
+}
+
+func updateSymlinkInfo(ctx context.Context, execer execer, projectID uint64, fileID uint64, fi *typesv1.FileInfo) error {
+	var isSymlink bool
+	err := execer.QueryRowContext(ctx,
+		"SELECT `link_target` IS NOT NULL FROM files WHERE `project_id` = ? AND `id` = ? LIMIT 1",
+		projectID, fileID,
+	).Scan(&isSymlink)
+	if err != nil {
+		return fmt.Errorf("looking up file: %w", err)
+	}
+	if !isSymlink {
+		return fmt.Errorf("file isn't a symlink, it can't be patched into one")
+	}
+	_, err = execer.ExecContext(ctx,
+		"UPDATE files\n"+
+			"SET\n"+
+			"	`size` = ?,\n"+
+			"	`mod_time_unix_ns` = ?,\n"+
+			"	`mode` = ?,\n"+
+			"	`link_target` = ?\n"+
+			"WHERE `project_id` = ? AND `id` = ? LIMIT 1",
+		fi.Size,
+		fi.ModTime.AsTime().UnixNano(),
+		fi.Mode,
+		fi.LinkTarget,
+		projectID,
+		fileID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating symlink: %w", err)
+	}
+	return nil
+}
+
 func markFileAsPending(ctx context.Context, execer execer, projectID uint64, fileID uint64, fi *typesv1.FileInfo) (uint64, error) {
 	res, err := execer.ExecContext(ctx,
 		"INSERT INTO pending_files (`file_id`) VALUES (?)",
@@ -865,6 +1163,10 @@ func finishPendingFile(ctx context.Context, db *sql.DB, pendingFileID uint64, bl
 }
 
 func finishPendingPatchFile(ctx context.Context, db *sql.DB, pendingFileID uint64, fi *typesv1.FileInfo, blake3_64_256_sum []byte) error {
+	attrs, err := attrsColumn(fi)
+	if err != nil {
+		return err
+	}
 	return withTx(ctx, db, func(tx *sql.Tx) error {
 		_, err := tx.ExecContext(ctx,
 			"UPDATE files\n"+
@@ -872,11 +1174,15 @@ func finishPendingPatchFile(ctx context.Context, db *sql.DB, pendingFileID uint6
 				"	`size` = ?,\n"+
 				"	`mod_time_unix_ns` = ?,\n"+
 				"	`mode` = ?,\n"+
+				"	`hard_link` = ?,\n"+
+				"	`attrs` = ?,\n"+
 				"	`blake3_64_256_sum` = ?\n"+
 				"WHERE id = ? LIMIT 1",
 			fi.Size,
 			fi.ModTime.AsTime().UnixNano(),
 			fi.Mode,
+			hardLinkColumn(fi),
+			attrs,
 			blake3_64_256_sum,
 			pendingFileID,
 		)
@@ -892,19 +1198,23 @@ func finishPendingPatchFile(ctx context.Context, db *sql.DB, pendingFileID uint6
 }
 
 func updateDirInfo(ctx context.Context, execer execer, projectID uint64, parentDirID *uint64, name string, fi *typesv1.FileInfo) error {
-	var err error
+	attrs, err := attrsColumn(fi)
+	if err != nil {
+		return err
+	}
 	if parentDirID != nil {
 		_, err = execer.ExecContext(ctx,
 			"UPDATE dirs\n"+
 				"SET\n"+
 				"	`name`=?,\n"+
 				"	`mod_time_unix_ns`=?,\n"+
-				"	`mode`=?\n"+
+				"	`mode`=?,\n"+
+				"	`attrs`=?\n"+
 				"WHERE `project_id` = ? AND\n"+
 				"	`parent_id` = ? AND\n"+
 				"	`name` = ?\n"+
 				"LIMIT 1",
-			fi.Name, fi.ModTime.AsTime().UnixNano(), fi.Mode,
+			fi.Name, fi.ModTime.AsTime().UnixNano(), fi.Mode, attrs,
 			projectID, *parentDirID, name,
 		)
 	} else {
@@ -913,12 +1223,13 @@ func updateDirInfo(ctx context.Context, execer execer, projectID uint64, parentD
 				"SET\n"+
 				"	`name`=?,\n"+
 				"	`mod_time_unix_ns`=?,\n"+
-				"	`mode`=?\n"+
+				"	`mode`=?,\n"+
+				"	`attrs`=?\n"+
 				"WHERE `project_id` = ? AND\n"+
 				"	`parent_id` IS NULL AND\n"+
 				"	`name` = ?\n"+
 				"LIMIT 1",
-			fi.Name, fi.ModTime.AsTime().UnixNano(), fi.Mode,
+			fi.Name, fi.ModTime.AsTime().UnixNano(), fi.Mode, attrs,
 			projectID, name,
 		)
 	}
@@ -973,27 +1284,30 @@ func createDir(ctx context.Context,
 	name string,
 	fi *typesv1.FileInfo,
 ) (uint64, error) {
-	var (
-		res sql.Result
-		err error
-	)
+	attrs, err := attrsColumn(fi)
+	if err != nil {
+		return 0, err
+	}
+	var res sql.Result
 	if parentDirID != nil {
 
 		res, err = execer.ExecContext(ctx,
-			"INSERT INTO dirs (`project_id`, `parent_id`, `name`, `mod_time_unix_ns`, `mode`) VALUES (?, ?, ?, ?, ?)",
+			"INSERT INTO dirs (`project_id`, `parent_id`, `name`, `mod_time_unix_ns`, `mode`, `attrs`) VALUES (?, ?, ?, ?, ?, ?)",
 			projectID,
 			parentDirID,
 			name,
 			fi.ModTime.AsTime().UnixNano(),
 			fi.Mode,
+			attrs,
 		)
 	} else {
 		res, err = execer.ExecContext(ctx,
-			"INSERT INTO dirs (`project_id`, `name`, `mod_time_unix_ns`, `mode`) VALUES (?, ?, ?, ?)",
+			"INSERT INTO dirs (`project_id`, `name`, `mod_time_unix_ns`, `mode`, `attrs`) VALUES (?, ?, ?, ?, ?)",
 			projectID,
 			name,
 			fi.ModTime.AsTime().UnixNano(),
 			fi.Mode,
+			attrs,
 		)
 	}
 	if err != nil {
@@ -1,3 +1,6 @@
+-- the schema of a new database, which drops any existing one. Databases made
+-- with an earlier schema are brought up to this one with the scripts of
+-- `migrations`, in order.
 CREATE DATABASE IF NOT EXISTS syncy;
 USE syncy;
 DROP TABLE IF EXISTS accounts;
@@ -21,6 +24,8 @@ CREATE TABLE projects (
     `name` VARCHAR(255) NOT NULL,
     `public_id` VARCHAR(255) NOT NULL,
     `created_at` TIMESTAMP DEFAULT NOW(),
+    -- `NamePolicy`
+    `name_policy` TINYINT NOT NULL DEFAULT 0,
     UNIQUE (`account_id`, `name`)
 );
 
@@ -31,6 +36,8 @@ CREATE TABLE dirs (
     `name` VARCHAR(255) NOT NULL,
     `mod_time_unix_ns` BIGINT NOT NULL,
     `mode` INT UNSIGNED NOT NULL,
+    -- `FileAttrs`, only set when synced
+    `attrs` BLOB DEFAULT NULL,
     UNIQUE (`project_id`, `parent_id`, `name`)
 );
 
@@ -45,6 +52,13 @@ CREATE TABLE files (
     `mode` INT UNSIGNED NOT NULL,
 
     `blake3_64_256_sum` BINARY(64) DEFAULT NULL,
+    -- only set for symlinks, which have no blob
+    `link_target` VARCHAR(4096) DEFAULT NULL,
+    -- only set for hard links, the path of the file they link to, whose
+    -- blob is copied as theirs
+    `hard_link` VARCHAR(4096) DEFAULT NULL,
+    -- `FileAttrs`, only set when synced
+    `attrs` BLOB DEFAULT NULL,
 
     UNIQUE (`project_id`, `dir_id`, `name`)
 );
@@ -2,35 +2,69 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"path/filepath"
 	"regexp"
+	"time"
 
 	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/aybabtme/syncy/pkg/logic/dirsync"
 	"github.com/aybabtme/syncy/pkg/storage/blobdb"
 	"github.com/aybabtme/syncy/pkg/storage/metadb"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
 	ErrAccountDoesntExist   = metadb.ErrAccountDoesntExist
 	ErrProjectDoesntExist   = metadb.ErrProjectDoesntExist
 	ErrParentDirDoesntExist = metadb.ErrParentDirDoesntExist
+	ErrNotAFile             = metadb.ErrNotAFile
+	ErrFileDoesntExist      = metadb.ErrFileDoesntExist
+	ErrNameCollision        = metadb.ErrNameCollision
+	ErrUploadIncomplete     = blobdb.ErrUploadIncomplete
+	ErrInvalidUploadID      = errors.New("invalid upload id")
+)
+
+// NamePolicy is what's done with the entries of a project whose name
+// collides with the one of a sibling.
+type NamePolicy = metadb.NamePolicy
+
+// Upload is what's kept of a file as it's uploaded, see `State.OpenUpload`.
+type Upload = blobdb.Upload
+
+const (
+	NamesAllow     = metadb.NamesAllow
+	NamesWarn      = metadb.NamesWarn
+	NamesReject    = metadb.NamesReject
+	NamesNormalize = metadb.NamesNormalize
 )
 
 type DB interface {
 	CreateAccount(ctx context.Context, accountName string) (accountPublicID string, err error)
-	CreateProject(ctx context.Context, accountPublicID, projectName string) (projectPublicID string, err error)
+	CreateProject(ctx context.Context, accountPublicID, projectName string, names NamePolicy) (projectPublicID string, err error)
+	ProjectNamePolicy(ctx context.Context, accountPublicID, projectPublicID string) (NamePolicy, error)
 	Stat(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path) (*typesv1.FileInfo, bool, error)
 	ListDir(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path) ([]*typesv1.FileInfo, bool, error)
 	GetSignature(ctx context.Context, accountPublicID, projectPublicID string) (*typesv1.DirSum, error)
 	GetFileSum(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path) (*typesv1.FileSum, bool, error)
+	GetTree(ctx context.Context, accountPublicID, projectPublicID string) (*typesv1.DirSum, error)
+	GetFileSums(ctx context.Context, accountPublicID, projectPublicID string, paths []*typesv1.Path) ([]*typesv1.FileSum, error)
 	CreatePath(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fi *typesv1.FileInfo, fn blobdb.CreateFunc) error
 	PatchPath(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fi *typesv1.FileInfo, sum *typesv1.FileSum, fn blobdb.PatchFunc) error
 	DeletePath(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fi *typesv1.FileInfo) error
+	MovePath(ctx context.Context, accountPublicID, projectPublicID string, from *typesv1.Path, fromInfo *typesv1.FileInfo, parentDir *typesv1.Path, fi *typesv1.FileInfo) error
+	ReadPath(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fn blobdb.ReadFunc) (bool, error)
+	UploadSize(ctx context.Context, accountPublicID, projectPublicID, uploadID string) (uint64, error)
+	OpenUpload(ctx context.Context, accountPublicID, projectPublicID, uploadID string, offset uint64) (*Upload, error)
+	RemoveUpload(ctx context.Context, accountPublicID, projectPublicID, uploadID string) error
 }
 
 var _ DB = (*State)(nil)
 
 type State struct {
+	ll *slog.Logger
 	// metadata such as FileMode (permissions), ModTime, are saved here
 	meta metadb.Metadata
 	// only dumb data is saved here, permissions/mode/times are not replicated
@@ -38,13 +72,16 @@ type State struct {
 	blob blobdb.Blob
 }
 
-func NewState(meta metadb.Metadata, blob blobdb.Blob) *State {
-	return &State{meta: meta, blob: blob}
+func NewState(ll *slog.Logger, meta metadb.Metadata, blob blobdb.Blob) *State {
+	return &State{ll: ll, meta: meta, blob: blob}
 }
 
 var (
 	AccountNameRegexp = regexp.MustCompile(`[a-zA-Z0-9-+_]+`)
 	ProjectNameRegexp = regexp.MustCompile(`[a-zA-Z0-9-+_]+`)
+	// ids of uploads, and the public ids of the accounts and projects they're
+	// kept for, are used as file names
+	uploadIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9-_]{1,64}$`)
 )
 
 func (state *State) CreateAccount(ctx context.Context, accountName string) (accountPublicID string, err error) {
@@ -54,15 +91,19 @@ func (state *State) CreateAccount(ctx context.Context, accountName string) (acco
 	return state.meta.CreateAccount(ctx, accountName)
 }
 
-func (state *State) CreateProject(ctx context.Context, accountPublicID, projectName string) (projectPublicID string, err error) {
+func (state *State) CreateProject(ctx context.Context, accountPublicID, projectName string, names NamePolicy) (projectPublicID string, err error) {
 	if !ProjectNameRegexp.MatchString(projectName) {
 		return "", fmt.Errorf("invalid project name: doesn't match regexp %s", ProjectNameRegexp.String())
 	}
-	return state.meta.CreateProject(ctx, accountPublicID, projectName, func(path string) error {
+	return state.meta.CreateProject(ctx, accountPublicID, projectName, names, func(path string) error {
 		return state.blob.CreateProjectRootPath(ctx, path)
 	})
 }
 
+func (state *State) ProjectNamePolicy(ctx context.Context, accountPublicID, projectPublicID string) (NamePolicy, error) {
+	return state.meta.ProjectNamePolicy(ctx, accountPublicID, projectPublicID)
+}
+
 func (state *State) Stat(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path) (*typesv1.FileInfo, bool, error) {
 	return state.meta.Stat(ctx, accountPublicID, projectPublicID, path)
 }
@@ -83,7 +124,34 @@ func (state *State) GetFileSum(ctx context.Context, accountPublicID, projectPubl
 	})
 }
 
+// GetTree is `GetSignature` without summing the content of files, they only
+// have their info.
+func (state *State) GetTree(ctx context.Context, accountPublicID, projectPublicID string) (*typesv1.DirSum, error) {
+	return state.meta.GetSignature(ctx, accountPublicID, projectPublicID, func(projectDir, filename string, fi *typesv1.FileInfo) (*typesv1.FileSum, bool, error) {
+		return &typesv1.FileSum{Info: fi}, true, nil
+	})
+}
+
+func (state *State) GetFileSums(ctx context.Context, accountPublicID, projectPublicID string, paths []*typesv1.Path) ([]*typesv1.FileSum, error) {
+	sums := make([]*typesv1.FileSum, 0, len(paths))
+	for _, path := range paths {
+		sum, ok, err := state.GetFileSum(ctx, accountPublicID, projectPublicID, path)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrFileDoesntExist, typesv1.StringFromPath(path))
+		}
+		sums = append(sums, sum)
+	}
+	return sums, nil
+}
+
 func (state *State) CreatePath(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fi *typesv1.FileInfo, fn blobdb.CreateFunc) error {
+	fi, err := state.checkName(ctx, accountPublicID, projectPublicID, path, fi)
+	if err != nil {
+		return err
+	}
 	// todo: do it in a transaction for safe rollback in case of mid-flight failure
 	// todo: store all the FileInfo and full Path in metadata, only store filename + data in blobs
 	return state.meta.CreatePathTx(ctx, accountPublicID, projectPublicID, path, fi, func(projectDir, filename string) (blake3_64_256_sum []byte, err error) {
@@ -104,3 +172,104 @@ func (state *State) DeletePath(ctx context.Context, accountPublicID, projectPubl
 		return state.blob.DeletePath(ctx, projectDir, filename, fi.IsDir)
 	})
 }
+
+func (state *State) MovePath(ctx context.Context, accountPublicID, projectPublicID string, from *typesv1.Path, fromInfo *typesv1.FileInfo, parentDir *typesv1.Path, fi *typesv1.FileInfo) error {
+	fi, err := state.checkName(ctx, accountPublicID, projectPublicID, parentDir, fi)
+	if err != nil {
+		return err
+	}
+	to := typesv1.PathJoin(parentDir, fi.Name)
+	return state.meta.MovePath(ctx, accountPublicID, projectPublicID, from, fromInfo, to, fi, func(projectDir, fromFilepath, toFilepath string) error {
+		return state.blob.MovePath(ctx, projectDir, fromFilepath, toFilepath)
+	})
+}
+
+func (state *State) ReadPath(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fn blobdb.ReadFunc) (bool, error) {
+	return state.meta.ReadPath(ctx, accountPublicID, projectPublicID, path, func(projectDir, filename string) error {
+		return state.blob.ReadPath(ctx, projectDir, filename, fn)
+	})
+}
+
+func (state *State) UploadSize(ctx context.Context, accountPublicID, projectPublicID, uploadID string) (uint64, error) {
+	namespace, err := uploadNamespace(accountPublicID, projectPublicID, uploadID)
+	if err != nil {
+		return 0, err
+	}
+	size, err := state.blob.UploadSize(ctx, namespace, uploadID)
+	return uint64(size), err
+}
+
+// OpenUpload opens the upload `uploadID` to resume it from `offset`, or to
+// start it if `offset` is 0. It's kept until it's removed.
+func (state *State) OpenUpload(ctx context.Context, accountPublicID, projectPublicID, uploadID string, offset uint64) (*Upload, error) {
+	namespace, err := uploadNamespace(accountPublicID, projectPublicID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	return state.blob.OpenUpload(ctx, namespace, uploadID, int64(offset))
+}
+
+func (state *State) RemoveUpload(ctx context.Context, accountPublicID, projectPublicID, uploadID string) error {
+	namespace, err := uploadNamespace(accountPublicID, projectPublicID, uploadID)
+	if err != nil {
+		return err
+	}
+	return state.blob.RemoveUpload(ctx, namespace, uploadID)
+}
+
+// ExpireUploads removes what was kept of the uploads of all projects that
+// weren't resumed for `maxAge`.
+func (state *State) ExpireUploads(ctx context.Context, maxAge time.Duration) (int, error) {
+	return state.blob.ExpireUploads(ctx, time.Now().Add(-maxAge))
+}
+
+// uploadNamespace is where the uploads of a project are kept.
+func uploadNamespace(accountPublicID, projectPublicID, uploadID string) (string, error) {
+	for _, id := range []string{accountPublicID, projectPublicID, uploadID} {
+		if !uploadIDRegexp.MatchString(id) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidUploadID, id)
+		}
+	}
+	return filepath.Join(accountPublicID, projectPublicID), nil
+}
+
+// checkName applies the name policy of the project to `fi`, about to be
+// added to the dir at `path`. It returns the info to add instead.
+func (state *State) checkName(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fi *typesv1.FileInfo) (*typesv1.FileInfo, error) {
+	names, err := state.meta.ProjectNamePolicy(ctx, accountPublicID, projectPublicID)
+	if err != nil {
+		return nil, err
+	}
+	if names == NamesAllow {
+		return fi, nil
+	}
+	if normalized := dirsync.NormalizeName(fi.Name); names == NamesNormalize && normalized != fi.Name {
+		fi = proto.Clone(fi).(*typesv1.FileInfo)
+		fi.Name = normalized
+	}
+	siblings, ok, err := state.meta.ListDir(ctx, accountPublicID, projectPublicID, path)
+	if err != nil {
+		return nil, fmt.Errorf("listing siblings: %w", err)
+	}
+	if !ok {
+		// the parent dir is missing, which fails later on
+		return fi, nil
+	}
+	key := dirsync.NameKey(fi.Name)
+	for _, sibling := range siblings {
+		if sibling.Name == fi.Name || dirsync.NameKey(sibling.Name) != key {
+			continue
+		}
+		if names == NamesReject {
+			return nil, fmt.Errorf("%w: %q and %q", ErrNameCollision, fi.Name, sibling.Name)
+		}
+		state.ll.WarnContext(ctx, "name collides with the one of a sibling",
+			slog.String("account_pub_id", accountPublicID),
+			slog.String("project_pub_id", projectPublicID),
+			slog.String("dir", typesv1.StringFromPath(path)),
+			slog.String("name", fi.Name),
+			slog.String("sibling", sibling.Name),
+		)
+	}
+	return fi, nil
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	typesv1 "github.com/aybabtme/syncy/pkg/gen/types/v1"
+	"github.com/aybabtme/syncy/pkg/storage/blobdb"
+	"github.com/aybabtme/syncy/pkg/storage/metadb"
+	"github.com/stretchr/testify/require"
+)
+
+// the same name, composed and decomposed
+const (
+	nfc = "caf\u00e9"
+	nfd = "cafe\u0301"
+)
+
+func TestCreatePathNamePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    NamePolicy
+		existing []string
+		create   string
+		want     []string
+		wantErr  error
+	}{
+		{name: "allow", names: NamesAllow, existing: []string{nfc}, create: nfd, want: []string{nfc, nfd}},
+		{name: "warn", names: NamesWarn, existing: []string{"README.md"}, create: "Readme.md", want: []string{"README.md", "Readme.md"}},
+		{name: "reject", names: NamesReject, existing: []string{"README.md"}, create: "Readme.md", wantErr: ErrNameCollision},
+		{name: "reject same name", names: NamesReject, existing: []string{nfc}, create: nfc, want: []string{nfc}},
+		{name: "normalize", names: NamesNormalize, create: nfd, want: []string{nfc}},
+		{name: "normalize onto existing", names: NamesNormalize, existing: []string{nfc}, create: nfd, want: []string{nfc}},
+		{name: "normalize collision", names: NamesNormalize, existing: []string{"CAFÉ"}, create: nfd, want: []string{"CAFÉ", nfc}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			meta := &fakeMeta{names: tt.names}
+			for _, name := range tt.existing {
+				meta.add(name)
+			}
+			state := NewState(slog.New(slog.NewTextHandler(io.Discard, nil)), meta, fakeBlob{})
+
+			err := state.CreatePath(ctx, "account", "project", &typesv1.Path{}, &typesv1.FileInfo{Name: tt.create}, nil)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, meta.order)
+		})
+	}
+}
+
+// fakeMeta keeps the entries of the root of a single project.
+type fakeMeta struct {
+	metadb.Metadata
+	names NamePolicy
+	// names of the entries, in the order they were added
+	order []string
+}
+
+func (fm *fakeMeta) add(name string) {
+	for _, existing := range fm.order {
+		if existing == name {
+			return
+		}
+	}
+	fm.order = append(fm.order, name)
+}
+
+func (fm *fakeMeta) ProjectNamePolicy(ctx context.Context, accountPublicID, projectPublicID string) (NamePolicy, error) {
+	return fm.names, nil
+}
+
+func (fm *fakeMeta) ListDir(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path) ([]*typesv1.FileInfo, bool, error) {
+	var entries []*typesv1.FileInfo
+	for _, name := range fm.order {
+		entries = append(entries, &typesv1.FileInfo{Name: name})
+	}
+	return entries, true, nil
+}
+
+func (fm *fakeMeta) CreatePathTx(ctx context.Context, accountPublicID, projectPublicID string, path *typesv1.Path, fi *typesv1.FileInfo, fn metadb.FileSaveAction) error {
+	if _, err := fn("project", fi.Name); err != nil {
+		return err
+	}
+	fm.add(fi.Name)
+	return nil
+}
+
+type fakeBlob struct {
+	blobdb.Blob
+}
+
+func (fakeBlob) CreatePath(ctx context.Context, projectDir string, filename string, isDir bool, fn blobdb.CreateFunc) ([]byte, error) {
+	return nil, nil
+}
@@ -55,7 +55,11 @@ func (hdl *Handler) CreateProject(ctx context.Context, req *connect.Request[v1.C
 	if req.Msg.ProjectName == "" {
 		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("missing project name"))
 	}
-	publicID, err := hdl.db.CreateProject(ctx, req.Msg.AccountId, req.Msg.ProjectName)
+	names, ok := namePolicies[req.Msg.NamePolicy]
+	if !ok {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown name policy: %s", req.Msg.NamePolicy.String()))
+	}
+	publicID, err := hdl.db.CreateProject(ctx, req.Msg.AccountId, req.Msg.ProjectName, names)
 	if err != nil {
 		if err == storage.ErrAccountDoesntExist {
 			return nil, connect.NewError(connect.CodeInvalidArgument, err)
@@ -66,6 +70,36 @@ func (hdl *Handler) CreateProject(ctx context.Context, req *connect.Request[v1.C
 	return connect.NewResponse(&v1.CreateProjectResponse{ProjectId: publicID}), nil
 }
 
+var namePolicies = map[v1.NamePolicy]storage.NamePolicy{
+	v1.NamePolicy_allow:     storage.NamesAllow,
+	v1.NamePolicy_warn:      storage.NamesWarn,
+	v1.NamePolicy_reject:    storage.NamesReject,
+	v1.NamePolicy_normalize: storage.NamesNormalize,
+}
+
+func (hdl *Handler) GetProject(ctx context.Context, req *connect.Request[v1.GetProjectRequest]) (*connect.Response[v1.GetProjectResponse], error) {
+	ll := hdl.ll.WithGroup("GetProject")
+	ll.DebugContext(ctx, "received GetProject req")
+	defer ll.DebugContext(ctx, "done GetProject")
+
+	accountPubID, projectID := req.Msg.GetMeta().AccountId, req.Msg.GetMeta().ProjectId
+	names, err := hdl.db.ProjectNamePolicy(ctx, accountPubID, projectID)
+	if err != nil {
+		if err == storage.ErrProjectDoesntExist {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		ll.ErrorContext(ctx, "getting name policy of project", slog.Any("err", err))
+		return nil, connect.NewError(connect.CodeInternal, errors.New("try again later"))
+	}
+	res := &v1.GetProjectResponse{}
+	for policy, stored := range namePolicies {
+		if stored == names {
+			res.NamePolicy = policy
+		}
+	}
+	return connect.NewResponse(res), nil
+}
+
 func (hdl *Handler) Stat(ctx context.Context, req *connect.Request[v1.StatRequest]) (*connect.Response[v1.StatResponse], error) {
 	ll := hdl.ll.WithGroup("Stat")
 	ll.DebugContext(ctx, "received Stat req",
@@ -158,6 +192,55 @@ func (hdl *Handler) GetFileSum(ctx context.Context, req *connect.Request[v1.GetF
 	}), nil
 }
 
+func (hdl *Handler) GetTree(ctx context.Context, req *connect.Request[v1.GetTreeRequest]) (*connect.Response[v1.GetTreeResponse], error) {
+	ll := hdl.ll.WithGroup("GetTree")
+	ll.DebugContext(ctx, "received GetTree req")
+	defer ll.DebugContext(ctx, "done GetTree")
+
+	accountPubID, projectID := req.Msg.GetMeta().AccountId, req.Msg.GetMeta().ProjectId
+	tree, err := hdl.db.GetTree(ctx, accountPubID, projectID)
+	if err != nil {
+		if err == storage.ErrProjectDoesntExist {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		ll.ErrorContext(ctx, "getting tree from DB", slog.Any("err", err))
+		return nil, connect.NewError(connect.CodeInternal, errors.New("try again later"))
+	}
+
+	return connect.NewResponse(&v1.GetTreeResponse{
+		Root: tree,
+	}), nil
+}
+
+// maxFileSumsBatch bounds the work of a single GetFileSums request.
+const maxFileSumsBatch = 1024
+
+func (hdl *Handler) GetFileSums(ctx context.Context, req *connect.Request[v1.GetFileSumsRequest]) (*connect.Response[v1.GetFileSumsResponse], error) {
+	ll := hdl.ll.WithGroup("GetFileSums")
+	ll.DebugContext(ctx, "received GetFileSums req", slog.Int("paths", len(req.Msg.Paths)))
+	defer ll.DebugContext(ctx, "done GetFileSums")
+
+	if len(req.Msg.Paths) > maxFileSumsBatch {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("can't ask for more than %d sums at once", maxFileSumsBatch))
+	}
+	accountPubID, projectID := req.Msg.GetMeta().AccountId, req.Msg.GetMeta().ProjectId
+	sums, err := hdl.db.GetFileSums(ctx, accountPubID, projectID, req.Msg.Paths)
+	if err != nil {
+		switch {
+		case err == storage.ErrProjectDoesntExist:
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		case errors.Is(err, storage.ErrFileDoesntExist):
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		ll.ErrorContext(ctx, "getting filesums from DB", slog.Any("err", err))
+		return nil, connect.NewError(connect.CodeInternal, errors.New("try again later"))
+	}
+
+	return connect.NewResponse(&v1.GetFileSumsResponse{
+		Sums: sums,
+	}), nil
+}
+
 func (hdl *Handler) Create(ctx context.Context, stream *connect.ClientStream[v1.CreateRequest]) (*connect.Response[v1.CreateResponse], error) {
 	ll := hdl.ll.WithGroup("Create")
 	ll.DebugContext(ctx, "received Create req")
@@ -184,10 +267,53 @@ func (hdl *Handler) Create(ctx context.Context, stream *connect.ClientStream[v1.
 	default:
 		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown hasher: %s", creating.Hasher.String()))
 	}
-	ll.DebugContext(ctx, "creating path")
+	if err := validateSymlink(creating.Path, creating.Info); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := dirsync.CheckHardLink(creating.Path, creating.Info); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
 	accountPubID, projectID := req.GetMeta().AccountId, req.GetMeta().ProjectId
+	var upload *storage.Upload
+	if creating.UploadId != "" && creating.Info.HardLink == nil && !creating.Info.IsDir && !creating.Info.IsSymlink() {
+		var err error
+		upload, err = hdl.db.OpenUpload(ctx, accountPubID, projectID, creating.UploadId, creating.Offset)
+		switch {
+		case errors.Is(err, storage.ErrInvalidUploadID):
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		case errors.Is(err, storage.ErrUploadIncomplete):
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		case err != nil:
+			ll.ErrorContext(ctx, "opening upload", slog.Any("err", err))
+			return nil, connect.NewError(connect.CodeInternal, errors.New("try again later"))
+		}
+	}
+	// an upload whose content doesn't match its sum is started over
+	uploadMismatch := false
+	ll.DebugContext(ctx, "creating path")
 	err := hdl.db.CreatePath(ctx, accountPubID, projectID, creating.Path, creating.Info, func(w io.Writer) (blake3_64_256_sum []byte, _ error) {
-		tgt := io.MultiWriter(w, h)
+		tgt := dirsync.MultiWriter(w, h)
+		if creating.Info.HardLink != nil {
+			if err := hdl.copyHardLink(ctx, accountPubID, projectID, creating.Info.HardLink, tgt); err != nil {
+				return nil, err
+			}
+			return h.Sum(nil), nil
+		}
+		if upload != nil {
+			// what was kept of the upload comes first, and what's received
+			// is kept along
+			if creating.Offset > 0 {
+				kept, err := upload.Kept()
+				if err == nil {
+					_, err = dirsync.CopySparse(tgt, kept)
+				}
+				if err != nil {
+					ll.ErrorContext(ctx, "reading what was kept of upload", slog.Any("err", err))
+					return nil, connect.NewError(connect.CodeInternal, errors.New("unable to resume upload"))
+				}
+			}
+			tgt = dirsync.MultiWriter(w, h, upload)
+		}
 		var err error
 		for {
 			if err = conn.Receive(&req); err != nil {
@@ -198,6 +324,9 @@ func (hdl *Handler) Create(ctx context.Context, stream *connect.ClientStream[v1.
 			case *v1.CreateRequest_Writing_:
 				ll.DebugContext(ctx, "writing file block")
 				_, err = tgt.Write(step.Writing.ContentBlock)
+				if err == nil && step.Writing.Hole > 0 {
+					err = dirsync.WriteHole(tgt, int64(step.Writing.Hole))
+				}
 				if err != nil {
 					ll.Error("writing content to target", slog.Any("err", err))
 					return nil, connect.NewError(connect.CodeInternal, errors.New("unable to write to target"))
@@ -207,6 +336,7 @@ func (hdl *Handler) Create(ctx context.Context, stream *connect.ClientStream[v1.
 				gotSum := h.Sum(nil)
 				wantSum := req.GetClosing().Sum
 				if !bytes.Equal(gotSum, wantSum) {
+					uploadMismatch = true
 					ll.ErrorContext(ctx, "hashsum mismatch",
 						slog.String("want", hex.EncodeToString(wantSum)),
 						slog.String("got", hex.EncodeToString(wantSum)),
@@ -222,17 +352,47 @@ func (hdl *Handler) Create(ctx context.Context, stream *connect.ClientStream[v1.
 			}
 		}
 	})
+	if upload != nil {
+		if closeErr := upload.Close(); closeErr != nil {
+			ll.ErrorContext(ctx, "closing upload", slog.Any("err", closeErr))
+		}
+		if err == nil || uploadMismatch {
+			if rmErr := hdl.db.RemoveUpload(ctx, accountPubID, projectID, creating.UploadId); rmErr != nil {
+				ll.ErrorContext(ctx, "removing upload", slog.Any("err", rmErr))
+			}
+		}
+	}
 	if err != nil {
 		if err == storage.ErrParentDirDoesntExist {
 			path := typesv1.StringFromPath(creating.Path)
 			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("parent directory doesn't exist for %q, create it first", path))
 		}
+		if errors.Is(err, storage.ErrNameCollision) {
+			return nil, connect.NewError(connect.CodeAlreadyExists, err)
+		}
 		ll.Error("creating path", slog.Any("err", err))
 		return nil, fmt.Errorf("failed to create path: %q", typesv1.StringFromPath(creating.Path))
 	}
 	return connect.NewResponse(&v1.CreateResponse{}), nil
 }
 
+func (hdl *Handler) GetUpload(ctx context.Context, req *connect.Request[v1.GetUploadRequest]) (*connect.Response[v1.GetUploadResponse], error) {
+	ll := hdl.ll.WithGroup("GetUpload")
+	ll.DebugContext(ctx, "received GetUpload req")
+	defer ll.DebugContext(ctx, "done GetUpload")
+
+	accountPubID, projectID := req.Msg.GetMeta().AccountId, req.Msg.GetMeta().ProjectId
+	size, err := hdl.db.UploadSize(ctx, accountPubID, projectID, req.Msg.UploadId)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidUploadID) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		ll.ErrorContext(ctx, "getting size of upload", slog.Any("err", err))
+		return nil, connect.NewError(connect.CodeInternal, errors.New("try again later"))
+	}
+	return connect.NewResponse(&v1.GetUploadResponse{Size: size}), nil
+}
+
 func (hdl *Handler) Patch(ctx context.Context, stream *connect.ClientStream[v1.PatchRequest]) (*connect.Response[v1.PatchResponse], error) {
 	ll := hdl.ll.WithGroup("Patch")
 	ll.DebugContext(ctx, "received Patch req")
@@ -259,10 +419,22 @@ func (hdl *Handler) Patch(ctx context.Context, stream *connect.ClientStream[v1.P
 	default:
 		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown hasher: %s", opening.Hasher.String()))
 	}
+	if err := validateSymlink(opening.Path, opening.Info); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := dirsync.CheckHardLink(opening.Path, opening.Info); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
 	ll.DebugContext(ctx, "opening path for patching")
 	accountPubID, projectID := req.GetMeta().AccountId, req.GetMeta().ProjectId
 	err := hdl.db.PatchPath(ctx, accountPubID, projectID, opening.Path, opening.Info, opening.Sum, func(orig io.ReadSeeker, w io.Writer) (blake3_64_256_sum []byte, _ error) {
-		tgt := io.MultiWriter(w, h)
+		tgt := dirsync.MultiWriter(w, h)
+		if opening.Info.HardLink != nil {
+			if err := hdl.copyHardLink(ctx, accountPubID, projectID, opening.Info.HardLink, tgt); err != nil {
+				return nil, err
+			}
+			return h.Sum(nil), nil
+		}
 
 		patcher := dirsync.NewFilePatcher(orig, tgt, opening.Sum)
 
@@ -280,8 +452,10 @@ func (hdl *Handler) Patch(ctx context.Context, stream *connect.ClientStream[v1.P
 					_, err = patcher.WriteBlock(p.BlockId)
 				case *typesv1.FileBlockPatch_Data:
 					_, err = patcher.WriteData(p.Data)
+				case *typesv1.FileBlockPatch_Hole:
+					_, err = patcher.WriteHole(p.Hole)
 				default:
-					return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("expecting patch of type `block_id` or `data`"))
+					return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("expecting patch of type `block_id`, `data` or `hole`"))
 				}
 				if err != nil {
 					ll.Error("writing content to target", slog.Any("err", err))
@@ -332,3 +506,210 @@ func (hdl *Handler) Delete(ctx context.Context, req *connect.Request[v1.DeleteRe
 
 	return connect.NewResponse(&v1.DeleteResponse{}), nil
 }
+
+func (hdl *Handler) Move(ctx context.Context, req *connect.Request[v1.MoveRequest]) (*connect.Response[v1.MoveResponse], error) {
+	ll := hdl.ll.WithGroup("Move")
+	ll.DebugContext(ctx, "received Move req")
+	defer ll.DebugContext(ctx, "done Move")
+
+	if len(req.Msg.GetFrom().GetElements()) == 0 || req.Msg.FromInfo == nil || req.Msg.Info == nil || req.Msg.Info.Name == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("missing path or info to move"))
+	}
+	if req.Msg.FromInfo.IsDir != req.Msg.Info.IsDir {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("can't change the type of what's moved"))
+	}
+	accountPubID, projectID := req.Msg.GetMeta().AccountId, req.Msg.GetMeta().ProjectId
+	if err := hdl.db.MovePath(ctx, accountPubID, projectID, req.Msg.From, req.Msg.FromInfo, req.Msg.ParentDir, req.Msg.Info); err != nil {
+		if err == storage.ErrProjectDoesntExist {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		if errors.Is(err, storage.ErrNameCollision) {
+			return nil, connect.NewError(connect.CodeAlreadyExists, err)
+		}
+		ll.ErrorContext(ctx, "couldn't move path", slog.Any("err", err))
+		return nil, connect.NewError(connect.CodeInternal, errors.New("unable to move path"))
+	}
+
+	return connect.NewResponse(&v1.MoveResponse{}), nil
+}
+
+const downloadBlockSize = 64 << 10
+
+func (hdl *Handler) Download(ctx context.Context, req *connect.Request[v1.DownloadRequest], stream *connect.ServerStream[v1.DownloadResponse]) error {
+	ll := hdl.ll.WithGroup("Download")
+	ll.DebugContext(ctx, "received Download req")
+	defer ll.DebugContext(ctx, "done Download")
+
+	var h hash.Hash
+	switch req.Msg.Hasher {
+	case v1.Hasher_blake3_64_256:
+		h = blake3.New(64, nil)
+	default:
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown hasher: %s", req.Msg.Hasher.String()))
+	}
+
+	accountPubID, projectID := req.Msg.GetMeta().AccountId, req.Msg.GetMeta().ProjectId
+	ok, err := hdl.db.ReadPath(ctx, accountPubID, projectID, req.Msg.Path, func(r io.Reader) error {
+		r = dirsync.TeeReader(r, h)
+		writingStep := &v1.DownloadResponse_Writing{}
+		writing := &v1.DownloadResponse{
+			Step: &v1.DownloadResponse_Writing_{Writing: writingStep},
+		}
+		buf := make([]byte, downloadBlockSize)
+		sendContent := func(r io.Reader) error {
+			writingStep.Hole = 0
+			for {
+				n, err := io.ReadFull(r, buf)
+				if n > 0 {
+					writingStep.ContentBlock = buf[:n]
+					ll.DebugContext(ctx, "sending file block")
+					if err := stream.Send(writing); err != nil {
+						return fmt.Errorf("sending file block: %w", err)
+					}
+				}
+				switch err {
+				case nil:
+					// continue
+				case io.EOF, io.ErrUnexpectedEOF:
+					return nil
+				default:
+					return fmt.Errorf("reading file: %w", err)
+				}
+			}
+		}
+		// the holes of sparse files are sent as their size
+		sr, sparse := r.(dirsync.SparseReader)
+		for sparse {
+			data, hole, err := sr.NextHole()
+			if err != nil {
+				return fmt.Errorf("finding holes of file: %w", err)
+			}
+			if hole == 0 {
+				break
+			}
+			if err := sendContent(io.LimitReader(r, data)); err != nil {
+				return err
+			}
+			if err := sr.SkipHole(hole); err != nil {
+				return fmt.Errorf("skipping hole of file: %w", err)
+			}
+			writingStep.ContentBlock, writingStep.Hole = nil, uint64(hole)
+			ll.DebugContext(ctx, "sending file hole")
+			if err := stream.Send(writing); err != nil {
+				return fmt.Errorf("sending file hole: %w", err)
+			}
+		}
+		if err := sendContent(r); err != nil {
+			return err
+		}
+		ll.DebugContext(ctx, "closing file")
+		return stream.Send(&v1.DownloadResponse{
+			Step: &v1.DownloadResponse_Closing_{Closing: &v1.DownloadResponse_Closing{Sum: h.Sum(nil)}},
+		})
+	})
+	return hdl.readPathError(ctx, ll, ok, err)
+}
+
+func (hdl *Handler) DownloadPatch(ctx context.Context, req *connect.Request[v1.DownloadPatchRequest], stream *connect.ServerStream[v1.DownloadPatchResponse]) error {
+	ll := hdl.ll.WithGroup("DownloadPatch")
+	ll.DebugContext(ctx, "received DownloadPatch req")
+	defer ll.DebugContext(ctx, "done DownloadPatch")
+
+	var h hash.Hash
+	switch req.Msg.Hasher {
+	case v1.Hasher_blake3_64_256:
+		h = blake3.New(64, nil)
+	default:
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown hasher: %s", req.Msg.Hasher.String()))
+	}
+	sum := req.Msg.Sum
+	if sum == nil {
+		return connect.NewError(connect.CodeInvalidArgument, errors.New("missing sum of the file to patch"))
+	}
+	if len(sum.SumBlocks) > 0 && sum.BlockSize == 0 {
+		return connect.NewError(connect.CodeInvalidArgument, errors.New("sum has blocks but no block size"))
+	}
+
+	accountPubID, projectID := req.Msg.GetMeta().AccountId, req.Msg.GetMeta().ProjectId
+	ok, err := hdl.db.ReadPath(ctx, accountPubID, projectID, req.Msg.Path, func(r io.Reader) error {
+		patch := &typesv1.FileBlockPatch{}
+		dataPatch := &typesv1.FileBlockPatch_Data{}
+		blockIDPatch := &typesv1.FileBlockPatch_BlockId{}
+		holePatch := &typesv1.FileBlockPatch_Hole{}
+		patching := &v1.DownloadPatchResponse{
+			Step: &v1.DownloadPatchResponse_Patching_{
+				Patching: &v1.DownloadPatchResponse_Patching{Patch: patch},
+			},
+		}
+		_, err := dirsync.Rsync(ctx, dirsync.TeeReader(r, h), sum,
+			func(b []byte) (int, error) {
+				ll.DebugContext(ctx, "sending block data patch")
+				dataPatch.Data = b
+				patch.Patch = dataPatch
+				return len(b), stream.Send(patching)
+			},
+			func(u uint32) (int, error) {
+				ll.DebugContext(ctx, "sending block id patch")
+				blockIDPatch.BlockId = u
+				patch.Patch = blockIDPatch
+				return 4, stream.Send(patching)
+			},
+			func(size uint64) (int, error) {
+				ll.DebugContext(ctx, "sending hole patch")
+				holePatch.Hole = size
+				patch.Patch = holePatch
+				return 8, stream.Send(patching)
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("sending block patches: %w", err)
+		}
+		ll.DebugContext(ctx, "closing file")
+		return stream.Send(&v1.DownloadPatchResponse{
+			Step: &v1.DownloadPatchResponse_Closing_{Closing: &v1.DownloadPatchResponse_Closing{Sum: h.Sum(nil)}},
+		})
+	})
+	return hdl.readPathError(ctx, ll, ok, err)
+}
+
+func (hdl *Handler) readPathError(ctx context.Context, ll *slog.Logger, ok bool, err error) error {
+	switch {
+	case err == storage.ErrProjectDoesntExist, err == storage.ErrNotAFile:
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case err != nil:
+		ll.ErrorContext(ctx, "reading path", slog.Any("err", err))
+		return connect.NewError(connect.CodeInternal, errors.New("unable to read path"))
+	case !ok:
+		return connect.NewError(connect.CodeNotFound, errors.New("no such file"))
+	}
+	return nil
+}
+
+// validateSymlink rejects symlinks with a target outside of the project,
+// they're never followed here but would be wherever the project is synced to.
+func validateSymlink(dir *typesv1.Path, fi *typesv1.FileInfo) error {
+	if !fi.IsSymlink() {
+		return nil
+	}
+	linkPath := typesv1.StringFromPath(typesv1.PathJoin(dir, fi.Name))
+	if _, err := dirsync.ResolveSymlink(linkPath, fi.LinkTarget); err != nil {
+		return fmt.Errorf("invalid symlink %q: %w", linkPath, err)
+	}
+	return nil
+}
+
+// copyHardLink writes the content of the file that a hard link links to, the
+// client doesn't send it again.
+func (hdl *Handler) copyHardLink(ctx context.Context, accountPubID, projectID string, target *typesv1.Path, w io.Writer) error {
+	ok, err := hdl.db.ReadPath(ctx, accountPubID, projectID, target, func(r io.Reader) error {
+		_, err := dirsync.CopySparse(w, r)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("copying hard link target %q: %w", typesv1.StringFromPath(target), err)
+	}
+	if !ok {
+		return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("hard link target %q doesn't exist, create it first", typesv1.StringFromPath(target)))
+	}
+	return nil
+}